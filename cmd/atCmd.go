@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/config"
+	"github.com/benleb/gloomberg/internal/nemo/pnl"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/research"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	flagAtWallets    []string
+	flagAtBlockRange uint64
+)
+
+// atCmd represents the at command.
+var atCmd = &cobra.Command{
+	Use:   "at <date>",
+	Short: "show the holdings of wallets & their floors at a past date",
+	Long: `Rewinds the pnl ledgers of the wallets (see import-wallet) to the given date (2006-01-02 or RFC3339) and values the holdings with the floors reconstructed from the recorded research events.
+Useful for performance reviews & tax snapshots.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: runAt,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(atCmd)
+
+	atCmd.Flags().StringSliceVarP(&flagAtWallets, "wallet", "w", make([]string, 0), "wallets (address or ens) to query (default: own wallets from config)")
+	atCmd.Flags().Uint64Var(&flagAtBlockRange, "block-range", 100_000, "number of blocks per eth_getLogs request")
+}
+
+func runAt(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	at, err := time.Parse(time.RFC3339, args[0])
+	if err != nil {
+		if at, err = time.Parse(time.DateOnly, args[0]); err != nil {
+			log.Fatalf("❌ invalid date %s (use 2006-01-02 or RFC3339)", args[0])
+		}
+	}
+
+	if at.After(time.Now()) {
+		log.Fatalf("❌ %s is in the future", at.Format(time.RFC3339))
+	}
+
+	// compatibility with old config key
+	var providerConfig interface{}
+	if cfg := viper.Get("provider"); cfg != nil {
+		providerConfig = cfg
+	} else {
+		providerConfig = viper.Get("nodes")
+	}
+
+	pool, err := provider.FromConfig(providerConfig)
+	if err != nil || pool == nil {
+		log.Fatal("❌ running provider failed, exiting")
+	}
+
+	pool.Rueidi = gb.Rueidi
+	gb.ProviderPool = pool
+
+	// wallets to query
+	wallets := make([]common.Address, 0)
+	walletNames := make(map[common.Address]string)
+	walletGroups := make(map[common.Address]string)
+
+	for _, walletArg := range flagAtWallets {
+		if common.IsHexAddress(walletArg) {
+			wallets = append(wallets, common.HexToAddress(walletArg))
+
+			continue
+		}
+
+		walletAddress, err := pool.ResolveENS(ctx, walletArg)
+		if err != nil {
+			log.Fatalf("❌ could not resolve %s: %s", walletArg, err)
+		}
+
+		wallets = append(wallets, walletAddress)
+		walletNames[walletAddress] = walletArg
+	}
+
+	if len(wallets) == 0 {
+		if ownWallets := config.GetOwnWalletsFromConfig(nil); ownWallets != nil {
+			for _, w := range *ownWallets {
+				wallets = append(wallets, w.Address)
+				walletNames[w.Address] = w.Name
+				walletGroups[w.Address] = w.Group
+			}
+		}
+	}
+
+	if len(wallets) == 0 {
+		log.Fatal("❌ no wallets to query")
+	}
+
+	blockNumber, err := pnl.BlockAtTime(ctx, pool, at)
+	if err != nil {
+		log.Fatalf("❌ finding the block at %s failed: %s", at.Format(time.RFC3339), err)
+	}
+
+	fmt.Printf("⏳ holdings at %s | block %s\n", style.BoldAlmostWhite(at.Format(time.RFC3339)), style.BoldAlmostWhite(strconv.FormatUint(blockNumber, 10)))
+
+	// holdings of all wallets
+	holdingsByWallet := make(map[common.Address][]*pnl.Holding)
+	contracts := make([]common.Address, 0)
+	seenContracts := make(map[common.Address]bool)
+
+	for _, walletAddress := range wallets {
+		ledger := pnl.LoadLedger(ctx, gb.Rueidi, walletAddress)
+
+		holdings, err := pnl.HoldingsAt(ctx, pool, ledger, blockNumber, max(flagAtBlockRange, 1))
+		if err != nil {
+			if errors.Is(err, pnl.ErrLedgerNotImported) {
+				log.Warnf("❗️ %s: %s", walletAddress.Hex(), err)
+			} else {
+				log.Errorf("❌ rewinding the ledger of %s failed: %s", walletAddress.Hex(), err)
+			}
+
+			continue
+		}
+
+		holdingsByWallet[walletAddress] = pnl.SortedHoldings(holdings)
+
+		for _, holding := range holdings {
+			if !seenContracts[holding.Token.Address] {
+				seenContracts[holding.Token.Address] = true
+				contracts = append(contracts, holding.Token.Address)
+			}
+		}
+	}
+
+	floors, err := research.FloorsAt(at, contracts)
+	if err != nil {
+		log.Errorf("❌ reconstructing the floors failed: %s", err)
+	}
+
+	collectionNames := make(map[common.Address]string)
+
+	totalValue := big.NewInt(0)
+	groupValues := make(map[string]*big.Int)
+
+	for _, walletAddress := range wallets {
+		holdings, ok := holdingsByWallet[walletAddress]
+		if !ok {
+			continue
+		}
+
+		walletName := walletNames[walletAddress]
+		if walletName == "" {
+			walletName = style.ShortenAdressPTR(&walletAddress)
+		}
+
+		fmt.Printf("\n👛 %s · %d holdings\n", style.BoldAlmostWhite(walletName), len(holdings))
+
+		// group by collection
+		numTokens := make(map[common.Address]int64)
+		collections := make([]common.Address, 0)
+
+		for _, holding := range holdings {
+			if _, ok := numTokens[holding.Token.Address]; !ok {
+				collections = append(collections, holding.Token.Address)
+			}
+
+			numTokens[holding.Token.Address] += holding.Amount
+		}
+
+		walletValue := big.NewInt(0)
+
+		for _, contractAddress := range collections {
+			name, ok := collectionNames[contractAddress]
+			if !ok {
+				if name, err = pool.ERC721CollectionName(ctx, contractAddress); err != nil || name == "" {
+					name = style.ShortenAdressPTR(&contractAddress)
+				}
+
+				collectionNames[contractAddress] = name
+			}
+
+			floor, ok := floors[contractAddress]
+			if !ok {
+				fmt.Printf("  %4dx %-32s  %s\n", numTokens[contractAddress], name, style.GrayStyle.Render("no floor recorded"))
+
+				continue
+			}
+
+			value := new(big.Int).Mul(floor.Floor.Wei(), big.NewInt(numTokens[contractAddress]))
+			walletValue.Add(walletValue, value)
+
+			fmt.Printf("  %4dx %-32s  floor %7.3fΞ %s · %8.3fΞ\n",
+				numTokens[contractAddress],
+				name,
+				floor.Floor.Ether(),
+				style.GrayStyle.Render("("+floor.Source+")"),
+				price.NewPrice(value).Ether(),
+			)
+		}
+
+		totalValue.Add(totalValue, walletValue)
+
+		if group := walletGroups[walletAddress]; group != "" {
+			if _, ok := groupValues[group]; !ok {
+				groupValues[group] = big.NewInt(0)
+			}
+
+			groupValues[group].Add(groupValues[group], walletValue)
+		}
+
+		fmt.Printf("  %s %.3fΞ\n", style.BoldAlmostWhite("value"), price.NewPrice(walletValue).Ether())
+	}
+
+	// aggregated per wallet group
+	if len(groupValues) > 0 {
+		groupNames := make([]string, 0, len(groupValues))
+		for group := range groupValues {
+			groupNames = append(groupNames, group)
+		}
+
+		sort.Strings(groupNames)
+
+		fmt.Println()
+
+		for _, group := range groupNames {
+			fmt.Printf("🗂️ %s %.3fΞ\n", style.BoldAlmostWhite(fmt.Sprintf("%-16s", group)), price.NewPrice(groupValues[group]).Ether())
+		}
+	}
+
+	fmt.Printf("\n💰 total value at %s: %s\n", at.Format(time.DateOnly), style.BoldAlmostWhite(fmt.Sprintf("%.3fΞ", price.NewPrice(totalValue).Ether())))
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/bench"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// benchCmd represents the bench command.
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "measure the throughput of the event pipeline",
+	Long: `Replays a corpus of txs & receipts through the pipeline (decode, parse, encode) and reports the events/sec,
+allocations & per-stage latencies for each number of workers. Without a corpus, a synthetic corpus of erc721 sales,
+mints & transfers is generated. Record a corpus of real txs with 'gloomberg bench record'.`,
+	Args: cobra.NoArgs,
+
+	Run: runBench,
+}
+
+// benchRecordCmd represents the bench record command.
+var benchRecordCmd = &cobra.Command{
+	Use:   "record <file>",
+	Short: "record the nft txs of the last blocks as corpus for 'gloomberg bench'",
+	Args:  cobra.ExactArgs(1),
+
+	Run: runBenchRecord,
+}
+
+var (
+	flagBenchCorpus     string
+	flagBenchEvents     int
+	flagBenchWorkers    []int
+	flagBenchRepeat     int
+	flagBenchNodes      bool
+	flagBenchCPUProfile string
+	flagBenchMemProfile string
+
+	flagBenchRecordBlocks uint64
+	flagBenchRecordMaxTxs int
+)
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchRecordCmd)
+
+	benchCmd.Flags().StringVarP(&flagBenchCorpus, "corpus", "c", "", "corpus recorded with 'gloomberg bench record' (synthetic if empty)")
+	benchCmd.Flags().IntVarP(&flagBenchEvents, "events", "n", 10_000, "number of txs of the synthetic corpus")
+	benchCmd.Flags().IntSliceVarP(&flagBenchWorkers, "workers", "w", []int{1, 2, 4, 8}, "numbers of workers to compare")
+	benchCmd.Flags().IntVarP(&flagBenchRepeat, "repeat", "r", 1, "number of times the corpus is replayed per run")
+	benchCmd.Flags().BoolVar(&flagBenchNodes, "nodes", false, "use the configured nodes for erc20/erc1155 decoding & conversions (not offline)")
+	benchCmd.Flags().StringVar(&flagBenchCPUProfile, "cpuprofile", "", "write a cpu profile to the file")
+	benchCmd.Flags().StringVar(&flagBenchMemProfile, "memprofile", "", "write a heap profile to the file")
+
+	benchRecordCmd.Flags().Uint64VarP(&flagBenchRecordBlocks, "blocks", "b", 25, "number of recent blocks to record")
+	benchRecordCmd.Flags().IntVarP(&flagBenchRecordMaxTxs, "max-txs", "m", 5_000, "max. number of txs to record (0 = all)")
+}
+
+func runBench(_ *cobra.Command, _ []string) {
+	var entries []*bench.Entry
+
+	var err error
+
+	if flagBenchCorpus != "" {
+		entries, err = bench.LoadCorpus(flagBenchCorpus)
+	} else {
+		entries, err = bench.SyntheticCorpus(flagBenchEvents)
+	}
+
+	if err != nil {
+		log.Fatalf("❌ loading the corpus failed: %s", err)
+	}
+
+	// an empty pool keeps the parsers offline
+	pool := &provider.Pool{}
+	if flagBenchNodes {
+		pool = benchProviderPool()
+	}
+
+	fmtCorpus := "synthetic corpus"
+	if flagBenchCorpus != "" {
+		fmtCorpus = flagBenchCorpus
+	}
+
+	fmt.Printf("⏱️  replaying %s txs from %s %dx · GOMAXPROCS %d\n\n",
+		style.BoldAlmostWhite(fmt.Sprint(len(entries))), style.BoldAlmostWhite(fmtCorpus), flagBenchRepeat, runtime.GOMAXPROCS(0),
+	)
+
+	if flagBenchCPUProfile != "" {
+		cpuProfile, err := os.Create(flagBenchCPUProfile)
+		if err != nil {
+			log.Fatalf("❌ creating the cpu profile failed: %s", err)
+		}
+		defer cpuProfile.Close()
+
+		if err := pprof.StartCPUProfile(cpuProfile); err != nil {
+			log.Fatalf("❌ starting the cpu profile failed: %s", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	var best *bench.Result
+
+	for _, workers := range flagBenchWorkers {
+		result := bench.Run(entries, pool, workers, flagBenchRepeat)
+
+		printBenchResult(result)
+
+		if best == nil || result.EventsPerSecond() > best.EventsPerSecond() {
+			best = result
+		}
+	}
+
+	if best != nil && len(flagBenchWorkers) > 1 {
+		fmt.Printf("🏁 best throughput with %s workers · %s (server.workers.newLogHandler is %d)\n",
+			style.BoldAlmostWhite(fmt.Sprint(best.Workers)),
+			style.BoldAlmostWhite(fmt.Sprintf("%.0f events/s", best.EventsPerSecond())),
+			viper.GetInt("server.workers.newLogHandler"),
+		)
+	}
+
+	if flagBenchMemProfile != "" {
+		memProfile, err := os.Create(flagBenchMemProfile)
+		if err != nil {
+			log.Fatalf("❌ creating the heap profile failed: %s", err)
+		}
+		defer memProfile.Close()
+
+		runtime.GC()
+
+		if err := pprof.WriteHeapProfile(memProfile); err != nil {
+			log.Fatalf("❌ writing the heap profile failed: %s", err)
+		}
+	}
+}
+
+func printBenchResult(result *bench.Result) {
+	out := strings.Builder{}
+
+	out.WriteString(fmt.Sprintf("  %s workers · %s · %s parsed in %s · %s allocs & %s per event\n",
+		style.BoldAlmostWhite(fmt.Sprintf("%2d", result.Workers)),
+		style.TrendLightGreenStyle.Render(fmt.Sprintf("%8.0f events/s", result.EventsPerSecond())),
+		style.BoldAlmostWhite(fmt.Sprint(result.NumParsed)),
+		result.Duration.Round(1e6),
+		style.BoldAlmostWhite(fmt.Sprintf("%.0f", result.AllocsPerEvent())),
+		style.BoldAlmostWhite(fmt.Sprintf("%.1fkB", result.BytesPerEvent()/1024)),
+	))
+
+	for _, stage := range result.Stages {
+		out.WriteString(style.GrayStyle.Render(fmt.Sprintf("      %-7s p50 %9s · p95 %9s · p99 %9s · max %9s",
+			stage.Name, stage.P50, stage.P95, stage.P99, stage.Max,
+		)) + "\n")
+	}
+
+	fmt.Println(out.String())
+}
+
+func runBenchRecord(_ *cobra.Command, args []string) {
+	pool := benchProviderPool()
+
+	progress := func(done int, total int) {
+		if done%100 == 0 || done == total {
+			fmt.Printf("\r📼 fetched %d/%d txs", done, total)
+		}
+	}
+
+	entries, err := bench.RecordCorpus(context.Background(), pool, flagBenchRecordBlocks, flagBenchRecordMaxTxs, progress)
+	if err != nil {
+		log.Fatalf("❌ recording the corpus failed: %s", err)
+	}
+
+	if err := bench.WriteCorpus(args[0], entries); err != nil {
+		log.Fatalf("❌ writing the corpus failed: %s", err)
+	}
+
+	fmt.Printf("\n📼 recorded %s txs from the last %d blocks to %s\n", style.BoldAlmostWhite(fmt.Sprint(len(entries))), flagBenchRecordBlocks, style.BoldAlmostWhite(args[0]))
+}
+
+func benchProviderPool() *provider.Pool {
+	// compatibility with old config key
+	var providerConfig interface{}
+	if cfg := viper.Get("provider"); cfg != nil {
+		providerConfig = cfg
+	} else {
+		providerConfig = viper.Get("nodes")
+	}
+
+	pool, err := provider.FromConfig(providerConfig)
+	if err != nil || pool == nil {
+		log.Fatal("❌ running provider failed, exiting")
+	}
+
+	return pool
+}
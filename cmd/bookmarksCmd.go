@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var flagBookmarksNum int
+
+// bookmarksCmd represents the bookmarks command.
+var bookmarksCmd = &cobra.Command{
+	Use:   "bookmarks",
+	Short: "show the bookmarked events",
+	Long: `Events are bookmarked in 'gloomberg live' by pressing 'b' (latest printed event) or via the 🔖 link
+in the web ui. Bookmarks are stored in degendb (mongodb.uri) alongside the notes.`,
+	Args: cobra.NoArgs,
+
+	Run: runBookmarks,
+}
+
+// bookmarksRemoveCmd represents the bookmarks rm command.
+var bookmarksRemoveCmd = &cobra.Command{
+	Use:   "rm <tx hash>",
+	Short: "remove a bookmark",
+	Args:  cobra.ExactArgs(1),
+
+	Run: runBookmarksRemove,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(bookmarksCmd)
+	bookmarksCmd.AddCommand(bookmarksRemoveCmd)
+
+	bookmarksCmd.Flags().IntVarP(&flagBookmarksNum, "num", "n", 0, "number of bookmarks to show (0 = all)")
+}
+
+func runBookmarks(_ *cobra.Command, _ []string) {
+	bookmarks, err := bookmarksDegenDB().Bookmarks(context.Background())
+	if err != nil {
+		log.Fatalf("❌ loading the bookmarks failed: %s", err)
+	}
+
+	if len(bookmarks) == 0 {
+		fmt.Println("no bookmarks yet - press 'b' in 'gloomberg live' to bookmark the latest event")
+
+		return
+	}
+
+	if flagBookmarksNum > 0 {
+		bookmarks = bookmarks[:min(len(bookmarks), flagBookmarksNum)]
+	}
+
+	for _, bookmark := range bookmarks {
+		collections := strings.Join(bookmark.Collections, ", ")
+		if collections == "" {
+			collections = "-"
+		}
+
+		line := fmt.Sprintf("%s  %-10s %8.3fΞ  %s  %s",
+			style.DarkGrayStyle.Render(bookmark.CreatedAt.Local().Format("2006-01-02 15:04")),
+			bookmark.Action,
+			bookmark.Price,
+			style.AlmostWhiteStyle.Render(collections),
+			style.TerminalLink(utils.GetEtherscanTxURL(bookmark.TxHash), style.ShortenHashStyled(common.HexToHash(bookmark.TxHash))),
+		)
+
+		if bookmark.Note != "" {
+			line += style.DarkGrayStyle.Render(" | ") + bookmark.Note
+		}
+
+		fmt.Println(line)
+	}
+}
+
+func runBookmarksRemove(_ *cobra.Command, args []string) {
+	txHash := common.HexToHash(args[0])
+
+	if err := bookmarksDegenDB().DeleteBookmark(context.Background(), txHash.Hex()); err != nil {
+		log.Fatalf("❌ removing the bookmark of %s failed: %s", txHash.Hex(), err)
+	}
+
+	fmt.Printf("🗑️ bookmark %s removed\n", style.BoldAlmostWhite(txHash.Hex()))
+}
+
+func bookmarksDegenDB() *degendb.DegenDB {
+	ddb := degendb.NewDegenDB()
+	if ddb == nil {
+		log.Fatal("❌ bookmarks are stored in degendb, please configure a reachable mongodb.uri")
+	}
+
+	return ddb
+}
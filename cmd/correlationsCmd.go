@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/correlations"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// correlationsCmd represents the correlations command.
+var correlationsCmd = &cobra.Command{
+	Use:   "correlations",
+	Short: "show which watched collections move together",
+	Long: `Calculates the correlation of the floor changes (or sales volumes) between the watched collections from the
+market samples recorded while running 'gloomberg live' & shows them as matrix. Values close to 1 mean the
+collections move together, values close to -1 that they move in opposite directions (e.g. during rotations).`,
+	Example: `  gloomberg correlations --metric volume --window 72h`,
+	Args:    cobra.NoArgs,
+
+	Run: runCorrelations,
+}
+
+var (
+	flagCorrelationsMetric string
+	flagCorrelationsWindow time.Duration
+)
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(correlationsCmd)
+
+	correlationsCmd.Flags().StringVarP(&flagCorrelationsMetric, "metric", "m", string(correlations.MetricFloor), "metric to correlate: floor, volume or topoffer")
+	correlationsCmd.Flags().DurationVarP(&flagCorrelationsWindow, "window", "w", time.Hour*24*7, "rolling window of samples to use")
+}
+
+func runCorrelations(_ *cobra.Command, _ []string) {
+	ctx := context.Background()
+
+	metric := correlations.Metric(flagCorrelationsMetric)
+	if metric != correlations.MetricFloor && metric != correlations.MetricVolume && metric != correlations.MetricTopOffer {
+		log.Fatalf("❌ unknown metric %s, use floor, volume or topoffer", flagCorrelationsMetric)
+	}
+
+	allSeries, err := correlations.Load(ctx, gb, flagCorrelationsWindow)
+	if err != nil {
+		log.Fatalf("❌ loading the market samples failed: %s", err)
+	}
+
+	matrix, err := correlations.Correlate(allSeries, metric, viper.GetDuration("correlations.interval"), viper.GetInt("correlations.min_samples"))
+	if errors.Is(err, correlations.ErrNotEnoughSamples) {
+		fmt.Printf("%s (%d collections sampled)\n", err, len(allSeries))
+
+		return
+	} else if err != nil {
+		log.Fatalf("❌ %s", err)
+	}
+
+	names := make([]string, len(matrix.Series))
+	for idx, series := range matrix.Series {
+		names[idx] = correlationName(ctx, series.Address)
+	}
+
+	out := strings.Builder{}
+
+	out.WriteString(fmt.Sprintf("\n  📈 %s correlations · last %s\n\n", style.BoldAlmostWhite(string(metric)), style.BoldAlmostWhite(flagCorrelationsWindow.String())))
+
+	// header with the column numbers
+	out.WriteString(fmt.Sprintf("  %21s", ""))
+
+	for idx := range matrix.Series {
+		out.WriteString(style.GrayStyle.Render(fmt.Sprintf("%6d", idx+1)))
+	}
+
+	out.WriteString("\n")
+
+	for i, row := range matrix.Values {
+		out.WriteString(fmt.Sprintf("  %s %-18s", style.GrayStyle.Render(fmt.Sprintf("%2d", i+1)), names[i]))
+
+		for j, value := range row {
+			out.WriteString(formatCorrelation(value, i == j))
+		}
+
+		out.WriteString("\n")
+	}
+
+	if pairs := matrix.TopPairs(3); len(pairs) > 0 {
+		out.WriteString("\n  moving together\n")
+
+		for _, pair := range pairs {
+			out.WriteString(fmt.Sprintf("    %s  %s ↔ %s\n",
+				formatCorrelation(pair.Correlation, false),
+				correlationName(ctx, pair.A.Address),
+				correlationName(ctx, pair.B.Address),
+			))
+		}
+	}
+
+	fmt.Println(out.String())
+}
+
+func formatCorrelation(value float64, isSelf bool) string {
+	switch {
+	case isSelf:
+		return style.DarkGrayStyle.Render(fmt.Sprintf("%6s", "-"))
+	case math.IsNaN(value):
+		return style.DarkGrayStyle.Render(fmt.Sprintf("%6s", "·"))
+	case value >= 0.7:
+		return style.TrendGreenStyle.Render(fmt.Sprintf("%6.2f", value))
+	case value >= 0.3:
+		return style.TrendLightGreenStyle.Render(fmt.Sprintf("%6.2f", value))
+	case value <= -0.7:
+		return style.TrendRedStyle.Render(fmt.Sprintf("%6.2f", value))
+	case value <= -0.3:
+		return style.TrendLightRedStyle.Render(fmt.Sprintf("%6.2f", value))
+	default:
+		return style.GrayStyle.Render(fmt.Sprintf("%6.2f", value))
+	}
+}
+
+func correlationName(ctx context.Context, address common.Address) string {
+	name, err := gb.Rueidi.GetCachedContractName(ctx, address)
+	if err != nil || name == "" {
+		return style.ShortenAddress(address)
+	}
+
+	if len([]rune(name)) > 18 {
+		name = string([]rune(name)[:17]) + "…"
+	}
+
+	return name
+}
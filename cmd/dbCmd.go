@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/rueidica"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// stateArchiveVersion is increased on incompatible changes of the archive format.
+const stateArchiveVersion = 1
+
+// stateArchive is the exported gloomberg state: the degendb documents (labels, degens, notes, bookmarks)
+// & the persistent redis keys (pnl ledgers, heatmaps, royalty stats, positions, ...).
+type stateArchive struct {
+	Version          int       `json:"version"`
+	GloombergVersion string    `json:"gloomberg_version"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	DegenDB map[string][]json.RawMessage `json:"degendb,omitempty"`
+	Redis   []*rueidica.StateEntry       `json:"redis,omitempty"`
+}
+
+// dbCmd represents the db command.
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "export & import the gloomberg state",
+	Long: `Exports the degendb documents (labels, degens, notes & bookmarks) & the persistent redis keys (pnl ledgers,
+sales heatmaps, royalty stats, positions, ...) to a single gzipped json archive & imports it again, e.g. to
+migrate to another machine or as backup. Caches like names, floors & slugs are refetched & not exported.`,
+}
+
+// dbExportCmd represents the db export command.
+var dbExportCmd = &cobra.Command{
+	Use:     "export [file]",
+	Short:   "export the state to an archive (default: gloomberg-state-<date>.json.gz)",
+	Example: `  gloomberg db export backups/gloomberg.json.gz`,
+	Args:    cobra.MaximumNArgs(1),
+
+	Run: runDBExport,
+}
+
+// dbImportCmd represents the db import command.
+var dbImportCmd = &cobra.Command{
+	Use:     "import <file>",
+	Short:   "import an exported archive, existing entries are replaced",
+	Example: `  gloomberg db import gloomberg-state-2023-10-01.json.gz`,
+	Args:    cobra.ExactArgs(1),
+
+	Run: runDBImport,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbExportCmd)
+	dbCmd.AddCommand(dbImportCmd)
+}
+
+func runDBExport(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	fileName := fmt.Sprintf("gloomberg-state-%s.json.gz", time.Now().Format("2006-01-02"))
+	if len(args) > 0 {
+		fileName = args[0]
+	}
+
+	archive := &stateArchive{
+		Version:          stateArchiveVersion,
+		GloombergVersion: internal.GloombergVersion,
+		CreatedAt:        time.Now(),
+	}
+
+	if ddb := degendb.NewDegenDB(); ddb != nil {
+		exported, err := ddb.Export(ctx)
+		if err != nil {
+			log.Fatalf("❌ exporting degendb failed: %s", err)
+		}
+
+		archive.DegenDB = exported
+	} else {
+		log.Warn("❕ degendb (mongodb.uri) not reachable, exporting redis only")
+	}
+
+	if viper.GetBool("redis.enabled") && gb.Rueidi != nil {
+		entries, err := gb.Rueidi.ExportState(ctx)
+		if err != nil {
+			log.Fatalf("❌ exporting redis failed: %s", err)
+		}
+
+		archive.Redis = entries
+	} else {
+		log.Warn("❕ redis not enabled, exporting degendb only")
+	}
+
+	if err := writeStateArchive(fileName, archive); err != nil {
+		log.Fatalf("❌ writing %s failed: %s", fileName, err)
+	}
+
+	fmt.Printf("📦 exported %s to %s\n", formatArchiveContents(archive), style.BoldAlmostWhite(fileName))
+}
+
+func runDBImport(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	archive, err := readStateArchive(args[0])
+	if err != nil {
+		log.Fatalf("❌ reading %s failed: %s", args[0], err)
+	}
+
+	if archive.Version > stateArchiveVersion {
+		log.Fatalf("❌ archive version %d is not supported, please update gloomberg", archive.Version)
+	}
+
+	if len(archive.DegenDB) > 0 {
+		ddb := degendb.NewDegenDB()
+		if ddb == nil {
+			log.Fatal("❌ the archive contains degendb documents, please configure a reachable mongodb.uri")
+		}
+
+		imported, err := ddb.Import(ctx, archive.DegenDB)
+		if err != nil {
+			log.Fatalf("❌ importing degendb failed: %s", err)
+		}
+
+		for collName, numDocuments := range imported {
+			fmt.Printf("  %s %d\n", style.GrayStyle.Render(collName), numDocuments)
+		}
+	}
+
+	if len(archive.Redis) > 0 {
+		if !viper.GetBool("redis.enabled") || gb.Rueidi == nil {
+			log.Fatal("❌ the archive contains redis keys, please enable redis")
+		}
+
+		numImported, err := gb.Rueidi.ImportState(ctx, archive.Redis)
+		if err != nil {
+			log.Fatalf("❌ importing redis failed: %s", err)
+		}
+
+		fmt.Printf("  %s %d\n", style.GrayStyle.Render("redis keys"), numImported)
+	}
+
+	fmt.Printf("📦 imported %s from %s (exported %s)\n", formatArchiveContents(archive), style.BoldAlmostWhite(args[0]), archive.CreatedAt.Format("2006-01-02 15:04"))
+}
+
+func writeStateArchive(fileName string, archive *stateArchive) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzWriter := gzip.NewWriter(file)
+
+	if err := json.NewEncoder(gzWriter).Encode(archive); err != nil {
+		return err
+	}
+
+	return gzWriter.Close()
+}
+
+func readStateArchive(fileName string) (*stateArchive, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gzReader.Close()
+
+	archive := &stateArchive{}
+	if err := json.NewDecoder(gzReader).Decode(archive); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// formatArchiveContents returns the number of documents & keys in the archive, e.g. "12 addresses · 3 notes · 42 redis keys".
+func formatArchiveContents(archive *stateArchive) string {
+	collNames := make([]string, 0, len(archive.DegenDB))
+	for collName := range archive.DegenDB {
+		collNames = append(collNames, collName)
+	}
+
+	sort.Strings(collNames)
+
+	contents := ""
+	for _, collName := range collNames {
+		contents += fmt.Sprintf("%d %s · ", len(archive.DegenDB[collName]), collName)
+	}
+
+	return contents + fmt.Sprintf("%d redis keys", len(archive.Redis))
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/discarded"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// discardedCmd represents the discarded command.
+var discardedCmd = &cobra.Command{
+	Use:   "discarded",
+	Short: "show the recently discarded events & why they were not shown",
+	Long: `Lists the events 'gloomberg live' did not print (e.g. below min value, hidden mints/transfers, plugin vetos) with the reason.
+Useful to tune the filters with evidence. The events are kept in redis while running 'gloomberg live' (discarded.size).`,
+	Args: cobra.NoArgs,
+
+	Run: runDiscarded,
+}
+
+var (
+	flagDiscardedNum    int
+	flagDiscardedReason string
+)
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(discardedCmd)
+
+	discardedCmd.Flags().IntVarP(&flagDiscardedNum, "num", "n", 50, "number of events to show")
+	discardedCmd.Flags().StringVarP(&flagDiscardedReason, "reason", "r", "", "only show events discarded for this reason (e.g. min-value, hidden-mint)")
+}
+
+func runDiscarded(_ *cobra.Command, _ []string) {
+	events, err := discarded.Load(context.Background(), gb)
+	if err != nil {
+		log.Fatalf("❌ loading the discarded events failed: %s", err)
+	}
+
+	if len(events) == 0 {
+		fmt.Println("no discarded events recorded yet - they are recorded while running 'gloomberg live'")
+
+		return
+	}
+
+	numByReason := make(map[discarded.Reason]int)
+	shown := 0
+
+	out := strings.Builder{}
+
+	for _, event := range events {
+		numByReason[event.Reason]++
+
+		if (flagDiscardedReason != "" && string(event.Reason) != flagDiscardedReason) || shown >= flagDiscardedNum {
+			continue
+		}
+
+		shown++
+
+		fmtDetail := ""
+		if event.Detail != "" {
+			fmtDetail = style.GrayStyle.Render(" (" + event.Detail + ")")
+		}
+
+		out.WriteString(fmt.Sprintf("%s  %-16s %-10s %8.3fΞ  %s%s  %s\n",
+			style.DarkGrayStyle.Render(event.At.Local().Format("01-02 15:04:05")),
+			event.Reason,
+			event.Action,
+			event.Price,
+			style.AlmostWhiteStyle.Render(strings.Join(event.Collections, ", ")),
+			fmtDetail,
+			style.TerminalLink(utils.GetEtherscanTxURL(event.TxHash.Hex()), style.ShortenHashStyled(event.TxHash)),
+		))
+	}
+
+	// summary by reason
+	reasons := make([]discarded.Reason, 0, len(numByReason))
+	for reason := range numByReason {
+		reasons = append(reasons, reason)
+	}
+
+	sort.Slice(reasons, func(i, j int) bool { return numByReason[reasons[i]] > numByReason[reasons[j]] })
+
+	fmtReasons := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		fmtReasons = append(fmtReasons, fmt.Sprintf("%s %s", reason, style.BoldAlmostWhite(fmt.Sprint(numByReason[reason]))))
+	}
+
+	out.WriteString(fmt.Sprintf("\n🗑️ %d discarded events · %s\n", len(events), strings.Join(fmtReasons, style.GrayStyle.Render(" · "))))
+
+	fmt.Print(out.String())
+}
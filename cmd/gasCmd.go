@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gashistory"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// gasCmd represents the gas command.
+var gasCmd = &cobra.Command{
+	Use:   "gas",
+	Short: "gas price tools",
+}
+
+// gasAdviseCmd represents the gas advise command.
+var gasAdviseCmd = &cobra.Command{
+	Use:   "advise",
+	Short: "show the historically cheapest hours for non-urgent txs",
+	Long: `Calculates the gas price percentiles per hour of the day & weekday (local time) from the gas history recorded while running 'gloomberg live'.
+Useful to schedule non-urgent actions like listings, transfers or ENS renewals.`,
+	Args: cobra.NoArgs,
+
+	Run: runGasAdvise,
+}
+
+var flagGasAdviseHours int
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(gasCmd)
+	gasCmd.AddCommand(gasAdviseCmd)
+
+	gasAdviseCmd.Flags().IntVarP(&flagGasAdviseHours, "hours", "n", 5, "number of cheapest hours to show")
+}
+
+func runGasAdvise(_ *cobra.Command, _ []string) {
+	samples, err := gashistory.Load(context.Background(), gb)
+	if err != nil {
+		log.Fatalf("❌ loading the gas history failed: %s", err)
+	}
+
+	advice, err := gashistory.Advise(samples)
+	if err != nil {
+		log.Fatalf("❌ %s (%d samples)", err, len(samples))
+	}
+
+	out := strings.Builder{}
+
+	out.WriteString(fmt.Sprintf("⛽️ gas history · %s samples over %s · now %s %s\n\n",
+		style.BoldAlmostWhite(fmt.Sprint(advice.NumSamples)),
+		style.BoldAlmostWhite(fmt.Sprintf("%.1f days", advice.To.Sub(advice.From).Hours()/24)),
+		style.BoldAlmostWhite(fmt.Sprintf("%.1fgw", advice.Current)),
+		style.GrayStyle.Render(fmt.Sprintf("(p%.0f)", advice.CurrentPercentile)),
+	))
+
+	out.WriteString("  cheapest hours " + style.GrayStyle.Render("(local time · median · p25-p75)") + "\n")
+
+	for _, slot := range advice.CheapestHours(flagGasAdviseHours) {
+		out.WriteString(fmt.Sprintf("    %s  %s  %s\n",
+			style.BoldAlmostWhite(fmt.Sprintf("%02d:00", slot.Hour)),
+			style.TrendLightGreenStyle.Render(fmt.Sprintf("%6.1fgw", slot.Median)),
+			style.GrayStyle.Render(fmt.Sprintf("%.1f-%.1fgw", slot.P25, slot.P75)),
+		))
+	}
+
+	mostExpensive := advice.Hours[len(advice.Hours)-1]
+	out.WriteString(fmt.Sprintf("\n  most expensive  %s  %s\n",
+		style.BoldAlmostWhite(fmt.Sprintf("%02d:00", mostExpensive.Hour)),
+		style.TrendLightRedStyle.Render(fmt.Sprintf("%.1fgw", mostExpensive.Median)),
+	))
+
+	fmtWeekdays := make([]string, 0, len(advice.Weekdays))
+	for _, slot := range advice.Weekdays {
+		fmtWeekdays = append(fmtWeekdays, fmt.Sprintf("%s %.1fgw", slot.Weekday.String()[:3], slot.Median))
+	}
+
+	out.WriteString("  weekdays        " + strings.Join(fmtWeekdays, style.GrayStyle.Render(" · ")) + "\n")
+
+	// next cheap hour
+	cheapest := advice.Hours[0]
+	currentHour := time.Now().Truncate(time.Hour)
+	nextCheap := currentHour
+
+	for nextCheap.Hour() != cheapest.Hour {
+		nextCheap = nextCheap.Add(time.Hour)
+	}
+
+	if nextCheap.Equal(currentHour) {
+		out.WriteString(fmt.Sprintf("\n  next cheapest window: %s\n", style.BoldAlmostWhite("now")))
+	} else {
+		out.WriteString(fmt.Sprintf("\n  next cheapest window: %s %s\n", style.BoldAlmostWhite(nextCheap.Format("Mon 15:04")), style.GrayStyle.Render("(in "+time.Until(nextCheap).Round(time.Minute).String()+")")))
+	}
+
+	fmt.Println(out.String())
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/slugs"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// heatmapCmd represents the heatmap command.
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap <slug|address>",
+	Short: "show the sales of a collection by weekday & hour",
+	Long:  `Renders a heatmap of the sales of a watched collection per hour-of-day & day-of-week (local time) to find the most active hours.`,
+	Args:  cobra.ExactArgs(1),
+
+	Run: runHeatmap,
+}
+
+// shades from no to most sales.
+var heatmapShades = []lipgloss.Color{"#1c1c1c", "#264d3b", "#2e7d4f", "#4caf50", "#9ccc65", "#e6ee9c"}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(heatmapCmd)
+}
+
+func runHeatmap(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	slugs.Setup(gb)
+
+	contractAddress, err := slugs.Address(ctx, args[0])
+	if err != nil {
+		log.Fatalf("❌ could not find collection %s: %s", args[0], err)
+	}
+
+	rawHeatmap, err := gb.Rueidi.GetSalesHeatmap(ctx, contractAddress)
+	if err != nil {
+		log.Fatalf("❌ could not get heatmap for %s: %s", contractAddress.Hex(), err)
+	}
+
+	if len(rawHeatmap) == 0 {
+		fmt.Printf("no sales recorded for %s yet - heatmaps are only recorded for watched collections while running 'gloomberg live'\n", style.BoldAlmostWhite(args[0]))
+
+		return
+	}
+
+	// sales[weekday][hour]
+	sales := [7][24]int64{}
+	maxSales := int64(0)
+	totalSales := int64(0)
+
+	for field, numSales := range rawHeatmap {
+		var weekday, hour int
+		if _, err := fmt.Sscanf(field, "%d:%d", &weekday, &hour); err != nil || weekday < 0 || weekday > 6 || hour < 0 || hour > 23 {
+			continue
+		}
+
+		sales[weekday][hour] = numSales
+		maxSales = max(maxSales, numSales)
+		totalSales += numSales
+	}
+
+	out := strings.Builder{}
+
+	out.WriteString(fmt.Sprintf("\n  %s · %s sales\n\n", style.BoldAlmostWhite(args[0]), style.BoldAlmostWhite(strconv.FormatInt(totalSales, 10))))
+
+	// hour header
+	out.WriteString("      ")
+
+	for hour := 0; hour < 24; hour++ {
+		if hour%3 == 0 {
+			out.WriteString(style.DarkGrayStyle.Render(fmt.Sprintf("%-6d", hour)))
+		}
+	}
+
+	out.WriteString("\n")
+
+	// start the week on monday
+	for _, weekday := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		out.WriteString("  " + style.GrayStyle.Render(weekday.String()[:3]) + " ")
+
+		for hour := 0; hour < 24; hour++ {
+			shade := heatmapShades[0]
+
+			if numSales := sales[weekday][hour]; numSales > 0 {
+				shade = heatmapShades[1+int(float64(numSales-1)/float64(maxSales)*float64(len(heatmapShades)-1))]
+			}
+
+			out.WriteString(lipgloss.NewStyle().Foreground(shade).Render("██"))
+		}
+
+		out.WriteString("\n")
+	}
+
+	// busiest hours
+	type slot struct {
+		weekday time.Weekday
+		hour    int
+		sales   int64
+	}
+
+	slots := make([]slot, 0)
+
+	for weekday := range sales {
+		for hour, numSales := range sales[weekday] {
+			if numSales > 0 {
+				slots = append(slots, slot{weekday: time.Weekday(weekday), hour: hour, sales: numSales})
+			}
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].sales > slots[j].sales })
+
+	fmtBusiest := make([]string, 0)
+	for _, s := range slots[:min(3, len(slots))] {
+		fmtBusiest = append(fmtBusiest, fmt.Sprintf("%s %02d:00 (%d)", s.weekday.String()[:3], s.hour, s.sales))
+	}
+
+	out.WriteString("\n  busiest: " + strings.Join(fmtBusiest, ", ") + "\n")
+
+	fmt.Println(out.String())
+}
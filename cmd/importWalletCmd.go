@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/benleb/gloomberg/internal/nemo/pnl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// importWalletCmd represents the import-wallet command.
+var importWalletCmd = &cobra.Command{
+	Use:   "import-wallet <address|ens>",
+	Short: "backfill the historical nft activity of a wallet into its pnl ledger",
+	Long:  `Fetches all historical nft transfers from/to a wallet via eth_getLogs and books them into the pnl ledger (holdings & cost basis) stored in redis.`,
+	Args:  cobra.ExactArgs(1),
+
+	Run: runImportWallet,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(importWalletCmd)
+
+	importWalletCmd.Flags().Uint64("from-block", 0, "block to start the import from (ignored if the ledger already has a later block)")
+	_ = viper.BindPFlag("pnl.import.from_block", importWalletCmd.Flags().Lookup("from-block"))
+	importWalletCmd.Flags().Uint64("block-range", 100_000, "number of blocks per eth_getLogs request")
+	_ = viper.BindPFlag("pnl.import.block_range", importWalletCmd.Flags().Lookup("block-range"))
+	importWalletCmd.Flags().Bool("reset", false, "discard the existing ledger and start from scratch")
+	_ = viper.BindPFlag("pnl.import.reset", importWalletCmd.Flags().Lookup("reset"))
+}
+
+func runImportWallet(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// compatibility with old config key
+	var providerConfig interface{}
+	if cfg := viper.Get("provider"); cfg != nil {
+		providerConfig = cfg
+	} else {
+		providerConfig = viper.Get("nodes")
+	}
+
+	pool, err := provider.FromConfig(providerConfig)
+	if err != nil || pool == nil {
+		log.Fatal("❌ running provider failed, exiting")
+	}
+
+	pool.Rueidi = gb.Rueidi
+	gb.ProviderPool = pool
+
+	// address or ens name
+	walletAddress := common.HexToAddress(args[0])
+	if !common.IsHexAddress(args[0]) {
+		if walletAddress, err = pool.ResolveENS(ctx, args[0]); err != nil {
+			log.Fatalf("❌ could not resolve %s: %s", args[0], err)
+		}
+	}
+
+	var ledger *pnl.Ledger
+	if viper.GetBool("pnl.import.reset") {
+		ledger = pnl.NewLedger(walletAddress)
+	} else {
+		ledger = pnl.LoadLedger(ctx, gb.Rueidi, walletAddress)
+	}
+
+	fmtWallet := style.BoldAlmostWhite(walletAddress.Hex())
+
+	fmt.Printf("📥 importing nft history of %s | starting at block %d\n", fmtWallet, max(ledger.LastBlock, viper.GetUint64("pnl.import.from_block")))
+
+	progress := func(p pnl.ImportProgress) {
+		// persist after each chunk so an aborted import can be continued
+		if err := ledger.Save(ctx, gb.Rueidi); err != nil {
+			log.Errorf("❌ saving ledger failed: %s", err)
+		}
+
+		if p.NumTxs > 0 {
+			fmt.Printf("  blocks %d - %d / %d · %s txs\n", p.FromBlock, p.ToBlock, p.HeadBlock, style.BoldAlmostWhite(strconv.Itoa(p.NumTxs)))
+		}
+	}
+
+	if err := pnl.ImportWallet(ctx, pool, ledger, viper.GetUint64("pnl.import.from_block"), max(viper.GetUint64("pnl.import.block_range"), 1), progress); err != nil {
+		log.Errorf("❌ import of %s failed: %s", walletAddress.Hex(), err)
+	}
+
+	if err := ledger.Save(ctx, gb.Rueidi); err != nil {
+		log.Fatalf("❌ saving ledger failed: %s", err)
+	}
+
+	fmt.Printf("\n✅ imported %s txs for %s | %d holdings · cost basis %.3fΞ · realized %+.3fΞ\n",
+		style.BoldAlmostWhite(strconv.FormatUint(ledger.NumTxs, 10)),
+		fmtWallet,
+		len(ledger.Holdings),
+		ledger.CostBasis().Ether(),
+		ledger.RealizedPnL().Ether(),
+	)
+}
@@ -3,34 +3,57 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/capflows"
 	"github.com/benleb/gloomberg/internal/chawago"
-	"github.com/benleb/gloomberg/internal/config"
+	"github.com/benleb/gloomberg/internal/correlations"
+	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/degendb/degendata"
+	"github.com/benleb/gloomberg/internal/deploywatch"
+	"github.com/benleb/gloomberg/internal/editions"
+	"github.com/benleb/gloomberg/internal/freezewatch"
+	"github.com/benleb/gloomberg/internal/gashistory"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/grails"
+	"github.com/benleb/gloomberg/internal/health"
 	"github.com/benleb/gloomberg/internal/jobs"
+	"github.com/benleb/gloomberg/internal/keyboard"
+	"github.com/benleb/gloomberg/internal/mev"
+	"github.com/benleb/gloomberg/internal/mintsigs"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
-	"github.com/benleb/gloomberg/internal/nemo/provider"
 	"github.com/benleb/gloomberg/internal/nemo/token"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
 	"github.com/benleb/gloomberg/internal/nemo/wallet"
 	"github.com/benleb/gloomberg/internal/nemo/watch"
 	"github.com/benleb/gloomberg/internal/nepa"
+	"github.com/benleb/gloomberg/internal/notes"
 	"github.com/benleb/gloomberg/internal/notify"
 	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/plugins"
+	"github.com/benleb/gloomberg/internal/positions"
+	"github.com/benleb/gloomberg/internal/profittaking"
 	"github.com/benleb/gloomberg/internal/pusu"
+	"github.com/benleb/gloomberg/internal/research"
+	"github.com/benleb/gloomberg/internal/ripoff"
 	seawatcher "github.com/benleb/gloomberg/internal/seawa"
 	seawaModels "github.com/benleb/gloomberg/internal/seawa/models"
+	"github.com/benleb/gloomberg/internal/sentiment"
+	"github.com/benleb/gloomberg/internal/slugs"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/benleb/gloomberg/internal/ticker"
 	"github.com/benleb/gloomberg/internal/trapri"
-	"github.com/benleb/gloomberg/internal/utils/wwatcher"
+	"github.com/benleb/gloomberg/internal/washtrade"
+	"github.com/benleb/gloomberg/internal/watchdog"
 	"github.com/benleb/gloomberg/internal/web"
 	"github.com/benleb/gloomberg/internal/ws"
 	"github.com/charmbracelet/log"
@@ -60,6 +83,18 @@ var liveCmd = &cobra.Command{
 func runGloomberg(_ *cobra.Command, _ []string) {
 	termenv.DefaultOutput().ClearScreen()
 
+	// client mode: follow a remote gloomberg server instead of watching the chain ourselves
+	if clientURL := viper.GetString("client"); clientURL != "" {
+		if strings.HasPrefix(clientURL, "redis") {
+			viper.Set("pubsub.sales.subscribe", true)
+		} else {
+			viper.Set("websockets.client.enabled", true)
+			viper.Set("websockets.client.url", clientURL)
+		}
+
+		viper.Set("chain.enabled", false)
+	}
+
 	// print header
 	header := style.GetHeader(internal.GloombergVersion)
 	fmt.Println(header)
@@ -99,34 +134,137 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 	// 	gb.DegenDB = degendb.NewDegenDB()
 	// }()
 
-	// compatibility with old config key
-	var providerConfig interface{}
-	if cfg := viper.Get("provider"); cfg != nil {
-		providerConfig = cfg
-	} else {
-		providerConfig = viper.Get("nodes")
-	}
+	// slug ↔ address resolution (cache, collection db & external apis)
+	slugs.Setup(gb)
 
 	//
-	// init provider pool
-	if pool, err := provider.FromConfig(providerConfig); err != nil {
-		gbl.Log.Fatal("❌ running provider failed, exiting")
-	} else if pool != nil {
-		gb.ProviderPool = pool
-		gb.ProviderPool.Rueidi = gb.Rueidi
-
-		// get all node names to be shown as a list of connected nodes
-		providers := gb.ProviderPool.GetProviders()
-		nodeNames := make([]string, 0)
-		for _, n := range providers {
-			nodeNames = append(nodeNames, style.BoldStyle.Render(n.Name))
+	// initialize the independent modules concurrently & show their readiness, e.g. "nodes 3/4 · redis ✓ · stream ✓ · web ✓".
+	// failing modules are shown as such instead of blocking or killing the whole start
+	stages := gloomberg.NewStartupStages()
+
+	startupTimeout := viper.GetDuration("startup.timeout")
+	startupDeadline := time.Now().Add(startupTimeout)
+
+	nodesReady := make(chan struct{})
+	collectionsReady := make(chan struct{})
+	seawaReady := make(chan struct{})
+
+	// set before the ready channels are closed
+	var (
+		nodesErr      error
+		streamWatcher *seawatcher.SeaWatcher
+	)
+
+	stages.Run("nodes", func(stage *gloomberg.Stage) error {
+		defer close(nodesReady)
+
+		nodesErr = setupProviders(stage)
+
+		return nodesErr
+	})
+
+	// collections & wallets are resolved via the providers
+	stages.Run("collections", func(_ *gloomberg.Stage) error {
+		defer close(collectionsReady)
+
+		<-nodesReady
+
+		if nodesErr != nil {
+			return errors.New("skipped, no providers")
 		}
 
-		gloomberg.Pr(fmt.Sprintf("connected to %s providers: %s", style.AlmostWhiteStyle.Render(strconv.Itoa(len(providers))), style.AlmostWhiteStyle.Render(strings.Join(nodeNames, ", "))))
+		setupCollections()
+
+		return nil
+	})
+
+	if viper.GetBool("redis.enabled") {
+		stages.Run("redis", func(_ *gloomberg.Stage) error { return pingRedis() })
+	} else {
+		stages.Skip("redis")
+	}
+
+	if viper.GetBool("seawatcher.enabled") || viper.GetBool("listings.enabled") {
+		stages.Run("stream", func(_ *gloomberg.Stage) error {
+			func() {
+				defer close(seawaReady)
+
+				streamWatcher = seawatcher.NewSeaWatcher(seawatcher.APIKeysFromConfig(), gb)
+			}()
+
+			if streamWatcher == nil {
+				return errors.New("opensea stream not available")
+			}
+
+			// events are received via redis pubsub if not connected to the stream locally
+			if !viper.GetBool("seawatcher.local") {
+				return nil
+			}
+
+			return waitForStream(startupTimeout)
+		})
+	} else {
+		close(seawaReady)
+		stages.Skip("stream")
+	}
+
+	if viper.GetBool("web.enabled") {
+		stages.Run("web", func(_ *gloomberg.Stage) error {
+			if _, err := web.StartWebUI(gb); err != nil {
+				return err
+			}
+
+			gloomberg.PrMod("web", "web-ui started")
+
+			return nil
+		})
+	}
+
+	if viper.GetBool("web.portfolio.enabled") {
+		stages.Run("portfolio", func(_ *gloomberg.Stage) error {
+			return web.StartPortfolioServer(gb)
+		})
+	}
+
+	// user plugins to annotate or veto events
+	if viper.GetBool("plugins.enabled") {
+		stages.Run("plugins", func(_ *gloomberg.Stage) error { return plugins.Load() })
+	}
+
+	// custom log parsers for events of protocols not parsed by default
+	if viper.IsSet("parsers") {
+		stages.Run("parsers", func(_ *gloomberg.Stage) error { return totra.LoadParsers() })
+	}
+
+	// grails (specific token ids) shown & highlighted regardless of the filters
+	if viper.GetBool("grails.enabled") {
+		stages.Run("grails", func(_ *gloomberg.Stage) error { return grails.Load() })
+	}
+
+	stages.Wait(startupTimeout)
+
+	// the event handlers need the providers, collections & stream (if enabled). stages still running after
+	// the startup timeout keep initializing in the background, except the providers everything else depends on
+	if !waitReady(nodesReady, startupDeadline) || gb.ProviderPool == nil {
+		gbl.Log.Fatal("❌ setting up the providers failed, exiting")
+	}
+
+	if !waitReady(collectionsReady, startupDeadline) {
+		gbl.Log.Warnf("⌛️ collections & wallets not loaded after %s, continuing while they are loaded", startupTimeout)
+	}
+
+	var seawa *seawatcher.SeaWatcher
+	if waitReady(seawaReady, startupDeadline) {
+		seawa = streamWatcher
+	} else {
+		gbl.Log.Warnf("⌛️ opensea stream not set up after %s, continuing without", startupTimeout)
 	}
 
 	//
 	// queue for everything to print to the console
+	// reflow the output on terminal resizes
+	style.WatchTerminalSize()
+
 	terminalPrinterQueue := make(chan string, viper.GetInt("gloomberg.eventhub.inQueuesSize"))
 
 	if viper.GetBool("notifications.smart_wallets.enabled") {
@@ -134,27 +272,22 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 		go alphaTicker.AlphaCallerTicker(gb, time.NewTicker(time.Minute*1))
 	}
 
+	// canceled on ctrl+c/sigterm to unsubscribe & handle the queued events before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// nepa
-	queueWsInTokenTransactions := make(chan *totra.TokenTransaction, viper.GetInt("gloomberg.eventhub.inQueuesSize"))
 	nePa := nepa.NewNePa(gb)
-
-	//
-	var seawa *seawatcher.SeaWatcher
-	if viper.GetBool("seawatcher.enabled") || viper.GetBool("listings.enabled") {
-		var openseaAPIKey string
-		if key := viper.GetString("api_keys.opensea"); key != "" {
-			openseaAPIKey = viper.GetString("api_keys.opensea")
-		} else if key := viper.GetString("seawatcher.api_key"); key != "" {
-			openseaAPIKey = viper.GetString("seawatcher.api_key")
-		}
-		seawa = seawatcher.NewSeaWatcher(openseaAPIKey, gb)
-	}
+	nePaDone := make(chan struct{})
 
 	// trapri | ttx printer to process and format the token transactions
 	go trapri.TokenTransactionFormatter(gb, seawa)
 
 	// start subscribing
-	go nePa.Run()
+	go func() {
+		nePa.Run(ctx)
+		close(nePaDone)
+	}()
 
 	go func() {
 		if _, err := notify.GetBot(); err != nil {
@@ -173,9 +306,9 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 	// }
 
 	//
-	// websockets client
+	// websockets client to get the events from a remote gloomberg server
 	if viper.GetBool("websockets.client.enabled") {
-		ws.StartWsClient(viper.GetString("websockets.client.url"), &queueWsInTokenTransactions)
+		go ws.StartClient(gb, viper.GetString("websockets.client.url"))
 	}
 
 	// //
@@ -184,139 +317,6 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 	// 	gloomclient.ConnectToServer("ws://10.0.0.99:42068/", &queueEvents)
 	// }
 
-	//
-	// get collections from config file
-	// collectionsSpinner := style.GetSpinner("setting up collections...")
-	// _ = collectionsSpinner.Start()
-
-	// collection from config file
-	// collectionsSpinner.Message("setting up config collections...")
-
-	for _, collection := range config.GetCollectionsFromConfiguration(gb.ProviderPool, gb.Rueidi) {
-		gb.CollectionDB.RWMu.Lock()
-		gb.CollectionDB.Collections[collection.ContractAddress] = collection
-		gb.CollectionDB.RWMu.Unlock()
-
-		// // buy rules
-		// if buyRule := collection.BuyRule; buyRule != nil {
-		// 	gbl.Log.Debugf("🛍️ buy rule for %s: %v", collection.ContractAddress.Hex(), buyRule)
-		// 	gb.BuyRules.Rules[collection.ContractAddress] = buyRule
-		// } else {
-		// 	gbl.Log.Debugf("🛍️ no buy rule for %s", collection.ContractAddress.Hex())
-		// }
-	}
-
-	// //
-	// // general buy rule
-	// if buyRule := config.GetGeneralBuyRuleFromConfiguration(); buyRule != nil {
-	// 	gb.BuyRules.Rules[utils.ZeroAddress] = buyRule
-	// }
-
-	// print collections from config & wallet holdings
-	// if len(gb.CollectionDB.Collections) > 0 {
-	// 	collectionNames := gb.CollectionDB.SortedAndColoredNames()
-	// 	collectionsSpinner.StopMessage(fmt.Sprint(style.BoldStyle.Render(strconv.Itoa(len(collectionNames))), " collections from config: ", strings.Join(collectionNames, ", "), "\n"))
-	// }
-
-	gloomberg.Pr(fmt.Sprintf("%s collections loaded from config", style.AlmostWhiteStyle.Render(strconv.Itoa(len(gb.CollectionDB.Collections)))))
-
-	// // stop spinner
-	// _ = collectionsSpinner.Stop()
-
-	//
-	// get own wallets from config file
-	if viper.GetBool("sales.enabled") {
-		gb.OwnWallets = config.GetOwnWalletsFromConfig(gb.ProviderPool)
-
-		if len(*gb.OwnWallets) > 0 {
-			// miwSpinner.StopMessage(fmt.Sprint(fmt.Sprint(style.BoldStyle.Render(strconv.Itoa(len(wwatcher.MIWC.WeightedMIWs))), " MIWs loaded", "\n")))
-			// _ = miwSpinner.Stop()
-			gloomberg.PrMod("wawa", fmt.Sprintf("%s own wallets: %s", style.AlmostWhiteStyle.Render(strconv.Itoa(len(*gb.OwnWallets))), strings.Join(gb.OwnWallets.FormattedNames(), ", ")))
-		}
-	}
-
-	//
-	// initialize collections database
-	if viper.GetBool("sales.enabled") {
-		// collectionsSpinner := style.GetSpinner("setting up collections...")
-		// _ = collectionsSpinner.Start()
-
-		if len(*gb.OwnWallets) > 0 {
-			// collections from wallet holdings
-			// collectionsSpinner.Message("setting up wallet collections...")
-
-			// read collections hold in wallets from opensea and store in currentCollections
-			gbl.Log.Debugf("gb.OwnWallets: %v | gb.CollectionDB: %+v | gb.ProviderPool: %+v", gb.OwnWallets, gb.CollectionDB, gb.ProviderPool)
-			// walletCollections := opensea.GetWalletCollections(gb.OwnWallets, gb.CollectionDB, gb.Nodes)
-			walletCollections := opensea.GetWalletCollections(gb)
-
-			for _, collection := range walletCollections {
-				if gb.CollectionDB.Collections[collection.ContractAddress] == nil {
-					gb.CollectionDB.Collections[collection.ContractAddress] = collection
-				}
-			}
-
-			gbl.Log.Infof("collections from wallets: %d", len(walletCollections))
-
-			GetWalletTokens(gb)
-		}
-
-		// // print collections from config & wallet holdings
-		// if len(gb.CollectionDB.Collections) > 0 {
-		// 	collectionNames := gb.CollectionDB.SortedAndColoredNames()
-		// 	collectionsSpinner.StopMessage(fmt.Sprint(style.BoldStyle.Render(strconv.Itoa(len(collectionNames))), " collections from config & wallets: ", strings.Join(collectionNames, ", "), "\n"))
-		// }
-
-		gloomberg.Pr(fmt.Sprintf("%s collections from config & wallets: ", style.AlmostWhiteStyle.Render(strconv.Itoa(len(gb.CollectionDB.Collections)))))
-
-		// _ = collectionsSpinner.Stop()
-	}
-
-	// for _, buyRule := range gb.BuyRules.Rules {
-	// 	percentageOfFloor := fmt.Sprintf("<=%.0f%%", buyRule.Threshold*100)
-
-	// 	out := strings.Builder{}
-
-	// 	// single line
-	// 	out.WriteString(fmt.Sprintf("rule %s:", style.BoldStyle.Render(buyRule.Name)))
-
-	// 	if buyRule.MaxPrice > 0.0 {
-	// 		out.WriteString(fmt.Sprintf(" max: %sΞ", style.BoldStyle.Render(fmt.Sprintf("%4.2f", buyRule.MaxPrice))))
-	// 	}
-
-	// 	if buyRule.MaxPrice == 0.0 && buyRule.Threshold > 0.0 {
-	// 		out.WriteString(fmt.Sprintf(" | threshold: %s%% of floor", style.BoldStyle.Render(percentageOfFloor)))
-	// 	}
-
-	// 	out.WriteString(fmt.Sprintf(" | min: %ss / %sl", style.BoldStyle.Render(fmt.Sprint(buyRule.MinSales)), style.BoldStyle.Render(fmt.Sprint(buyRule.MinListings))))
-
-	// 	// print buy rules
-	// 	gbl.Log.Infof(out.String())
-	// }
-
-	//
-	// wallet watcher (todo) & MIWs
-	if viper.GetBool("sales.enabled") {
-		watcher := config.GetWatchRulesFromConfig()
-		gb.Watcher = watcher
-
-		//
-		// MIWs
-		// miwSpinner := style.GetSpinner("setting up MIWs...")
-		// _ = miwSpinner.Start()
-
-		wwatcher.LoadMIWs()
-
-		if len(wwatcher.MIWC.WeightedMIWs) > 0 {
-			// miwSpinner.StopMessage(fmt.Sprint(fmt.Sprint(style.BoldStyle.Render(strconv.Itoa(len(wwatcher.MIWC.WeightedMIWs))), " MIWs loaded", "\n")))
-			// _ = miwSpinner.Stop()
-			gloomberg.Pr(fmt.Sprintf("%s MIWs loaded", style.AlmostWhiteStyle.Render(strconv.Itoa(len(wwatcher.MIWC.WeightedMIWs)))))
-		}
-		//  else {
-		// 	_ = miwSpinner.StopFail()
-		// }
-	}
-
 	// old printer active until fully migrated
 	go func() {
 		gbl.Log.Debug("starting OLD terminal printer...")
@@ -324,12 +324,16 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 		for eventLine := range terminalPrinterQueue {
 			gbl.Log.Debugf("OLD terminal printer eventLine: %s", eventLine)
 
+			if gloomberg.OutputPaused() {
+				continue
+			}
+
 			if viper.GetBool("log.debug") {
 				debugPrefix := fmt.Sprintf("%d | ", len(terminalPrinterQueue))
 				eventLine = fmt.Sprint(debugPrefix, eventLine)
 			}
 
-			fmt.Println(eventLine)
+			fmt.Println(style.FitToTerminal(eventLine))
 		}
 	}()
 
@@ -346,9 +350,53 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 
 		// start gasline ticker
 		gasTicker = time.NewTicker(tickerInterval)
+		gloomberg.RegisterIdleTicker("gasline", gasTicker, func() time.Duration { return viper.GetDuration("ticker.gasline") })
+
 		go gloomberg.GasTicker(gb, gasTicker, gb.ProviderPool, terminalPrinterQueue)
 	}
 
+	// slow down the polling & tickers while there is no watched activity
+	go gloomberg.WatchIdle()
+
+	// floor, top offer & volume samples of the own collections for 'gloomberg correlations' & 'gloomberg offerwall'
+	if viper.GetBool("correlations.enabled") && viper.GetBool("redis.enabled") {
+		go correlations.Record(gb)
+	}
+
+	// gas history for 'gloomberg gas advise'
+	if viper.GetBool("gas.history.enabled") && gb.ProviderPool != nil {
+		go gashistory.Record(gb)
+	}
+
+	// image hashes of the established collections for the rip-off detection
+	if ripoff.Enabled() && gb.ProviderPool != nil {
+		go ripoff.LoadReferences(gb)
+	}
+
+	// notes attached to wallets & collections with 'gloomberg note add'
+	if notes.Enabled() {
+		go notes.Start()
+	}
+
+	// mints of watched contracts as soon as they hit the mempool
+	if viper.GetBool("mempool.enabled") && gb.ProviderPool != nil {
+		go chawago.WatchMempool(gb)
+	}
+
+	// most used mint functions
+	if mintsigs.Enabled() && viper.GetDuration("mintsigs.interval") > 0 {
+		go mintsigs.PrintStats()
+	}
+
+	if editions.Enabled() {
+		go editions.RunSummaries()
+	}
+
+	// simulated transfers of watched collections to detect frozen transfers
+	if viper.GetBool("freezewatch.enabled") && viper.GetDuration("freezewatch.interval") > 0 && gb.ProviderPool != nil {
+		go freezewatch.Run(gb)
+	}
+
 	// manifold ticker
 	if viper.GetBool("notifications.manifold.enabled") {
 		manifoldTicker := time.NewTicker(time.Hour * 1)
@@ -375,6 +423,16 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 		go gb.Stats.StartTicker(viper.GetDuration("ticker.statsbox"), terminalPrinterQueue)
 	}
 
+	// daily digest via telegram/discord
+	if viper.GetBool("notifications.digest.enabled") {
+		go notify.StartDailyDigest(gb)
+	}
+
+	// continuously edited telegram message with the latest events of high-volume collections
+	if notify.TapeEnabled() {
+		go notify.StartTape()
+	}
+
 	//
 	// subscribe to OpenSea API
 	if viper.GetBool("seawatcher.local") || viper.GetBool("pubsub.client.enabled") {
@@ -416,6 +474,23 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 		}()
 	}
 
+	//
+	// receive the listings & bids of the grail collections
+	if grails.Enabled() && (seawa != nil || viper.GetBool("pubsub.client.enabled")) {
+		go func() {
+			subscriptions := grails.SlugSubscriptions(context.Background())
+			if len(subscriptions) == 0 {
+				return
+			}
+
+			if seawa != nil {
+				seawa.Subscribe(subscriptions)
+			} else {
+				gb.PublishSlubSubscriptions(subscriptions)
+			}
+		}()
+	}
+
 	//
 	// degendata - ranks
 	// ❕ placed on the end to have the least interference with the calls
@@ -429,11 +504,81 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 	}()
 
 	//
-	// web ui
-	if viper.GetBool("web.enabled") {
-		go web.StartWebUI(gb) //nolint:errcheck
+	// alerts for listings & offers of own wallets live for too long without action
+	if viper.GetBool("positions.enabled") {
+		go positions.Start(gb)
+	}
 
-		gloomberg.PrMod("web", "web-ui started")
+	//
+	// watchdog to restart stalled subsystems
+	if viper.GetBool("watchdog.enabled") {
+		go watchdog.Start(gb, seawa, terminalPrinterQueue)
+	}
+
+	//
+	// research mode recording the order flow of selected collections
+	if viper.GetBool("research.enabled") {
+		var subscribe func(degendb.SlugSubscriptions) uint64
+		if seawa != nil {
+			subscribe = seawa.Subscribe
+		}
+
+		go research.Start(gb, subscribe)
+	}
+
+	//
+	// degendata - address labels
+	// used to label mev bots, detect exchange deposits of (own/watched) sellers, capital flows of the own wallets
+	// & sales between wallets funded by each other
+	if viper.GetBool("mev.enabled") || viper.GetBool("profittaking.enabled") || viper.GetBool("capflows.enabled") || viper.GetBool("washtrading.enabled") {
+		go func() {
+			// labels are optional for the mev labeling (known bots can be configured via mev.bots)
+			labels, err := degendata.LoadAddressLabels()
+			if err != nil {
+				gbl.Log.Debugf("error loading address labels: %v", err)
+			}
+
+			if viper.GetBool("mev.enabled") {
+				mev.LoadBots(labels)
+			}
+
+			if viper.GetBool("profittaking.enabled") {
+				profittaking.Start(gb, labels)
+			}
+
+			if viper.GetBool("capflows.enabled") {
+				capflows.Start(gb, labels)
+			}
+
+			if viper.GetBool("washtrading.enabled") {
+				washtrade.LoadFundings(labels)
+			}
+		}()
+	}
+
+	//
+	// new erc721/erc1155 contracts deployed on mainnet
+	if deploywatch.Enabled() {
+		go deploywatch.Start(gb)
+	}
+
+	//
+	// social mentions of watched collections
+	if viper.GetBool("sentiment.enabled") {
+		go sentiment.Start(gb.CollectionDB)
+	}
+
+	//
+	// keybindings to adjust the filters while running
+	if viper.GetBool("ui.keybindings.enabled") && !viper.GetBool("ui.headless") {
+		go gloomberg.ListenForKeys()
+	}
+
+	//
+	// prometheus metrics & dependency health gauges
+	if viper.GetBool("metrics.enabled") {
+		go health.StartMetricsServer()
+		go health.StartChecks(gb.Rdb, gb.ProviderPool)
 	}
 
 	go func() {
@@ -443,8 +588,25 @@ func runGloomberg(_ *cobra.Command, _ []string) {
 		gloomberg.Prf("wallet watcher started: %+v", wawa)
 	}()
 
-	// loop forever
-	select {}
+	// run until ctrl+c/sigterm
+	<-ctx.Done()
+
+	// restore echo & line buffering of the terminal before draining, a second ctrl+c exits immediately
+	keyboard.Restore()
+
+	// a second ctrl+c exits immediately
+	stop()
+
+	shutdownTimeout := viper.GetDuration("chain.shutdown_timeout")
+	gbl.Log.Infof("👋 shutting down, handling the queued events (max. %s)...", shutdownTimeout)
+
+	select {
+	case <-nePaDone:
+	case <-time.After(shutdownTimeout):
+		gbl.Log.Warnf("👋 handling the queued events took longer than %s, exiting anyway", shutdownTimeout)
+	}
+
+	GracefulShutdown()
 }
 
 var degendataPath string
@@ -467,6 +629,31 @@ func init() { //nolint:gochecknoinits
 	liveCmd.Flags().Uint16("websockets-port", 42068, "websockets server port")
 	_ = viper.BindPFlag("websockets.server.port", liveCmd.Flags().Lookup("websockets-port"))
 
+	// client mode
+	liveCmd.Flags().String("client", "", "follow a remote gloomberg server instead of the chain (wss://<host>:<web-ui-port>/feed or \"redis\" for the redis pubsub)")
+	_ = viper.BindPFlag("client", liveCmd.Flags().Lookup("client"))
+	viper.SetDefault("chain.enabled", true)
+	// fetch the logs missed while a node subscription was down via eth_getLogs (at most max_blocks)
+	viper.SetDefault("chain.backfill.enabled", true)
+	viper.SetDefault("chain.backfill.max_blocks", 100)
+	// number of blocks to wait before processing logs, reorged events are annotated either way
+	viper.SetDefault("chain.confirmations", 0)
+	// processed tx hashes are remembered to skip the further logs of a tx (bounded, least recently seen are evicted first)
+	viper.SetDefault("chain.dedup.size", 100_000)
+	viper.SetDefault("chain.dedup.ttl", time.Hour)
+	// re-subscribe via a node if it delivered no logs for this long while other nodes still do (0 to disable)
+	viper.SetDefault("chain.stall_timeout", time.Minute*3)
+	// max. time to handle the queued events on ctrl+c/sigterm before exiting
+	viper.SetDefault("chain.shutdown_timeout", time.Second*10)
+	// rpc calls prefer nodes with low latency, error rate & blocks behind, nodes failing quarantine_after
+	// consecutive calls are skipped for backoff (doubling up to max_backoff). interval is the block number probe
+	viper.SetDefault("chain.health.interval", time.Second*30)
+	viper.SetDefault("chain.health.quarantine_after", 3)
+	viper.SetDefault("chain.health.backoff", time.Second*5)
+	viper.SetDefault("chain.health.max_backoff", time.Minute*5)
+	viper.SetDefault("websockets.client.insecure", false)
+	viper.SetDefault("websockets.client.max_backoff", time.Minute)
+
 	// metrics/prometheus
 	liveCmd.Flags().Bool("metrics", false, "enable metrics server")
 	_ = viper.BindPFlag("metrics.enabled", liveCmd.Flags().Lookup("metrics"))
@@ -474,6 +661,8 @@ func init() { //nolint:gochecknoinits
 	_ = viper.BindPFlag("metrics.host", liveCmd.Flags().Lookup("metrics-host"))
 	liveCmd.Flags().Uint16("metrics-port", 9090, "metrics server port")
 	_ = viper.BindPFlag("metrics.port", liveCmd.Flags().Lookup("metrics-port"))
+	viper.SetDefault("metrics.health_interval", time.Second*17)
+	viper.SetDefault("metrics.health_timeout", time.Second*5)
 
 	// notifications
 	liveCmd.Flags().Bool("telegram", false, "send telegram notifications")
@@ -485,6 +674,9 @@ func init() { //nolint:gochecknoinits
 	// no ui
 	liveCmd.Flags().Bool("headless", false, "run without terminal output")
 	_ = viper.BindPFlag("ui.headless", liveCmd.Flags().Lookup("headless"))
+	liveCmd.Flags().Bool("keys", keyboard.IsTerminal(), "enable keybindings (m: mints, t: transfers, +/-: min value, p: pause, c: clear)")
+	_ = viper.BindPFlag("ui.keybindings.enabled", liveCmd.Flags().Lookup("keys"))
+	viper.SetDefault("ui.keybindings.min_value_step", 0.05)
 
 	// web ui
 	liveCmd.Flags().Bool("web-ui", false, "enable web ui")
@@ -493,6 +685,9 @@ func init() { //nolint:gochecknoinits
 	_ = viper.BindPFlag("web.host", liveCmd.Flags().Lookup("web-ui-host"))
 	liveCmd.Flags().Uint16("web-ui-port", 42069, "web ui port")
 	_ = viper.BindPFlag("web.port", liveCmd.Flags().Lookup("web-ui-port"))
+	// read-only portfolio pages of the wallet groups, served on a separate port & gated by a token derived from the secret
+	viper.SetDefault("web.portfolio.enabled", false)
+	viper.SetDefault("web.portfolio.port", 42070)
 
 	// wallets
 	liveCmd.Flags().StringSliceVarP(&ownWallets, "wallets", "w", []string{}, "Own wallet addresses")
@@ -518,6 +713,23 @@ func init() { //nolint:gochecknoinits
 	liveCmd.Flags().Bool("show-unknown", false, "Show unknown")
 	_ = viper.BindPFlag("show.unknown", liveCmd.Flags().Lookup("show-unknown"))
 
+	// gas used, effective gas price & total tx cost of sales & mints (verbose mode only)
+	viper.SetDefault("show.gas_used", true)
+	// seller proceeds, royalties & marketplace fees of seaport sales as extra line (verbose mode only)
+	viper.SetDefault("show.fee_breakdown", false)
+	// seaport order parameters (zone, conduit, counter, salt) of listings & offers (verbose mode only)
+	liveCmd.Flags().Bool("show-order-data", false, "show the seaport order parameters of listings & offers (verbose mode only)")
+	_ = viper.BindPFlag("show.order_data", liveCmd.Flags().Lookup("show-order-data"))
+
+	// max time to wait for the modules initialized in parallel before showing their readiness
+	viper.SetDefault("startup.timeout", time.Second*20)
+
+	// decode calls/events of unknown protocols via verified abis from etherscan
+	liveCmd.Flags().Bool("decode-calls", false, "decode calls to watched & unknown contracts (requires etherscan api key)")
+	_ = viper.BindPFlag("abireg.enabled", liveCmd.Flags().Lookup("decode-calls"))
+	// abis that could not be fetched (rate limit, invalid key, ...) are retried after retry_after, unverified ones never
+	viper.SetDefault("abireg.retry_after", time.Minute*10)
+
 	// degendb
 	liveCmd.Flags().StringVar(&degendataPath, "degendata", "degendata", "path to degendata repo")
 	_ = viper.BindPFlag("degendata.path", liveCmd.Flags().Lookup("degendata"))
@@ -525,10 +737,22 @@ func init() { //nolint:gochecknoinits
 	// worker settings
 	viper.SetDefault("trapri.numOpenSeaEventhandlers", 3)
 
+	// purchases of at least min_items items across collections via aggregators (blur, gem, reservoir, ...)
+	// are shown as a single sweep line instead of a line per collection
+	viper.SetDefault("sweeps.enabled", true)
+	viper.SetDefault("sweeps.min_items", 5)
+
+	// show each maker's collection offer at most once per window unless the price improves (0 to disable)
+	viper.SetDefault("trapri.offer_dedup_window", time.Minute*15)
+
 	// eventhub
 	viper.SetDefault("gloomberg.terminalPrinter.numWorker", 1)
 	viper.SetDefault("gloomberg.eventhub.numHandler", 3)
 	viper.SetDefault("gloomberg.eventhub.inQueuesSize", 512)
+
+	// drop general sales if this many are waiting to be printed & summarize them every interval
+	viper.SetDefault("output.shed_queue_size", 256)
+	viper.SetDefault("output.summary_interval", time.Second*10)
 	viper.SetDefault("gloomberg.eventhub.outQueuesSize", 32)
 
 	// first txs
@@ -555,6 +779,247 @@ func init() { //nolint:gochecknoinits
 
 	// opensea settings
 	viper.SetDefault("seawatcher.auto_subscribe_after_sales", 37)
+	// switch to the next api key from seawatcher.api_keys after too many disconnects
+	viper.SetDefault("seawatcher.key_rotation.max_disconnects", 5)
+	viper.SetDefault("seawatcher.key_rotation.window", time.Minute*10)
+	// subscribe to the sales of own collections via the stream too (merged with the sales from the chain)
+	viper.SetDefault("seawatcher.sales", false)
+
+	// merge the same sale received from the chain & the stream into one event. stream sales wait for the window
+	// for the chain event & are printed on their own if it doesn't arrive, chain sales are remembered for the ttl
+	viper.SetDefault("dedupe.cross_source.enabled", true)
+	viper.SetDefault("dedupe.cross_source.window", time.Second*15)
+	viper.SetDefault("dedupe.cross_source.ttl", time.Minute*10)
+
+	// floor estimation
+	viper.SetDefault("floor.listing_ttl", time.Hour*24)
+	// max relative gap between the lowest listings to still trust the lowest one
+	viper.SetDefault("floor.max_listing_gap", 0.2)
+	// max relative deviation of the floor from the recent sales average
+	viper.SetDefault("floor.max_sales_deviation", 0.5)
+
+	// alert on listings of own collections below floor * max_floor_ratio
+	viper.SetDefault("deals.enabled", false)
+	viper.SetDefault("deals.max_floor_ratio", 0.8)
+	viper.SetDefault("deals.low_confidence", false)
+
+	// hold back listings until no relist of the token happened for this duration & collapse
+	// rapid list/cancel/list cycles into a single "relisted" line (0 = disabled)
+	viper.SetDefault("listings.relist_settle", time.Duration(0))
+
+	// book own txs to the pnl ledgers & show the acquisition context on own sales
+	viper.SetDefault("pnl.live.enabled", true)
+
+	// record sales per weekday/hour of watched collections (gloomberg heatmap <slug>)
+	viper.SetDefault("heatmap.enabled", true)
+
+	// floor, top collection offer & sales volume of the own collections sampled every interval for
+	// 'gloomberg correlations' & 'gloomberg offerwall'. correlated
+	// pairs need at least min_samples intervals in common
+	viper.SetDefault("correlations.enabled", true)
+	viper.SetDefault("correlations.interval", time.Hour)
+	viper.SetDefault("correlations.size", 24*30)
+	viper.SetDefault("correlations.min_samples", 12)
+
+	// record the gas price for 'gloomberg gas advise' (ring buffer of 14 days at 1m interval)
+	viper.SetDefault("gas.history.enabled", true)
+	viper.SetDefault("gas.history.interval", time.Minute)
+	viper.SetDefault("gas.history.size", 14*24*60)
+
+	// restart stalled subsystems
+	viper.SetDefault("watchdog.enabled", true)
+	viper.SetDefault("watchdog.interval", time.Minute)
+	viper.SetDefault("watchdog.cooldown", time.Minute*5)
+	viper.SetDefault("watchdog.chain_timeout", time.Minute*3)
+	viper.SetDefault("watchdog.stream_timeout", time.Minute*10)
+
+	// marketplace the token ids & notifications link to, further marketplaces are shown as extra links
+	viper.SetDefault("links.marketplace", "opensea")
+	viper.SetDefault("links.marketplaces", []string{"opensea", "blur"})
+
+	// show payments in usdc/ape/... in their original denomination next to the eth equivalent
+	viper.SetDefault("currencies.show_original", true)
+	viper.SetDefault("currencies.rate_ttl", time.Minute*5)
+	// failed fetches are not retried for failure_ttl, e.g. for currencies without chainlink feed
+	viper.SetDefault("currencies.failure_ttl", time.Minute*1)
+	// price oracles used to convert usdc/dai/ape/blur payments to eth, the first returning a rate wins
+	viper.SetDefault("currencies.oracles", []string{"static", "chainlink", "coingecko"})
+
+	// suppress zero-value transfers & lookalike addresses targeting own wallets
+	viper.SetDefault("scamfilter.enabled", true)
+	// number of leading/trailing hex chars to consider an address a lookalike (1-20)
+	viper.SetDefault("scamfilter.lookalike_chars", 4)
+
+	// proxy upgrade alerts
+	viper.SetDefault("proxywatch.enabled", true)
+	viper.SetDefault("proxywatch.telegram_chat_id", 0)
+
+	// alerts when blend loans with collateral from own collections are auctioned or seized
+	viper.SetDefault("blendwatch.enabled", true)
+	viper.SetDefault("blendwatch.telegram_chat_id", 0)
+	// window to count the auctions per collection in (liquidation waves)
+	viper.SetDefault("blendwatch.wave_window", time.Hour)
+
+	// alerts when watched collections pause or block transfers, checked via Paused events & simulated transfers
+	viper.SetDefault("freezewatch.enabled", true)
+	viper.SetDefault("freezewatch.telegram_chat_id", 0)
+	viper.SetDefault("freezewatch.interval", time.Minute*15)
+
+	// show new erc721/erc1155 contracts (erc-165 probing of contract creation txs) with their name & symbol
+	viper.SetDefault("deploywatch.enabled", false)
+
+	// detect primary ens name changes of own & watched wallets & update the cached names
+	viper.SetDefault("enswatch.enabled", true)
+
+	// mention counts of watched collections on twitter or farcaster
+	viper.SetDefault("sentiment.enabled", false)
+	viper.SetDefault("sentiment.source", "")
+	viper.SetDefault("sentiment.interval", time.Minute*15)
+	viper.SetDefault("sentiment.window", time.Hour*6)
+	viper.SetDefault("sentiment.farcaster.search_url", "https://api.neynar.com/v2/farcaster/cast/search")
+
+	// default language of the telegram notifications (en, de), can be set per watch group & chat
+	viper.SetDefault("notifications.language", "en")
+
+	// additional notification channels, every channel can have its own go template (notifications.<channel>.template)
+	viper.SetDefault("notifications.discord.enabled", false)
+	viper.SetDefault("notifications.webhook.enabled", false)
+
+	// daily session summary via telegram/discord, optionally with the stats box rendered as png
+	viper.SetDefault("notifications.digest.enabled", false)
+	viper.SetDefault("notifications.digest.time", "22:00")
+	viper.SetDefault("notifications.digest.image", true)
+
+	// pause the calls to failing/rate-limiting apis (opensea) after threshold consecutive failures,
+	// use cached data only & probe for recovery every probe_interval
+	viper.SetDefault("circuit_breaker.enabled", true)
+	viper.SetDefault("circuit_breaker.threshold", 5)
+	viper.SetDefault("circuit_breaker.probe_interval", time.Minute)
+
+	// telegram "live tape": one continuously edited message per collection with the latest events
+	viper.SetDefault("notifications.telegram.tape.enabled", false)
+	viper.SetDefault("notifications.telegram.tape.chat_id", 0)
+	viper.SetDefault("notifications.telegram.tape.collections", []string{})
+	viper.SetDefault("notifications.telegram.tape.actions", []string{"Sale", "Purchase", "Mint"})
+	viper.SetDefault("notifications.telegram.tape.size", 10)
+	viper.SetDefault("notifications.telegram.tape.interval", time.Second*5)
+	viper.SetDefault("notifications.telegram.tape.rotate", time.Hour*6)
+
+	// persist the order flow of research.slugs to parquet files
+	viper.SetDefault("research.enabled", false)
+	viper.SetDefault("research.directory", "research")
+	viper.SetDefault("research.rotate_interval", time.Hour)
+
+	// scripts (exec or wasi) to annotate or veto events before they are printed & notified
+	viper.SetDefault("plugins.enabled", false)
+	viper.SetDefault("plugins.timeout", time.Millisecond*500)
+
+	// public merkle proof apis used by the allowlist checker ({root}, {address} & {contract} are replaced)
+	viper.SetDefault("allowlist.proof_apis", []string{"https://lanyard.org/api/v1/proof?root={root}&unhashedLeaf={address}"})
+
+	// truncate output lines to the terminal width instead of letting them wrap
+	viper.SetDefault("terminal.truncate", true)
+
+	// label known mev bots (mev.bots & degendata labels) & same-block flips, their share of the volume is shown in the stats
+	viper.SetDefault("mev.enabled", true)
+	viper.SetDefault("mev.bots", []string{})
+
+	// track mints, burns & redeems of manifold/zora editions of own collections & summarize ended editions
+	viper.SetDefault("editions.enabled", true)
+	viper.SetDefault("editions.telegram_chat_id", 0)
+
+	// collect the function signatures used by mints, label mint lines & print the most used ones every interval
+	viper.SetDefault("mintsigs.enabled", true)
+	viper.SetDefault("mintsigs.interval", time.Minute*30)
+	viper.SetDefault("mintsigs.top", 5)
+	// hint at copies if at most this many other contracts use exactly the same mint functions
+	viper.SetDefault("mintsigs.copy_max_contracts", 2)
+	// alert trending mints (min_mints within the window) with a "cast send" template of their calldata, once per interval
+	viper.SetDefault("mintsigs.templates.enabled", true)
+	viper.SetDefault("mintsigs.templates.min_mints", 10)
+	viper.SetDefault("mintsigs.templates.window", time.Minute*2)
+	viper.SetDefault("mintsigs.templates.interval", time.Minute*15)
+
+	// watch pending txs for mints of own collections & mempool.contracts (needs a node with full pending tx subscriptions)
+	viper.SetDefault("mempool.enabled", false)
+	viper.SetDefault("mempool.contracts", []string{})
+	// additional mint function selectors, e.g. "0xa0712d68" for mint(uint256)
+	viper.SetDefault("mempool.selectors", []string{})
+	// show mints of all contracts, not only the watched ones
+	viper.SetDefault("mempool.all_contracts", false)
+
+	// keep the last discarded (not printed) events with their reason for 'gloomberg discarded' & /discarded (web ui)
+	viper.SetDefault("discarded.enabled", true)
+	viper.SetDefault("discarded.size", 500)
+
+	// notes attached to wallets & collections (stored in degendb/mongodb), shown as tooltip in the web ui
+	// & appended to the terminal lines in verbose mode if notes.terminal is set
+	viper.SetDefault("notes.enabled", true)
+	viper.SetDefault("notes.terminal", true)
+	viper.SetDefault("notes.refresh", time.Minute*5)
+
+	// bookmark printed events with 'b' or the 'bookmark <tx hash> [note]' web command (stored in degendb/mongodb),
+	// the last bookmarks.recent printed events are kept to save their details with the bookmark
+	viper.SetDefault("bookmarks.enabled", true)
+	viper.SetDefault("bookmarks.recent", 100)
+
+	// specific token ids (grails.tokens) whose listings, bids & sales are always shown, highlighted & sent
+	// to the telegram chat (grails.notify), the listings & bids of their collections are subscribed
+	viper.SetDefault("grails.enabled", true)
+	viper.SetDefault("grails.notify", true)
+
+	// track the listings & offers of own wallets & alert if they are live for positions.stale_after without being
+	// filled or replaced, with a reprice hint based on the floor & salira (repeated every stale_after)
+	viper.SetDefault("positions.enabled", true)
+	viper.SetDefault("positions.stale_after", time.Hour*24*3)
+	viper.SetDefault("positions.check_interval", time.Hour)
+	viper.SetDefault("positions.notify", true)
+	// undercut the floor by this ratio if there are more listings than sales
+	viper.SetDefault("positions.undercut", 0.01)
+	viper.SetDefault("positions.offer_floor_ratio", 0.9)
+	// alert before the best offers received for own tokens & the own listings/offers expire (0 to disable)
+	viper.SetDefault("positions.expiry_alert", time.Minute*15)
+
+	// show the net proceeds of accepting collection offers (after creator royalty & marketplace fee) for collections
+	// held by own wallets. royalties are fetched from opensea & cached, proceeds.royalties overrides them (in bps)
+	viper.SetDefault("proceeds.enabled", true)
+	viper.SetDefault("proceeds.marketplace_fee_bps", 250)
+	viper.SetDefault("proceeds.royalties", map[string]int{})
+
+	// check the royalties paid by seaport sales of watched collections against their erc2981 royaltyInfo &
+	// count the results per collection (shown via 'gloomberg royalties', redis required for the stats)
+	viper.SetDefault("royalties.enabled", false)
+
+	// compare the token images of new collections with the configured collections (& ripoff.references)
+	// via perceptual hashes & mark near-identical ones as likely derivatives
+	viper.SetDefault("ripoff.enabled", false)
+	viper.SetDefault("ripoff.references", []string{})
+	viper.SetDefault("ripoff.samples", 3)
+	viper.SetDefault("ripoff.max_distance", 6)
+
+	// flag sales between the same wallet, wallets funded by each other (funded_by of the degendata address labels)
+	// & tokens sold back to their previous seller within the window. flagged sales are not counted in the volume stats
+	viper.SetDefault("washtrading.enabled", true)
+	viper.SetDefault("washtrading.window", time.Hour*24*7)
+
+	// flag tokens sold several times within a few blocks at prices differing by more than the given factor
+	viper.SetDefault("anomalies.enabled", true)
+	viper.SetDefault("anomalies.blocks", 5)
+	viper.SetDefault("anomalies.price_ratio", 5.0)
+
+	// tag own/watched sellers as "taking profit" when the proceeds are moved to an exchange within the window
+	viper.SetDefault("profittaking.enabled", false)
+	viper.SetDefault("profittaking.window", time.Hour*6)
+	viper.SetDefault("profittaking.tag_duration", time.Hour*24)
+
+	// wallet age & tx count of buyers/sellers (first tx via etherscan, api_keys.etherscan required)
+	viper.SetDefault("walletage.enabled", false)
+	viper.SetDefault("walletage.fresh", time.Hour*24*7)
+	viper.SetDefault("walletage.timeout", time.Second*3)
+
+	// eth/weth deposits & withdrawals between own wallets & exchanges (degendata labels & capflows.exchanges)
+	viper.SetDefault("capflows.enabled", false)
+	viper.SetDefault("capflows.exchanges", map[string]string{})
 
 	//
 	// timeframes
@@ -573,11 +1038,20 @@ func init() { //nolint:gochecknoinits
 	viper.SetDefault("ticker.statsbox", internal.BlockTime*9)
 	viper.SetDefault("ticker.gasline", internal.BlockTime*3)
 
+	// without own or watched events for idle.after, the stats box (incl. balances), gasline & gas history
+	// intervals are multiplied by idle.slowdown. the first new event restores them (opt-in, 0 disables)
+	viper.SetDefault("idle.after", time.Duration(0))
+	viper.SetDefault("idle.slowdown", 4.0)
+
 	// stats settings
 	viper.SetDefault("stats.enabled", true)
 	viper.SetDefault("stats.balances", true)
+	viper.SetDefault("stats.balances_concurrency", 3)
+	viper.SetDefault("stats.balances_stale_after", time.Minute*5)
 	viper.SetDefault("stats.timeframe", time.Minute*13) // 13
 	viper.SetDefault("stats.lines", 6)
+	// own wallet groups (wallets.group) shown only as aggregated balance in the stats box
+	viper.SetDefault("stats.wallet_groups.collapsed", []string{})
 	// high volume mints detection
 	viper.SetDefault("stats.high_volume.check_interval", time.Second*17)
 	viper.SetDefault("stats.high_volume.min_checks_below_threshold", 2)
@@ -0,0 +1,120 @@
+package mintcmd
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/allowlist"
+	"github.com/benleb/gloomberg/internal/config"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	flagAllowlistContract string
+	flagAllowlistWallets  []string
+	flagAllowlistStart    string
+)
+
+// allowlistCmd represents the allowlist command.
+var allowlistCmd = &cobra.Command{
+	Use:   "allowlist",
+	Short: "Check if own wallets are on the allowlist of an upcoming mint.",
+	Run:   checkAllowlist,
+}
+
+func init() {
+	MintCmd.AddCommand(allowlistCmd)
+
+	allowlistCmd.Flags().StringVar(&flagAllowlistContract, "contract", "", "contract of the upcoming mint")
+	_ = allowlistCmd.MarkFlagRequired("contract")
+
+	allowlistCmd.Flags().StringSliceVarP(&flagAllowlistWallets, "wallets", "w", make([]string, 0), "wallets to check (default: own wallets from config)")
+	allowlistCmd.Flags().StringVar(&flagAllowlistStart, "start", "", "mint start (RFC3339), shows a countdown with the eligibility until the mint starts")
+}
+
+func checkAllowlist(_ *cobra.Command, _ []string) {
+	if !common.IsHexAddress(flagAllowlistContract) {
+		log.Fatalf("❌ invalid contract address: %s", flagAllowlistContract)
+	}
+
+	contract := common.HexToAddress(flagAllowlistContract)
+
+	// wallets to check
+	wallets := make([]common.Address, 0)
+	walletNames := make(map[common.Address]string)
+
+	for _, address := range flagAllowlistWallets {
+		if common.IsHexAddress(address) {
+			wallets = append(wallets, common.HexToAddress(address))
+		}
+	}
+
+	if len(wallets) == 0 {
+		if ownWallets := config.GetOwnWalletsFromConfig(nil); ownWallets != nil {
+			for _, w := range *ownWallets {
+				wallets = append(wallets, w.Address)
+				walletNames[w.Address] = w.Name
+			}
+		}
+	}
+
+	if len(wallets) == 0 {
+		log.Fatal("❌ no wallets to check")
+	}
+
+	walletName := func(address common.Address) string {
+		if name, ok := walletNames[address]; ok {
+			return style.Bold(name)
+		}
+
+		return style.Bold(style.ShortenAdressPTR(&address))
+	}
+
+	// rpc endpoint
+	rpcs := viper.GetStringSlice("mint.rpcs")
+	if len(rpcs) == 0 {
+		rpcs = viper.GetStringSlice("endpoints")
+	}
+
+	if len(rpcs) == 0 {
+		log.Fatal("❌ no rpc endpoints configured (mint.rpcs or endpoints)")
+	}
+
+	rpcClient, err := ethclient.Dial(rpcs[rand.Intn(len(rpcs))]) //nolint:gosec
+	if err != nil {
+		log.Fatalf("❌ failed to connect to rpc endpoint: %v", err)
+	}
+
+	check := func() string {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		return allowlist.Summary(allowlist.Check(ctx, rpcClient, contract, wallets), walletName)
+	}
+
+	if flagAllowlistStart == "" {
+		log.Printf("  🎟️  %s", check())
+
+		return
+	}
+
+	startDate, err := time.Parse(time.RFC3339, flagAllowlistStart)
+	if err != nil {
+		log.Fatalf("❌ invalid start date: %v", err)
+	}
+
+	// countdown with the (possibly changing) eligibility until the mint starts
+	ticker := time.NewTicker(time.Minute)
+
+	for ; time.Now().Before(startDate); <-ticker.C {
+		log.Printf(" 💤  mint start in %s | 🎟️  %s", style.BoldAlmostWhite(time.Until(startDate).Truncate(time.Second).String()), check())
+	}
+
+	log.Printf(" 🚀  mint started | 🎟️  %s", check())
+}
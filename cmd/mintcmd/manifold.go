@@ -23,6 +23,7 @@ import (
 
 	"github.com/benleb/gloomberg/internal"
 	manifoldABIs "github.com/benleb/gloomberg/internal/abis/manifold"
+	"github.com/benleb/gloomberg/internal/allowlist"
 	"github.com/benleb/gloomberg/internal/nemo/manifold"
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/style"
@@ -423,10 +424,32 @@ func mintManifold(_ *cobra.Command, _ []string) {
 		}
 	}
 
+	// eligibility of the mint wallets from the manifold merkle proofs
+	fmtEligibility := "public mint"
+
+	if !isPublicMint {
+		eligibilities := make([]*allowlist.Eligibility, 0)
+		walletTags := make(map[common.Address]string)
+
+		for _, mintWallet := range mintWallets.ToSlice() {
+			walletTags[*mintWallet.address] = mintWallet.tag
+
+			eligibilities = append(eligibilities, &allowlist.Eligibility{
+				Wallet:   *mintWallet.address,
+				Eligible: len(mintWallet.merkleProofs) > 0,
+				Amount:   uint64(len(mintWallet.mintIndices)),
+				Method:   "manifold",
+			})
+		}
+
+		fmtEligibility = allowlist.Summary(eligibilities, func(address common.Address) string { return walletTags[address] })
+	}
+
 	if startDate.After(time.Now()) && waitForStart {
 		log.Print("")
 		log.Print("")
 		log.Printf(" 💤 💤 💤  waiting for mint start in %s  💤 💤 💤", style.BoldAlmostWhite(time.Until(startDate).Truncate(time.Second).String()))
+		log.Printf("        🎟️  %s", fmtEligibility)
 		log.Print("")
 		log.Printf(style.GrayStyle.Render("    (use --no-wait to skip waiting)"))
 		log.Print("")
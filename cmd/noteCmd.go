@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// noteCmd represents the note command.
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "attach notes to wallets & collections",
+	Long: `Notes are stored in degendb (mongodb.uri) and shown as tooltip in the web ui & appended
+to the terminal lines of events involving the wallet/collection in verbose mode.`,
+}
+
+// noteAddCmd represents the note add command.
+var noteAddCmd = &cobra.Command{
+	Use:     "add <address> <note>",
+	Short:   "attach a note to a wallet or collection (replaces an existing note)",
+	Example: `  gloomberg note add 0xabc... "npc whale, fades every pump"`,
+	Args:    cobra.MinimumNArgs(2),
+
+	Run: runNoteAdd,
+}
+
+// noteRemoveCmd represents the note rm command.
+var noteRemoveCmd = &cobra.Command{
+	Use:   "rm <address>",
+	Short: "remove the note of a wallet or collection",
+	Args:  cobra.ExactArgs(1),
+
+	Run: runNoteRemove,
+}
+
+// noteListCmd represents the note list command.
+var noteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list all notes",
+	Args:  cobra.NoArgs,
+
+	Run: runNoteList,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(noteCmd)
+	noteCmd.AddCommand(noteAddCmd)
+	noteCmd.AddCommand(noteRemoveCmd)
+	noteCmd.AddCommand(noteListCmd)
+}
+
+func runNoteAdd(_ *cobra.Command, args []string) {
+	address := parseNoteAddress(args[0])
+	text := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	if err := noteDegenDB().SetNote(context.Background(), address, text); err != nil {
+		log.Fatalf("❌ saving the note failed: %s", err)
+	}
+
+	fmt.Printf("📝 %s · %s\n", style.BoldAlmostWhite(address.Hex()), text)
+}
+
+func runNoteRemove(_ *cobra.Command, args []string) {
+	address := parseNoteAddress(args[0])
+
+	if err := noteDegenDB().DeleteNote(context.Background(), address); err != nil {
+		log.Fatalf("❌ removing the note of %s failed: %s", address.Hex(), err)
+	}
+
+	fmt.Printf("🗑️ note of %s removed\n", style.BoldAlmostWhite(address.Hex()))
+}
+
+func runNoteList(_ *cobra.Command, _ []string) {
+	notes, err := noteDegenDB().Notes(context.Background())
+	if err != nil {
+		log.Fatalf("❌ loading the notes failed: %s", err)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("no notes yet - add one with 'gloomberg note add <address> <note>'")
+
+		return
+	}
+
+	sort.Slice(notes, func(i, j int) bool { return notes[i].UpdatedAt.After(notes[j].UpdatedAt) })
+
+	for _, note := range notes {
+		fmt.Printf("%s  %s  %s\n",
+			style.DarkGrayStyle.Render(note.UpdatedAt.Local().Format("2006-01-02")),
+			style.AlmostWhiteStyle.Render(note.HexAddress),
+			note.Text,
+		)
+	}
+}
+
+func parseNoteAddress(rawAddress string) common.Address {
+	if !common.IsHexAddress(rawAddress) {
+		log.Fatalf("❌ invalid address: %s", rawAddress)
+	}
+
+	return common.HexToAddress(rawAddress)
+}
+
+func noteDegenDB() *degendb.DegenDB {
+	ddb := degendb.NewDegenDB()
+	if ddb == nil {
+		log.Fatal("❌ notes are stored in degendb, please configure a reachable mongodb.uri")
+	}
+
+	return ddb
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/correlations"
+	"github.com/benleb/gloomberg/internal/slugs"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// offerwallCmd represents the offerwall command.
+var offerwallCmd = &cobra.Command{
+	Use:   "offerwall <slug|address>",
+	Short: "show the floor & top collection offer of a collection over time",
+	Long: `Charts the floor (ask side) & the top collection offer (bid side) of a watched collection from the market samples
+recorded while running 'gloomberg live' (correlations.enabled), to see the strength of the bid side over time.`,
+	Example: `  gloomberg offerwall pudgypenguins --window 72h`,
+	Args:    cobra.ExactArgs(1),
+
+	Run: runOfferwall,
+}
+
+var flagOfferwallWindow time.Duration
+
+// levels of the sparklines from low to high.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(offerwallCmd)
+
+	offerwallCmd.Flags().DurationVarP(&flagOfferwallWindow, "window", "w", time.Hour*24*7, "window of samples to show")
+	offerwallCmd.Flags().Int("width", 72, "width of the charts")
+}
+
+func runOfferwall(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	slugs.Setup(gb)
+
+	contractAddress, err := slugs.Address(ctx, args[0])
+	if err != nil {
+		log.Fatalf("❌ could not find collection %s: %s", args[0], err)
+	}
+
+	series, err := correlations.LoadSeries(ctx, gb, contractAddress, flagOfferwallWindow)
+	if err != nil {
+		log.Fatalf("❌ loading the market samples failed: %s", err)
+	}
+
+	floors := make([]float64, 0, len(series.Samples))
+	topOffers := make([]float64, 0, len(series.Samples))
+	numWithOffer := 0
+
+	for _, sample := range series.Samples {
+		floors = append(floors, sample.Floor)
+		topOffers = append(topOffers, sample.TopOffer)
+
+		if sample.TopOffer > 0 {
+			numWithOffer++
+		}
+	}
+
+	if numWithOffer == 0 {
+		fmt.Printf("no top offers recorded for %s yet - keep 'gloomberg live' running with correlations enabled for a while\n", style.BoldAlmostWhite(args[0]))
+
+		return
+	}
+
+	width, _ := cmd.Flags().GetInt("width")
+
+	// both series on the same scale to see the gap between ask & bid
+	low, high := math.Inf(1), math.Inf(-1)
+
+	for _, value := range append(floors, topOffers...) {
+		if value > 0 {
+			low, high = math.Min(low, value), math.Max(high, value)
+		}
+	}
+
+	out := strings.Builder{}
+
+	out.WriteString(fmt.Sprintf("\n  %s · %d samples · %s → %s\n\n",
+		style.BoldAlmostWhite(args[0]),
+		len(series.Samples),
+		series.Samples[0].At.Format("02.01. 15:04"),
+		series.Samples[len(series.Samples)-1].At.Format("02.01. 15:04"),
+	))
+
+	last := series.Samples[len(series.Samples)-1]
+
+	out.WriteString(fmt.Sprintf("  %s %s  %s\n", style.GrayStyle.Render("floor    "), style.TrendLightRedStyle.Render(sparkline(floors, width, low, high)), style.BoldAlmostWhite(fmt.Sprintf("%.3fΞ", last.Floor))))
+	out.WriteString(fmt.Sprintf("  %s %s  %s\n", style.GrayStyle.Render("top offer"), style.TrendLightGreenStyle.Render(sparkline(topOffers, width, low, high)), style.BoldAlmostWhite(fmt.Sprintf("%.3fΞ", last.TopOffer))))
+
+	out.WriteString(fmt.Sprintf("\n  %s %.3fΞ - %.3fΞ\n", style.DarkGrayStyle.Render("range"), low, high))
+
+	// top offer relative to the floor, the higher the stronger the bid side
+	ratios := make([]float64, 0, numWithOffer)
+
+	for _, sample := range series.Samples {
+		if sample.Floor > 0 && sample.TopOffer > 0 {
+			ratios = append(ratios, sample.TopOffer/sample.Floor)
+		}
+	}
+
+	if len(ratios) > 0 {
+		var sum float64
+		for _, ratio := range ratios {
+			sum += ratio
+		}
+
+		out.WriteString(fmt.Sprintf("  %s now %s · avg %s\n",
+			style.DarkGrayStyle.Render("offer/floor"),
+			style.BoldAlmostWhite(fmt.Sprintf("%.0f%%", ratios[len(ratios)-1]*100)),
+			style.BoldAlmostWhite(fmt.Sprintf("%.0f%%", sum/float64(len(ratios))*100)),
+		))
+	}
+
+	fmt.Println(out.String())
+}
+
+// sparkline renders the values as a line of block characters, scaled to low-high & downsampled to the width.
+// unknown values (0) are shown as space.
+func sparkline(values []float64, width int, low float64, high float64) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+
+	columns := min(width, len(values))
+	line := make([]rune, 0, columns)
+
+	for column := 0; column < columns; column++ {
+		// last value of the samples in this column
+		value := values[min(len(values)-1, (column+1)*len(values)/columns-1)]
+
+		switch {
+		case value <= 0:
+			line = append(line, ' ')
+		case high <= low:
+			line = append(line, sparkLevels[len(sparkLevels)/2])
+		default:
+			line = append(line, sparkLevels[int((value-low)/(high-low)*float64(len(sparkLevels)-1)+0.5)])
+		}
+	}
+
+	return string(line)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/benleb/gloomberg/cmd/mintcmd"
 	"github.com/benleb/gloomberg/cmd/oncecmd"
 	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/config"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/style"
@@ -61,7 +62,10 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gloomberg.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.gloomberg.yaml), a comma-separated list of files is merged in order (e.g. team.yaml,personal.yaml)")
+
+	rootCmd.PersistentFlags().String("profile", "", "config profile to apply (e.g. trader, minter, server, quiet or one defined in the config)")
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
 
 	rootCmd.PersistentFlags().Bool("dev", false, "dev mode")
 	_ = viper.BindPFlag("dev.mode", rootCmd.PersistentFlags().Lookup("dev"))
@@ -104,6 +108,9 @@ func init() {
 	// logging
 	viper.SetDefault("log.log_file", "/tmp/gloomberg.log")
 
+	// clickable osc 8 links in the terminal output: auto (if the terminal supports them), always or never
+	viper.SetDefault("hyperlinks", "auto")
+
 	// // api keys from nodes providers & other services
 	// viper.SetDefault("api_keys", map[string]string{"alchemy": "", "infura": "", "moralis": "", "opensea": "", "etherscan": ""})
 
@@ -113,6 +120,8 @@ func init() {
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.database", 0)
 	viper.SetDefault("redis.password", "")
+	viper.SetDefault("redis.username", "")
+	viper.SetDefault("redis.tls.enabled", false)
 
 	// ipfs
 	// viper.SetDefault("ipfs.gateway", "https://ipfs.io/ipfs/")
@@ -131,8 +140,18 @@ func init() {
 	// floor_ttl is only intended & suitable for caching purposes, not for buying decisions!
 	viper.SetDefault("cache.floor_ttl", 10*time.Minute)
 	viper.SetDefault("cache.salira_ttl", 1*time.Hour)
+	viper.SetDefault("cache.royalty_ttl", 24*time.Hour)
+	viper.SetDefault("cache.royalty_info_ttl", 7*24*time.Hour)
+	viper.SetDefault("cache.wallet_age_ttl", 24*time.Hour)
+	viper.SetDefault("cache.tokenbound_ttl", 30*24*time.Hour)
 	viper.SetDefault("cache.slug_ttl", 3*24*time.Hour)
 	viper.SetDefault("cache.notifications_lock_ttl", time.Millisecond*1337)
+
+	// names & slugs expiring within this share of their ttl are refreshed in the background
+	viper.SetDefault("cache.refresh_ahead", 0.1)
+
+	// verified contract abis (fetched from etherscan)
+	viper.SetDefault("cache.abi_ttl", 7*24*time.Hour)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -141,9 +160,18 @@ func initConfig() {
 	home, err := os.UserHomeDir()
 	cobra.CheckErr(err)
 
-	if cfgFile != "" {
+	// multiple config files are merged in order, later files override earlier ones
+	cfgFiles := make([]string, 0)
+
+	for _, file := range strings.Split(cfgFile, ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			cfgFiles = append(cfgFiles, file)
+		}
+	}
+
+	if len(cfgFiles) > 0 {
 		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
+		viper.SetConfigFile(cfgFiles[0])
 	} else {
 		// Search config in home directory with name ".gloomberg.yaml"
 		viper.AddConfigPath(home)
@@ -171,8 +199,30 @@ func initConfig() {
 		}
 	}
 
+	// merge the overlays (e.g. personal api keys & overrides on top of a shared team watchlist).
+	// maps are merged key by key, lists & values of later files replace those of earlier ones
+	if len(cfgFiles) > 1 {
+		for _, file := range cfgFiles[1:] {
+			viper.SetConfigFile(file)
+
+			if err := viper.MergeInConfig(); err != nil {
+				fmt.Printf("config file error: %s - %s\n", file, err.Error())
+			}
+		}
+	}
+
+	// apply config profile
+	if profile := viper.GetString("profile"); profile != "" {
+		if err := config.ApplyProfile(profile); err != nil {
+			fmt.Printf("config profile error: %s - %s (available: %s)\n", profile, err.Error(), strings.Join(config.Profiles(), ", "))
+			os.Exit(1)
+		}
+	}
+
 	gbl.GetSugaredLogger()
 
+	style.SetHyperlinks(style.HyperlinkMode(viper.GetString("hyperlinks")))
+
 	// // if command is not generate
 	if rootCmd.CalledAs() != "generate" {
 		gb = gloomberg.New()
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/slugs"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// royaltiesCmd represents the royalties command.
+var royaltiesCmd = &cobra.Command{
+	Use:   "royalties <slug|address>...",
+	Short: "show the royalty compliance of the sales of collections",
+	Long: `Shows how many sales of watched collections paid the erc2981 royalty of the collection, recorded while
+running 'gloomberg live' with royalties.enabled (seaport sales only).`,
+	Example: `  gloomberg royalties pudgypenguins lilpudgys`,
+	Args:    cobra.MinimumNArgs(1),
+
+	Run: runRoyalties,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(royaltiesCmd)
+}
+
+func runRoyalties(_ *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	slugs.Setup(gb)
+
+	out := strings.Builder{}
+	out.WriteString("\n")
+
+	for _, arg := range args {
+		contractAddress, err := slugs.Address(ctx, arg)
+		if err != nil {
+			log.Errorf("❌ could not find collection %s: %s", arg, err)
+
+			continue
+		}
+
+		stats, err := gb.Rueidi.GetRoyaltyStats(ctx, contractAddress)
+		if err != nil {
+			log.Errorf("❌ could not get royalty stats for %s: %s", contractAddress.Hex(), err)
+
+			continue
+		}
+
+		numSales := stats["sales"]
+		if numSales == 0 {
+			out.WriteString(fmt.Sprintf("  %s · no sales recorded yet\n", style.BoldAlmostWhite(arg)))
+
+			continue
+		}
+
+		compliance := float64(stats["paid"]) / float64(numSales) * 100
+
+		out.WriteString(fmt.Sprintf("  %s · %s sales · %s paid · %d partially · %d skipped\n",
+			style.BoldAlmostWhite(arg),
+			style.BoldAlmostWhite(fmt.Sprint(numSales)),
+			style.BoldAlmostWhite(fmt.Sprintf("%.0f%%", compliance)),
+			stats["partial"],
+			stats["unpaid"],
+		))
+
+		out.WriteString(fmt.Sprintf("    %s %.3fΞ of %.3fΞ expected\n",
+			style.DarkGrayStyle.Render("royalties"),
+			float64(stats["paid_gwei"])/1e9,
+			float64(stats["expected_gwei"])/1e9,
+		))
+	}
+
+	fmt.Println(out.String())
+}
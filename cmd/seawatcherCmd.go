@@ -30,19 +30,14 @@ func init() {
 }
 
 func runSeawatcher(_ *cobra.Command, _ []string) {
-	// find api key
-	var apiKey string
-	switch {
-	case viper.GetString("api_keys.opensea") != "":
-		apiKey = viper.GetString("api_keys.opensea")
-	case viper.GetString("seawatcher.api_key") != "":
-		apiKey = viper.GetString("seawatcher.api_key")
-	default:
+	// find api keys
+	apiKeys := seawa.APIKeysFromConfig()
+	if len(apiKeys) == 0 {
 		log.Fatal("no api key found")
 	}
 
 	// start sea watcher & loop forever
-	sw := seawa.NewSeaWatcher(apiKey, gb)
+	sw := seawa.NewSeaWatcher(apiKeys, gb)
 
 	sw.Pr("⚓️ seawatcher started... 🌊 👀")
 
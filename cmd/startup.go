@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/chains"
+	"github.com/benleb/gloomberg/internal/config"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils/wwatcher"
+	"github.com/spf13/viper"
+)
+
+// setupProviders connects to the providers of mainnet & the additional chains (l2s).
+func setupProviders(stage *gloomberg.Stage) error {
+	// compatibility with old config key
+	var providerConfig interface{}
+	if cfg := viper.Get("provider"); cfg != nil {
+		providerConfig = cfg
+	} else {
+		providerConfig = viper.Get("nodes")
+	}
+
+	//
+	// init provider pool
+	pool, err := provider.FromConfig(providerConfig)
+	if err != nil || pool == nil {
+		return fmt.Errorf("setting up the providers failed: %w", err)
+	}
+
+	gb.ProviderPool = pool
+	gb.ProviderPool.Rueidi = gb.Rueidi
+
+	// get all node names to be shown as a list of connected nodes
+	providers := gb.ProviderPool.GetProviders()
+	nodeNames := make([]string, 0)
+	for _, n := range providers {
+		nodeNames = append(nodeNames, style.BoldStyle.Render(n.Name))
+	}
+
+	gloomberg.Pr(fmt.Sprintf("connected to %s providers: %s", style.AlmostWhiteStyle.Render(strconv.Itoa(len(providers))), style.AlmostWhiteStyle.Render(strings.Join(nodeNames, ", "))))
+
+	// what each endpoint can do
+	for _, p := range providers {
+		gloomberg.Pr(fmt.Sprintf("  %s %s", style.BoldAlmostWhite(p.Name), style.GrayStyle.Render(p.Capabilities.String())))
+	}
+
+	numConnected, numConfigured := len(providers), pool.NumConfigured()
+	stage.Progress(numConnected, numConfigured)
+
+	//
+	// provider pools of the additional chains (l2s) watched alongside mainnet
+	for _, chain := range chains.Enabled() {
+		pool, err := provider.FromConfig(viper.Get("chains." + chain.Name + ".provider"))
+		if err != nil || pool == nil {
+			gbl.Log.Errorf("❌ setting up the %s providers failed: %s", chain.Name, err)
+
+			continue
+		}
+
+		pool.Rueidi = gb.Rueidi
+		gb.ChainPools[chain.ID] = pool
+
+		gloomberg.Pr(fmt.Sprintf("connected to %s %s providers", style.AlmostWhiteStyle.Render(strconv.Itoa(len(pool.GetProviders()))), chain.Tag()))
+
+		numConnected, numConfigured = numConnected+len(pool.GetProviders()), numConfigured+pool.NumConfigured()
+		stage.Progress(numConnected, numConfigured)
+	}
+
+	if len(providers) == 0 {
+		return errors.New("no provider connected")
+	}
+
+	return nil
+}
+
+// setupCollections loads the collections from the config, the own wallets with their collections & the watch rules.
+func setupCollections() {
+	// collection from config file
+	for _, collection := range config.GetCollectionsFromConfiguration(gb.ProviderPool, gb.Rueidi) {
+		gb.CollectionDB.RWMu.Lock()
+		gb.CollectionDB.Collections[collection.ContractAddress] = collection
+		gb.CollectionDB.RWMu.Unlock()
+	}
+
+	gloomberg.Pr(fmt.Sprintf("%s collections loaded from config", style.AlmostWhiteStyle.Render(strconv.Itoa(len(gb.CollectionDB.Collections)))))
+
+	if !viper.GetBool("sales.enabled") {
+		return
+	}
+
+	//
+	// get own wallets from config file
+	gb.OwnWallets = config.GetOwnWalletsFromConfig(gb.ProviderPool)
+
+	if len(*gb.OwnWallets) > 0 {
+		gloomberg.PrMod("wawa", fmt.Sprintf("%s own wallets: %s", style.AlmostWhiteStyle.Render(strconv.Itoa(len(*gb.OwnWallets))), strings.Join(gb.OwnWallets.FormattedNames(), ", ")))
+
+		// read collections hold in wallets from opensea and store in currentCollections
+		gbl.Log.Debugf("gb.OwnWallets: %v | gb.CollectionDB: %+v | gb.ProviderPool: %+v", gb.OwnWallets, gb.CollectionDB, gb.ProviderPool)
+		walletCollections := opensea.GetWalletCollections(gb)
+
+		gb.CollectionDB.RWMu.Lock()
+		for _, collection := range walletCollections {
+			if gb.CollectionDB.Collections[collection.ContractAddress] == nil {
+				gb.CollectionDB.Collections[collection.ContractAddress] = collection
+			}
+		}
+		gb.CollectionDB.RWMu.Unlock()
+
+		gbl.Log.Infof("collections from wallets: %d", len(walletCollections))
+
+		GetWalletTokens(gb)
+	}
+
+	gloomberg.Pr(fmt.Sprintf("%s collections from config & wallets: ", style.AlmostWhiteStyle.Render(strconv.Itoa(len(gb.CollectionDB.Collections)))))
+
+	//
+	// wallet watcher (todo) & MIWs
+	gb.Watcher = config.GetWatchRulesFromConfig()
+
+	wwatcher.LoadMIWs()
+
+	if len(wwatcher.MIWC.WeightedMIWs) > 0 {
+		gloomberg.Pr(fmt.Sprintf("%s MIWs loaded", style.AlmostWhiteStyle.Render(strconv.Itoa(len(wwatcher.MIWC.WeightedMIWs)))))
+	}
+}
+
+// waitReady waits until the channel is closed or the deadline is reached & returns false if it is not closed.
+func waitReady(ready <-chan struct{}, deadline time.Time) bool {
+	// prefer ready over an elapsed deadline
+	select {
+	case <-ready:
+		return true
+	default:
+	}
+
+	select {
+	case <-ready:
+		return true
+	case <-time.After(max(time.Until(deadline), 0)):
+		return false
+	}
+}
+
+// pingRedis checks if the redis server is reachable.
+func pingRedis() error {
+	if gb.Rdb == nil {
+		return errors.New("no redis client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("startup.timeout"))
+	defer cancel()
+
+	return gb.Rdb.Do(ctx, gb.Rdb.B().Ping().Build()).Error()
+}
+
+// waitForStream waits until the connection to the opensea stream is established.
+func waitForStream(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for health.StreamConnectedSince(health.SourceOpenSea).IsZero() {
+		if time.Now().After(deadline) {
+			return errors.New("not connected to the opensea stream yet")
+		}
+
+		time.Sleep(time.Millisecond * 250)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/seawa"
+	"github.com/benleb/gloomberg/internal/slugs"
+	"github.com/benleb/gloomberg/internal/tape"
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// tapeCmd represents the tape command.
+var tapeCmd = &cobra.Command{
+	Use:   "tape <slug|address>",
+	Short: "show the listings, sales & offers of a single collection",
+	Long: `Focused mode for actively trading a single collection. Shows only its listings, sales & offers in a dense columnar layout below a header with the live floor & top bid.
+Sales are received via the configured nodes, listings & offers via the OpenSea stream (api_keys.opensea required).`,
+	Args: cobra.ExactArgs(1),
+
+	Run: runTape,
+}
+
+func init() { //nolint:gochecknoinits
+	rootCmd.AddCommand(tapeCmd)
+}
+
+func runTape(_ *cobra.Command, args []string) {
+	slugs.Setup(gb)
+
+	contractAddress, err := slugs.Address(context.Background(), args[0])
+	if err != nil {
+		log.Fatalf("❌ could not find collection %s: %s", args[0], err)
+	}
+
+	slug := args[0]
+	if s, err := slugs.Slug(context.Background(), contractAddress); err == nil && s != "" {
+		slug = s
+	}
+
+	// compatibility with old config key
+	var providerConfig interface{}
+	if cfg := viper.Get("provider"); cfg != nil {
+		providerConfig = cfg
+	} else {
+		providerConfig = viper.Get("nodes")
+	}
+
+	pool, err := provider.FromConfig(providerConfig)
+	if err != nil || pool == nil {
+		log.Fatal("❌ running provider failed, exiting")
+	}
+
+	pool.Rueidi = gb.Rueidi
+	gb.ProviderPool = pool
+
+	// the tape prints on its own, status messages of the other modules would break the layout
+	gloomberg.PauseOutput(true)
+
+	// listings & offers via the opensea stream
+	if apiKeys := seawa.APIKeysFromConfig(); len(apiKeys) > 0 {
+		viper.Set("seawatcher.local", true)
+
+		if seaWatcher := seawa.NewSeaWatcher(apiKeys, gb); seaWatcher != nil {
+			seaWatcher.Subscribe(degendb.SlugSubscriptions{{Slug: slug, Events: []degendb.EventType{degendb.Listing, degendb.Bid, degendb.CollectionOffer}}})
+		}
+	} else {
+		log.Warn("no OpenSea api key found, showing sales only")
+	}
+
+	collectionTape := tape.New(gb, slug, contractAddress)
+
+	if err := collectionTape.Start(); err != nil {
+		log.Fatalf("❌ subscribing to %s failed: %s", slug, err)
+	}
+
+	// reset the terminal on exit
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	<-signals
+
+	collectionTape.Close()
+}
@@ -0,0 +1,244 @@
+package allowlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrNoProof          = errors.New("no proof found")
+	ErrInvalidProof     = errors.New("proof does not match the merkle root")
+	ErrUnexpectedStatus = errors.New("unexpected status code")
+)
+
+// Eligibility of a wallet for an allowlist mint.
+type Eligibility struct {
+	Wallet common.Address
+
+	Eligible bool
+
+	// number of allowed mints, 0 if unknown (e.g. merkle proofs without quantity)
+	Amount uint64
+
+	// how the eligibility was found, e.g. "allowlist(address)" or "merkle"
+	Method string
+}
+
+// getter is a view function taking the wallet address as only argument.
+type getter struct {
+	signature   string
+	returnsBool bool
+}
+
+// common mapping getters used by allowlist mints, uint results are interpreted as allowed amount.
+var mappingGetters = []getter{
+	{signature: "allowlist(address)"},
+	{signature: "allowList(address)"},
+	{signature: "whitelist(address)"},
+	{signature: "whiteList(address)"},
+	{signature: "presaleAllowance(address)"},
+	{signature: "allowlistAllowance(address)"},
+	{signature: "isAllowlisted(address)", returnsBool: true},
+	{signature: "isWhitelisted(address)", returnsBool: true},
+	{signature: "whitelisted(address)", returnsBool: true},
+	{signature: "allowlisted(address)", returnsBool: true},
+}
+
+// common getters for the merkle root of an allowlist.
+var merkleRootGetters = []string{
+	"merkleRoot()",
+	"allowlistMerkleRoot()",
+	"allowListMerkleRoot()",
+	"whitelistMerkleRoot()",
+	"presaleMerkleRoot()",
+	"allowlistRoot()",
+	"whitelistRoot()",
+}
+
+// Check probes the contract for common allowlist patterns & returns the eligibility of the wallets.
+// mapping getters are checked first, then merkle roots with the proofs from the allowlist.proof_apis.
+func Check(ctx context.Context, caller ethereum.ContractCaller, contract common.Address, wallets []common.Address) []*Eligibility {
+	eligibilities := make([]*Eligibility, 0, len(wallets))
+
+	merkleRoots := getMerkleRoots(ctx, caller, contract)
+
+	for _, wallet := range wallets {
+		eligibility := checkMappings(ctx, caller, contract, wallet)
+
+		if !eligibility.Eligible {
+			for _, root := range merkleRoots {
+				if err := checkMerkleProof(ctx, contract, root, wallet); err == nil {
+					eligibility = &Eligibility{Wallet: wallet, Eligible: true, Method: "merkle"}
+
+					break
+				} else if !errors.Is(err, ErrNoProof) {
+					gbl.Log.Debugf("🎟️ merkle proof check for %s failed: %s", wallet.Hex(), err)
+				}
+			}
+		}
+
+		eligibilities = append(eligibilities, eligibility)
+	}
+
+	return eligibilities
+}
+
+// Summary formats the eligibilities as short text, e.g. "eligible: 0x12…34 ×2, 0x56…78 ✓ | not eligible: 0x9a…bc".
+func Summary(eligibilities []*Eligibility, walletName func(common.Address) string) string {
+	eligible := make([]string, 0)
+	notEligible := make([]string, 0)
+
+	for _, eligibility := range eligibilities {
+		name := walletName(eligibility.Wallet)
+
+		switch {
+		case !eligibility.Eligible:
+			notEligible = append(notEligible, name)
+		case eligibility.Amount > 0:
+			eligible = append(eligible, fmt.Sprintf("%s ×%d", name, eligibility.Amount))
+		default:
+			eligible = append(eligible, name+" ✓")
+		}
+	}
+
+	sort.Strings(eligible)
+	sort.Strings(notEligible)
+
+	parts := make([]string, 0)
+
+	if len(eligible) > 0 {
+		parts = append(parts, "eligible: "+strings.Join(eligible, ", "))
+	}
+
+	if len(notEligible) > 0 {
+		parts = append(parts, "not eligible: "+strings.Join(notEligible, ", "))
+	}
+
+	return strings.Join(parts, " | ")
+}
+
+func checkMappings(ctx context.Context, caller ethereum.ContractCaller, contract common.Address, wallet common.Address) *Eligibility {
+	for _, g := range mappingGetters {
+		calldata := append(selector(g.signature), common.LeftPadBytes(wallet.Bytes(), 32)...)
+
+		result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: calldata}, nil)
+		if err != nil || len(result) != 32 {
+			continue
+		}
+
+		value := new(big.Int).SetBytes(result)
+		if value.Sign() == 0 {
+			// the getter exists but the wallet is not on the list
+			return &Eligibility{Wallet: wallet, Method: g.signature}
+		}
+
+		eligibility := &Eligibility{Wallet: wallet, Eligible: true, Method: g.signature}
+
+		// everything > 1 for bool getters would be a misinterpretation, large values are probably no amounts
+		if !g.returnsBool && value.IsUint64() && value.Uint64() < 10_000 {
+			eligibility.Amount = value.Uint64()
+		}
+
+		return eligibility
+	}
+
+	return &Eligibility{Wallet: wallet}
+}
+
+func getMerkleRoots(ctx context.Context, caller ethereum.ContractCaller, contract common.Address) []common.Hash {
+	roots := make([]common.Hash, 0)
+
+	for _, signature := range merkleRootGetters {
+		result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: selector(signature)}, nil)
+		if err != nil || len(result) != 32 {
+			continue
+		}
+
+		if root := common.BytesToHash(result); root != (common.Hash{}) {
+			gbl.Log.Debugf("🎟️ found merkle root via %s: %s", signature, root.Hex())
+
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+}
+
+type proofResponse struct {
+	Proof []string `json:"proof"`
+}
+
+// checkMerkleProof fetches a proof for the wallet from the proof apis & verifies it against the root.
+func checkMerkleProof(ctx context.Context, contract common.Address, root common.Hash, wallet common.Address) error {
+	for _, apiURL := range viper.GetStringSlice("allowlist.proof_apis") {
+		replacer := strings.NewReplacer("{root}", root.Hex(), "{address}", wallet.Hex(), "{contract}", contract.Hex())
+
+		response, err := utils.HTTP.GetWithTLS12(ctx, replacer.Replace(apiURL))
+		if err != nil {
+			return err
+		}
+
+		var proof proofResponse
+
+		switch response.StatusCode {
+		case http.StatusOK:
+			err = json.NewDecoder(response.Body).Decode(&proof)
+		case http.StatusNotFound:
+			err = ErrNoProof
+		default:
+			err = fmt.Errorf("%w: %s", ErrUnexpectedStatus, response.Status)
+		}
+
+		response.Body.Close()
+
+		if err != nil || len(proof.Proof) == 0 {
+			continue
+		}
+
+		hashes := make([]common.Hash, 0, len(proof.Proof))
+		for _, hash := range proof.Proof {
+			hashes = append(hashes, common.HexToHash(hash))
+		}
+
+		if verifyProof(root, crypto.Keccak256Hash(wallet.Bytes()), hashes) {
+			return nil
+		}
+
+		return ErrInvalidProof
+	}
+
+	return ErrNoProof
+}
+
+// verifyProof verifies a proof for the leaf with sorted pairs (openzeppelin MerkleProof).
+func verifyProof(root common.Hash, leaf common.Hash, proof []common.Hash) bool {
+	computed := leaf
+
+	for _, sibling := range proof {
+		if bytes.Compare(computed.Bytes(), sibling.Bytes()) <= 0 {
+			computed = crypto.Keccak256Hash(computed.Bytes(), sibling.Bytes())
+		} else {
+			computed = crypto.Keccak256Hash(sibling.Bytes(), computed.Bytes())
+		}
+	}
+
+	return computed == root
+}
+
+func selector(signature string) []byte {
+	return crypto.Keccak256([]byte(signature))[:4]
+}
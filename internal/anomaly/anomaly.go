@@ -0,0 +1,149 @@
+package anomaly
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// tokenSale is a sale of a single token seen in the stream.
+type tokenSale struct {
+	tx          common.Hash
+	chainID     uint64
+	blockNumber uint64
+	priceEther  float64
+}
+
+var (
+	// recent sales per chain & nft id
+	recentSales   = make(map[uint64]map[string][]tokenSale)
+	recentSalesMu sync.Mutex
+
+	// block number of the last cleanup of the recent sales per chain
+	lastCleanup = make(map[uint64]uint64)
+)
+
+// Enabled returns true if tokens sold several times within a few blocks at very different prices should be flagged.
+func Enabled() bool {
+	return viper.GetBool("anomalies.enabled")
+}
+
+// Label returns a label linking the previous sale if the token of a single-token sale was already sold within
+// anomalies.blocks blocks at a price differing by more than the factor anomalies.price_ratio. the earlier sale
+// is already shown, so both sales are flagged with an additional line linking both txs.
+func Label(ttx *totra.TokenTransaction) string {
+	if !degendb.SaleTypes.Contains(ttx.Action) || ttx.TxReceipt == nil || ttx.TxReceipt.BlockNumber == nil {
+		return ""
+	}
+
+	var nftTransfer *totra.TokenTransfer
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.Token == nil {
+			continue
+		}
+
+		// bundles have no per-token price
+		if nftTransfer != nil {
+			return ""
+		}
+
+		nftTransfer = transfer
+	}
+
+	if nftTransfer == nil || ttx.GetPrice().Ether() <= 0 {
+		return ""
+	}
+
+	sale := tokenSale{
+		tx:          ttx.TxHash,
+		chainID:     ttx.ChainID,
+		blockNumber: ttx.TxReceipt.BlockNumber.Uint64(),
+		priceEther:  ttx.GetPrice().Ether(),
+	}
+
+	previous := remember(nftTransfer.Token.NftID(), sale)
+	if previous == nil {
+		return ""
+	}
+
+	blocks := sale.blockNumber - previous.blockNumber
+	if previous.blockNumber > sale.blockNumber {
+		blocks = previous.blockNumber - sale.blockNumber
+	}
+
+	ratio := max(sale.priceEther, previous.priceEther) / min(sale.priceEther, previous.priceEther)
+
+	fmtPrevious := style.TerminalLink(links.ChainTx(previous.chainID, previous.tx), style.ShortenHashStyled(previous.tx))
+	fmtCurrent := style.TerminalLink(links.ChainTx(sale.chainID, sale.tx), style.ShortenHashStyled(sale.tx))
+
+	gloomberg.PrModf("anomaly", "%s sold again in %s for %.3fΞ %s %s",
+		nftTransfer.Token.ShortID(),
+		fmtCurrent,
+		sale.priceEther,
+		style.DarkGrayStyle.Render(fmt.Sprintf("(%.0fx of %.3fΞ in", ratio, previous.priceEther)),
+		fmtPrevious+style.DarkGrayStyle.Render(fmt.Sprintf(", %d blocks apart)", blocks)),
+	)
+
+	return "⚠️ " + style.BoldAlmostWhite("sold twice") + style.DarkGrayStyle.Render(fmt.Sprintf(" within %d blocks for %.3fΞ (%.0fx) in ", blocks, previous.priceEther, ratio)) + fmtPrevious
+}
+
+// remember stores the sale & returns an earlier sale of the token within the block window with a price
+// differing by more than the configured factor. txs are processed concurrently, so the "earlier" sale
+// might be in a later block.
+func remember(nftID string, sale tokenSale) *tokenSale {
+	window := viper.GetUint64("anomalies.blocks")
+	priceRatio := viper.GetFloat64("anomalies.price_ratio")
+
+	recentSalesMu.Lock()
+	defer recentSalesMu.Unlock()
+
+	chainSales, ok := recentSales[sale.chainID]
+	if !ok {
+		chainSales = make(map[string][]tokenSale)
+		recentSales[sale.chainID] = chainSales
+	}
+
+	var anomaly *tokenSale
+
+	sales := make([]tokenSale, 0, len(chainSales[nftID])+1)
+
+	for _, previous := range chainSales[nftID] {
+		if previous.blockNumber+window < sale.blockNumber {
+			continue
+		}
+
+		sales = append(sales, previous)
+
+		if previous.tx == sale.tx || anomaly != nil {
+			continue
+		}
+
+		if previous.priceEther > 0 && max(sale.priceEther, previous.priceEther)/min(sale.priceEther, previous.priceEther) >= priceRatio {
+			anomalous := previous
+			anomaly = &anomalous
+		}
+	}
+
+	chainSales[nftID] = append(sales, sale)
+
+	// forget sales outside the window once per window
+	if sale.blockNumber > lastCleanup[sale.chainID]+window {
+		for id, tokenSales := range chainSales {
+			if tokenSales[len(tokenSales)-1].blockNumber+window < sale.blockNumber {
+				delete(chainSales, id)
+			}
+		}
+
+		lastCleanup[sale.chainID] = sale.blockNumber
+	}
+
+	return anomaly
+}
@@ -0,0 +1,329 @@
+package ansimg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	cellWidth  = 7
+	cellHeight = 15
+	padding    = 12
+)
+
+var (
+	defaultForeground = color.RGBA{R: 0xd0, G: 0xd0, B: 0xd0, A: 0xff}
+	defaultBackground = color.RGBA{R: 0x12, G: 0x12, B: 0x16, A: 0xff}
+
+	// the 16 basic ansi colors
+	basicColors = [16]color.RGBA{
+		{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x31, 0x31, 0xff}, {0x0d, 0xbc, 0x79, 0xff}, {0xe5, 0xe5, 0x10, 0xff},
+		{0x24, 0x72, 0xc8, 0xff}, {0xbc, 0x3f, 0xbc, 0xff}, {0x11, 0xa8, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+		{0x66, 0x66, 0x66, 0xff}, {0xf1, 0x4c, 0x4c, 0xff}, {0x23, 0xd1, 0x8b, 0xff}, {0xf5, 0xf5, 0x43, 0xff},
+		{0x3b, 0x8e, 0xea, 0xff}, {0xd6, 0x70, 0xd6, 0xff}, {0x29, 0xb8, 0xdb, 0xff}, {0xff, 0xff, 0xff, 0xff},
+	}
+
+	// box drawing characters as line segments from the cell center: left, right, up, down
+	boxSegments = map[rune][4]bool{
+		'─': {true, true, false, false}, '━': {true, true, false, false},
+		'│': {false, false, true, true}, '┃': {false, false, true, true},
+		'┌': {false, true, false, true}, '╭': {false, true, false, true},
+		'┐': {true, false, false, true}, '╮': {true, false, false, true},
+		'└': {false, true, true, false}, '╰': {false, true, true, false},
+		'┘': {true, false, true, false}, '╯': {true, false, true, false},
+		'├': {false, true, true, true}, '┤': {true, false, true, true},
+		'┬': {true, true, false, true}, '┴': {true, true, true, false},
+		'┼': {true, true, true, true},
+	}
+)
+
+// cellStyle is the sgr state of a cell.
+type cellStyle struct {
+	fg    color.RGBA
+	bg    color.RGBA
+	bold  bool
+	faint bool
+}
+
+type cell struct {
+	r     rune
+	width int
+	style cellStyle
+}
+
+// PNG renders text with ansi colors like the stats box to a png.
+func PNG(text string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := png.Encode(&buf, Render(text)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Render renders text with ansi colors (sgr sequences) to an image using a fixed 7x13 font.
+// runes not available in the font (e.g. emojis) are left blank, box drawing characters & Ξ are drawn.
+func Render(text string) *image.RGBA {
+	lines := parse(text)
+
+	columns := 0
+	for _, line := range lines {
+		lineWidth := 0
+		for _, c := range line {
+			lineWidth += c.width
+		}
+
+		columns = max(columns, lineWidth)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, columns*cellWidth+2*padding, len(lines)*cellHeight+2*padding))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: defaultBackground}, image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{Dst: img, Face: basicfont.Face7x13}
+
+	for row, line := range lines {
+		column := 0
+
+		for _, c := range line {
+			x := padding + column*cellWidth
+			y := padding + row*cellHeight
+
+			if c.style.bg != defaultBackground {
+				draw.Draw(img, image.Rect(x, y, x+c.width*cellWidth, y+cellHeight), &image.Uniform{C: c.style.bg}, image.Point{}, draw.Src)
+			}
+
+			fg := c.style.fg
+			if c.style.faint {
+				fg = color.RGBA{R: fg.R / 2, G: fg.G / 2, B: fg.B / 2, A: 0xff}
+			}
+
+			drawRune(img, drawer, c.r, x, y, fg, c.style.bold)
+
+			column += c.width
+		}
+	}
+
+	return img
+}
+
+func drawRune(img *image.RGBA, drawer *font.Drawer, r rune, x int, y int, fg color.RGBA, bold bool) {
+	centerX, centerY := x+cellWidth/2, y+cellHeight/2
+
+	switch {
+	case r == ' ':
+		return
+
+	case r < 0x7f:
+		drawer.Src = &image.Uniform{C: fg}
+
+		for offset := 0; offset <= boolToInt(bold); offset++ {
+			drawer.Dot = fixed.P(x+offset, y+11)
+			drawer.DrawString(string(r))
+		}
+
+	case r == 'Ξ':
+		for _, lineY := range []int{y + 3, centerY, y + 11} {
+			fillRect(img, x+1, lineY, x+cellWidth-1, lineY+1, fg)
+		}
+
+	case r == '·' || r == '•':
+		fillRect(img, centerX-1, centerY-1, centerX+1, centerY+1, fg)
+
+	default:
+		segments, ok := boxSegments[r]
+		if !ok {
+			return
+		}
+
+		if segments[0] {
+			fillRect(img, x, centerY, centerX+1, centerY+1, fg)
+		}
+
+		if segments[1] {
+			fillRect(img, centerX, centerY, x+cellWidth, centerY+1, fg)
+		}
+
+		if segments[2] {
+			fillRect(img, centerX, y, centerX+1, centerY+1, fg)
+		}
+
+		if segments[3] {
+			fillRect(img, centerX, centerY, centerX+1, y+cellHeight, fg)
+		}
+	}
+}
+
+// parse splits the text into lines of styled cells. sgr sequences change the style, other escape
+// sequences like osc 8 hyperlinks are dropped.
+func parse(text string) [][]cell {
+	lines := make([][]cell, 0)
+	current := make([]cell, 0)
+	style := cellStyle{fg: defaultForeground, bg: defaultBackground}
+
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\n':
+			lines = append(lines, current)
+			current = make([]cell, 0)
+
+		case r == '\x1b' && i+1 < len(runes) && runes[i+1] == '[':
+			// csi, read until the final byte
+			end := i + 2
+			for end < len(runes) && (runes[end] < 0x40 || runes[end] > 0x7e) {
+				end++
+			}
+
+			if end < len(runes) && runes[end] == 'm' {
+				style = applySGR(style, string(runes[i+2:end]))
+			}
+
+			i = end
+
+		case r == '\x1b' && i+1 < len(runes) && runes[i+1] == ']':
+			// osc, terminated by bel or st (esc \)
+			end := i + 2
+			for end < len(runes) && runes[end] != '\a' && !(runes[end] == '\x1b' && end+1 < len(runes) && runes[end+1] == '\\') {
+				end++
+			}
+
+			if end < len(runes) && runes[end] == '\x1b' {
+				end++
+			}
+
+			i = end
+
+		case r == '\r' || r < 0x20:
+			continue
+
+		default:
+			width := lipgloss.Width(string(r))
+			if width == 0 {
+				continue
+			}
+
+			current = append(current, cell{r: r, width: width, style: style})
+		}
+	}
+
+	if len(current) > 0 {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+func applySGR(style cellStyle, params string) cellStyle {
+	codes := strings.Split(params, ";")
+
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			code = 0
+		}
+
+		switch {
+		case code == 0:
+			style = cellStyle{fg: defaultForeground, bg: defaultBackground}
+		case code == 1:
+			style.bold = true
+		case code == 2:
+			style.faint = true
+		case code == 22:
+			style.bold, style.faint = false, false
+		case code >= 30 && code <= 37:
+			style.fg = basicColors[code-30]
+		case code >= 90 && code <= 97:
+			style.fg = basicColors[code-90+8]
+		case code >= 40 && code <= 47:
+			style.bg = basicColors[code-40]
+		case code >= 100 && code <= 107:
+			style.bg = basicColors[code-100+8]
+		case code == 39:
+			style.fg = defaultForeground
+		case code == 49:
+			style.bg = defaultBackground
+		case code == 38 || code == 48:
+			c, consumed, ok := extendedColor(codes[i+1:])
+			if ok {
+				if code == 38 {
+					style.fg = c
+				} else {
+					style.bg = c
+				}
+			}
+
+			i += consumed
+		}
+	}
+
+	return style
+}
+
+// extendedColor parses the 5;n (256 colors) & 2;r;g;b (true color) parameters of 38/48.
+func extendedColor(params []string) (color.RGBA, int, bool) {
+	values := make([]uint8, 0, len(params))
+
+	for _, param := range params {
+		value, err := strconv.Atoi(param)
+		if err != nil || value < 0 || value > 255 {
+			value = 0
+		}
+
+		values = append(values, uint8(value))
+	}
+
+	switch {
+	case len(values) >= 2 && values[0] == 5:
+		return color256(values[1]), 2, true
+	case len(values) >= 4 && values[0] == 2:
+		return color.RGBA{R: values[1], G: values[2], B: values[3], A: 0xff}, 4, true
+	}
+
+	return color.RGBA{}, len(values), false
+}
+
+// color256 returns the rgb value of a xterm 256 color.
+func color256(n uint8) color.RGBA {
+	switch {
+	case n < 16:
+		return basicColors[n]
+
+	case n < 232:
+		levels := [6]uint8{0x00, 0x5f, 0x87, 0xaf, 0xd7, 0xff}
+		n -= 16
+
+		return color.RGBA{R: levels[n/36], G: levels[(n/6)%6], B: levels[n%6], A: 0xff}
+
+	default:
+		gray := 8 + (n-232)*10
+
+		return color.RGBA{R: gray, G: gray, B: gray, A: 0xff}
+	}
+}
+
+func fillRect(img *image.RGBA, x0 int, y0 int, x1 int, y1 int, c color.RGBA) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,219 @@
+package bench
+
+import (
+	"encoding/json"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stages of the pipeline replayed for each tx.
+var Stages = []string{
+	// unmarshalling the tx & receipt as received from the nodes
+	"decode",
+	// creating the token transaction (transfers, prices, marketplace, action)
+	"parse",
+	// marshalling the token transaction as published via redis
+	"encode",
+}
+
+// StageStats are the latencies of a pipeline stage.
+type StageStats struct {
+	Name  string
+	Count int
+
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+	Total time.Duration
+}
+
+// Result of a benchmark run.
+type Result struct {
+	Workers int
+
+	// replayed txs & the token transactions created from them
+	NumEvents int
+	NumParsed int
+
+	Duration time.Duration
+
+	// heap allocations & allocated bytes during the run
+	Mallocs    uint64
+	TotalAlloc uint64
+
+	Stages []*StageStats
+}
+
+// EventsPerSecond returns the throughput of the run.
+func (r *Result) EventsPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+
+	return float64(r.NumEvents) / r.Duration.Seconds()
+}
+
+// AllocsPerEvent returns the heap allocations per replayed tx.
+func (r *Result) AllocsPerEvent() float64 {
+	if r.NumEvents == 0 {
+		return 0
+	}
+
+	return float64(r.Mallocs) / float64(r.NumEvents)
+}
+
+// BytesPerEvent returns the allocated bytes per replayed tx.
+func (r *Result) BytesPerEvent() float64 {
+	if r.NumEvents == 0 {
+		return 0
+	}
+
+	return float64(r.TotalAlloc) / float64(r.NumEvents)
+}
+
+// Run replays the corpus repeat times through the pipeline stages with the given number of workers.
+// the provider pool is used by the parsers for erc20/erc1155 decoding & currency conversions, an empty
+// pool keeps the run offline.
+func Run(entries []*Entry, pool *provider.Pool, workers int, repeat int) *Result {
+	workers = max(workers, 1)
+	repeat = max(repeat, 1)
+
+	queue := make(chan *Entry, workers*2)
+
+	// latencies per worker & stage, merged after the run
+	latencies := make([][][]time.Duration, workers)
+	numParsed := make([]int, workers)
+
+	var wg sync.WaitGroup
+
+	runtime.GC()
+
+	var memBefore runtime.MemStats
+	runtime.ReadMemStats(&memBefore)
+
+	startedAt := time.Now()
+
+	for workerID := 0; workerID < workers; workerID++ {
+		latencies[workerID] = make([][]time.Duration, len(Stages))
+
+		wg.Add(1)
+
+		go func(workerID int) {
+			defer wg.Done()
+
+			for entry := range queue {
+				if replay(entry, pool, latencies[workerID]) {
+					numParsed[workerID]++
+				}
+			}
+		}(workerID)
+	}
+
+	for iteration := 0; iteration < repeat; iteration++ {
+		for _, entry := range entries {
+			queue <- entry
+		}
+	}
+
+	close(queue)
+
+	wg.Wait()
+
+	duration := time.Since(startedAt)
+
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+
+	result := &Result{
+		Workers:    workers,
+		NumEvents:  len(entries) * repeat,
+		Duration:   duration,
+		Mallocs:    memAfter.Mallocs - memBefore.Mallocs,
+		TotalAlloc: memAfter.TotalAlloc - memBefore.TotalAlloc,
+		Stages:     make([]*StageStats, 0, len(Stages)),
+	}
+
+	for workerID := range numParsed {
+		result.NumParsed += numParsed[workerID]
+	}
+
+	for stageIdx, name := range Stages {
+		stageLatencies := make([]time.Duration, 0, result.NumEvents)
+		for workerID := range latencies {
+			stageLatencies = append(stageLatencies, latencies[workerID][stageIdx]...)
+		}
+
+		result.Stages = append(result.Stages, newStageStats(name, stageLatencies))
+	}
+
+	return result
+}
+
+// replay runs the entry through the stages & returns true if a token transaction was created.
+func replay(entry *Entry, pool *provider.Pool, latencies [][]time.Duration) bool {
+	startedAt := time.Now()
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalJSON(entry.Tx); err != nil {
+		return false
+	}
+
+	receipt := new(types.Receipt)
+	if err := receipt.UnmarshalJSON(entry.Receipt); err != nil {
+		return false
+	}
+
+	decodedAt := time.Now()
+	latencies[0] = append(latencies[0], decodedAt.Sub(startedAt))
+
+	ttx := totra.NewTokenTransaction(tx, receipt, pool)
+
+	parsedAt := time.Now()
+	latencies[1] = append(latencies[1], parsedAt.Sub(decodedAt))
+
+	if ttx == nil {
+		return false
+	}
+
+	ttx.ChainID = entry.ChainID
+
+	if _, err := json.Marshal(ttx); err != nil {
+		return false
+	}
+
+	latencies[2] = append(latencies[2], time.Since(parsedAt))
+
+	return true
+}
+
+func newStageStats(name string, latencies []time.Duration) *StageStats {
+	stats := &StageStats{Name: name, Count: len(latencies)}
+
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	slices.Sort(latencies)
+
+	for _, latency := range latencies {
+		stats.Total += latency
+	}
+
+	percentile := func(p float64) time.Duration {
+		return latencies[min(len(latencies)-1, int(float64(len(latencies))*p))]
+	}
+
+	stats.P50 = percentile(0.50)
+	stats.P95 = percentile(0.95)
+	stats.P99 = percentile(0.99)
+	stats.Max = latencies[len(latencies)-1]
+
+	return stats
+}
@@ -0,0 +1,179 @@
+package bench
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrEmptyCorpus = errors.New("corpus contains no transactions")
+
+// Entry is a recorded tx with its receipt, kept as json like it is received from the nodes.
+type Entry struct {
+	ChainID uint64          `json:"chainId"`
+	Tx      json.RawMessage `json:"tx"`
+	Receipt json.RawMessage `json:"receipt"`
+}
+
+// LoadCorpus reads a corpus of json lines entries, gzipped if the file name ends with .gz.
+func LoadCorpus(fileName string) ([]*Entry, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	if strings.HasSuffix(fileName, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	entries := make([]*Entry, 0)
+
+	decoder := json.NewDecoder(bufio.NewReader(reader))
+
+	for {
+		var entry Entry
+
+		if err := decoder.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrEmptyCorpus
+	}
+
+	return entries, nil
+}
+
+// WriteCorpus writes the entries as json lines, gzipped if the file name ends with .gz.
+func WriteCorpus(fileName string, entries []*Entry) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var writer io.Writer = file
+
+	if strings.HasSuffix(fileName, ".gz") {
+		gzipWriter := gzip.NewWriter(file)
+		defer gzipWriter.Close()
+
+		writer = gzipWriter
+	}
+
+	encoder := json.NewEncoder(writer)
+
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newEntry marshals the tx & receipt into a corpus entry.
+func newEntry(chainID uint64, tx *types.Transaction, receipt *types.Receipt) (*Entry, error) {
+	rawTx, err := tx.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	rawReceipt, err := receipt.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Entry{ChainID: chainID, Tx: rawTx, Receipt: rawReceipt}, nil
+}
+
+// RecordCorpus fetches the txs moving nfts in the last numBlocks blocks & their receipts via the nodes.
+// progress is called after each fetched tx with the number of fetched & total txs.
+func RecordCorpus(ctx context.Context, pool *provider.Pool, numBlocks uint64, maxTxs int, progress func(done int, total int)) ([]*Entry, error) {
+	head, err := pool.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := pool.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(head - min(head, max(numBlocks, 1)-1)),
+		ToBlock:   new(big.Int).SetUint64(head),
+		Topics:    [][]common.Hash{{common.HexToHash(string(topic.Transfer)), common.HexToHash(string(topic.TransferSingle))}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// erc721 transfers have 4 topics, erc20 transfers are only recorded as part of nft txs
+	txHashes := make([]common.Hash, 0)
+	seen := make(map[common.Hash]bool)
+
+	for _, txLog := range logs {
+		if len(txLog.Topics) < 4 || seen[txLog.TxHash] {
+			continue
+		}
+
+		seen[txLog.TxHash] = true
+		txHashes = append(txHashes, txLog.TxHash)
+	}
+
+	if maxTxs > 0 && len(txHashes) > maxTxs {
+		txHashes = txHashes[:maxTxs]
+	}
+
+	entries := make([]*Entry, 0, len(txHashes))
+
+	for idx, txHash := range txHashes {
+		tx, err := pool.TransactionByHash(ctx, txHash)
+		if err != nil || tx == nil {
+			continue
+		}
+
+		receipt, err := pool.TransactionReceipt(ctx, txHash)
+		if err != nil || receipt == nil {
+			continue
+		}
+
+		if entry, err := newEntry(tx.ChainId().Uint64(), tx, receipt); err == nil {
+			entries = append(entries, entry)
+		}
+
+		if progress != nil {
+			progress(idx+1, len(txHashes))
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, ErrEmptyCorpus
+	}
+
+	return entries, nil
+}
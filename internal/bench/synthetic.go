@@ -0,0 +1,120 @@
+package bench
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const numSyntheticCollections = 20
+
+// SyntheticCorpus generates a deterministic corpus of numTxs signed erc721 sales (60%), mints (30%)
+// & transfers (10%). it is used if no recorded corpus is given & only covers the chain-independent parts
+// of the pipeline, marketplace orders & erc20/erc1155 transfers need a recorded corpus.
+func SyntheticCorpus(numTxs int) ([]*Entry, error) {
+	chainID := big.NewInt(1)
+
+	privateKey, err := crypto.ToECDSA(crypto.Keccak256([]byte("gloomberg bench")))
+	if err != nil {
+		return nil, err
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	entries := make([]*Entry, 0, numTxs)
+
+	for idx := 0; idx < numTxs; idx++ {
+		collection := syntheticAddress("collection", idx%numSyntheticCollections)
+		tokenID := big.NewInt(int64(idx))
+
+		value := new(big.Int)
+		logs := make([]*types.Log, 0, 3)
+
+		switch kind := idx % 10; {
+		case kind < 6:
+			// sale from a seller to the sender of the tx
+			value.Mul(big.NewInt(int64(1+idx%50)), big.NewInt(1e16))
+			logs = append(logs, transferLog(collection, syntheticAddress("seller", idx%500), sender, tokenID))
+
+		case kind < 9:
+			// mint of 1-3 tokens
+			numTokens := 1 + idx%3
+			value.Mul(big.NewInt(int64(numTokens)), big.NewInt(1e16))
+
+			for tokenIdx := 0; tokenIdx < numTokens; tokenIdx++ {
+				logs = append(logs, transferLog(collection, internal.ZeroAddress, sender, big.NewInt(int64(idx*3+tokenIdx))))
+			}
+
+		default:
+			logs = append(logs, transferLog(collection, sender, syntheticAddress("receiver", idx%500), tokenID))
+		}
+
+		to := collection
+
+		tx, err := types.SignNewTx(privateKey, signer, &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     uint64(idx),
+			GasTipCap: big.NewInt(1e9),
+			GasFeeCap: big.NewInt(30e9),
+			Gas:       200_000,
+			To:        &to,
+			Value:     value,
+			Data:      common.FromHex("0xa0712d68"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		blockNumber := big.NewInt(int64(18_000_000 + idx/100))
+
+		for logIdx, txLog := range logs {
+			txLog.TxHash = tx.Hash()
+			txLog.BlockNumber = blockNumber.Uint64()
+			txLog.Index = uint(logIdx)
+		}
+
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 120_000,
+			Logs:              logs,
+			TxHash:            tx.Hash(),
+			GasUsed:           120_000,
+			EffectiveGasPrice: big.NewInt(20e9),
+			BlockNumber:       blockNumber,
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		entry, err := newEntry(chainID.Uint64(), tx, receipt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func transferLog(contractAddress common.Address, from common.Address, to common.Address, tokenID *big.Int) *types.Log {
+	return &types.Log{
+		Address: contractAddress,
+		Topics: []common.Hash{
+			common.HexToHash(string(topic.Transfer)),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+			common.BigToHash(tokenID),
+		},
+		Data: []byte{},
+	}
+}
+
+func syntheticAddress(kind string, idx int) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte(fmt.Sprintf("bench %s %d", kind, idx))))
+}
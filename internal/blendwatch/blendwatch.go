@@ -0,0 +1,193 @@
+package blendwatch
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/collections"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/notify"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var (
+	topicLoanOfferTaken = common.HexToHash(string(topic.LoanOfferTaken))
+	topicRefinance      = common.HexToHash(string(topic.Refinance))
+	topicRepay          = common.HexToHash(string(topic.Repay))
+	topicStartAuction   = common.HexToHash(string(topic.StartAuction))
+	topicSeize          = common.HexToHash(string(topic.Seize))
+)
+
+var blendAlertsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_blend_alerts_total",
+	Help: "The number of blend auctions & seizures of loans with collateral from own collections.",
+}, []string{"event"})
+
+// lien is a blend loan with collateral from an own collection seen since the start.
+type lien struct {
+	tokenID   *big.Int
+	principal *big.Int
+	inAuction bool
+}
+
+var (
+	// liens of own collections by lien id, removed on repayment or seizure
+	liens   = make(map[string]*lien)
+	liensMu sync.Mutex
+
+	// start times of the recent auctions per collection
+	auctions   = make(map[common.Address][]time.Time)
+	auctionsMu sync.Mutex
+)
+
+// HandleReceipt checks the logs of a tx for blend auctions & seizures of loans with
+// collateral from own collections and raises an alert.
+func HandleReceipt(gb *gloomberg.Gloomberg, receipt *types.Receipt) {
+	if receipt == nil || !viper.GetBool("blendwatch.enabled") {
+		return
+	}
+
+	for _, txLog := range receipt.Logs {
+		// all blend events start with lienId, collection
+		if txLog.Address != internal.BlurBlendContractAddress || len(txLog.Topics) == 0 || len(txLog.Data) < 2*32 {
+			continue
+		}
+
+		collectionAddress := common.BytesToAddress(txLog.Data[32:64])
+
+		gb.CollectionDB.RWMu.RLock()
+		collection, ok := gb.CollectionDB.Collections[collectionAddress]
+		gb.CollectionDB.RWMu.RUnlock()
+
+		if !ok || collection == nil || (collection.Source != degendb.FromWallet && collection.Source != degendb.FromConfiguration) {
+			continue
+		}
+
+		switch txLog.Topics[0] {
+		case topicLoanOfferTaken, topicRefinance, topicRepay:
+			trackLien(txLog)
+
+		case topicStartAuction:
+			l := updateLien(txLog, true)
+			numAuctions := countAuction(collectionAddress)
+
+			alert(collection, txLog, "auction started", l, numAuctions)
+
+		case topicSeize:
+			l := updateLien(txLog, false)
+
+			alert(collection, txLog, "collateral seized", l, 0)
+		}
+	}
+}
+
+// trackLien keeps the token & principal of new or refinanced loans to enrich later alerts.
+func trackLien(txLog *types.Log) {
+	lienID := new(big.Int).SetBytes(txLog.Data[:32]).String()
+
+	liensMu.Lock()
+	defer liensMu.Unlock()
+
+	switch txLog.Topics[0] {
+	case topicLoanOfferTaken:
+		if len(txLog.Data) >= 9*32 {
+			liens[lienID] = &lien{tokenID: new(big.Int).SetBytes(txLog.Data[7*32 : 8*32]), principal: new(big.Int).SetBytes(txLog.Data[5*32 : 6*32])}
+		}
+
+	case topicRefinance:
+		// a refinance also ends a running auction
+		if l, ok := liens[lienID]; ok && len(txLog.Data) >= 6*32 {
+			l.principal = new(big.Int).SetBytes(txLog.Data[3*32 : 4*32])
+			l.inAuction = false
+		}
+
+	case topicRepay:
+		delete(liens, lienID)
+	}
+}
+
+// updateLien returns the known lien of the log & marks it as in auction or removes it after a seizure.
+func updateLien(txLog *types.Log, inAuction bool) *lien {
+	lienID := new(big.Int).SetBytes(txLog.Data[:32]).String()
+
+	liensMu.Lock()
+	defer liensMu.Unlock()
+
+	l, ok := liens[lienID]
+	if !ok {
+		return nil
+	}
+
+	if inAuction {
+		l.inAuction = true
+	} else {
+		delete(liens, lienID)
+	}
+
+	return l
+}
+
+// countAuction records an auction of the collection & returns the number of its auctions within blendwatch.wave_window.
+func countAuction(collectionAddress common.Address) int {
+	auctionsMu.Lock()
+	defer auctionsMu.Unlock()
+
+	recent := make([]time.Time, 0, len(auctions[collectionAddress])+1)
+
+	for _, startedAt := range auctions[collectionAddress] {
+		if time.Since(startedAt) < viper.GetDuration("blendwatch.wave_window") {
+			recent = append(recent, startedAt)
+		}
+	}
+
+	auctions[collectionAddress] = append(recent, time.Now())
+
+	return len(auctions[collectionAddress])
+}
+
+func alert(collection *collections.Collection, txLog *types.Log, event string, l *lien, numAuctions int) {
+	blendAlertsCounter.WithLabelValues(topic.Topic(txLog.Topics[0].Hex()).String()).Inc()
+
+	details := make([]string, 0, 2)
+
+	if l != nil {
+		details = append(details, fmt.Sprintf("#%s", l.tokenID), fmt.Sprintf("%.4fΞ principal", price.NewPrice(l.principal).Ether()))
+	}
+
+	if numAuctions > 1 {
+		details = append(details, fmt.Sprintf("%d auctions in %s", numAuctions, viper.GetDuration("blendwatch.wave_window")))
+	}
+
+	fmtDetails := ""
+	if len(details) > 0 {
+		fmtDetails = " | " + strings.Join(details, " · ")
+	}
+
+	gloomberg.PrModf("blend", "%s %s%s | %s",
+		collection.Style().Render(collection.Name),
+		style.BoldAlmostWhite(event),
+		fmtDetails,
+		style.TerminalLink(utils.GetEtherscanTxURL(txLog.TxHash.Hex()), style.ShortenHashStyled(txLog.TxHash)),
+	)
+
+	if viper.GetBool("notifications.telegram.enabled") {
+		message := strings.Builder{}
+		message.WriteString(fmt.Sprintf("🪓 *%s* blend %s%s\n", collection.Name, event, strings.ReplaceAll(fmtDetails, " | ", " · ")))
+		message.WriteString(fmt.Sprintf("[tx](%s)", utils.GetEtherscanTxURL(txLog.TxHash.Hex())))
+
+		go notify.SendMessageViaTelegram(message.String(), viper.GetInt64("blendwatch.telegram_chat_id"), "", 0, nil)
+	}
+}
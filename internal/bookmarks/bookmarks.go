@@ -0,0 +1,133 @@
+package bookmarks
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrDisabled  = errors.New("bookmarks are disabled or no mongodb.uri is configured")
+	ErrNoEvents  = errors.New("no events printed yet")
+	ErrNoDegenDB = errors.New("degendb not reachable")
+)
+
+var (
+	// the latest printed events, newest last
+	recent   = make([]*degendb.PreformattedEvent, 0)
+	recentMu sync.RWMutex
+
+	// bookmarks are stored in degendb, connected on the first bookmark
+	ddb     *degendb.DegenDB
+	ddbOnce sync.Once
+)
+
+// Enabled returns true if events can be bookmarked.
+func Enabled() bool {
+	return viper.GetBool("bookmarks.enabled") && viper.GetString("mongodb.uri") != ""
+}
+
+// Remember keeps a printed event so it can be bookmarked by its tx hash or as the latest event.
+func Remember(event *degendb.PreformattedEvent) {
+	if event == nil || !Enabled() {
+		return
+	}
+
+	size := max(viper.GetInt("bookmarks.recent"), 1)
+
+	recentMu.Lock()
+	defer recentMu.Unlock()
+
+	recent = append(recent, event)
+	if len(recent) > size {
+		recent = recent[len(recent)-size:]
+	}
+}
+
+// AddLatest bookmarks the latest printed event.
+func AddLatest(note string) (*degendb.Bookmark, error) {
+	recentMu.RLock()
+
+	if len(recent) == 0 {
+		recentMu.RUnlock()
+
+		return nil, ErrNoEvents
+	}
+
+	latest := recent[len(recent)-1]
+
+	recentMu.RUnlock()
+
+	return save(newBookmark(latest, note))
+}
+
+// Add bookmarks the event with the given tx hash. events no longer in the recent
+// events are bookmarked with their tx hash only.
+func Add(txHash common.Hash, note string) (*degendb.Bookmark, error) {
+	bookmark := &degendb.Bookmark{TxHash: txHash.Hex(), Note: note}
+
+	recentMu.RLock()
+
+	for i := len(recent) - 1; i >= 0; i-- {
+		if recent[i].TxHash == txHash {
+			bookmark = newBookmark(recent[i], note)
+
+			break
+		}
+	}
+
+	recentMu.RUnlock()
+
+	return save(bookmark)
+}
+
+func save(bookmark *degendb.Bookmark) (*degendb.Bookmark, error) {
+	if !Enabled() {
+		return nil, ErrDisabled
+	}
+
+	ddbOnce.Do(func() { ddb = degendb.NewDegenDB() })
+
+	if ddb == nil {
+		return nil, ErrNoDegenDB
+	}
+
+	if err := ddb.SetBookmark(context.Background(), bookmark); err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+func newBookmark(event *degendb.PreformattedEvent, note string) *degendb.Bookmark {
+	collections := make([]string, 0, len(event.TransferredCollections))
+	for _, collection := range event.TransferredCollections {
+		collections = append(collections, collection.CollectionName)
+	}
+
+	bookmark := &degendb.Bookmark{
+		TxHash:      event.TxHash.Hex(),
+		Action:      event.Action,
+		Collections: collections,
+		Note:        note,
+		EventAt:     event.ReceivedAt,
+	}
+
+	if event.Price != nil {
+		bookmark.Price = event.Price.Ether()
+	}
+
+	if event.FromAddress != (common.Address{}) {
+		bookmark.From = event.FromAddress.Hex()
+	}
+
+	if event.ToAddress != (common.Address{}) {
+		bookmark.To = event.ToAddress.Hex()
+	}
+
+	return bookmark
+}
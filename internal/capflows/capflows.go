@@ -0,0 +1,221 @@
+package capflows
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// maximum number of blocks fetched per check.
+const maxBlocksPerCheck = 5
+
+// Flow is a deposit of eth/weth from an own wallet to an exchange or a withdrawal from an exchange.
+type Flow struct {
+	Type     degendb.EventType
+	Wallet   common.Address
+	Exchange *degendb.Address
+
+	Value  *big.Int
+	Token  string
+	TxHash common.Hash
+}
+
+var (
+	// exchange addresses from the degendb address labels & capflows.exchanges.
+	exchanges   = make(map[common.Address]*degendb.Address)
+	exchangesMu sync.RWMutex
+)
+
+// Start sets the exchange addresses from the given labels & watches new blocks for eth/weth
+// moved between the own wallets & exchanges.
+func Start(gb *gloomberg.Gloomberg, labels map[common.Address]*degendb.Address) {
+	exchangesMu.Lock()
+
+	for address, label := range labels {
+		if label.HasTag(degendb.TagExchange, degendb.TagExchangeDeposit) {
+			exchanges[address] = label
+		}
+	}
+
+	for name, address := range viper.GetStringMapString("capflows.exchanges") {
+		exchanges[common.HexToAddress(address)] = &degendb.Address{Address: common.HexToAddress(address), Name: name, Tags: []degendb.Tag{degendb.TagExchange}}
+	}
+
+	numExchanges := len(exchanges)
+
+	exchangesMu.Unlock()
+
+	if numExchanges == 0 || gb.OwnWallets == nil || len(*gb.OwnWallets) == 0 {
+		gbl.Log.Warn("🏦 no exchange addresses or own wallets found, capital flow tracking disabled")
+
+		return
+	}
+
+	gloomberg.PrModf("capflows", "watching deposits & withdrawals between own wallets & %s exchange addresses", style.AlmostWhiteStyle.Render(fmt.Sprint(numExchanges)))
+
+	go watchBlocks(gb)
+}
+
+// watchBlocks checks the txs & weth transfers of new blocks for flows between own wallets & exchanges.
+func watchBlocks(gb *gloomberg.Gloomberg) {
+	var lastBlock uint64
+
+	ticker := time.NewTicker(internal.BlockTime)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), internal.BlockTime)
+
+		headBlock, err := gb.ProviderPool.BlockNumber(ctx)
+		if err != nil || lastBlock == 0 || headBlock <= lastBlock {
+			if err == nil {
+				lastBlock = headBlock
+			}
+
+			cancel()
+
+			continue
+		}
+
+		// catch up at most a few blocks to avoid hammering the nodes after downtimes
+		fromBlock := max(lastBlock+1, headBlock-maxBlocksPerCheck+1)
+
+		for blockNumber := fromBlock; blockNumber <= headBlock; blockNumber++ {
+			checkBlock(ctx, gb, blockNumber)
+		}
+
+		checkWETHTransfers(ctx, gb, fromBlock, headBlock)
+
+		lastBlock = headBlock
+
+		cancel()
+	}
+}
+
+// checkBlock checks the eth transfers in the block.
+func checkBlock(ctx context.Context, gb *gloomberg.Gloomberg, blockNumber uint64) {
+	block, err := gb.ProviderPool.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		gbl.Log.Debugf("🏦 failed to get block %d: %s", blockNumber, err)
+
+		return
+	}
+
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || tx.Value().Sign() == 0 {
+			continue
+		}
+
+		sender, err := types.LatestSignerForChainID(tx.ChainId()).Sender(tx)
+		if err != nil {
+			continue
+		}
+
+		if flow := classify(gb, sender, *tx.To()); flow != nil {
+			flow.Value, flow.Token, flow.TxHash = tx.Value(), "ETH", tx.Hash()
+			add(gb, flow)
+		}
+	}
+}
+
+// checkWETHTransfers checks the weth transfers from & to the own wallets in the block range.
+func checkWETHTransfers(ctx context.Context, gb *gloomberg.Gloomberg, fromBlock uint64, toBlock uint64) {
+	walletTopics := make([]common.Hash, 0, len(*gb.OwnWallets))
+	for _, address := range gb.OwnWallets.Addresses() {
+		walletTopics = append(walletTopics, common.BytesToHash(address.Bytes()))
+	}
+
+	transferTopic := []common.Hash{common.HexToHash(string(topic.Transfer))}
+
+	// sent by & sent to own wallets
+	for _, topics := range [][][]common.Hash{{transferTopic, walletTopics}, {transferTopic, nil, walletTopics}} {
+		logs, err := gb.ProviderPool.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Addresses: []common.Address{internal.WETHContractAddress},
+			Topics:    topics,
+		})
+		if err != nil {
+			gbl.Log.Debugf("🏦 failed to get weth transfers: %s", err)
+
+			continue
+		}
+
+		for _, txLog := range logs {
+			if len(txLog.Topics) < 3 || len(txLog.Data) < 32 {
+				continue
+			}
+
+			if flow := classify(gb, common.BytesToAddress(txLog.Topics[1].Bytes()), common.BytesToAddress(txLog.Topics[2].Bytes())); flow != nil {
+				flow.Value, flow.Token, flow.TxHash = new(big.Int).SetBytes(txLog.Data[:32]), "WETH", txLog.TxHash
+				add(gb, flow)
+			}
+		}
+	}
+}
+
+// classify returns a deposit if from is an own wallet & to an exchange, a withdrawal if it is the other way around.
+func classify(gb *gloomberg.Gloomberg, from common.Address, to common.Address) *Flow {
+	exchangesMu.RLock()
+	defer exchangesMu.RUnlock()
+
+	if exchange, ok := exchanges[to]; ok && isOwnWallet(gb, from) {
+		return &Flow{Type: degendb.Deposit, Wallet: from, Exchange: exchange}
+	}
+
+	if exchange, ok := exchanges[from]; ok && isOwnWallet(gb, to) {
+		return &Flow{Type: degendb.Withdrawal, Wallet: to, Exchange: exchange}
+	}
+
+	return nil
+}
+
+func add(gb *gloomberg.Gloomberg, flow *Flow) {
+	if gb.Stats != nil {
+		gb.Stats.AddCapitalFlow(flow.Type, flow.Value)
+	}
+
+	exchange := flow.Exchange.Name
+	if exchange == "" {
+		exchange = style.ShortenAdressPTR(&flow.Exchange.Address)
+	}
+
+	direction := "→"
+	if flow.Type == degendb.Withdrawal {
+		direction = "←"
+	}
+
+	walletName := style.ShortenAdressPTR(&flow.Wallet)
+	if w := (*gb.OwnWallets)[flow.Wallet]; w != nil && w.Name != "" {
+		walletName = w.Name
+	}
+
+	gloomberg.PrModf("capflows", "%s %s %s %s %s %s | %s",
+		flow.Type.Icon(),
+		style.BoldAlmostWhite(walletName),
+		flow.Type.ActionName(),
+		style.BoldAlmostWhite(fmt.Sprintf("%.3f %s", price.NewPrice(flow.Value).Ether(), flow.Token)),
+		direction,
+		style.AlmostWhiteStyle.Render(exchange),
+		style.TerminalLink(links.Tx(flow.TxHash), style.ShortenHashStyled(flow.TxHash)),
+	)
+}
+
+func isOwnWallet(gb *gloomberg.Gloomberg, address common.Address) bool {
+	return gb.OwnWallets != nil && gb.OwnWallets.ContainsAddressFromSlice([]common.Address{address}) != internal.ZeroAddress
+}
@@ -0,0 +1,95 @@
+package chains
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Chain is an evm chain gloomberg can watch.
+type Chain struct {
+	ID   uint64
+	Name string
+
+	// shown in front of the events of the chain
+	Short string
+	Color lipgloss.Color
+
+	// symbol of the native currency
+	Symbol string
+
+	// block explorer base url & the chain name used in opensea urls
+	Explorer string
+	OpenSea  string
+}
+
+var (
+	Ethereum = &Chain{ID: 1, Name: "ethereum", Short: "eth", Color: lipgloss.Color("#627eea"), Symbol: "Ξ", Explorer: "https://etherscan.io", OpenSea: "ethereum"}
+	Base     = &Chain{ID: 8453, Name: "base", Short: "base", Color: lipgloss.Color("#0052ff"), Symbol: "Ξ", Explorer: "https://basescan.org", OpenSea: "base"}
+	Arbitrum = &Chain{ID: 42161, Name: "arbitrum", Short: "arb", Color: lipgloss.Color("#28a0f0"), Symbol: "Ξ", Explorer: "https://arbiscan.io", OpenSea: "arbitrum"}
+	Optimism = &Chain{ID: 10, Name: "optimism", Short: "op", Color: lipgloss.Color("#ff0420"), Symbol: "Ξ", Explorer: "https://optimistic.etherscan.io", OpenSea: "optimism"}
+	Polygon  = &Chain{ID: 137, Name: "polygon", Short: "poly", Color: lipgloss.Color("#8247e5"), Symbol: "POL", Explorer: "https://polygonscan.com", OpenSea: "matic"}
+
+	all = []*Chain{Ethereum, Base, Arbitrum, Optimism, Polygon}
+)
+
+// ByID returns the chain with the given id, mainnet for 0 (events without chain id) & nil if unknown.
+func ByID(chainID uint64) *Chain {
+	if chainID == 0 {
+		return Ethereum
+	}
+
+	for _, chain := range all {
+		if chain.ID == chainID {
+			return chain
+		}
+	}
+
+	return nil
+}
+
+// ByName returns the chain with the given name or nil if unknown.
+func ByName(name string) *Chain {
+	for _, chain := range all {
+		if chain.Name == strings.ToLower(name) {
+			return chain
+		}
+	}
+
+	return nil
+}
+
+// IsMainnet returns true for ethereum mainnet & events without chain id.
+func IsMainnet(chainID uint64) bool {
+	return chainID == 0 || chainID == Ethereum.ID
+}
+
+// Enabled returns the additional chains configured & enabled via chains.<name>.enabled.
+func Enabled() []*Chain {
+	enabled := make([]*Chain, 0)
+
+	for _, chain := range all[1:] {
+		if viper.GetBool("chains." + chain.Name + ".enabled") {
+			enabled = append(enabled, chain)
+		}
+	}
+
+	return enabled
+}
+
+// Tag returns the short name of the chain in its color.
+func (c *Chain) Tag() string {
+	return lipgloss.NewStyle().Foreground(c.Color).Render(c.Short)
+}
+
+// TxURL returns the block explorer url of the transaction.
+func (c *Chain) TxURL(txHash common.Hash) string {
+	return c.Explorer + "/tx/" + txHash.Hex()
+}
+
+// AddressURL returns the block explorer url of the address.
+func (c *Chain) AddressURL(address common.Address) string {
+	return c.Explorer + "/address/" + address.Hex()
+}
@@ -2,18 +2,23 @@ package chawago
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/benleb/gloomberg/internal/chains"
 	"github.com/benleb/gloomberg/internal/chawago/models"
+	"github.com/benleb/gloomberg/internal/health"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/provider"
 	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils/ttlset"
 	"github.com/charmbracelet/log"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
 )
 
 var numWorkersRawLogs = 4
@@ -35,94 +40,150 @@ var txReceivedCounter = promauto.NewCounter(prometheus.CounterOpts{
 	Help: "The number of received transactions from the chain.",
 })
 
+var knownTransactionsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gloomberg_chainwatcher_known_transactions",
+	Help: "The number of tx hashes remembered to skip already processed transactions.",
+}, []string{"source"})
+
+// newKnownTransactions returns the bounded set of processed tx hashes, sized via chain.dedup.
+func newKnownTransactions() *ttlset.Set[common.Hash] {
+	return ttlset.New[common.Hash](viper.GetInt("chain.dedup.size"), viper.GetDuration("chain.dedup.ttl"))
+}
+
 // GetTransactionsForLogs utilizes the providerPool to fetch the transaction & receipt for logs from qRawLogs.
 // The transaction with the receipt is then sent to qTxsWithLogs.
-func GetTransactionsForLogs(gb *gloomberg.Gloomberg, qRawLogs chan types.Log) chan *models.TxWithLogs {
-	return GetTransactionsForLogsWithChannel(gb, qRawLogs, make(chan *models.TxWithLogs, 10240))
+// If the context is canceled, the workers handle the logs still queued & call wg.Done (wg is optional).
+func GetTransactionsForLogs(ctx context.Context, gb *gloomberg.Gloomberg, qRawLogs chan types.Log, wg *sync.WaitGroup) chan *models.TxWithLogs {
+	return getTransactionsForLogs(ctx, gb, 0, qRawLogs, make(chan *models.TxWithLogs, 10240), wg)
 }
 
 func GetTransactionsForLogsWithChannel(gb *gloomberg.Gloomberg, qRawLogs chan types.Log, qTxsWithLogs chan *models.TxWithLogs) chan *models.TxWithLogs {
-	knownTransactions := make(map[common.Hash]bool)
-	knownTransactionsMu := &sync.RWMutex{}
+	return getTransactionsForLogs(context.Background(), gb, 0, qRawLogs, qTxsWithLogs, nil)
+}
+
+// GetTransactionsForChainLogs fetches the transactions & receipts for logs of another chain (l2) via its provider pool.
+// the transactions are tagged with the chain id.
+func GetTransactionsForChainLogs(ctx context.Context, gb *gloomberg.Gloomberg, chainID uint64, qRawLogs chan types.Log, wg *sync.WaitGroup) chan *models.TxWithLogs {
+	return getTransactionsForLogs(ctx, gb, chainID, qRawLogs, make(chan *models.TxWithLogs, 10240), wg)
+}
+
+func getTransactionsForLogs(ctx context.Context, gb *gloomberg.Gloomberg, chainID uint64, qRawLogs chan types.Log, qTxsWithLogs chan *models.TxWithLogs, wg *sync.WaitGroup) chan *models.TxWithLogs {
+	providerPool := gb.PoolFor(chainID)
+	isMainnet := chains.IsMainnet(chainID)
+
+	knownTransactions := newKnownTransactions()
+	source := fmt.Sprint(chainID)
+	if chain := chains.ByID(chainID); chain != nil {
+		source = chain.Name
+	}
+
+	knownTransactionsOccupancy := knownTransactionsGauge.WithLabelValues(source)
 
 	if qTxsWithLogs == nil {
 		qTxsWithLogs = make(chan *models.TxWithLogs, 10240)
 	}
 
-	// handle received logs
-	for workerID := 1; workerID <= numWorkersRawLogs; workerID++ {
-		log.Debugf("starting rawLogs worker %d", workerID)
+	// queued logs are still fetched while shutting down
+	fetchCtx := context.WithoutCancel(ctx)
 
-		go func() {
-			for rawLog := range qRawLogs {
-				logsReceivedCounter.Inc()
+	handleLog := func(rawLog types.Log) {
+		logsReceivedCounter.Inc()
 
-				// skip if we already processed this logs tx
-				knownTransactionsMu.Lock()
-				known, ok := knownTransactions[rawLog.TxHash]
-				knownTransactionsMu.Unlock()
+		// skip if we already processed this logs tx. check & mark in one step, otherwise two
+		// workers receiving logs of the same tx could both fetch it & emit it twice.
+		// all logs of the tx are taken from its receipt below, so one event per tx is enough.
+		known := !knownTransactions.Add(rawLog.TxHash)
+		knownTransactionsOccupancy.Set(float64(knownTransactions.Len()))
 
-				if known && ok {
-					// we already know this transaction
-					log.Debugf("❕ already known log/transaction: %s", style.BoldStyle.Render(rawLog.TxHash.String()))
+		if known {
+			// we already know this transaction
+			log.Debugf("❕ already known log/transaction: %s", style.BoldStyle.Render(rawLog.TxHash.String()))
 
-					continue
-				}
+			return
+		}
 
-				knownTransactionsMu.Lock()
-				knownTransactions[rawLog.TxHash] = true
-				knownTransactionsMu.Unlock()
+		log.Debugf("🪵 %#v", rawLog)
 
-				log.Debugf("🪵 %#v", rawLog)
+		// block numbers of other chains are unrelated to mainnet blocks
+		if isMainnet && rawLog.BlockNumber > gb.CurrentBlock {
+			gb.CurrentBlock = rawLog.BlockNumber
+			gb.In.NewBlock <- gb.CurrentBlock
+		}
 
-				if rawLog.BlockNumber > gb.CurrentBlock {
-					gb.CurrentBlock = rawLog.BlockNumber
-					gb.In.NewBlock <- gb.CurrentBlock
-				}
+		// fetch the full transaction this log belongs to
+		tx, err := providerPool.TransactionByHash(fetchCtx, rawLog.TxHash)
+		if err != nil {
+			log.Printf("❌ getting %s failed: %s", style.TerminalLink("https://etherscan.io/tx/"+rawLog.TxHash.String(), "transaction"), err)
 
-				// fetch the full transaction this log belongs to
-				tx, err := gb.ProviderPool.TransactionByHash(context.Background(), rawLog.TxHash)
-				if err != nil {
-					log.Printf("❌ getting %s failed: %s", style.TerminalLink("https://etherscan.io/tx/"+rawLog.TxHash.String(), "transaction"), err)
+			return
+		} else if tx == nil {
+			log.Printf("❌ %s is nil", style.TerminalLink("https://etherscan.io/tx/"+rawLog.TxHash.String(), "transaction"))
 
-					continue
-				} else if tx == nil {
-					log.Printf("❌ %s is nil", style.TerminalLink("https://etherscan.io/tx/"+rawLog.TxHash.String(), "transaction"))
+			return
+		}
 
-					continue
-				}
+		log.Debugf("📝 %s", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), "transaction"))
 
-				log.Debugf("📝 %s", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), "transaction"))
+		// fetch the receipt to get all logs for this transaction
+		receipt, err := providerPool.TransactionReceipt(fetchCtx, tx.Hash())
+		if err != nil {
+			log.Printf("❗️ error getting %s receipt: %s", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), "transaction"), err)
 
-				// fetch the receipt to get all logs for this transaction
-				receipt, err := gb.ProviderPool.TransactionReceipt(context.Background(), tx.Hash())
-				if err != nil {
-					log.Printf("❗️ error getting %s receipt: %s", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), "transaction"), err)
+			return
+		} else if receipt == nil {
+			log.Printf("❗️ %s receipt is nil", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), "transaction"))
 
-					continue
-				} else if receipt == nil {
-					log.Printf("❗️ %s receipt is nil", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), "transaction"))
+			return
+		}
 
-					continue
-				}
+		// queue lengths
+		log.Debugf("qLogs: %d  |  qTxsWithLogs: %d", len(qRawLogs), len(qTxsWithLogs))
 
-				// queue lengths
-				log.Debugf("qLogs: %d  |  qTxsWithLogs: %d", len(qRawLogs), len(qTxsWithLogs))
+		// output TxWithLogs
+		txWithLogs := &models.TxWithLogs{
+			Transaction: tx,
+			Receipt:     receipt,
+			ChainID:     chainID,
+		}
 
-				// output TxWithLogs
-				txWithLogs := &models.TxWithLogs{
-					Transaction: tx,
-					Receipt:     receipt,
-				}
+		// qTxsWithLogs <- txWithLogs
 
-				// qTxsWithLogs <- txWithLogs
+		gb.In.TxWithLogs <- txWithLogs
 
-				gb.In.TxWithLogs <- txWithLogs
+		txReceivedCounter.Inc()
 
-				txReceivedCounter.Inc()
+		// update last log received at timestamp to detect stalled providers
+		providerPool.LastLogReceivedAt = time.Now()
+		health.EventReceived(health.SourceChain)
+	}
 
-				// update last log received at timestamp to detect stalled providers
-				gb.ProviderPool.LastLogReceivedAt = time.Now()
+	// handle received logs
+	for workerID := 1; workerID <= numWorkersRawLogs; workerID++ {
+		log.Debugf("starting rawLogs worker %d", workerID)
+
+		if wg != nil {
+			wg.Add(1)
+		}
+
+		go func() {
+			if wg != nil {
+				defer wg.Done()
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					Drain(qRawLogs, handleLog)
+
+					return
+
+				case rawLog, ok := <-qRawLogs:
+					if !ok {
+						return
+					}
+
+					handleLog(rawLog)
+				}
 			}
 		}()
 	}
@@ -133,8 +194,8 @@ func GetTransactionsForLogsWithChannel(gb *gloomberg.Gloomberg, qRawLogs chan ty
 // GetPendingTransactions utilizes the providerPool to fetch the transaction & receipt for logs from qRawLogs.
 // The transaction with the receipt is then sent to qTxsWithLogs.
 func GetPendingTransactions(qPendingTx chan *types.Transaction, qTxsWithLogs chan *models.TxWithLogs, providerPool *provider.Pool) {
-	knownTransactions := make(map[common.Hash]bool)
-	knownTransactionsMu := &sync.RWMutex{}
+	knownTransactions := newKnownTransactions()
+	knownTransactionsOccupancy := knownTransactionsGauge.WithLabelValues("pending")
 
 	// handle received logs
 	for workerID := 1; workerID <= numWorkersRawLogs; workerID++ {
@@ -143,21 +204,16 @@ func GetPendingTransactions(qPendingTx chan *types.Transaction, qTxsWithLogs cha
 		go func() {
 			for pendingTx := range qPendingTx {
 				// skip if we already processed this logs tx
-				knownTransactionsMu.Lock()
-				known, ok := knownTransactions[pendingTx.Hash()]
-				knownTransactionsMu.Unlock()
+				known := !knownTransactions.Add(pendingTx.Hash())
+				knownTransactionsOccupancy.Set(float64(knownTransactions.Len()))
 
-				if known && ok {
+				if known {
 					// we already know this transaction
 					log.Debugf("❕ already known log/transaction: %s", style.BoldStyle.Render(pendingTx.Hash().String()))
 
 					continue
 				}
 
-				knownTransactionsMu.Lock()
-				knownTransactions[pendingTx.Hash()] = true
-				knownTransactionsMu.Unlock()
-
 				// queue lengths
 				log.Debugf("qPendingTx: %d  |  qTxsWithLogs: %d", len(qPendingTx), len(qTxsWithLogs))
 
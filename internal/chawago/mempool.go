@@ -0,0 +1,146 @@
+package chawago
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/chawago/models"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/mintsigs"
+	"github.com/benleb/gloomberg/internal/nemo/abireg"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// WatchMempool subscribes to the pending transactions & prints the mints of watched contracts
+// the moment they hit the mempool. a tx is considered a mint if its selector was used by a
+// confirmed mint before (mintsigs), is configured in mempool.selectors or the verified abi
+// names it mint*/claim*.
+func WatchMempool(gb *gloomberg.Gloomberg) {
+	qPendingTx := make(chan *types.Transaction, 10240)
+	qPendingTxsWithLogs := make(chan *models.TxWithLogs, 10240)
+
+	subscribedTo, err := gb.ProviderPool.SubscribeToEverythingPending(qPendingTx)
+	if err != nil {
+		gbl.Log.Warnf("🔮 subscribing to pending transactions failed: %s", err)
+
+		return
+	}
+
+	gbl.Log.Infof("🔮 watching the mempool for mints via %d nodes", subscribedTo)
+
+	GetPendingTransactions(qPendingTx, qPendingTxsWithLogs, gb.ProviderPool)
+
+	selectors := mapset.NewSet[[4]byte]()
+
+	for _, selector := range viper.GetStringSlice("mempool.selectors") {
+		if decoded, err := hexutil.Decode(selector); err == nil && len(decoded) == 4 {
+			selectors.Add([4]byte(decoded))
+		}
+	}
+
+	for pendingTx := range qPendingTxsWithLogs {
+		handlePendingTx(gb, pendingTx.Transaction, selectors)
+	}
+}
+
+func handlePendingTx(gb *gloomberg.Gloomberg, tx *types.Transaction, selectors mapset.Set[[4]byte]) {
+	if tx == nil || tx.To() == nil || len(tx.Data()) < 4 {
+		return
+	}
+
+	contractAddress := *tx.To()
+
+	if !isWatchedContract(gb, contractAddress) {
+		return
+	}
+
+	selector := [4]byte(tx.Data()[:4])
+
+	label, isMint := mintLabel(gb, contractAddress, selector, selectors)
+	if !isMint {
+		return
+	}
+
+	name := style.ShortenAddress(contractAddress)
+
+	gb.CollectionDB.RWMu.RLock()
+	if collection, ok := gb.CollectionDB.Collections[contractAddress]; ok && collection.Name != "" {
+		name = collection.Render(collection.Name)
+	}
+	gb.CollectionDB.RWMu.RUnlock()
+
+	fmtValue := "free"
+	if tx.Value() != nil && tx.Value().Sign() > 0 {
+		fmtValue = fmt.Sprintf("%.4fΞ", utils.WeiToEther(tx.Value()))
+	}
+
+	from := "?"
+	if sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		from = style.TerminalLink(links.Profile(sender), style.ShortenAddress(sender))
+	}
+
+	gloomberg.PrModf("mempool", "pending mint %s %s · %s by %s · tip %s gwei | %s",
+		name,
+		style.AlmostWhiteStyle.Render(label),
+		style.BoldAlmostWhite(fmtValue),
+		from,
+		style.AlmostWhiteStyle.Render(fmt.Sprintf("%.1f", utils.WeiToGwei(tx.GasTipCap()))),
+		style.TerminalLink(links.Tx(tx.Hash()), style.ShortenHashStyled(tx.Hash())),
+	)
+}
+
+// isWatchedContract returns true for own collections (wallets/config) & the contracts in mempool.contracts.
+func isWatchedContract(gb *gloomberg.Gloomberg, contractAddress common.Address) bool {
+	if viper.GetBool("mempool.all_contracts") {
+		return true
+	}
+
+	for _, address := range viper.GetStringSlice("mempool.contracts") {
+		if common.HexToAddress(address) == contractAddress {
+			return true
+		}
+	}
+
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	collection, ok := gb.CollectionDB.Collections[contractAddress]
+
+	return ok && collection.IsOwn()
+}
+
+// mintLabel returns the signature (or selector) of the called function if it is a mint function.
+func mintLabel(gb *gloomberg.Gloomberg, contractAddress common.Address, selector [4]byte, selectors mapset.Set[[4]byte]) (string, bool) {
+	if isMint, label := mintsigs.IsMintSelector(selector); isMint {
+		return label, true
+	}
+
+	if selectors.Contains(selector) {
+		return hexutil.Encode(selector[:]), true
+	}
+
+	// abi lookups for every contract would be way too many with mempool.all_contracts
+	if !abireg.Enabled() || viper.GetBool("mempool.all_contracts") {
+		return "", false
+	}
+
+	signature, err := gb.ABIs.MethodSignature(context.Background(), contractAddress, selector[:])
+	if err != nil {
+		return "", false
+	}
+
+	if name := strings.ToLower(signature); strings.HasPrefix(name, "mint") || strings.HasPrefix(name, "claim") {
+		return signature, true
+	}
+
+	return "", false
+}
@@ -12,6 +12,9 @@ type TxWithLogs struct {
 	*types.Transaction
 	*types.Receipt
 	Pending bool
+
+	// chain the tx was sent on, 0 for mainnet
+	ChainID uint64
 }
 
 // getTxMessage is used to get the From field of a transaction.
@@ -0,0 +1,202 @@
+package chawago
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/style"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// number of blocks the block hashes & emitted txs are kept to detect reorgs.
+const reorgDepth = 64
+
+type reorgWatcher struct {
+	gb *gloomberg.Gloomberg
+
+	confirmations uint64
+	head          uint64
+
+	// latest seen hash per block number
+	hashes map[uint64]common.Hash
+	// emitted txs per block number -> tx hash -> block hash
+	emitted map[uint64]map[common.Hash]common.Hash
+	// logs waiting for the configured number of confirmations
+	pending map[uint64][]types.Log
+
+	// block hashes already reported as replaced
+	replaced mapset.Set[common.Hash]
+}
+
+// WatchReorgs tracks the block hashes of the logs from qLogs & reports events from blocks that were replaced
+// by a reorg. with chain.confirmations > 0, logs are held back until their block got the number of
+// confirmations & logs from replaced blocks are dropped before they are emitted.
+func WatchReorgs(gb *gloomberg.Gloomberg, qLogs chan types.Log) chan types.Log {
+	confirmedLogs := make(chan types.Log, 10240)
+
+	rw := &reorgWatcher{
+		gb: gb,
+
+		confirmations: viper.GetUint64("chain.confirmations"),
+
+		hashes:   make(map[uint64]common.Hash),
+		emitted:  make(map[uint64]map[common.Hash]common.Hash),
+		pending:  make(map[uint64][]types.Log),
+		replaced: mapset.NewThreadUnsafeSet[common.Hash](),
+	}
+
+	if rw.confirmations > 0 {
+		gbl.Log.Infof("🔀 waiting for %d confirmations before processing logs", rw.confirmations)
+	}
+
+	go rw.run(qLogs, confirmedLogs)
+
+	return confirmedLogs
+}
+
+func (rw *reorgWatcher) run(qLogs chan types.Log, confirmedLogs chan types.Log) {
+	for txLog := range qLogs {
+		// logs of a block removed by a reorg are re-sent with removed set by the subscription
+		if txLog.Removed {
+			rw.blockReplaced(txLog.BlockNumber, txLog.BlockHash)
+
+			continue
+		}
+
+		if knownHash, ok := rw.hashes[txLog.BlockNumber]; ok && knownHash != txLog.BlockHash {
+			rw.blockReplaced(txLog.BlockNumber, knownHash)
+		}
+
+		rw.hashes[txLog.BlockNumber] = txLog.BlockHash
+
+		if rw.confirmations == 0 {
+			rw.emit(txLog, confirmedLogs)
+		} else {
+			rw.pending[txLog.BlockNumber] = append(rw.pending[txLog.BlockNumber], txLog)
+		}
+
+		if txLog.BlockNumber > rw.head {
+			rw.head = txLog.BlockNumber
+
+			rw.flush(confirmedLogs)
+			rw.prune()
+		}
+	}
+}
+
+func (rw *reorgWatcher) emit(txLog types.Log, confirmedLogs chan types.Log) {
+	if rw.emitted[txLog.BlockNumber] == nil {
+		rw.emitted[txLog.BlockNumber] = make(map[common.Hash]common.Hash)
+	}
+
+	rw.emitted[txLog.BlockNumber][txLog.TxHash] = txLog.BlockHash
+
+	confirmedLogs <- txLog
+}
+
+// flush emits the pending logs of all blocks with enough confirmations (in block order).
+// logs from blocks replaced in the meantime are dropped.
+func (rw *reorgWatcher) flush(confirmedLogs chan types.Log) {
+	confirmedBlocks := make([]uint64, 0, len(rw.pending))
+
+	for blockNumber := range rw.pending {
+		if blockNumber+rw.confirmations <= rw.head {
+			confirmedBlocks = append(confirmedBlocks, blockNumber)
+		}
+	}
+
+	slices.Sort(confirmedBlocks)
+
+	for _, blockNumber := range confirmedBlocks {
+		numDropped := 0
+
+		for _, txLog := range rw.pending[blockNumber] {
+			if txLog.BlockHash != rw.hashes[blockNumber] {
+				numDropped++
+
+				continue
+			}
+
+			rw.emit(txLog, confirmedLogs)
+		}
+
+		if numDropped > 0 {
+			gbl.Log.Debugf("🔀 dropped %d logs of replaced block %d", numDropped, blockNumber)
+		}
+
+		delete(rw.pending, blockNumber)
+	}
+}
+
+// prune removes the hashes & emitted txs of blocks older than reorgDepth.
+func (rw *reorgWatcher) prune() {
+	if rw.head <= reorgDepth {
+		return
+	}
+
+	for blockNumber := range rw.hashes {
+		if blockNumber < rw.head-reorgDepth {
+			delete(rw.hashes, blockNumber)
+			delete(rw.emitted, blockNumber)
+		}
+	}
+}
+
+// blockReplaced reports the replaced block & checks the already emitted txs from it.
+func (rw *reorgWatcher) blockReplaced(blockNumber uint64, blockHash common.Hash) {
+	if rw.replaced.Contains(blockHash) {
+		return
+	}
+
+	rw.replaced.Add(blockHash)
+
+	affectedTxs := make([]common.Hash, 0)
+
+	for txHash, emittedFrom := range rw.emitted[blockNumber] {
+		if emittedFrom == blockHash {
+			affectedTxs = append(affectedTxs, txHash)
+		}
+	}
+
+	if len(affectedTxs) == 0 {
+		gbl.Log.Debugf("🔀 block %d (%s) got replaced, no events affected", blockNumber, style.ShortenHashStyled(blockHash))
+
+		return
+	}
+
+	gloomberg.PrModf("reorg", "block %s got replaced by a reorg · checking %s events emitted from it",
+		style.BoldAlmostWhite(fmt.Sprint(blockNumber)),
+		style.AlmostWhiteStyle.Render(fmt.Sprint(len(affectedTxs))),
+	)
+
+	go verifyReorgedTxs(rw.gb, blockNumber, blockHash, affectedTxs)
+}
+
+// verifyReorgedTxs checks if the txs from a replaced block made it into the new chain & annotates the ones that did not.
+func verifyReorgedTxs(gb *gloomberg.Gloomberg, blockNumber uint64, blockHash common.Hash, txHashes []common.Hash) {
+	// give the new chain a moment to settle
+	time.Sleep(internal.BlockTime)
+
+	for _, txHash := range txHashes {
+		fmtTx := style.TerminalLink(links.Tx(txHash), style.ShortenHashStyled(txHash))
+
+		receipt, err := gb.ProviderPool.TransactionReceipt(context.Background(), txHash)
+
+		switch {
+		case err != nil || receipt == nil:
+			gloomberg.PrModf("reorg", "%s %s was removed from the chain by the reorg of block %d, its event did not happen",
+				style.PinkBoldStyle.Render("void"), fmtTx, blockNumber)
+		case receipt.BlockHash != blockHash:
+			gbl.Log.Debugf("🔀 %s re-included in block %d", txHash.Hex(), receipt.BlockNumber)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package chawago
+
+import "time"
+
+// time without new items after which a queue is considered drained.
+const drainIdleTimeout = 500 * time.Millisecond
+
+// Drain handles the items left in the queue until it stays empty for drainIdleTimeout or is closed.
+// used by workers to finish the queued events after their context got canceled.
+func Drain[T any](queue chan T, handle func(T)) {
+	for {
+		select {
+		case item, ok := <-queue:
+			if !ok {
+				return
+			}
+
+			handle(item)
+
+		case <-time.After(drainIdleTimeout):
+			return
+		}
+	}
+}
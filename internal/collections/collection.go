@@ -26,6 +26,8 @@ import (
 	"github.com/spf13/viper"
 )
 
+var errNoProvider = errors.New("no provider to get the collection name")
+
 type BaseCollection struct{}
 
 // Collection represents the collections configured by the user.
@@ -40,6 +42,7 @@ type Collection struct {
 	Show struct {
 		Sales     bool `mapstructure:"sales"`
 		Mints     bool `mapstructure:"mints"`
+		Burns     bool `mapstructure:"burns"`
 		Transfers bool `mapstructure:"transfers"`
 		Listings  bool `mapstructure:"listings"`
 		History   bool `mapstructure:"history"`
@@ -80,6 +83,9 @@ type Collection struct {
 	PreviousFloorPrice     float64             `mapstructure:"previousFloorPrice"`
 	HighestCollectionOffer float64
 
+	// active listings to estimate the floor
+	activeListings *listingBook
+
 	Raw *osmodels.AssetCollection
 }
 
@@ -94,36 +100,28 @@ func NewCollection(contractAddress common.Address, name string, nodes *provider.
 	case contractAddress == internal.ENSContractAddress, contractAddress == internal.ENSNameWrapperContractAddress:
 		collectionName = "ENS"
 	default:
-		name, err := rueidi.GetCachedContractName(ctx, contractAddress)
-
-		switch {
-		case errors.Is(err, nil):
-			gbl.Log.Debugf("cache | cached collection name: %s", name)
-
-			if name != "" {
-				collectionName = name
+		// concurrent lookups of the same (new) collection are coalesced into a single chain call
+		name, err := rueidi.ContractName(ctx, contractAddress, func(ctx context.Context) (string, error) {
+			if nodes == nil {
+				return "", errNoProvider
 			}
 
-		case nodes != nil:
-			if name, err := nodes.ERC721CollectionName(ctx, contractAddress); err == nil {
-				gbl.Log.Debugf("chain | collection name via chain call: %s", name)
+			return nodes.ERC721CollectionName(ctx, contractAddress)
+		})
 
-				if name != "" {
-					collectionName = name
-				}
+		switch {
+		case err == nil:
+			gbl.Log.Debugf("collection name: %s", name)
 
-				// cache collection name
-				// gbCache.CacheCollectionName(contractAddress, collectionName)
-				err := rueidi.StoreContractName(ctx, contractAddress, collectionName)
-				if err != nil {
-					gbl.Log.Errorf("error storing contract name: %s | %s", style.ShortenAdressPTR(&contractAddress), err)
-				}
-			}
+			collectionName = name
 
-		default:
+		case nodes == nil:
 			gbl.Log.Errorf("error getting collection name, using: %s | %s", style.ShortenAdressPTR(&contractAddress), err)
 
 			collectionName = style.ShortenAdressPTR(&contractAddress)
+
+		default:
+			gbl.Log.Debugf("error getting collection name via chain call: %s | %s", style.ShortenAdressPTR(&contractAddress), err)
 		}
 	}
 
@@ -148,6 +146,8 @@ func NewCollection(contractAddress common.Address, name string, nodes *provider.
 		PreviousFloorPrice:     0,
 		HighestCollectionOffer: 0,
 
+		activeListings: newListingBook(),
+
 		Raw: &osmodels.AssetCollection{},
 	}
 
@@ -0,0 +1,201 @@
+package collections
+
+import (
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/spf13/viper"
+)
+
+type FloorConfidence string
+
+const (
+	FloorConfidenceNone   FloorConfidence = "none"
+	FloorConfidenceLow    FloorConfidence = "low"
+	FloorConfidenceMedium FloorConfidence = "medium"
+	FloorConfidenceHigh   FloorConfidence = "high"
+)
+
+// FloorEstimate is the floor of a collection cross-checked against the next
+// lowest listings and the recent sales to detect manipulated (single) listings.
+type FloorEstimate struct {
+	Floor      float64
+	Confidence FloorConfidence
+
+	// lowest three active listings
+	Lowest []float64
+
+	// average price per item of the recent sales
+	SalesAverage float64
+}
+
+type activeListing struct {
+	price    float64
+	listedAt time.Time
+}
+
+// listingBook keeps the active listings of a collection by token id (as string, ids can exceed int64).
+type listingBook struct {
+	listings map[string]*activeListing
+	mu       *sync.Mutex
+}
+
+func newListingBook() *listingBook {
+	return &listingBook{listings: make(map[string]*activeListing), mu: &sync.Mutex{}}
+}
+
+// AddListingPrice adds/updates the listing price (in ether) of a token.
+func (uc *Collection) AddListingPrice(tokenID *big.Int, priceEther float64) {
+	if tokenID == nil {
+		return
+	}
+
+	book := uc.activeListings
+
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	book.listings[tokenID.String()] = &activeListing{price: priceEther, listedAt: time.Now()}
+}
+
+// RemoveListing removes a listing, e.g. after the token has been sold.
+func (uc *Collection) RemoveListing(tokenID *big.Int) {
+	if tokenID == nil {
+		return
+	}
+
+	book := uc.activeListings
+
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	delete(book.listings, tokenID.String())
+}
+
+// lowestListings returns the n lowest, not yet expired listing prices.
+func (uc *Collection) lowestListings(n int) []float64 {
+	book := uc.activeListings
+
+	book.mu.Lock()
+	defer book.mu.Unlock()
+
+	prices := make([]float64, 0, len(book.listings))
+
+	for tokenID, listing := range book.listings {
+		if time.Since(listing.listedAt) > viper.GetDuration("floor.listing_ttl") {
+			delete(book.listings, tokenID)
+
+			continue
+		}
+
+		prices = append(prices, listing.price)
+	}
+
+	sort.Float64s(prices)
+
+	return prices[:int(math.Min(float64(n), float64(len(prices))))]
+}
+
+// recentSalesAverage returns the average price per item of the sales in the default salira timeframe.
+func (uc *Collection) recentSalesAverage() float64 {
+	var volume float64
+
+	var numItems uint64
+
+	for _, event := range uc.RecentEvents.ToSlice() {
+		if event.Type != degendb.Sale || time.Since(event.Timestamp) > viper.GetDuration("salira.default_timeframe") {
+			continue
+		}
+
+		if event.AmountWei == nil || event.AmountTokens == 0 {
+			continue
+		}
+
+		value, _ := utils.WeiToEther(event.AmountWei).Float64()
+
+		volume += value
+		numItems += event.AmountTokens
+	}
+
+	if numItems == 0 {
+		return 0.0
+	}
+
+	return volume / float64(numItems)
+}
+
+// GetFloorEstimate returns the floor cross-checked against the 2nd/3rd lowest
+// listings and the recent sales average with a confidence of the estimate.
+func (uc *Collection) GetFloorEstimate() *FloorEstimate {
+	maxGap := viper.GetFloat64("floor.max_listing_gap")
+
+	estimate := &FloorEstimate{
+		Lowest:       uc.lowestListings(3),
+		SalesAverage: uc.recentSalesAverage(),
+		Confidence:   FloorConfidenceNone,
+	}
+
+	// is b within the allowed gap above a?
+	isClose := func(a, b float64) bool { return a > 0 && a >= b*(1-maxGap) }
+
+	lowest := estimate.Lowest
+
+	switch {
+	case len(lowest) == 0 && estimate.SalesAverage > 0:
+		estimate.Floor = estimate.SalesAverage
+		estimate.Confidence = FloorConfidenceLow
+
+	case len(lowest) == 0:
+		return estimate
+
+	case len(lowest) == 1:
+		estimate.Floor = lowest[0]
+		estimate.Confidence = FloorConfidenceLow
+
+	// lowest listing is backed by the next one(s)
+	case isClose(lowest[0], lowest[1]):
+		estimate.Floor = lowest[0]
+		estimate.Confidence = FloorConfidenceHigh
+
+		if len(lowest) < 3 {
+			estimate.Confidence = FloorConfidenceMedium
+		}
+
+	// lowest listing is an outlier, use the second lowest if backed by the third
+	case len(lowest) == 3 && isClose(lowest[1], lowest[2]):
+		estimate.Floor = lowest[1]
+		estimate.Confidence = FloorConfidenceMedium
+
+	// no consistent listings at all
+	default:
+		estimate.Floor = lowest[len(lowest)-1]
+		estimate.Confidence = FloorConfidenceLow
+	}
+
+	// cross-check with the recent sales
+	if estimate.SalesAverage > 0 && estimate.Floor > 0 {
+		ratio := estimate.Floor / estimate.SalesAverage
+
+		if ratio < 1-viper.GetFloat64("floor.max_sales_deviation") || ratio > 1+viper.GetFloat64("floor.max_sales_deviation") {
+			estimate.Confidence = estimate.Confidence.lower()
+		}
+	}
+
+	return estimate
+}
+
+func (fc FloorConfidence) lower() FloorConfidence {
+	switch fc {
+	case FloorConfidenceHigh:
+		return FloorConfidenceMedium
+	case FloorConfidenceMedium:
+		return FloorConfidenceLow
+	default:
+		return FloorConfidenceLow
+	}
+}
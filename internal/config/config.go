@@ -16,6 +16,7 @@ import (
 	"github.com/benleb/gloomberg/internal/nemo/wallet"
 	"github.com/benleb/gloomberg/internal/nemo/watch"
 	"github.com/benleb/gloomberg/internal/rueidica"
+	"github.com/benleb/gloomberg/internal/slugs"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/benleb/gloomberg/internal/utils"
 	"github.com/benleb/gloomberg/internal/utils/hooks"
@@ -137,7 +138,14 @@ func GetCollectionsFromConfiguration(providerPool *provider.Pool, rueidica *ruei
 	ownCollections := make([]*collections.Collection, 0)
 
 	for address, collection := range viper.GetStringMap("collections") {
-		contractAddress := common.HexToAddress(address)
+		// collections can be configured by address or opensea slug
+		contractAddress, err := slugs.Address(context.Background(), address)
+		if err != nil {
+			gbl.Log.Warnf("❌ could not resolve collection %s: %s", address, err)
+
+			continue
+		}
+
 		currentCollection := collections.NewCollection(contractAddress, "", providerPool, degendb.FromConfiguration, rueidica)
 
 		if collection == nil && common.IsHexAddress(address) {
@@ -152,7 +160,7 @@ func GetCollectionsFromConfiguration(providerPool *provider.Pool, rueidica *ruei
 			}
 
 			// general settings
-			if viper.Sub("collections."+currentCollection.ContractAddress.String()+".buy") != nil && !viper.IsSet("show.listings") {
+			if viper.Sub("collections."+address+".buy") != nil && !viper.IsSet("show.listings") {
 				currentCollection.Show.Listings = false
 				currentCollection.FetchListings = true
 			} else {
@@ -185,6 +193,10 @@ func GetCollectionsFromConfiguration(providerPool *provider.Pool, rueidica *ruei
 			}
 		}
 
+		if !common.IsHexAddress(address) && currentCollection.OpenseaSlug == "" {
+			currentCollection.OpenseaSlug = address
+		}
+
 		// // validating buy rule
 		// if rule := viper.Sub("collections." + currentCollection.ContractAddress.String() + ".buy"); rule != nil {
 		// 	if buyRule := ValidateRawBuyRule(rule, currentCollection.OpenseaSlug); buyRule != nil {
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// builtinProfiles are used if no profile with the same name is defined in the config file.
+// a profile overrides the regular config, flags given on the command line still take precedence.
+var builtinProfiles = map[string]map[string]interface{}{
+	// busy trading desk session: no noise, highlight deals
+	"trader": {
+		"show":  map[string]interface{}{"mints": false, "transfers": false, "airdrops": false, "min_value": 0.1},
+		"deals": map[string]interface{}{"enabled": true},
+	},
+
+	// mint hunting: show all mints & cheap stuff
+	"minter": {
+		"show":  map[string]interface{}{"mints": true, "airdrops": true, "min_value": 0.0},
+		"stats": map[string]interface{}{"enabled": true},
+	},
+
+	// background server: no terminal ui, but metrics & notifications
+	"server": {
+		"ui":      map[string]interface{}{"headless": true, "keybindings": map[string]interface{}{"enabled": false}},
+		"metrics": map[string]interface{}{"enabled": true},
+		"stats":   map[string]interface{}{"enabled": false},
+	},
+
+	// only the important stuff
+	"quiet": {
+		"show":   map[string]interface{}{"mints": false, "transfers": false, "burns": false, "reburns": false, "airdrops": false, "unknown": false, "min_value": 1.0},
+		"stats":  map[string]interface{}{"enabled": false},
+		"ticker": map[string]interface{}{"gasline": 0},
+		"notifications": map[string]interface{}{
+			"manifold": map[string]interface{}{"enabled": false},
+		},
+	},
+}
+
+// ApplyProfile merges the named profile (from the "profiles" section of the config or the builtin ones) into the config.
+func ApplyProfile(name string) error {
+	profile := viper.GetStringMap("profiles." + name)
+
+	if len(profile) == 0 {
+		builtinProfile, ok := builtinProfiles[name]
+		if !ok {
+			return ErrUnknownProfile
+		}
+
+		profile = builtinProfile
+	}
+
+	return viper.MergeConfigMap(profile)
+}
+
+// Profiles returns the names of all available profiles.
+func Profiles() []string {
+	names := make([]string, 0)
+
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+
+	for name := range viper.GetStringMap("profiles") {
+		if _, ok := builtinProfiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -45,6 +45,8 @@ var (
 	NFTfiContractAddress           = common.HexToAddress("0x5660E206496808F7b5cDB8C56A696a96AE5E9b23")
 	NFTLoanTicketV2ContractAddress = common.HexToAddress("0x0E258c84Df0f8728ae4A6426EA5FD163Eb6b9D1B")
 	BorrowerNoteTicket             = common.HexToAddress("0xbD85BF4C970b91984e6A2b8Ba9C577A58A8C20f9")
+	ArcadeLoanCoreV2               = common.HexToAddress("0x81b2F8Fc75Bab64A6b144aa6d2fAa127B4Fa7fD9")
+	ArcadeLoanCoreV3               = common.HexToAddress("0x89bc08BA00f135d608bc335f6B33D7a9ABCC98aF")
 
 	// uniswapv2.
 
@@ -59,6 +61,7 @@ var (
 	UniswapV3QuoterV2ContractAddress      = common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e")
 
 	ZeroAddress = common.HexToAddress("0x0000000000000000000000000000000000000000")
+	DeadAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
 	ZeroHash    = common.Hash{}
 
 	// BaseLogger is the logger used to print to the terminal without reporting caller or timestamp.
@@ -321,3 +324,8 @@ var CountryFlags = map[string]string{
 	"ZM": "🇿🇲",
 	"ZW": "🇿🇼",
 }
+
+// IsBurnAddress returns true for the addresses tokens are burned to (zero address & 0x...dEaD).
+func IsBurnAddress(address common.Address) bool {
+	return address == ZeroAddress || address == DeadAddress
+}
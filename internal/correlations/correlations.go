@@ -0,0 +1,393 @@
+package correlations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/seawa/models"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var ErrNotEnoughSamples = errors.New("not enough market samples recorded yet, keep 'gloomberg live' running for a while")
+
+// Metric is the value correlated between the collections.
+type Metric string
+
+const (
+	// MetricFloor correlates the relative floor changes between the samples.
+	MetricFloor Metric = "floor"
+	// MetricVolume correlates the sales volume per sample interval.
+	MetricVolume Metric = "volume"
+	// MetricTopOffer correlates the relative top collection offer changes between the samples.
+	MetricTopOffer Metric = "topoffer"
+)
+
+// Sample is the floor, the top collection offer & the sales volume of a collection since the previous sample.
+type Sample struct {
+	At       time.Time
+	Floor    float64
+	Volume   float64
+	TopOffer float64
+}
+
+// Series are the samples of a collection, oldest first.
+type Series struct {
+	Address common.Address
+	Samples []*Sample
+}
+
+// Matrix are the pairwise correlations of the series, NaN if they have not enough samples in common.
+type Matrix struct {
+	Series []*Series
+	Values [][]float64
+}
+
+// Pair is the correlation of two collections.
+type Pair struct {
+	A, B        *Series
+	Correlation float64
+}
+
+var (
+	// sales volume (in ether) per collection since the last sample
+	volumes   = make(map[common.Address]float64)
+	volumesMu sync.Mutex
+
+	// highest live collection offer (per item) per collection
+	topOffers   = make(map[common.Address]*topOffer)
+	topOffersMu sync.Mutex
+)
+
+type topOffer struct {
+	price     float64
+	expiresAt time.Time
+}
+
+// Record samples the floor, top collection offer & sales volume of the own (wallet/configured) collections
+// every correlations.interval into a ring buffer per collection in redis.
+func Record(gb *gloomberg.Gloomberg) {
+	chanTokenTransactions := gb.SubscribeTokenTransactions()
+	chanCollectionOffer := gb.SubscribeCollectionOffer()
+
+	ticker := time.NewTicker(max(viper.GetDuration("correlations.interval"), time.Minute))
+
+	for {
+		select {
+		case ttx := <-chanTokenTransactions:
+			addVolume(ttx)
+
+		case offer := <-chanCollectionOffer:
+			addOffer(offer)
+
+		case <-ticker.C:
+			record(gb)
+		}
+	}
+}
+
+// addVolume adds the price of a sale to the volumes of the sold collections.
+func addVolume(ttx *totra.TokenTransaction) {
+	if (ttx.Action != degendb.Sale && ttx.Action != degendb.Purchase) || ttx.AmountPaid == nil || ttx.AmountPaid.Sign() <= 0 {
+		return
+	}
+
+	numNFTs := 0
+	transfersByContract := make(map[common.Address]int)
+
+	for _, transfer := range ttx.Transfers {
+		if transfer.Standard.IsERC721orERC1155() && transfer.Token != nil {
+			transfersByContract[transfer.Token.Address]++
+			numNFTs++
+		}
+	}
+
+	if numNFTs == 0 {
+		return
+	}
+
+	amountPaid, _ := utils.WeiToEther(ttx.AmountPaid).Float64()
+
+	volumesMu.Lock()
+	defer volumesMu.Unlock()
+
+	for contractAddress, numTransfers := range transfersByContract {
+		volumes[contractAddress] += amountPaid * float64(numTransfers) / float64(numNFTs)
+	}
+}
+
+// addOffer keeps the offer if it is higher than the current top offer of the collection or the current one expired.
+func addOffer(offer *models.CollectionOffer) {
+	if offer.Payload.BasePrice == nil || offer.Payload.BasePrice.Sign() <= 0 {
+		return
+	}
+
+	perItemPrice, _ := utils.WeiToEther(new(big.Int).Div(offer.Payload.BasePrice, big.NewInt(int64(max(1, offer.Payload.Quantity))))).Float64()
+	contractAddress := offer.Payload.ContractCriteria.Address
+
+	topOffersMu.Lock()
+	defer topOffersMu.Unlock()
+
+	if current, ok := topOffers[contractAddress]; ok && current.price >= perItemPrice && current.expiresAt.After(time.Now()) {
+		return
+	}
+
+	topOffers[contractAddress] = &topOffer{price: perItemPrice, expiresAt: offer.Payload.ExpirationDate}
+}
+
+// currentTopOffer returns the top live offer of the collection, 0 if unknown or expired.
+func currentTopOffer(contractAddress common.Address) float64 {
+	topOffersMu.Lock()
+	defer topOffersMu.Unlock()
+
+	if current, ok := topOffers[contractAddress]; ok && current.expiresAt.After(time.Now()) {
+		return current.price
+	}
+
+	return 0
+}
+
+func record(gb *gloomberg.Gloomberg) {
+	samples := make(map[common.Address]string)
+
+	gb.CollectionDB.RWMu.RLock()
+	for contractAddress, collection := range gb.CollectionDB.Collections {
+		if !collection.IsOwn() {
+			continue
+		}
+
+		volumesMu.Lock()
+		volume := volumes[contractAddress]
+		delete(volumes, contractAddress)
+		volumesMu.Unlock()
+
+		samples[contractAddress] = fmt.Sprintf("%d:%.6f:%.6f:%.6f", time.Now().Unix(), collection.GetFloorEstimate().Floor, volume, currentTopOffer(contractAddress))
+	}
+	gb.CollectionDB.RWMu.RUnlock()
+
+	for contractAddress, sample := range samples {
+		if err := gb.Rueidi.AddMarketSample(context.Background(), contractAddress, sample, viper.GetInt64("correlations.size")); err != nil {
+			gbl.Log.Debugf("📈 storing market sample of %s failed: %s", contractAddress.Hex(), err)
+		}
+	}
+}
+
+// Load returns the series of all sampled collections within the window.
+func Load(ctx context.Context, gb *gloomberg.Gloomberg, window time.Duration) ([]*Series, error) {
+	addresses, err := gb.Rueidi.GetMarketSampleAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allSeries := make([]*Series, 0, len(addresses))
+
+	for _, address := range addresses {
+		series, err := LoadSeries(ctx, gb, address, window)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(series.Samples) > 0 {
+			allSeries = append(allSeries, series)
+		}
+	}
+
+	sort.Slice(allSeries, func(i, j int) bool { return allSeries[i].Address.Hex() < allSeries[j].Address.Hex() })
+
+	return allSeries, nil
+}
+
+// LoadSeries returns the samples of a single collection within the window.
+func LoadSeries(ctx context.Context, gb *gloomberg.Gloomberg, address common.Address, window time.Duration) (*Series, error) {
+	rawSamples, err := gb.Rueidi.GetMarketSamples(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &Series{Address: address, Samples: make([]*Sample, 0, len(rawSamples))}
+
+	for i := len(rawSamples) - 1; i >= 0; i-- {
+		sample, err := parseSample(rawSamples[i])
+		if err != nil || time.Since(sample.At) > window {
+			continue
+		}
+
+		series.Samples = append(series.Samples, sample)
+	}
+
+	return series, nil
+}
+
+// Correlate calculates the pearson correlation of the metric between all series. the samples are
+// matched by their interval, pairs with less than minSamples samples in common are NaN.
+func Correlate(allSeries []*Series, metric Metric, interval time.Duration, minSamples int) (*Matrix, error) {
+	if len(allSeries) < 2 {
+		return nil, ErrNotEnoughSamples
+	}
+
+	values := make([]map[int64]float64, len(allSeries))
+	for idx, series := range allSeries {
+		values[idx] = metricValues(series, metric, interval)
+	}
+
+	matrix := &Matrix{Series: allSeries, Values: make([][]float64, len(allSeries))}
+	hasCorrelation := false
+
+	for i := range allSeries {
+		matrix.Values[i] = make([]float64, len(allSeries))
+
+		for j := range allSeries {
+			if i == j {
+				matrix.Values[i][j] = 1
+
+				continue
+			}
+
+			matrix.Values[i][j] = pearson(values[i], values[j], minSamples)
+
+			if !math.IsNaN(matrix.Values[i][j]) {
+				hasCorrelation = true
+			}
+		}
+	}
+
+	if !hasCorrelation {
+		return nil, ErrNotEnoughSamples
+	}
+
+	return matrix, nil
+}
+
+// TopPairs returns the n most positively correlated pairs.
+func (m *Matrix) TopPairs(n int) []*Pair {
+	pairs := make([]*Pair, 0)
+
+	for i := range m.Series {
+		for j := i + 1; j < len(m.Series); j++ {
+			if !math.IsNaN(m.Values[i][j]) {
+				pairs = append(pairs, &Pair{A: m.Series[i], B: m.Series[j], Correlation: m.Values[i][j]})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Correlation > pairs[j].Correlation })
+
+	return pairs[:min(n, len(pairs))]
+}
+
+// metricValues returns the values of the metric by interval index.
+func metricValues(series *Series, metric Metric, interval time.Duration) map[int64]float64 {
+	values := make(map[int64]float64)
+
+	var previous *Sample
+
+	for _, sample := range series.Samples {
+		slot := sample.At.Unix() / int64(interval.Seconds())
+
+		switch metric {
+		case MetricVolume:
+			values[slot] = sample.Volume
+
+		case MetricFloor:
+			// relative change to the previous sample, floors of 0 are unknown
+			if previous != nil && previous.Floor > 0 && sample.Floor > 0 {
+				values[slot] = sample.Floor/previous.Floor - 1
+			}
+
+		case MetricTopOffer:
+			// top offers of 0 are unknown (samples recorded before the top offer or without offers)
+			if previous != nil && previous.TopOffer > 0 && sample.TopOffer > 0 {
+				values[slot] = sample.TopOffer/previous.TopOffer - 1
+			}
+		}
+
+		previous = sample
+	}
+
+	return values
+}
+
+func pearson(a map[int64]float64, b map[int64]float64, minSamples int) float64 {
+	xs := make([]float64, 0, len(a))
+	ys := make([]float64, 0, len(a))
+
+	for slot, x := range a {
+		if y, ok := b[slot]; ok {
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+
+	if len(xs) < max(minSamples, 2) {
+		return math.NaN()
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var covariance, varianceX, varianceY float64
+	for i := range xs {
+		covariance += (xs[i] - meanX) * (ys[i] - meanY)
+		varianceX += (xs[i] - meanX) * (xs[i] - meanX)
+		varianceY += (ys[i] - meanY) * (ys[i] - meanY)
+	}
+
+	// no movement at all, no correlation to calculate
+	if varianceX == 0 || varianceY == 0 {
+		return math.NaN()
+	}
+
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
+
+func parseSample(rawSample string) (*Sample, error) {
+	// "unix:floor:volume" or "unix:floor:volume:topoffer"
+	parts := strings.Split(rawSample, ":")
+	if len(parts) != 3 && len(parts) != 4 {
+		return nil, fmt.Errorf("invalid market sample: %s", rawSample)
+	}
+
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	floor, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &Sample{At: time.Unix(unix, 0), Floor: floor, Volume: volume}
+
+	if len(parts) == 4 {
+		if sample.TopOffer, err = strconv.ParseFloat(parts[3], 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return sample, nil
+}
@@ -0,0 +1,193 @@
+package crossdedupe
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var dedupedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_cross_source_sales_total",
+	Help: "The number of sales received from the stream, by outcome (merged, dropped, printed).",
+}, []string{"outcome"})
+
+// key identifies a sold token across the chain & the stream.
+type key struct {
+	tx       common.Hash
+	contract common.Address
+	tokenID  string
+}
+
+// StreamSale is a sale received from the opensea stream.
+type StreamSale struct {
+	TxHash          common.Hash
+	ContractAddress common.Address
+	TokenID         *big.Int
+
+	// per item price & the symbol of the payment token
+	Price         *price.Price
+	PaymentSymbol string
+
+	receivedAt time.Time
+	timer      *time.Timer
+}
+
+var (
+	// sales printed from the chain & when they were printed
+	chainSales = make(map[key]time.Time)
+	// stream sales waiting for the same sale from the chain
+	pendingStreamSales = make(map[key]*StreamSale)
+
+	lastCleanup time.Time
+
+	mu sync.Mutex
+)
+
+// Enabled returns true if sales received from the chain & the stream should be merged into one event.
+func Enabled() bool {
+	return viper.GetBool("dedupe.cross_source.enabled")
+}
+
+// AddStreamSale drops the stream sale if the sale was already printed from the chain. otherwise it is held
+// for dedupe.cross_source.window to be merged into the chain event & printed via print if the chain doesn't
+// deliver the sale in time. print can be nil for stream sales only used for the merge.
+func AddStreamSale(sale *StreamSale, print func()) {
+	if sale.TokenID == nil || sale.TxHash == (common.Hash{}) {
+		if print != nil {
+			print()
+		}
+
+		return
+	}
+
+	saleKey := key{tx: sale.TxHash, contract: sale.ContractAddress, tokenID: sale.TokenID.String()}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := chainSales[saleKey]; ok {
+		dedupedCounter.WithLabelValues("dropped").Inc()
+
+		return
+	}
+
+	if _, ok := pendingStreamSales[saleKey]; ok {
+		return
+	}
+
+	sale.receivedAt = time.Now()
+	sale.timer = time.AfterFunc(viper.GetDuration("dedupe.cross_source.window"), func() {
+		mu.Lock()
+		pending, ok := pendingStreamSales[saleKey]
+		if ok && pending == sale {
+			delete(pendingStreamSales, saleKey)
+		}
+		mu.Unlock()
+
+		// merged into the chain event in the meantime
+		if !ok || pending != sale {
+			return
+		}
+
+		dedupedCounter.WithLabelValues("printed").Inc()
+
+		if print != nil {
+			print()
+		}
+	})
+
+	pendingStreamSales[saleKey] = sale
+}
+
+// Label remembers the tokens of a sale printed from the chain & returns a label with the details of the
+// same sale received from the stream before, which is not printed separately then.
+func Label(ttx *totra.TokenTransaction) string {
+	if !degendb.SaleTypes.Contains(ttx.Action) || ttx.TxHash == (common.Hash{}) {
+		return ""
+	}
+
+	now := time.Now()
+
+	merged := make([]*StreamSale, 0)
+
+	mu.Lock()
+
+	cleanup(now)
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.Token == nil {
+			continue
+		}
+
+		saleKey := key{tx: ttx.TxHash, contract: transfer.Token.Address, tokenID: transfer.Token.ID.String()}
+
+		chainSales[saleKey] = now
+
+		if pending, ok := pendingStreamSales[saleKey]; ok {
+			pending.timer.Stop()
+			delete(pendingStreamSales, saleKey)
+
+			merged = append(merged, pending)
+		}
+	}
+
+	mu.Unlock()
+
+	if len(merged) == 0 {
+		return ""
+	}
+
+	dedupedCounter.WithLabelValues("merged").Add(float64(len(merged)))
+
+	return mergedLabel(ttx, merged)
+}
+
+// mergedLabel shows how much earlier the stream was & its price or payment token if they differ from the chain.
+func mergedLabel(ttx *totra.TokenTransaction, merged []*StreamSale) string {
+	first := merged[0]
+
+	details := []string{fmt.Sprintf("%s earlier", time.Since(first.receivedAt).Truncate(100*time.Millisecond))}
+
+	// the price of a single token can be compared directly
+	if len(merged) == 1 && first.Price != nil && first.Price.Ether() > 0 {
+		chainPrice := ttx.GetPrice().Ether()
+		if chainPrice == 0 || math.Abs(chainPrice-first.Price.Ether())/first.Price.Ether() > 0.01 {
+			details = append(details, fmt.Sprintf("%.4fΞ on opensea", first.Price.Ether()))
+		}
+	}
+
+	if symbol := strings.ToUpper(first.PaymentSymbol); symbol != "" && symbol != "ETH" {
+		details = append(details, "paid in "+symbol)
+	}
+
+	return "🌊 " + style.OpenSea.Render("stream") + style.DarkGrayStyle.Render(" "+strings.Join(details, " · "))
+}
+
+// cleanup removes chain sales older than dedupe.cross_source.ttl, at most once a minute.
+func cleanup(now time.Time) {
+	if now.Sub(lastCleanup) < time.Minute {
+		return
+	}
+
+	lastCleanup = now
+
+	ttl := viper.GetDuration("dedupe.cross_source.ttl")
+
+	for saleKey, printedAt := range chainSales {
+		if now.Sub(printedAt) > ttl {
+			delete(chainSales, saleKey)
+		}
+	}
+}
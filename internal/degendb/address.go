@@ -26,6 +26,9 @@ type Address struct {
 	// Tags is a list of tags associated with this wallet/collection
 	Tags []Tag `bson:"tags,omitempty" json:"tags"`
 
+	// FundedBy is the address that sent the first eth to this wallet
+	FundedBy *common.Address `bson:"funded_by,omitempty" json:"funded_by,omitempty"`
+
 	//
 	// Collection data
 
@@ -57,3 +60,16 @@ type Address struct {
 func (a *Address) String() string {
 	return a.Address.Hex()
 }
+
+// HasTag returns true if the address is tagged with any of the given tags.
+func (a *Address) HasTag(tags ...Tag) bool {
+	for _, tag := range a.Tags {
+		for _, t := range tags {
+			if tag == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
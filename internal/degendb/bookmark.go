@@ -0,0 +1,85 @@
+package degendb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrBookmarkNotFound = errors.New("no bookmark for this tx")
+
+type Bookmark struct {
+	// TxHash is the hash of the bookmarked event
+	TxHash string `bson:"_id" json:"tx_hash"`
+
+	// Action is the type of the event (Sale, Mint, ...)
+	Action string `bson:"action" json:"action"`
+
+	// Collections are the names of the transferred collections
+	Collections []string `bson:"collections" json:"collections"`
+
+	// Price is the total price of the event in ether
+	Price float64 `bson:"price" json:"price"`
+
+	// From & To are the sender/seller & receiver/buyer of the event
+	From string `bson:"from,omitempty" json:"from,omitempty"`
+	To   string `bson:"to,omitempty" json:"to,omitempty"`
+
+	// Note is an optional note to remember why the event was bookmarked
+	Note string `bson:"note,omitempty" json:"note,omitempty"`
+
+	// EventAt is the time the event was received
+	EventAt time.Time `bson:"event_at,omitempty" json:"event_at,omitempty"`
+
+	// CreatedAt is the time the event was bookmarked
+	CreatedAt time.Time `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
+
+// SetBookmark saves a bookmark, bookmarking an event again updates it.
+func (ddb *DegenDB) SetBookmark(ctx context.Context, bookmark *Bookmark) error {
+	bookmarksColl := ddb.mongo.Database(mongoDB).Collection(collBookmarks)
+
+	if bookmark.CreatedAt.IsZero() {
+		bookmark.CreatedAt = time.Now()
+	}
+
+	_, err := bookmarksColl.ReplaceOne(ctx, bson.M{"_id": bookmark.TxHash}, bookmark, options.Replace().SetUpsert(true))
+
+	return err
+}
+
+// DeleteBookmark removes the bookmark of an event.
+func (ddb *DegenDB) DeleteBookmark(ctx context.Context, txHash string) error {
+	bookmarksColl := ddb.mongo.Database(mongoDB).Collection(collBookmarks)
+
+	result, err := bookmarksColl.DeleteOne(ctx, bson.M{"_id": txHash})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrBookmarkNotFound
+	}
+
+	return nil
+}
+
+// Bookmarks returns all bookmarks, newest first.
+func (ddb *DegenDB) Bookmarks(ctx context.Context) ([]*Bookmark, error) {
+	bookmarksColl := ddb.mongo.Database(mongoDB).Collection(collBookmarks)
+
+	cursor, err := bookmarksColl.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := make([]*Bookmark, 0)
+	if err := cursor.All(ctx, &bookmarks); err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
@@ -9,6 +9,15 @@ import (
 
 type Tag string
 
+const (
+	// TagExchange marks hot wallets & deposit addresses of centralized exchanges.
+	TagExchange Tag = "exchange"
+	// TagExchangeDeposit marks (user) deposit addresses of centralized exchanges.
+	TagExchangeDeposit Tag = "exchange-deposit"
+	// TagMEVBot marks known mev/arbitrage bots.
+	TagMEVBot Tag = "mev-bot"
+)
+
 type Degen struct {
 	// ID is the unique identifier for this degen
 	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -9,12 +9,11 @@ import (
 	"path"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
-	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/slugs"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/charmbracelet/log"
 	"github.com/ethereum/go-ethereum/common"
@@ -40,41 +39,15 @@ func LoadOpenseaRanks(gb *gloomberg.Gloomberg) error {
 		slug := strings.TrimSuffix(rFile.Name(), "_opensea.json")
 		filePath := path.Join(ddPathRanks, rFile.Name())
 
-		address, ok := gb.CollectionDB.OpenSeaSlugsAndAddresses()[slug]
-		if !ok {
-			if addr, err := gb.Rueidi.GetAddressForOSSlug(context.Background(), slug); err == nil && addr != "" {
-				address = common.HexToAddress(addr)
-
-				gloomberg.PrDModf("ddb", "found address %s for slug %s in cache", style.AlmostWhiteStyle.Render(addr), style.AlmostWhiteStyle.Render(slug))
-			} else if collectionResponse := opensea.GetCollection(slug); collectionResponse != nil {
-				// don't fuck opensea
-				time.Sleep(time.Millisecond * 337)
-
-				gloomberg.PrDModf("ddb", "fetched address %s for slug %s from opensea", style.AlmostWhiteStyle.Render(addr), style.AlmostWhiteStyle.Render(slug))
-
-				if len(collectionResponse.Collection.PrimaryAssetContracts) > 0 {
-					address = common.HexToAddress(collectionResponse.Collection.PrimaryAssetContracts[0].Address)
-				} else {
-					log.Warnf("failed to get address for %s from opensea", style.AlmostWhiteStyle.Render(slug))
-
-					continue
-				}
-			} else {
-				log.Warnf("failed to get collection data for %s from opensea", style.AlmostWhiteStyle.Render(slug))
+		// cached, from our collectionDB or fetched from opensea/reservoir
+		address, err := slugs.Address(context.Background(), slug)
+		if err != nil {
+			log.Warnf("failed to get address for %s: %s", style.AlmostWhiteStyle.Render(slug), err)
 
-				continue
-			}
-		} else {
-			gloomberg.PrDModf("ddb", fmt.Sprintf("address %s for slug %s from our collectionDB", style.AlmostWhiteStyle.Render(address.Hex()), style.AlmostWhiteStyle.Render(slug)))
+			continue
 		}
 
-		// cache
-		if slug != "" && address != (common.Address{}) {
-			_ = gb.Rueidi.StoreAddressForOSSlug(context.Background(), slug, address)
-			_ = gb.Rueidi.StoreOSSlugForAddress(context.Background(), address, slug)
-
-			gloomberg.PrDModf("ddb", "stored address %s for slug %s in cache", style.AlmostWhiteStyle.Render(address.Hex()), style.AlmostWhiteStyle.Render(slug))
-		}
+		gloomberg.PrDModf("ddb", "address %s for slug %s", style.AlmostWhiteStyle.Render(address.Hex()), style.AlmostWhiteStyle.Render(slug))
 
 		ranksOpensea := make(degendb.OpenSeaRanks)
 		ranksFile, err := os.Open(filePath)
@@ -127,3 +100,52 @@ func LoadOpenseaRanks(gb *gloomberg.Gloomberg) error {
 
 	return nil
 }
+
+// LoadAddressLabels loads the labeled addresses (exchanges, bridges, ...) from the json files in <degendata>/addresses.
+func LoadAddressLabels() (map[common.Address]*degendb.Address, error) {
+	ddPathAddresses := path.Join(viper.GetString("degendata.path"), "addresses")
+	log.Debugf("loading address labels from %s", ddPathAddresses)
+
+	addressFiles, err := os.ReadDir(ddPathAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[common.Address]*degendb.Address)
+
+	for _, aFile := range addressFiles {
+		if aFile.IsDir() || path.Ext(aFile.Name()) != ".json" {
+			continue
+		}
+
+		addressBytes, err := os.ReadFile(path.Join(ddPathAddresses, aFile.Name()))
+		if err != nil {
+			log.Errorf("failed to read content from file: %s", err)
+
+			continue
+		}
+
+		addresses := make([]*degendb.Address, 0)
+
+		if err := json.Unmarshal(addressBytes, &addresses); err != nil {
+			log.Errorf("failed to decode addresses from json file %s: %s", aFile.Name(), err)
+
+			continue
+		}
+
+		for _, address := range addresses {
+			if !common.IsHexAddress(address.HexAddress) {
+				gloomberg.PrDModf("ddb", "%s | invalid address %s", aFile.Name(), style.AlmostWhiteStyle.Render(address.HexAddress))
+
+				continue
+			}
+
+			address.Address = common.HexToAddress(address.HexAddress)
+			labels[address.Address] = address
+		}
+	}
+
+	gloomberg.PrMod("ddb", fmt.Sprintf("%s labeled addresses loaded", style.AlmostWhiteStyle.Render(strconv.Itoa(len(labels)))))
+
+	return labels, nil
+}
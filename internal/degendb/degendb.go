@@ -16,8 +16,10 @@ import (
 const (
 	mongoDB         = "dev-degendb"
 	collAddresses   = "addresses"
+	collBookmarks   = "bookmarks"
 	collCollections = "collections"
 	collDegens      = "degens"
+	collNotes       = "notes"
 	collTokens      = "tokens"
 )
 
@@ -66,6 +66,7 @@ var (
 	BurnRedeem              = &GBEventType{name: "BurnRedeem", actionName: "redeemed burned", icon: "🔥", openseaEventName: ""}
 	Loan                    = &GBEventType{name: "Loan", actionName: "loaned", icon: "💸", openseaEventName: ""}
 	RepayLoan               = &GBEventType{name: "RepayLoan", actionName: "repaid loan", icon: "💸", openseaEventName: ""}
+	Liquidation             = &GBEventType{name: "Liquidation", actionName: "seized collateral", icon: "🪓", openseaEventName: ""}
 	Listing                 = &GBEventType{name: "Listing", actionName: "listed", icon: "📢", openseaEventName: "item_listed"}
 	Bid                     = &GBEventType{name: "Bid", actionName: "(got) bid", icon: "💦", openseaEventName: "item_received_bid"}
 	OwnBid                  = &GBEventType{name: "OwnBid", actionName: "bid", icon: "🤑", openseaEventName: ""}
@@ -74,6 +75,9 @@ var (
 	AcceptedCollectionOffer = &GBEventType{name: "AcceptedCollectionOffer", actionName: "accepted collection offer", icon: "🤝", openseaEventName: ""}
 	MetadataUpdated         = &GBEventType{name: "MetadataUpdated", actionName: "metadata updated", icon: "♻️", openseaEventName: "item_metadata_updated"}
 	Cancelled               = &GBEventType{name: "Cancelled", actionName: "cancelled", icon: "❌", openseaEventName: "item_cancelled"}
+	Deposit                 = &GBEventType{name: "Deposit", actionName: "deposited", icon: "🏦", openseaEventName: ""}
+	Withdrawal              = &GBEventType{name: "Withdrawal", actionName: "withdrew", icon: "🏧", openseaEventName: ""}
+	NewCollection           = &GBEventType{name: "NewCollection", actionName: "deployed", icon: "🐣", openseaEventName: ""}
 
 	// event type sets.
 	SaleTypes = mapset.NewSet[EventType](Sale, Purchase)
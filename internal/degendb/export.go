@@ -0,0 +1,88 @@
+package degendb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// collections included in exports of the degendb state (labels, degens, notes & bookmarks).
+// tokens & collections are refetched from the chain/marketplaces & not exported.
+var exportCollections = []string{collAddresses, collDegens, collNotes, collBookmarks}
+
+// Export returns the documents of the exported collections as canonical extended json by collection name.
+func (ddb *DegenDB) Export(ctx context.Context) (map[string][]json.RawMessage, error) {
+	exported := make(map[string][]json.RawMessage, len(exportCollections))
+
+	for _, collName := range exportCollections {
+		cursor, err := ddb.mongo.Database(mongoDB).Collection(collName).Find(ctx, bson.D{})
+		if err != nil {
+			return nil, fmt.Errorf("reading %s failed: %w", collName, err)
+		}
+
+		documents := make([]json.RawMessage, 0)
+
+		for cursor.Next(ctx) {
+			document, err := bson.MarshalExtJSON(cursor.Current, true, false)
+			if err != nil {
+				_ = cursor.Close(ctx)
+
+				return nil, fmt.Errorf("encoding a %s document failed: %w", collName, err)
+			}
+
+			documents = append(documents, document)
+		}
+
+		if err := cursor.Err(); err != nil {
+			return nil, fmt.Errorf("reading %s failed: %w", collName, err)
+		}
+
+		_ = cursor.Close(ctx)
+
+		exported[collName] = documents
+	}
+
+	return exported, nil
+}
+
+// Import upserts the exported documents by their _id & returns the number of imported documents by collection.
+// unknown collections are ignored.
+func (ddb *DegenDB) Import(ctx context.Context, exported map[string][]json.RawMessage) (map[string]int, error) {
+	imported := make(map[string]int, len(exported))
+
+	for _, collName := range exportCollections {
+		coll := ddb.mongo.Database(mongoDB).Collection(collName)
+
+		for _, rawDocument := range exported[collName] {
+			var document bson.D
+			if err := bson.UnmarshalExtJSON(rawDocument, true, &document); err != nil {
+				return imported, fmt.Errorf("decoding a %s document failed: %w", collName, err)
+			}
+
+			var id interface{}
+
+			for _, element := range document {
+				if element.Key == "_id" {
+					id = element.Value
+
+					break
+				}
+			}
+
+			if id == nil {
+				continue
+			}
+
+			if _, err := coll.ReplaceOne(ctx, bson.M{"_id": id}, document, options.Replace().SetUpsert(true)); err != nil {
+				return imported, fmt.Errorf("importing into %s failed: %w", collName, err)
+			}
+
+			imported[collName]++
+		}
+	}
+
+	return imported, nil
+}
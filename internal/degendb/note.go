@@ -0,0 +1,80 @@
+package degendb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNoteNotFound = errors.New("no note for this address")
+
+type Note struct {
+	// HexAddress is the address of the wallet/collection the note is attached to
+	HexAddress string `bson:"_id" json:"hex_address"`
+
+	// Text is the note itself
+	Text string `bson:"text" json:"text"`
+
+	// CreatedAt is the time this note was created
+	CreatedAt time.Time `bson:"created_at,omitempty" json:"created_at,omitempty"`
+
+	// UpdatedAt is the time this note was last updated
+	UpdatedAt time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+func (n *Note) Address() common.Address {
+	return common.HexToAddress(n.HexAddress)
+}
+
+// SetNote attaches a note to a wallet or collection, an existing note is replaced.
+func (ddb *DegenDB) SetNote(ctx context.Context, address common.Address, text string) error {
+	notesColl := ddb.mongo.Database(mongoDB).Collection(collNotes)
+
+	now := time.Now()
+
+	update := bson.M{
+		"$set":         bson.M{"text": text, "updated_at": now},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+
+	_, err := notesColl.UpdateByID(ctx, address.Hex(), update, options.Update().SetUpsert(true))
+
+	return err
+}
+
+// DeleteNote removes the note of a wallet or collection.
+func (ddb *DegenDB) DeleteNote(ctx context.Context, address common.Address) error {
+	notesColl := ddb.mongo.Database(mongoDB).Collection(collNotes)
+
+	result, err := notesColl.DeleteOne(ctx, bson.M{"_id": address.Hex()})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNoteNotFound
+	}
+
+	return nil
+}
+
+// Notes returns all notes.
+func (ddb *DegenDB) Notes(ctx context.Context) ([]*Note, error) {
+	notesColl := ddb.mongo.Database(mongoDB).Collection(collNotes)
+
+	cursor, err := notesColl.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]*Note, 0)
+	if err := cursor.All(ctx, &notes); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
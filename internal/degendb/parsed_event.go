@@ -1,8 +1,10 @@
 package degendb
 
 import (
+	"math/big"
 	"time"
 
+	"github.com/benleb/gloomberg/internal/links"
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ethereum/go-ethereum/common"
@@ -10,6 +12,7 @@ import (
 
 type PreformattedEvent struct {
 	TxHash                 common.Hash
+	ChainID                uint64
 	Action                 string
 	ReceivedAt             time.Time
 	Typemoji               string
@@ -19,6 +22,9 @@ type PreformattedEvent struct {
 	EtherscanURL           string
 	OpenSeaURL             string
 
+	// links to the configured marketplaces, preferred first
+	MarketplaceLinks []*links.Link
+
 	// "attributes"
 	IsOwnWallet     bool
 	IsOwnCollection bool
@@ -35,10 +41,27 @@ type PreformattedEvent struct {
 	To          *Degen
 	ToAddress   common.Address
 
+	// notes attached to the sender & receiver
+	FromNote string
+	ToNote   string
+
+	// proceeds of the seller, royalties & marketplace fees of sales, nil if unknown
+	Fees *FeeBreakdown
+
+	// gas used & costs of the tx, nil without receipt
+	Gas *GasCosts
+
 	Colors EventColors
 	Other  map[string]interface{}
 }
 
+// FeeBreakdown splits the price of a sale into the proceeds of the seller, creator royalties & marketplace fees.
+type FeeBreakdown struct {
+	Seller          *price.Price
+	Royalties       *price.Price
+	MarketplaceFees *price.Price
+}
+
 type EventColors struct {
 	Time          lipgloss.Color
 	Price         lipgloss.Color
@@ -59,6 +82,9 @@ type TransferredCollection struct {
 	CollectionName    string
 	TransferredTokens []TransferredToken
 
+	// note attached to the collection
+	Note string
+
 	Colors CollectionColors
 
 	// from & to per collection as we print one line per collection...^^
@@ -72,3 +98,18 @@ type TransferredToken struct {
 	RankSymbol string
 	Amount     int64
 }
+
+// GasCosts are the gas used & paid by the tx of an event.
+type GasCosts struct {
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+
+	// priority fee (tip) per gas paid to the block builder, nil if unknown
+	PriorityFee *big.Int
+
+	// gas used * effective gas price
+	TxFee *price.Price
+
+	// the receipt lacks the effective gas price, the max fee is used as upper bound
+	IsUpperBound bool
+}
@@ -14,4 +14,7 @@ type RecentEvent struct {
 	// Event Volume
 	AmountWei    *big.Int `bson:"amount_wei,omitempty"    json:"amount_wei,omitempty"`
 	AmountTokens uint64   `bson:"amount_tokens,omitempty" json:"amount_tokens,omitempty"`
+
+	// IsBot is true if a (known) mev bot was involved
+	IsBot bool `bson:"is_bot,omitempty" json:"is_bot,omitempty"`
 }
@@ -0,0 +1,171 @@
+package deploywatch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/standard"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// selector of supportsInterface(bytes4).
+var supportsInterfaceSelector = hexutil.MustDecode("0x01ffc9a7")
+
+// erc-165 interface ids of nft contracts.
+var nftInterfaces = map[standard.Standard][4]byte{
+	standard.ERC721:  {0x80, 0xac, 0x58, 0xcd},
+	standard.ERC1155: {0xd9, 0xb6, 0x7a, 0x26},
+}
+
+// max number of blocks checked at once.
+const maxBlocksPerCheck = 5
+
+// NewCollection is a newly deployed erc721/erc1155 contract.
+type NewCollection struct {
+	ContractAddress common.Address
+	Deployer        common.Address
+	TxHash          common.Hash
+	BlockNumber     uint64
+
+	Standard standard.Standard
+	Name     string
+	Symbol   string
+}
+
+// Enabled returns true if new nft contract deployments should be shown.
+func Enabled() bool {
+	return viper.GetBool("deploywatch.enabled")
+}
+
+// Start checks the contract creation txs of new blocks for erc721/erc1155 deployments.
+// contracts deployed by factories (internal txs) are not seen.
+func Start(gb *gloomberg.Gloomberg) {
+	newBlocks := gb.SubscribNewBlocks()
+
+	gloomberg.PrMod("deploy", "watching contract creations for new collections")
+
+	var lastBlock uint64
+
+	for blockNumber := range newBlocks {
+		if blockNumber <= lastBlock {
+			continue
+		}
+
+		// catch up at most a few blocks after downtimes
+		fromBlock := blockNumber
+		if lastBlock > 0 {
+			fromBlock = max(lastBlock+1, blockNumber-maxBlocksPerCheck+1)
+		}
+
+		for number := fromBlock; number <= blockNumber; number++ {
+			checkBlock(gb, number)
+		}
+
+		lastBlock = blockNumber
+	}
+}
+
+// checkBlock checks the contract creation txs of the block.
+func checkBlock(gb *gloomberg.Gloomberg, blockNumber uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), internal.BlockTime)
+	defer cancel()
+
+	block, err := gb.ProviderPool.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		gbl.Log.Debugf("🐣 failed to get block %d: %s", blockNumber, err)
+
+		return
+	}
+
+	for _, tx := range block.Transactions() {
+		if tx.To() != nil {
+			continue
+		}
+
+		receipt, err := gb.ProviderPool.TransactionReceipt(ctx, tx.Hash())
+		if err != nil || receipt == nil || receipt.Status == 0 || receipt.ContractAddress == internal.ZeroAddress {
+			continue
+		}
+
+		nftStandard := probeStandard(ctx, gb, receipt.ContractAddress)
+		if nftStandard == standard.UNKNOWN {
+			continue
+		}
+
+		collection := &NewCollection{
+			ContractAddress: receipt.ContractAddress,
+			TxHash:          tx.Hash(),
+			BlockNumber:     blockNumber,
+			Standard:        nftStandard,
+		}
+
+		if metadata, err := gb.ProviderPool.ERC721CollectionMetadata(ctx, receipt.ContractAddress); err == nil {
+			collection.Name, _ = metadata["name"].(string)
+			collection.Symbol, _ = metadata["symbol"].(string)
+		}
+
+		if deployer, err := types.LatestSignerForChainID(tx.ChainId()).Sender(tx); err == nil {
+			collection.Deployer = deployer
+		}
+
+		printNewCollection(collection)
+	}
+}
+
+// probeStandard returns the nft standard the contract supports via erc-165 or UNKNOWN.
+func probeStandard(ctx context.Context, gb *gloomberg.Gloomberg, contractAddress common.Address) standard.Standard {
+	for nftStandard, interfaceID := range nftInterfaces {
+		callData := make([]byte, 0, 4+32)
+		callData = append(callData, supportsInterfaceSelector...)
+		callData = append(callData, common.RightPadBytes(interfaceID[:], 32)...)
+
+		result, err := gb.ProviderPool.CallContract(ctx, ethereum.CallMsg{To: &contractAddress, Data: callData}, nil)
+		if err != nil || len(result) < 32 {
+			continue
+		}
+
+		if new(big.Int).SetBytes(result[:32]).Sign() != 0 {
+			return nftStandard
+		}
+	}
+
+	return standard.UNKNOWN
+}
+
+func printNewCollection(collection *NewCollection) {
+	name := collection.Name
+	if name == "" {
+		name = "unnamed"
+	}
+
+	fmtSymbol := ""
+	if collection.Symbol != "" {
+		fmtSymbol = style.DarkGrayStyle.Render(" (" + strings.ToUpper(collection.Symbol) + ")")
+	}
+
+	fmtDeployer := ""
+	if collection.Deployer != internal.ZeroAddress {
+		fmtDeployer = style.DarkGrayStyle.Render(" by ") + style.ShortenAddressStyled(&collection.Deployer, style.GrayStyle)
+	}
+
+	gloomberg.PrModf("deploy", "%s %s%s %s%s %s",
+		style.BoldAlmostWhite(degendb.NewCollection.String()),
+		style.BoldAlmostWhite(name),
+		fmtSymbol,
+		style.GrayStyle.Render(fmt.Sprintf("%s %s", collection.Standard, degendb.NewCollection.ActionName())),
+		fmtDeployer,
+		style.TerminalLink(links.ChainTx(0, collection.TxHash), style.ShortenAddressStyled(&collection.ContractAddress, style.GrayStyle)),
+	)
+}
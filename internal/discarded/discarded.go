@@ -0,0 +1,165 @@
+package discarded
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+// Reason is the filter that discarded an event.
+type Reason string
+
+const (
+	ReasonScam           Reason = "scam"
+	ReasonPluginVeto     Reason = "plugin-veto"
+	ReasonIgnored        Reason = "ignored-collection"
+	ReasonMinValue       Reason = "min-value"
+	ReasonHiddenMint     Reason = "hidden-mint"
+	ReasonHiddenBurn     Reason = "hidden-burn"
+	ReasonHiddenReburn   Reason = "hidden-reburn"
+	ReasonHiddenTransfer Reason = "hidden-transfer"
+	ReasonHiddenUnknown  Reason = "hidden-unknown"
+	ReasonNoTokens       Reason = "no-tokens"
+)
+
+// Event is a discarded event with the reason it was discarded.
+type Event struct {
+	At          time.Time   `json:"at"`
+	TxHash      common.Hash `json:"tx_hash"`
+	Action      string      `json:"action"`
+	Collections []string    `json:"collections"`
+	Price       float64     `json:"price"`
+	Reason      Reason      `json:"reason"`
+	Detail      string      `json:"detail,omitempty"`
+}
+
+var (
+	// ring buffer of the recently discarded events
+	events   = make([]*Event, 0)
+	next     int
+	eventsMu sync.RWMutex
+
+	discardedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gloomberg_discarded_events_total",
+		Help: "The number of events not printed by reason.",
+	}, []string{"reason"})
+)
+
+// Enabled returns true if discarded events should be kept.
+func Enabled() bool {
+	return viper.GetBool("discarded.enabled")
+}
+
+// Add counts a discarded event & keeps it in the ring buffer (and redis for 'gloomberg discarded').
+func Add(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, reason Reason, detail string) {
+	discardedCounter.WithLabelValues(string(reason)).Inc()
+
+	if !Enabled() {
+		return
+	}
+
+	event := &Event{
+		At:          time.Now(),
+		TxHash:      ttx.TxHash,
+		Action:      ttx.Action.String(),
+		Collections: collectionNames(gb, ttx),
+		Price:       ttx.GetPrice().Ether(),
+		Reason:      reason,
+		Detail:      detail,
+	}
+
+	size := max(viper.GetInt("discarded.size"), 1)
+
+	eventsMu.Lock()
+	if len(events) < size {
+		events = append(events, event)
+	} else {
+		events[next%len(events)] = event
+	}
+
+	next = (next + 1) % size
+	eventsMu.Unlock()
+
+	if viper.GetBool("redis.enabled") && gb.Rueidi != nil {
+		go store(gb, event, int64(size))
+	}
+}
+
+// Recent returns the last n discarded events (optionally only with the given reason), newest first.
+func Recent(n int, reason Reason) []*Event {
+	eventsMu.RLock()
+	recent := make([]*Event, 0, len(events))
+
+	for _, event := range events {
+		if reason == "" || event.Reason == reason {
+			recent = append(recent, event)
+		}
+	}
+	eventsMu.RUnlock()
+
+	sort.Slice(recent, func(i, j int) bool { return recent[i].At.After(recent[j].At) })
+
+	return recent[:min(max(n, 0), len(recent))]
+}
+
+// Load returns the discarded events stored in redis by a running 'gloomberg live', newest first.
+func Load(ctx context.Context, gb *gloomberg.Gloomberg) ([]*Event, error) {
+	rawEvents, err := gb.Rueidi.GetDiscardedEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]*Event, 0, len(rawEvents))
+
+	for _, rawEvent := range rawEvents {
+		var event Event
+		if err := json.Unmarshal([]byte(rawEvent), &event); err != nil {
+			continue
+		}
+
+		loaded = append(loaded, &event)
+	}
+
+	return loaded, nil
+}
+
+func store(gb *gloomberg.Gloomberg, event *Event, size int64) {
+	rawEvent, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	if err := gb.Rueidi.AddDiscardedEvent(context.Background(), string(rawEvent), size); err != nil {
+		gbl.Log.Debugf("❗️ storing discarded event %s failed: %s", event.TxHash.Hex(), err)
+	}
+}
+
+func collectionNames(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) []string {
+	names := make([]string, 0)
+
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	for contractAddress := range ttx.GetTransfersByContract() {
+		if collection, ok := gb.CollectionDB.Collections[contractAddress]; ok && collection.Name != "" {
+			names = append(names, collection.Name)
+		} else {
+			names = append(names, style.ShortenAddress(contractAddress))
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
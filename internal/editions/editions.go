@@ -0,0 +1,359 @@
+package editions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/collections"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/pnl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/standard"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/notify"
+	"github.com/benleb/gloomberg/internal/style"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+var (
+	topicClaimMint      = common.HexToHash(string(topic.ClaimMint))
+	topicClaimMintBatch = common.HexToHash(string(topic.ClaimMintBatch))
+	topicZoraPurchased  = common.HexToHash(string(topic.ZoraPurchased))
+
+	// getClaim(address,uint256) of the manifold lazy claim extensions
+	getClaimSelector = hexutil.MustDecode("0x0f79ab39")
+	// sale(address,uint256) of the zora sale strategies (minters)
+	saleSelector = hexutil.MustDecode("0x611efc09")
+)
+
+// Edition is an erc1155 token of an own collection minted via a manifold claim or zora sale or redeemed via burn-redeem.
+type Edition struct {
+	Contract common.Address
+	TokenID  *big.Int
+	Name     string
+
+	// manifold, zora or burn-redeem
+	Platform string
+
+	Minted   uint64
+	Burned   uint64
+	Redeemed uint64
+
+	// max supply of the claim, 0 for open editions
+	TotalMax uint64
+	// end of the claim/sale, zero if unknown or open-ended
+	EndsAt time.Time
+
+	LastSeen time.Time
+}
+
+func (e *Edition) key() string {
+	return editionKey(e.Contract, e.TokenID)
+}
+
+func editionKey(contractAddress common.Address, tokenID *big.Int) string {
+	return fmt.Sprintf("%s:%s", contractAddress.Hex(), tokenID)
+}
+
+// kind returns e.g. "manifold open edition" or "zora 100 edition".
+func (e *Edition) kind() string {
+	size := "open edition"
+	if e.TotalMax > 0 {
+		size = fmt.Sprintf("%d edition", e.TotalMax)
+	}
+
+	if e.Platform == "" {
+		return size
+	}
+
+	return e.Platform + " " + size
+}
+
+var (
+	// editions by contract:tokenID
+	editions   = make(map[string]*Edition)
+	editionsMu sync.Mutex
+
+	// editions we are currently looking up or already looked up
+	lookups = mapset.NewSet[string]()
+)
+
+// Enabled returns true if the editions of own collections should be tracked.
+func Enabled() bool {
+	return viper.GetBool("editions.enabled")
+}
+
+// Track counts the erc1155 mints, burns & redeems of own collections in a mint, burn or burn-redeem tx
+// and returns a label like "manifold open edition · 1234 minted · ends in 3h". the claim/sale of new
+// editions is looked up in the background.
+func Track(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) string {
+	if ttx.Action != degendb.Mint && ttx.Action != degendb.Burn && ttx.Action != degendb.BurnRedeem {
+		return ""
+	}
+
+	isBurnRedeem := ttx.Action == degendb.BurnRedeem || (ttx.Tx != nil && ttx.Tx.To() != nil && *ttx.Tx.To() == internal.ManifoldBurnRedeemERC1155)
+
+	labels := make([]string, 0)
+
+	for _, transfer := range ttx.Transfers {
+		if transfer.Standard != standard.ERC1155 || transfer.Token == nil || transfer.Token.ID == nil || transfer.AmountTokens == nil {
+			continue
+		}
+
+		isMint := transfer.From == internal.ZeroAddress
+		isBurn := internal.IsBurnAddress(transfer.To)
+
+		if !isMint && !isBurn {
+			continue
+		}
+
+		collection := ownCollection(gb, transfer.Token.Address)
+		if collection == nil {
+			continue
+		}
+
+		edition := getEdition(collection, transfer.Token.Address, transfer.Token.ID)
+
+		editionsMu.Lock()
+
+		amount := transfer.AmountTokens.Uint64()
+
+		switch {
+		case isBurn:
+			edition.Burned += amount
+		case isBurnRedeem:
+			edition.Redeemed += amount
+			if edition.Platform == "" {
+				edition.Platform = "burn-redeem"
+			}
+		default:
+			edition.Minted += amount
+		}
+
+		edition.LastSeen = time.Now()
+
+		// the burned tokens of a burn-redeem are labeled by the redeemed token
+		if !isBurn || ttx.Action == degendb.Burn {
+			labels = append(labels, edition.label())
+		}
+
+		editionsMu.Unlock()
+
+		if lookups.Add(edition.key()) {
+			go lookupSale(gb.PoolFor(ttx.ChainID), ttx.TxReceipt, edition)
+		}
+	}
+
+	return strings.Join(labels, " | ")
+}
+
+// label returns the kind & counts of the edition, the edition must be locked.
+func (e *Edition) label() string {
+	parts := []string{"🎟️ " + e.kind()}
+
+	if e.Minted > 0 {
+		minted := fmt.Sprintf("%d minted", e.Minted)
+		if e.TotalMax > 0 {
+			minted = fmt.Sprintf("%d/%d minted", e.Minted, e.TotalMax)
+		}
+
+		parts = append(parts, minted)
+	}
+
+	if e.Redeemed > 0 {
+		parts = append(parts, fmt.Sprintf("%d redeemed", e.Redeemed))
+	}
+
+	if e.Burned > 0 {
+		parts = append(parts, fmt.Sprintf("%d burned", e.Burned))
+	}
+
+	if !e.EndsAt.IsZero() && time.Now().Before(e.EndsAt) {
+		parts = append(parts, "ends in "+pnl.FormatHeldFor(time.Until(e.EndsAt)))
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// ownCollection returns the collection if it is an own (wallet or configured) collection.
+func ownCollection(gb *gloomberg.Gloomberg, contractAddress common.Address) *collections.Collection {
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	collection, ok := gb.CollectionDB.Collections[contractAddress]
+	if !ok || collection == nil || (collection.Source != degendb.FromWallet && collection.Source != degendb.FromConfiguration) {
+		return nil
+	}
+
+	return collection
+}
+
+func getEdition(collection *collections.Collection, contractAddress common.Address, tokenID *big.Int) *Edition {
+	editionsMu.Lock()
+	defer editionsMu.Unlock()
+
+	key := editionKey(contractAddress, tokenID)
+
+	edition, ok := editions[key]
+	if !ok {
+		edition = &Edition{Contract: contractAddress, TokenID: tokenID, Name: collection.Name}
+		editions[key] = edition
+	}
+
+	return edition
+}
+
+// lookupSale gets the max supply & end of the manifold claim or zora sale that minted the edition.
+func lookupSale(providerPool *provider.Pool, receipt *types.Receipt, edition *Edition) {
+	if providerPool == nil || receipt == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, txLog := range receipt.Logs {
+		if len(txLog.Topics) < 3 {
+			continue
+		}
+
+		var platform string
+
+		var callData []byte
+
+		switch txLog.Topics[0] {
+		case topicClaimMint, topicClaimMintBatch:
+			// ClaimMint(creatorContract, instanceId) emitted by the extension
+			if common.BytesToAddress(txLog.Topics[1].Bytes()) != edition.Contract {
+				continue
+			}
+
+			platform = "manifold"
+			callData = append(append(append([]byte{}, getClaimSelector...), txLog.Topics[1].Bytes()...), txLog.Topics[2].Bytes()...)
+
+		case topicZoraPurchased:
+			// Purchased(sender, minter, tokenId, quantity, value) emitted by the collection
+			if txLog.Address != edition.Contract || len(txLog.Topics) < 4 || txLog.Topics[3].Big().Cmp(edition.TokenID) != 0 {
+				continue
+			}
+
+			platform = "zora"
+			callData = append(append(append([]byte{}, saleSelector...), common.LeftPadBytes(edition.Contract.Bytes(), 32)...), txLog.Topics[3].Bytes()...)
+
+		default:
+			continue
+		}
+
+		// manifold: the extension emitting the log, zora: the minter (sale strategy)
+		callTo := txLog.Address
+		if platform == "zora" {
+			callTo = common.BytesToAddress(txLog.Topics[2].Bytes())
+		}
+
+		result, err := providerPool.CallContract(ctx, ethereum.CallMsg{To: &callTo, Data: callData}, nil)
+		if err != nil {
+			gbl.Log.Debugf("🎟️ looking up %s sale of %s failed: %s", platform, edition.key(), err)
+
+			continue
+		}
+
+		editionsMu.Lock()
+
+		edition.Platform = platform
+
+		switch platform {
+		case "manifold":
+			// Claim(total, totalMax, walletMax, startDate, endDate, ...), returned as tuple with dynamic fields
+			if offset := new(big.Int).SetBytes(result[:min(len(result), 32)]).Uint64(); offset < uint64(len(result)) {
+				if claim := result[offset:]; len(claim) >= 5*32 {
+					edition.Minted = max(edition.Minted, new(big.Int).SetBytes(claim[:32]).Uint64())
+					edition.TotalMax = new(big.Int).SetBytes(claim[32:64]).Uint64()
+					edition.EndsAt = unixOrZero(new(big.Int).SetBytes(claim[4*32 : 5*32]))
+				}
+			}
+
+		case "zora":
+			// SalesConfig(saleStart, saleEnd, maxTokensPerAddress, pricePerToken, fundsRecipient)
+			if len(result) >= 2*32 {
+				edition.EndsAt = unixOrZero(new(big.Int).SetBytes(result[32:64]))
+			}
+		}
+
+		editionsMu.Unlock()
+
+		return
+	}
+}
+
+// unixOrZero returns the time of a unix timestamp or zero for 0 & open-ended (max uint64) sales.
+func unixOrZero(timestamp *big.Int) time.Time {
+	if timestamp.Sign() == 0 || !timestamp.IsInt64() || timestamp.Int64() > time.Now().AddDate(100, 0, 0).Unix() {
+		return time.Time{}
+	}
+
+	return time.Unix(timestamp.Int64(), 0)
+}
+
+// RunSummaries prints a summary of the editions ended since the last check & forgets editions not seen for a week.
+func RunSummaries() {
+	ticker := time.NewTicker(time.Minute)
+
+	for range ticker.C {
+		ended := make([]*Edition, 0)
+
+		editionsMu.Lock()
+
+		for key, edition := range editions {
+			switch {
+			case !edition.EndsAt.IsZero() && time.Now().After(edition.EndsAt):
+				ended = append(ended, edition)
+			case time.Since(edition.LastSeen) > 7*24*time.Hour:
+			default:
+				continue
+			}
+
+			delete(editions, key)
+			lookups.Remove(key)
+		}
+
+		editionsMu.Unlock()
+
+		for _, edition := range ended {
+			printSummary(edition)
+		}
+	}
+}
+
+func printSummary(edition *Edition) {
+	counts := make([]string, 0, 3)
+	counts = append(counts, fmt.Sprintf("%d minted", edition.Minted))
+
+	if edition.Redeemed > 0 {
+		counts = append(counts, fmt.Sprintf("%d redeemed", edition.Redeemed))
+	}
+
+	if edition.Burned > 0 {
+		counts = append(counts, fmt.Sprintf("%d burned", edition.Burned))
+	}
+
+	gloomberg.PrModf("edition", "%s #%s %s ended | %s", style.BoldAlmostWhite(edition.Name), edition.TokenID, edition.kind(), strings.Join(counts, " · "))
+
+	if viper.GetBool("notifications.telegram.enabled") && viper.GetInt64("editions.telegram_chat_id") != 0 {
+		message := fmt.Sprintf("🎟️ *%s* #%s %s ended\n%s", edition.Name, edition.TokenID, edition.kind(), strings.Join(counts, " · "))
+
+		go notify.SendMessageViaTelegram(message, viper.GetInt64("editions.telegram_chat_id"), "", 0, nil)
+	}
+}
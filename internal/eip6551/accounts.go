@@ -0,0 +1,113 @@
+package eip6551
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	// erc-6551 reference registry v0.3.
+	ERC6551RegistryV3 = common.HexToAddress("0x000000006551c19487814612e58FE06813775758")
+
+	topicAccountCreated        = common.HexToHash(string(topic.AccountCreated))
+	topicERC6551AccountCreated = common.HexToHash(string(topic.ERC6551AccountCreated))
+)
+
+// Account is a token bound account owned by the parent nft.
+type Account struct {
+	Address       common.Address
+	TokenContract common.Address
+	TokenID       *big.Int
+}
+
+// maxKnownAccounts bounds the accounts kept in memory, the oldest are evicted first.
+const maxKnownAccounts = 10_000
+
+var (
+	knownAccounts = make(map[common.Address]*Account)
+	// addresses of the known accounts in the order they were registered
+	knownAccountsOrder = make([]common.Address, 0)
+	knownAccountsMu    = &sync.RWMutex{}
+)
+
+// String returns the parent nft of the account as "<token contract>/<token id>".
+func (a *Account) String() string {
+	return a.TokenContract.Hex() + "/" + a.TokenID.String()
+}
+
+// ParseAccount parses the account at address from its parent nft formatted as "<token contract>/<token id>".
+func ParseAccount(address common.Address, parent string) (*Account, error) {
+	tokenContract, tokenID, ok := strings.Cut(parent, "/")
+	if !ok || !common.IsHexAddress(tokenContract) {
+		return nil, fmt.Errorf("invalid parent nft: %s", parent)
+	}
+
+	id, ok := new(big.Int).SetString(tokenID, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token id: %s", tokenID)
+	}
+
+	return &Account{Address: address, TokenContract: common.HexToAddress(tokenContract), TokenID: id}, nil
+}
+
+// ParseAccountCreated parses the account creation events of the v0.2 & v0.3 registries.
+func ParseAccountCreated(txLog *types.Log) *Account {
+	if txLog == nil || len(txLog.Topics) == 0 {
+		return nil
+	}
+
+	switch {
+	// v0.2 | no indexed params: account, implementation, chainId, tokenContract, tokenId, salt
+	case txLog.Topics[0] == topicAccountCreated && len(txLog.Data) >= 6*32:
+		return &Account{
+			Address:       common.BytesToAddress(txLog.Data[0:32]),
+			TokenContract: common.BytesToAddress(txLog.Data[96:128]),
+			TokenID:       new(big.Int).SetBytes(txLog.Data[128:160]),
+		}
+
+	// v0.3 | indexed: implementation, tokenContract, tokenId | data: account, salt, chainId
+	case txLog.Topics[0] == topicERC6551AccountCreated && len(txLog.Topics) == 4 && len(txLog.Data) >= 32:
+		return &Account{
+			Address:       common.BytesToAddress(txLog.Data[0:32]),
+			TokenContract: common.BytesToAddress(txLog.Topics[2].Bytes()),
+			TokenID:       new(big.Int).SetBytes(txLog.Topics[3].Bytes()),
+		}
+	}
+
+	return nil
+}
+
+// RegisterAccount remembers a token bound account to attribute its activity to the parent nft.
+func RegisterAccount(account *Account) {
+	knownAccountsMu.Lock()
+	defer knownAccountsMu.Unlock()
+
+	if _, ok := knownAccounts[account.Address]; !ok {
+		knownAccountsOrder = append(knownAccountsOrder, account.Address)
+	}
+
+	knownAccounts[account.Address] = account
+
+	// evict the oldest accounts
+	if overflow := len(knownAccountsOrder) - maxKnownAccounts; overflow > 0 {
+		for _, address := range knownAccountsOrder[:overflow] {
+			delete(knownAccounts, address)
+		}
+
+		knownAccountsOrder = knownAccountsOrder[overflow:]
+	}
+}
+
+// LookupAccount returns the token bound account for the address or nil if it is not known.
+func LookupAccount(address common.Address) *Account {
+	knownAccountsMu.RLock()
+	defer knownAccountsMu.RUnlock()
+
+	return knownAccounts[address]
+}
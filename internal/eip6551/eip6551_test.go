@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func Test_getCreationCode(t *testing.T) {
@@ -93,3 +94,104 @@ func TestGetTokenboundTokenAddress(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseAccountCreated(t *testing.T) {
+	account := common.HexToAddress("0x952311534da0393ADBe14006d43d830e32218f39")
+	tokenContract := common.HexToAddress("0xa23a9e6002cebb284e1797be6e0cad201d13c2f2")
+	tokenId := big.NewInt(1925)
+
+	dataV2 := make([]byte, 0)
+	dataV2 = append(dataV2, common.LeftPadBytes(account.Bytes(), 32)...)
+	dataV2 = append(dataV2, common.LeftPadBytes(TokenboundERC6551AccountProxy.Bytes(), 32)...)
+	dataV2 = append(dataV2, chainID...)
+	dataV2 = append(dataV2, common.LeftPadBytes(tokenContract.Bytes(), 32)...)
+	dataV2 = append(dataV2, common.LeftPadBytes(tokenId.Bytes(), 32)...)
+	dataV2 = append(dataV2, salt...)
+
+	dataV3 := make([]byte, 0)
+	dataV3 = append(dataV3, common.LeftPadBytes(account.Bytes(), 32)...)
+	dataV3 = append(dataV3, salt...)
+	dataV3 = append(dataV3, chainID...)
+
+	tests := []struct {
+		name string
+		log  *types.Log
+		want *Account
+	}{
+		{
+			name: "Test v0.2 AccountCreated",
+			log:  &types.Log{Topics: []common.Hash{topicAccountCreated}, Data: dataV2},
+			want: &Account{Address: account, TokenContract: tokenContract, TokenID: tokenId},
+		},
+		{
+			name: "Test v0.3 ERC6551AccountCreated",
+			log: &types.Log{
+				Topics: []common.Hash{topicERC6551AccountCreated, common.BytesToHash(TokenboundERC6551AccountProxy.Bytes()), common.BytesToHash(tokenContract.Bytes()), common.BigToHash(tokenId)},
+				Data:   dataV3,
+			},
+			want: &Account{Address: account, TokenContract: tokenContract, TokenID: tokenId},
+		},
+		{
+			name: "Test unrelated log",
+			log:  &types.Log{Topics: []common.Hash{common.HexToHash("0x01")}, Data: dataV2},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAccountCreated(tt.log); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAccountCreated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAccount(t *testing.T) {
+	account := &Account{
+		Address:       common.HexToAddress("0x952311534da0393ADBe14006d43d830e32218f39"),
+		TokenContract: common.HexToAddress("0xa23a9e6002cebb284e1797be6e0cad201d13c2f2"),
+		TokenID:       big.NewInt(1925),
+	}
+
+	tests := []struct {
+		name    string
+		parent  string
+		want    *Account
+		wantErr bool
+	}{
+		{name: "round trip", parent: account.String(), want: account},
+		{name: "missing token id", parent: account.TokenContract.Hex(), wantErr: true},
+		{name: "invalid contract", parent: "0x123/1", wantErr: true},
+		{name: "invalid token id", parent: account.TokenContract.Hex() + "/abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccount(account.Address, tt.parent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterAccountEvictsOldest(t *testing.T) {
+	for i := 0; i <= maxKnownAccounts; i++ {
+		RegisterAccount(&Account{Address: common.BigToAddress(big.NewInt(int64(i + 1))), TokenID: big.NewInt(int64(i))})
+	}
+
+	if LookupAccount(common.BigToAddress(big.NewInt(1))) != nil {
+		t.Errorf("oldest account not evicted")
+	}
+
+	if LookupAccount(common.BigToAddress(big.NewInt(maxKnownAccounts+1))) == nil {
+		t.Errorf("newest account not registered")
+	}
+
+	if len(knownAccounts) != maxKnownAccounts || len(knownAccountsOrder) != maxKnownAccounts {
+		t.Errorf("known accounts = %d (order %d), want %d", len(knownAccounts), len(knownAccountsOrder), maxKnownAccounts)
+	}
+}
@@ -0,0 +1,189 @@
+package enswatch
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/go-ens/v3"
+)
+
+var topicNameChanged = common.HexToHash(string(topic.NameChanged))
+
+var (
+	// ens registry & the selector of resolver(bytes32)
+	ensRegistry      = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+	resolverSelector = hexutil.MustDecode("0x0178b8bf")
+)
+
+var nameChangesCounter = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "gloomberg_ens_name_changes_total",
+	Help: "The number of detected primary ens name changes of own & watched wallets.",
+})
+
+// HandleReceipt checks the logs of a tx for primary ens name changes (NameChanged on the reverse node)
+// of own & watched wallets, updates the cached name & the wallets and prints an info line.
+func HandleReceipt(gb *gloomberg.Gloomberg, receipt *types.Receipt) {
+	if receipt == nil || !viper.GetBool("enswatch.enabled") {
+		return
+	}
+
+	var reverseNodes map[common.Hash]common.Address
+
+	for _, txLog := range receipt.Logs {
+		if len(txLog.Topics) < 2 || txLog.Topics[0] != topicNameChanged {
+			continue
+		}
+
+		// only built if the tx contains a name change
+		if reverseNodes == nil {
+			reverseNodes = watchedReverseNodes(gb)
+		}
+
+		address, ok := reverseNodes[txLog.Topics[1]]
+		if !ok {
+			continue
+		}
+
+		// any contract can emit NameChanged, only the resolver of the reverse node sets the name
+		if !isResolverOf(gb, txLog.Address, txLog.Topics[1]) {
+			gbl.Log.Debugf("🪪 ignoring name change of %s emitted by %s: not the resolver of the reverse node", address.Hex(), txLog.Address.Hex())
+
+			continue
+		}
+
+		handleNameChange(gb, address, decodeName(txLog.Data), txLog.TxHash)
+	}
+}
+
+func handleNameChange(gb *gloomberg.Gloomberg, address common.Address, newName string, txHash common.Hash) {
+	ctx := context.Background()
+
+	oldName, _ := gb.Rueidi.GetCachedENSName(ctx, address)
+	if strings.EqualFold(oldName, newName) {
+		return
+	}
+
+	// a primary name is only valid if it resolves back to the address
+	if newName != "" {
+		resolvedAddress, err := gb.ProviderPool.ResolveENS(ctx, newName)
+		if err != nil || resolvedAddress != address {
+			gbl.Log.Debugf("🪪 ignoring name change of %s to %s: forward resolution does not match (%s)", address.Hex(), newName, resolvedAddress.Hex())
+
+			return
+		}
+	}
+
+	if err := gb.Rueidi.StoreENSName(ctx, address, newName); err != nil {
+		gbl.Log.Warnf("❗️ storing the new ens name of %s failed: %s", address.Hex(), err)
+	}
+
+	updateWallets(gb, address, newName)
+
+	nameChangesCounter.Inc()
+
+	fmtOldName := oldName
+	if fmtOldName == "" {
+		fmtOldName = style.ShortenAddress(address)
+	}
+
+	fmtNewName := newName
+	if fmtNewName == "" {
+		fmtNewName = "(no primary name)"
+	}
+
+	gloomberg.PrModf("ens", "%s changed the primary ens name to %s | %s",
+		style.AlmostWhiteStyle.Render(fmtOldName),
+		style.BoldAlmostWhite(fmtNewName),
+		style.TerminalLink(utils.GetEtherscanTxURL(txHash.Hex()), style.ShortenHashStyled(txHash)),
+	)
+}
+
+// updateWallets sets the new name for the own & watched wallets with the address.
+func updateWallets(gb *gloomberg.Gloomberg, address common.Address, newName string) {
+	if gb.OwnWallets != nil {
+		if w, ok := (*gb.OwnWallets)[address]; ok && w != nil {
+			w.ENSName = newName
+		}
+	}
+
+	if gb.Watcher == nil {
+		return
+	}
+
+	for _, user := range gb.Watcher.WatchUsers {
+		for _, w := range user.Wallets {
+			if w != nil && w.Address == address {
+				w.ENSName = newName
+			}
+		}
+	}
+}
+
+// watchedReverseNodes returns the reverse nodes (<address>.addr.reverse) of the own & watched wallets.
+func watchedReverseNodes(gb *gloomberg.Gloomberg) map[common.Hash]common.Address {
+	addresses := make([]common.Address, 0)
+
+	if gb.OwnWallets != nil {
+		addresses = append(addresses, gb.OwnWallets.Addresses()...)
+	}
+
+	if gb.Watcher != nil {
+		for address := range gb.Watcher.UserAddresses {
+			addresses = append(addresses, address)
+		}
+	}
+
+	reverseNodes := make(map[common.Hash]common.Address, len(addresses))
+
+	for _, address := range addresses {
+		node, err := ens.NameHash(strings.ToLower(address.Hex()[2:]) + ".addr.reverse")
+		if err != nil {
+			continue
+		}
+
+		reverseNodes[common.Hash(node)] = address
+	}
+
+	return reverseNodes
+}
+
+// isResolverOf returns true if the ens registry has the contract set as resolver of the node.
+func isResolverOf(gb *gloomberg.Gloomberg, contractAddress common.Address, node common.Hash) bool {
+	callData := make([]byte, 0, 4+32)
+	callData = append(callData, resolverSelector...)
+	callData = append(callData, node.Bytes()...)
+
+	result, err := gb.ProviderPool.CallContract(context.Background(), ethereum.CallMsg{To: &ensRegistry, Data: callData}, nil)
+	if err != nil || len(result) < 32 {
+		return false
+	}
+
+	return common.BytesToAddress(result[:32]) == contractAddress
+}
+
+// decodeName decodes the abi encoded string of the NameChanged event.
+func decodeName(data []byte) string {
+	if len(data) < 64 {
+		return ""
+	}
+
+	length := new(big.Int).SetBytes(data[32:64])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data)-64) {
+		return ""
+	}
+
+	return string(data[64 : 64+length.Uint64()])
+}
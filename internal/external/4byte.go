@@ -3,8 +3,8 @@ package external
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
-	"log"
 	"os"
 	"time"
 
@@ -13,6 +13,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+var ErrNoMethodSignature = errors.New("no method signature found")
+
 type MethodSignatureResponse struct {
 	Count    int               `json:"count"`
 	Next     interface{}       `json:"next"`
@@ -53,14 +55,14 @@ type EventSignature struct {
 
 func GetMethodSignature(methodID string) (MethodSignature, error) {
 	// build url
-	url := "https://www.4byte.directory/api/v1/event-signatures/?hex_signature=" + methodID
-	log.Printf("url: %#v", url)
+	url := "https://www.4byte.directory/api/v1/signatures/?hex_signature=" + methodID
+
 	response, err := utils.HTTP.Get(context.Background(), url)
 	if err != nil {
 		if os.IsTimeout(err) {
-			gbl.Log.Warnf("⌛️ timeout while fetching blur slug: %+v", err.Error())
+			gbl.Log.Warnf("⌛️ timeout while fetching 4byte method signature: %+v", err.Error())
 		} else {
-			gbl.Log.Errorf("❌ blur slug error: %+v", err.Error())
+			gbl.Log.Errorf("❌ 4byte method signature error: %+v", err.Error())
 		}
 
 		return MethodSignature{}, err
@@ -68,7 +70,7 @@ func GetMethodSignature(methodID string) (MethodSignature, error) {
 
 	defer response.Body.Close()
 
-	gbl.Log.Infof("4byte event signatures response status: %s", response.Status)
+	gbl.Log.Debugf("4byte method signatures response status: %s", response.Status)
 
 	// map to EventSignatures
 	var methodSignatureRsponse MethodSignatureResponse
@@ -76,7 +78,7 @@ func GetMethodSignature(methodID string) (MethodSignature, error) {
 	// read response body
 	bodyBytes, err := io.ReadAll(response.Body)
 	if err != nil {
-		gbl.Log.Errorf("❌ error reading 4byte event signatures response: %+v", err.Error())
+		gbl.Log.Errorf("❌ error reading 4byte method signatures response: %+v", err.Error())
 
 		return MethodSignature{}, err
 	}
@@ -84,13 +86,13 @@ func GetMethodSignature(methodID string) (MethodSignature, error) {
 	// unmarshal body
 	err = json.Unmarshal(bodyBytes, &methodSignatureRsponse)
 	if err != nil {
-		gbl.Log.Errorf("❌ error decoding 4byte event signatures response: %+v", err.Error())
+		gbl.Log.Errorf("❌ error decoding 4byte method signatures response: %+v", err.Error())
 
 		return MethodSignature{}, err
 	} else if len(methodSignatureRsponse.Results) == 0 {
-		gbl.Log.Warnf("❌ no 4byte event signatures found for %s", methodID)
+		gbl.Log.Debugf("❌ no 4byte method signatures found for %s", methodID)
 
-		return MethodSignature{}, err
+		return MethodSignature{}, ErrNoMethodSignature
 	}
 
 	return methodSignatureRsponse.Results[0], nil
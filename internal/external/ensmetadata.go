@@ -62,6 +62,11 @@ func GetENSMetadataForTokenID(tokenID *big.Int) (*ENSMetadata, error) {
 	return parseENSMetadataResponse(response)
 }
 
+// NewENSMetadata returns the metadata of a name resolved without the metadata service (name & url only).
+func NewENSMetadata(name string) *ENSMetadata {
+	return &ENSMetadata{Name: name, URL: "https://app.ens.domains/" + name}
+}
+
 func parseENSMetadataResponse(response *http.Response) (*ENSMetadata, error) {
 	bodyBytes, err := io.ReadAll(response.Body)
 
@@ -12,7 +12,6 @@ import (
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/benleb/gloomberg/internal"
 	"github.com/benleb/gloomberg/internal/gbl"
@@ -65,7 +64,13 @@ type Token string
 
 const apiBaseURL = "https://api.etherscan.io/api"
 
-var ErrInvalidJSON = errors.New("invalid json")
+var (
+	ErrInvalidJSON         = errors.New("invalid json")
+	ErrNoEtherscanAPIKey   = errors.New("api_keys.etherscan not set")
+	ErrContractNotVerified = errors.New("contract source code not verified")
+	ErrEtherscanRequest    = errors.New("etherscan request failed")
+	ErrNoTransactions      = errors.New("no transactions found")
+)
 
 func GetEstimatedGasPrice() *big.Int {
 	var estimatedGasPrice *big.Int
@@ -143,34 +148,30 @@ func GetGasOracle() *GasOracle {
 	return &gasOracle
 }
 
-func GetBalances(wallets *wallet.Wallets) ([]*AccountBalance, error) {
-	balances := MultiAccountBalance(wallets)
-
-	for _, balance := range balances {
-		wethBalance, err := GetWETHBalance(common.HexToAddress(balance.Account))
-		if err != nil || wethBalance == nil {
-			gbl.Log.Warnf("could not get weth balance for %s: %s", balance.Account, err.Error())
-
-			continue
-		}
-
-		balance.BalanceWETH = wethBalance
-
-		// blur pool
-		blurPoolBalance, err := GetBlurPoolBalance(common.HexToAddress(balance.Account))
-		if err != nil || blurPoolBalance == nil {
-			gbl.Log.Warnf("could not get blur pool balance for %s: %s", balance.Account, err.Error())
-
-			continue
-		}
+// GetBalance fetches the eth, weth & blur pool balance of a single wallet.
+// any failed request results in an error to avoid showing incomplete (too low) totals.
+func GetBalance(walletAddress common.Address) (*AccountBalance, error) {
+	ethBalance, err := GetETHBalance(walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("eth balance: %w", err)
+	}
 
-		balance.BalanceBlurPool = blurPoolBalance
+	wethBalance, err := GetWETHBalance(walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("weth balance: %w", err)
+	}
 
-		// throttle to avoid hitting the apis reqs/s limit
-		time.Sleep(time.Millisecond * 173)
+	blurPoolBalance, err := GetBlurPoolBalance(walletAddress)
+	if err != nil {
+		return nil, fmt.Errorf("blur pool balance: %w", err)
 	}
 
-	return balances, nil
+	return &AccountBalance{
+		Account:         walletAddress.Hex(),
+		BalanceETH:      ethBalance,
+		BalanceWETH:     wethBalance,
+		BalanceBlurPool: blurPoolBalance,
+	}, nil
 }
 
 func MultiAccountBalance(wallets *wallet.Wallets) []*AccountBalance {
@@ -283,6 +284,15 @@ func GetBlurPoolBalance(walletAddress common.Address) (*big.Int, error) {
 	return GetTokenBalance(walletAddress, internal.BlurPoolTokenContractAddress)
 }
 
+func GetETHBalance(walletAddress common.Address) (*big.Int, error) {
+	// etherscan api access required
+	if !viper.IsSet("api_keys.etherscan") {
+		return nil, ErrNoEtherscanAPIKey
+	}
+
+	return fetchBalance(walletAddress, withAPIKey(fmt.Sprintf(apiBaseURL+"?module=account&action=balance&address=%s&tag=latest", walletAddress)))
+}
+
 func GetTokenBalance(walletAddress common.Address, tokenAddress common.Address) (*big.Int, error) {
 	// etherscan api access required
 	if !viper.IsSet("api_keys.etherscan") {
@@ -294,6 +304,12 @@ func GetTokenBalance(walletAddress common.Address, tokenAddress common.Address)
 		tokenAddress, walletAddress,
 	))
 
+	return fetchBalance(walletAddress, url)
+}
+
+// fetchBalance fetches & parses the result of an etherscan balance/tokenbalance request.
+func fetchBalance(walletAddress common.Address, url string) (*big.Int, error) {
+
 	// // fetch balance
 	// request, _ := http.NewRequest("GET", url, nil)
 	// // client, _ := createEtherscanHTTPClient()
@@ -433,3 +449,105 @@ type Transaction struct {
 	Input             string `json:"input"`
 	Confirmations     string `json:"confirmations"`
 }
+
+// GetFirstTransaction fetches the oldest (normal) transaction sent by or to the wallet.
+func GetFirstTransaction(walletAddress common.Address) (*Transaction, error) {
+	if !viper.IsSet("api_keys.etherscan") {
+		return nil, ErrNoEtherscanAPIKey
+	}
+
+	url := withAPIKey(fmt.Sprintf("%s?module=account&action=txlist&address=%s&page=1&offset=1&startblock=0&endblock=99999999&sort=asc", apiBaseURL, walletAddress.Hex()))
+
+	response, err := utils.HTTP.GetWithTLS12(context.Background(), url)
+	if err != nil {
+		if os.IsTimeout(err) {
+			gbl.Log.Warnf("⌛️ first tx · timeout while fetching: %+v", err.Error())
+		} else {
+			gbl.Log.Errorf("❌ first tx · error: %+v", err.Error())
+		}
+
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil || !json.Valid(responseBody) {
+		gbl.Log.Warnf("first tx · invalid json: %s", err)
+
+		return nil, ErrInvalidJSON
+	}
+
+	var transactions *TransactionsResponse
+	if err := json.NewDecoder(bytes.NewReader(responseBody)).Decode(&transactions); err != nil {
+		gbl.Log.Warnf("first tx · decode error: %s", err.Error())
+
+		return nil, err
+	}
+
+	// status "0" & no result -> wallet without any tx
+	if len(transactions.Result) == 0 {
+		return nil, ErrNoTransactions
+	}
+
+	return &transactions.Result[0], nil
+}
+
+type ContractABIResponse struct {
+	Response
+	Result string `json:"result"`
+}
+
+// GetContractABI fetches the verified abi for a contract from etherscan.
+func GetContractABI(contractAddress common.Address) (string, error) {
+	if !viper.IsSet("api_keys.etherscan") {
+		return "", ErrNoEtherscanAPIKey
+	}
+
+	url := withAPIKey(fmt.Sprintf("%s?module=contract&action=getabi&address=%s", apiBaseURL, contractAddress.Hex()))
+
+	response, err := utils.HTTP.GetWithTLS12(context.Background(), url)
+	if err != nil {
+		if os.IsTimeout(err) {
+			gbl.Log.Warnf("⌛️ contract abi · timeout while fetching: %+v", err.Error())
+		} else {
+			gbl.Log.Errorf("❌ contract abi · error: %+v", err.Error())
+		}
+
+		return "", err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		gbl.Log.Errorf("❌ contract abi · response read error: %+v", err.Error())
+
+		return "", err
+	}
+
+	if !json.Valid(responseBody) {
+		gbl.Log.Warnf("contract abi · invalid json")
+
+		return "", ErrInvalidJSON
+	}
+
+	var contractABIResponse *ContractABIResponse
+	if err := json.NewDecoder(bytes.NewReader(responseBody)).Decode(&contractABIResponse); err != nil {
+		gbl.Log.Warnf("contract abi · decode error: %s", err.Error())
+
+		return "", err
+	}
+
+	// status "0" -> not verified, rate limited, invalid api key, ...
+	if contractABIResponse.Status != "1" {
+		gbl.Log.Debugf("contract abi · no abi for %s: %s | %s", contractAddress.Hex(), contractABIResponse.Message, contractABIResponse.Result)
+
+		// only "Contract source code not verified" is permanent, the other errors are worth a retry
+		if strings.Contains(strings.ToLower(contractABIResponse.Result), "not verified") {
+			return "", fmt.Errorf("%w: %s", ErrContractNotVerified, contractABIResponse.Result)
+		}
+
+		return "", fmt.Errorf("%w: %s", ErrEtherscanRequest, contractABIResponse.Result)
+	}
+
+	return contractABIResponse.Result, nil
+}
@@ -0,0 +1,83 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var ErrReservoirCollectionNotFound = errors.New("collection not found on reservoir")
+
+type ReservoirCollection struct {
+	ID              string `json:"id"`
+	Slug            string `json:"slug"`
+	Name            string `json:"name"`
+	PrimaryContract string `json:"primaryContract"`
+}
+
+type reservoirCollectionsResponse struct {
+	Collections []*ReservoirCollection `json:"collections"`
+}
+
+// ContractAddress returns the primary contract of the collection.
+func (rc *ReservoirCollection) ContractAddress() common.Address {
+	if common.IsHexAddress(rc.PrimaryContract) {
+		return common.HexToAddress(rc.PrimaryContract)
+	}
+
+	return common.HexToAddress(rc.ID)
+}
+
+// GetReservoirCollectionBySlug returns the collection with the given opensea slug from reservoir.
+func GetReservoirCollectionBySlug(ctx context.Context, slug string) (*ReservoirCollection, error) {
+	return getReservoirCollection(ctx, "slug", slug)
+}
+
+// GetReservoirCollectionByAddress returns the collection of the given contract from reservoir.
+func GetReservoirCollectionByAddress(ctx context.Context, contractAddress common.Address) (*ReservoirCollection, error) {
+	return getReservoirCollection(ctx, "id", contractAddress.Hex())
+}
+
+func getReservoirCollection(ctx context.Context, param string, value string) (*ReservoirCollection, error) {
+	// https://docs.reservoir.tools/reference/getcollectionsv7
+	requestURL := fmt.Sprintf("https://api.reservoir.tools/collections/v7?%s=%s&limit=1", param, url.QueryEscape(value))
+
+	header := http.Header{}
+	if apiKey := viper.GetString("api_keys.reservoir"); apiKey != "" {
+		header.Add("x-api-key", apiKey)
+	}
+
+	response, err := utils.HTTP.GetWithHeader(ctx, requestURL, header)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reservoir: %s", response.Status)
+	}
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded *reservoirCollectionsResponse
+	if err := json.Unmarshal(responseBody, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded == nil || len(decoded.Collections) == 0 || decoded.Collections[0] == nil {
+		return nil, ErrReservoirCollectionNotFound
+	}
+
+	return decoded.Collections[0], nil
+}
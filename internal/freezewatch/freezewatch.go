@@ -0,0 +1,254 @@
+package freezewatch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/notify"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var (
+	topicTransfer = common.HexToHash(string(topic.Transfer))
+	topicPaused   = common.HexToHash(string(topic.Paused))
+	topicUnpaused = common.HexToHash(string(topic.Unpaused))
+
+	pausedSelector       = hexutil.MustDecode("0x5c975abb")
+	ownerOfSelector      = hexutil.MustDecode("0x6352211e")
+	transferFromSelector = hexutil.MustDecode("0x23b872dd")
+)
+
+var freezesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_transfer_freezes_total",
+	Help: "The number of detected transfer freezes of watched collections.",
+}, []string{"collection"})
+
+// state is the last known transfer state of a watched collection.
+type state struct {
+	// last erc721 token moved, used to simulate a transfer by its owner
+	tokenID *big.Int
+
+	frozen bool
+}
+
+var (
+	states   = make(map[common.Address]*state)
+	statesMu sync.Mutex
+)
+
+// HandleReceipt remembers moved tokens of watched collections & checks their transfers on Paused/Unpaused events.
+func HandleReceipt(gb *gloomberg.Gloomberg, receipt *types.Receipt) {
+	if receipt == nil || !viper.GetBool("freezewatch.enabled") {
+		return
+	}
+
+	for _, txLog := range receipt.Logs {
+		if len(txLog.Topics) == 0 || !isWatched(gb, txLog.Address) {
+			continue
+		}
+
+		switch txLog.Topics[0] {
+		case topicTransfer:
+			// erc721 transfers have the token id as 3rd indexed arg
+			if len(txLog.Topics) == 4 {
+				getState(txLog.Address).setTokenID(txLog.Topics[3].Big())
+			}
+
+		case topicPaused, topicUnpaused:
+			go checkCollection(gb, txLog.Address, txLog.TxHash)
+		}
+	}
+}
+
+// Run checks the transfers of all watched collections every freezewatch.interval.
+func Run(gb *gloomberg.Gloomberg) {
+	interval := viper.GetDuration("freezewatch.interval")
+
+	ticker := time.NewTicker(interval)
+	gloomberg.RegisterIdleTicker("freezewatch", ticker, func() time.Duration { return viper.GetDuration("freezewatch.interval") })
+
+	for range ticker.C {
+		for _, contractAddress := range watchedCollections(gb) {
+			checkCollection(gb, contractAddress, common.Hash{})
+		}
+	}
+}
+
+// checkCollection checks if transfers of the collection are frozen & alerts on changes.
+func checkCollection(gb *gloomberg.Gloomberg, contractAddress common.Address, txHash common.Hash) {
+	if gb.ProviderPool == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collectionState := getState(contractAddress)
+
+	frozen, reason, ok := transfersFrozen(ctx, gb.ProviderPool, contractAddress, collectionState.getTokenID())
+	if !ok {
+		return
+	}
+
+	statesMu.Lock()
+	changed := collectionState.frozen != frozen
+	collectionState.frozen = frozen
+	statesMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if frozen {
+		freezesCounter.WithLabelValues(contractAddress.Hex()).Inc()
+	}
+
+	alert(gb, contractAddress, frozen, reason, txHash)
+}
+
+// transfersFrozen returns true & the reason if transfers of the collection are frozen. if a token is known,
+// a transfer by its owner to itself is simulated, otherwise paused() is checked. ok is false if the state is unknown.
+func transfersFrozen(ctx context.Context, providerPool *provider.Pool, contractAddress common.Address, tokenID *big.Int) (bool, string, bool) {
+	if tokenID != nil {
+		tokenIDBytes := common.BigToHash(tokenID).Bytes()
+
+		ownerData := append(append([]byte{}, ownerOfSelector...), tokenIDBytes...)
+
+		result, err := providerPool.CallContract(ctx, ethereum.CallMsg{To: &contractAddress, Data: ownerData}, nil)
+		if err == nil && len(result) >= 32 {
+			owner := common.BytesToAddress(result[:32])
+
+			transferData := make([]byte, 0, 4+3*32)
+			transferData = append(transferData, transferFromSelector...)
+			transferData = append(transferData, common.LeftPadBytes(owner.Bytes(), 32)...)
+			transferData = append(transferData, common.LeftPadBytes(owner.Bytes(), 32)...)
+			transferData = append(transferData, tokenIDBytes...)
+
+			_, err = providerPool.CallContract(ctx, ethereum.CallMsg{From: owner, To: &contractAddress, Data: transferData}, nil)
+
+			switch {
+			case err == nil:
+				return false, "", true
+			case strings.Contains(err.Error(), "revert"):
+				return true, fmt.Sprintf("transfer of #%s by its owner reverts: %s", tokenID, err), true
+			default:
+				gbl.Log.Debugf("🧊 simulating transfer of %s #%s failed: %s", contractAddress.Hex(), tokenID, err)
+			}
+		}
+	}
+
+	result, err := providerPool.CallContract(ctx, ethereum.CallMsg{To: &contractAddress, Data: pausedSelector}, nil)
+	if err != nil || len(result) < 32 {
+		return false, "", false
+	}
+
+	return new(big.Int).SetBytes(result[:32]).Sign() != 0, "contract is paused", true
+}
+
+func alert(gb *gloomberg.Gloomberg, contractAddress common.Address, frozen bool, reason string, txHash common.Hash) {
+	gb.CollectionDB.RWMu.RLock()
+	collection := gb.CollectionDB.Collections[contractAddress]
+	gb.CollectionDB.RWMu.RUnlock()
+
+	if collection == nil {
+		return
+	}
+
+	change := "transfers unfrozen"
+	if frozen {
+		change = "transfers frozen"
+	}
+
+	fmtTx := ""
+	if txHash != (common.Hash{}) {
+		fmtTx = " | " + style.TerminalLink(utils.GetEtherscanTxURL(txHash.Hex()), style.ShortenHashStyled(txHash))
+	}
+
+	gloomberg.PrModf("freeze", "%s %s | %s%s", collection.Style().Render(collection.Name), style.BoldAlmostWhite(change), reason, fmtTx)
+
+	if viper.GetBool("notifications.telegram.enabled") && viper.GetInt64("freezewatch.telegram_chat_id") != 0 {
+		message := strings.Builder{}
+		message.WriteString(fmt.Sprintf("🧊 *%s* %s\n", collection.Name, change))
+
+		if reason != "" {
+			message.WriteString(reason + "\n")
+		}
+
+		if txHash != (common.Hash{}) {
+			message.WriteString(fmt.Sprintf("[tx](%s) · ", utils.GetEtherscanTxURL(txHash.Hex())))
+		}
+
+		message.WriteString(fmt.Sprintf("[contract](%s)", utils.GetEtherscanAddressURL(&contractAddress)))
+
+		go notify.SendMessageViaTelegram(message.String(), viper.GetInt64("freezewatch.telegram_chat_id"), "", 0, nil)
+	}
+}
+
+// isWatched returns true for own (wallet or configured) collections.
+func isWatched(gb *gloomberg.Gloomberg, contractAddress common.Address) bool {
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	collection, ok := gb.CollectionDB.Collections[contractAddress]
+
+	return ok && collection != nil && (collection.Source == degendb.FromWallet || collection.Source == degendb.FromConfiguration)
+}
+
+func watchedCollections(gb *gloomberg.Gloomberg) []common.Address {
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	addresses := make([]common.Address, 0)
+
+	for address, collection := range gb.CollectionDB.Collections {
+		if collection != nil && (collection.Source == degendb.FromWallet || collection.Source == degendb.FromConfiguration) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+func getState(contractAddress common.Address) *state {
+	statesMu.Lock()
+	defer statesMu.Unlock()
+
+	collectionState, ok := states[contractAddress]
+	if !ok {
+		collectionState = &state{}
+		states[contractAddress] = collectionState
+	}
+
+	return collectionState
+}
+
+func (s *state) setTokenID(tokenID *big.Int) {
+	statesMu.Lock()
+	defer statesMu.Unlock()
+
+	s.tokenID = tokenID
+}
+
+func (s *state) getTokenID() *big.Int {
+	statesMu.Lock()
+	defer statesMu.Unlock()
+
+	return s.tokenID
+}
@@ -0,0 +1,178 @@
+package gashistory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/spf13/viper"
+)
+
+var ErrNotEnoughSamples = errors.New("not enough gas samples recorded yet, keep 'gloomberg live' running for a while")
+
+// Sample is a gas price at a point in time.
+type Sample struct {
+	At   time.Time
+	Gwei float64
+}
+
+// Slot are the gas price percentiles of an hour of the day or a weekday.
+type Slot struct {
+	Hour    int
+	Weekday time.Weekday
+
+	Median     float64
+	P25        float64
+	P75        float64
+	NumSamples int
+}
+
+// Advice are the cheapest & most expensive hours/weekdays derived from the gas history.
+type Advice struct {
+	From time.Time
+	To   time.Time
+
+	NumSamples int
+
+	// current gas price & its percentile in the history
+	Current           float64
+	CurrentPercentile float64
+
+	// sorted from cheapest to most expensive
+	Hours    []*Slot
+	Weekdays []*Slot
+}
+
+// Record samples the current gas price every gas.history.interval into the gas history ring buffer in redis.
+func Record(gb *gloomberg.Gloomberg) {
+	ticker := time.NewTicker(viper.GetDuration("gas.history.interval"))
+	gloomberg.RegisterIdleTicker("gashistory", ticker, func() time.Duration { return viper.GetDuration("gas.history.interval") })
+
+	for range ticker.C {
+		gasInfo, err := gb.ProviderPool.GetCurrentGasInfo()
+		if err != nil || gasInfo == nil || gasInfo.GasPriceWei == nil || gasInfo.GasPriceWei.Sign() <= 0 {
+			continue
+		}
+
+		gasPriceGwei, _ := utils.WeiToGwei(gasInfo.GasPriceWei).Float64()
+
+		if err := gb.Rueidi.AddGasSample(context.Background(), time.Now(), gasPriceGwei, viper.GetInt64("gas.history.size")); err != nil {
+			gbl.Log.Debugf("⛽️ storing gas sample failed: %s", err)
+		}
+	}
+}
+
+// Load returns the samples of the gas history, oldest first.
+func Load(ctx context.Context, gb *gloomberg.Gloomberg) ([]*Sample, error) {
+	rawSamples, err := gb.Rueidi.GetGasHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]*Sample, 0, len(rawSamples))
+
+	for i := len(rawSamples) - 1; i >= 0; i-- {
+		rawAt, rawGwei, ok := strings.Cut(rawSamples[i], ":")
+		if !ok {
+			continue
+		}
+
+		unix, err := strconv.ParseInt(rawAt, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		gwei, err := strconv.ParseFloat(rawGwei, 64)
+		if err != nil {
+			continue
+		}
+
+		samples = append(samples, &Sample{At: time.Unix(unix, 0), Gwei: gwei})
+	}
+
+	return samples, nil
+}
+
+// Advise calculates the gas price percentiles per hour of the day & weekday (local time).
+func Advise(samples []*Sample) (*Advice, error) {
+	// at least a day of samples to say something about the hours
+	if len(samples) < 2 || samples[len(samples)-1].At.Sub(samples[0].At) < time.Hour*24 {
+		return nil, ErrNotEnoughSamples
+	}
+
+	byHour := make(map[int][]float64)
+	byWeekday := make(map[time.Weekday][]float64)
+	all := make([]float64, 0, len(samples))
+
+	for _, sample := range samples {
+		localAt := sample.At.Local()
+
+		byHour[localAt.Hour()] = append(byHour[localAt.Hour()], sample.Gwei)
+		byWeekday[localAt.Weekday()] = append(byWeekday[localAt.Weekday()], sample.Gwei)
+		all = append(all, sample.Gwei)
+	}
+
+	current := samples[len(samples)-1].Gwei
+
+	sort.Float64s(all)
+
+	advice := &Advice{
+		From:              samples[0].At,
+		To:                samples[len(samples)-1].At,
+		NumSamples:        len(samples),
+		Current:           current,
+		CurrentPercentile: float64(sort.SearchFloat64s(all, current)) / float64(len(all)) * 100,
+		Hours:             make([]*Slot, 0, len(byHour)),
+		Weekdays:          make([]*Slot, 0, len(byWeekday)),
+	}
+
+	for hour, prices := range byHour {
+		slot := newSlot(prices)
+		slot.Hour = hour
+
+		advice.Hours = append(advice.Hours, slot)
+	}
+
+	for weekday, prices := range byWeekday {
+		slot := newSlot(prices)
+		slot.Weekday = weekday
+
+		advice.Weekdays = append(advice.Weekdays, slot)
+	}
+
+	sort.Slice(advice.Hours, func(i, j int) bool { return advice.Hours[i].Median < advice.Hours[j].Median })
+	sort.Slice(advice.Weekdays, func(i, j int) bool { return advice.Weekdays[i].Median < advice.Weekdays[j].Median })
+
+	return advice, nil
+}
+
+// CheapestHours returns the n hours with the lowest median gas price.
+func (a *Advice) CheapestHours(n int) []*Slot {
+	return a.Hours[:min(max(n, 0), len(a.Hours))]
+}
+
+func newSlot(prices []float64) *Slot {
+	sort.Float64s(prices)
+
+	return &Slot{
+		Median:     percentile(prices, 50),
+		P25:        percentile(prices, 25),
+		P75:        percentile(prices, 75),
+		NumSamples: len(prices),
+	}
+}
+
+// percentile of the sorted values (nearest rank).
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[int(p/100*float64(len(sorted)-1)+0.5)]
+}
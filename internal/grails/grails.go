@@ -0,0 +1,149 @@
+package grails
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/slugs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Config of the watched token ids of a collection in grails.tokens.
+type Config struct {
+	Contract string   `mapstructure:"contract"`
+	IDs      []string `mapstructure:"ids"`
+
+	// optional name shown in the label
+	Name string `mapstructure:"name"`
+}
+
+var (
+	// watched token ids (decimal) per contract
+	grails = make(map[common.Address]map[string]bool)
+	names  = make(map[common.Address]string)
+	mu     sync.RWMutex
+)
+
+// Enabled returns true if grail watching is enabled & at least one token is watched.
+func Enabled() bool {
+	if !viper.GetBool("grails.enabled") {
+		return false
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return len(grails) > 0
+}
+
+// Load reads the watched tokens from grails.tokens.
+func Load() error {
+	var configs []Config
+	if err := viper.UnmarshalKey("grails.tokens", &configs); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	numTokens := 0
+
+	for _, config := range configs {
+		if !common.IsHexAddress(config.Contract) {
+			return fmt.Errorf("invalid grail contract: %s", config.Contract)
+		}
+
+		contractAddress := common.HexToAddress(config.Contract)
+
+		if _, ok := grails[contractAddress]; !ok {
+			grails[contractAddress] = make(map[string]bool)
+		}
+
+		for _, rawID := range config.IDs {
+			tokenID, ok := new(big.Int).SetString(strings.TrimSpace(rawID), 10)
+			if !ok {
+				return fmt.Errorf("invalid grail token id of %s: %s", config.Contract, rawID)
+			}
+
+			grails[contractAddress][tokenID.String()] = true
+			numTokens++
+		}
+
+		if config.Name != "" {
+			names[contractAddress] = config.Name
+		}
+	}
+
+	gbl.Log.Infof("💎 watching %d grails of %d collections", numTokens, len(grails))
+
+	return nil
+}
+
+// Contains returns true if the token is a watched grail.
+func Contains(contractAddress common.Address, tokenID *big.Int) bool {
+	if tokenID == nil {
+		return false
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return grails[contractAddress][tokenID.String()]
+}
+
+// Label returns a label if one of the transferred tokens of the tx is a watched grail.
+func Label(ttx *totra.TokenTransaction) string {
+	for _, transfer := range ttx.Transfers {
+		if transfer.Token == nil || !Contains(transfer.Token.Address, transfer.Token.ID) {
+			continue
+		}
+
+		mu.RLock()
+		name := names[transfer.Token.Address]
+		mu.RUnlock()
+
+		if name == "" {
+			return fmt.Sprintf("💎 grail #%s", transfer.Token.ID)
+		}
+
+		return fmt.Sprintf("💎 grail %s #%s", name, transfer.Token.ID)
+	}
+
+	return ""
+}
+
+// SlugSubscriptions returns the listing & bid subscriptions for the collections of the grails
+// so their order events are received even if the collections are not held or configured.
+func SlugSubscriptions(ctx context.Context) degendb.SlugSubscriptions {
+	mu.RLock()
+	contractAddresses := make([]common.Address, 0, len(grails))
+	for contractAddress := range grails {
+		contractAddresses = append(contractAddresses, contractAddress)
+	}
+	mu.RUnlock()
+
+	subscriptions := make(degendb.SlugSubscriptions, 0, len(contractAddresses))
+
+	for _, contractAddress := range contractAddresses {
+		slug, err := slugs.Slug(ctx, contractAddress)
+		if err != nil {
+			gbl.Log.Warnf("💎 no slug found for grail collection %s: %s", contractAddress.Hex(), err)
+
+			continue
+		}
+
+		subscriptions = append(subscriptions, degendb.SlugSubscription{
+			Slug:   slug,
+			Events: []degendb.EventType{degendb.Listing, degendb.Bid},
+		})
+	}
+
+	return subscriptions
+}
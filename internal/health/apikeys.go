@@ -0,0 +1,138 @@
+package health
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// APIKeyStatus is the health of a single api key of a service.
+type APIKeyStatus struct {
+	// masked key, safe to print & use as metric label
+	Key string
+
+	Active bool
+	Dead   bool
+
+	Disconnects  uint64
+	AuthFailures uint64
+
+	LastDisconnect  time.Time
+	LastAuthFailure time.Time
+}
+
+var (
+	apiKeyDisconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gloomberg_api_key_disconnects_total",
+		Help: "The number of stream disconnects per service & api key.",
+	}, []string{"service", "key"})
+
+	apiKeyAuthFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gloomberg_api_key_auth_failures_total",
+		Help: "The number of 401/403 responses per service & api key.",
+	}, []string{"service", "key", "status"})
+
+	apiKeyUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gloomberg_api_key_up",
+		Help: "Whether an api key is usable (1) or considered dead (0).",
+	}, []string{"service", "key"})
+
+	apiKeys   = make(map[string][]*APIKeyStatus)
+	apiKeysMu = &sync.RWMutex{}
+)
+
+// MaskAPIKey returns the last 4 characters of a key, e.g. "…a1b2".
+func MaskAPIKey(key string) string {
+	if len(key) <= 4 {
+		return "…" + key
+	}
+
+	return "…" + key[len(key)-4:]
+}
+
+// RegisterAPIKeys sets the keys of a service, the first one is marked as active.
+func RegisterAPIKeys(service string, keys []string) {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+
+	apiKeys[service] = make([]*APIKeyStatus, 0, len(keys))
+
+	for idx, key := range keys {
+		apiKeys[service] = append(apiKeys[service], &APIKeyStatus{Key: MaskAPIKey(key), Active: idx == 0})
+		apiKeyUp.WithLabelValues(service, MaskAPIKey(key)).Set(1)
+	}
+}
+
+// APIKeyDisconnected counts a stream disconnect of the key.
+func APIKeyDisconnected(service string, key string) {
+	apiKeyDisconnects.WithLabelValues(service, MaskAPIKey(key)).Inc()
+
+	if status := getAPIKey(service, key); status != nil {
+		apiKeysMu.Lock()
+		status.Disconnects++
+		status.LastDisconnect = time.Now()
+		apiKeysMu.Unlock()
+	}
+}
+
+// APIKeyAuthFailed counts a 401/403 response for the key.
+func APIKeyAuthFailed(service string, key string, statusCode int) {
+	apiKeyAuthFailures.WithLabelValues(service, MaskAPIKey(key), strconv.Itoa(statusCode)).Inc()
+
+	if status := getAPIKey(service, key); status != nil {
+		apiKeysMu.Lock()
+		status.AuthFailures++
+		status.LastAuthFailure = time.Now()
+		apiKeysMu.Unlock()
+	}
+}
+
+// SetAPIKeyDead marks a key as (un)usable.
+func SetAPIKeyDead(service string, key string, dead bool) {
+	apiKeyUp.WithLabelValues(service, MaskAPIKey(key)).Set(boolToFloat(!dead))
+
+	if status := getAPIKey(service, key); status != nil {
+		apiKeysMu.Lock()
+		status.Dead = dead
+		apiKeysMu.Unlock()
+	}
+}
+
+// SetActiveAPIKey marks the key currently in use.
+func SetActiveAPIKey(service string, key string) {
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+
+	for _, status := range apiKeys[service] {
+		status.Active = status.Key == MaskAPIKey(key)
+	}
+}
+
+// APIKeys returns a copy of the key states of a service.
+func APIKeys(service string) []APIKeyStatus {
+	apiKeysMu.RLock()
+	defer apiKeysMu.RUnlock()
+
+	keys := make([]APIKeyStatus, 0, len(apiKeys[service]))
+	for _, status := range apiKeys[service] {
+		keys = append(keys, *status)
+	}
+
+	return keys
+}
+
+func getAPIKey(service string, key string) *APIKeyStatus {
+	apiKeysMu.RLock()
+	defer apiKeysMu.RUnlock()
+
+	for _, status := range apiKeys[service] {
+		if status.Key == MaskAPIKey(key) {
+			return status
+		}
+	}
+
+	return nil
+}
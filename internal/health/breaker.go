@@ -0,0 +1,135 @@
+package health
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker open, using cached data only")
+
+// CircuitBreaker pauses the calls to a failing api. after circuit_breaker.threshold consecutive
+// failures it opens & only lets a single probe call through every circuit_breaker.probe_interval.
+// the first successful probe closes it again.
+type CircuitBreaker struct {
+	source string
+
+	failures  int
+	open      bool
+	openedAt  time.Time
+	lastProbe time.Time
+
+	mu sync.Mutex
+}
+
+var (
+	breakers   = make(map[string]*CircuitBreaker)
+	breakersMu sync.Mutex
+
+	breakerOpen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gloomberg_circuit_breaker_open",
+		Help: "Whether the circuit breaker of an api is open (1) or closed (0).",
+	}, []string{"source"})
+)
+
+// Breaker returns the circuit breaker of the given source.
+func Breaker(source string) *CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	breaker, ok := breakers[source]
+	if !ok {
+		breaker = &CircuitBreaker{source: source}
+		breakers[source] = breaker
+	}
+
+	return breaker
+}
+
+// DegradedSources returns the sources with an open circuit breaker.
+func DegradedSources() []string {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	degraded := make([]string, 0)
+
+	for source, breaker := range breakers {
+		if breaker.IsOpen() {
+			degraded = append(degraded, source)
+		}
+	}
+
+	sort.Strings(degraded)
+
+	return degraded
+}
+
+// Allow returns true if a call should be made, when open only one probe per probe interval is allowed.
+func (cb *CircuitBreaker) Allow() bool {
+	if !viper.GetBool("circuit_breaker.enabled") {
+		return true
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !cb.open {
+		return true
+	}
+
+	if time.Since(cb.lastProbe) < viper.GetDuration("circuit_breaker.probe_interval") {
+		return false
+	}
+
+	cb.lastProbe = time.Now()
+
+	return true
+}
+
+// Success records a successful call & closes the breaker.
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+
+	if cb.open {
+		cb.open = false
+		breakerOpen.WithLabelValues(cb.source).Set(0)
+
+		gbl.Log.Infof("✅ %s recovered after %s, circuit breaker closed", cb.source, time.Since(cb.openedAt).Truncate(time.Second))
+	}
+}
+
+// Failure records a failed or rate-limited call & opens the breaker if the threshold is reached.
+func (cb *CircuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+
+	if cb.open || cb.failures < viper.GetInt("circuit_breaker.threshold") || !viper.GetBool("circuit_breaker.enabled") {
+		return
+	}
+
+	cb.open = true
+	cb.openedAt = time.Now()
+	cb.lastProbe = time.Now()
+	breakerOpen.WithLabelValues(cb.source).Set(1)
+
+	gbl.Log.Warnf("🔌 %s failed %d times in a row, circuit breaker open - using cached data only", cb.source, cb.failures)
+}
+
+// IsOpen returns true if the calls are paused.
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.open
+}
@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/rueidis"
+	"github.com/spf13/viper"
+)
+
+// event sources.
+const (
+	SourceChain   = "chain"
+	SourceOpenSea = "opensea"
+	SourcePubSub  = "pubsub"
+	SourceRemote  = "remote"
+
+	// heartbeat of the stats box ticker.
+	SourceStatsTicker = "statsbox"
+)
+
+var (
+	redisUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gloomberg_dependency_redis_up",
+		Help: "Whether the redis server is reachable (1) or not (0).",
+	})
+
+	rpcUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gloomberg_dependency_rpc_up",
+		Help: "Whether the rpc endpoint of a provider is reachable (1) or not (0).",
+	}, []string{"provider"})
+
+	streamConnected = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gloomberg_dependency_stream_connected",
+		Help: "Whether the connection to an event stream is established (1) or not (0).",
+	}, []string{"stream"})
+
+	lastEventAge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gloomberg_dependency_last_event_age_seconds",
+		Help: "Seconds since the last event was received from a source.",
+	}, []string{"source"})
+
+	lastEvents   = make(map[string]time.Time)
+	lastEventsMu = &sync.RWMutex{}
+
+	connectedSince   = make(map[string]time.Time)
+	connectedSinceMu = &sync.RWMutex{}
+)
+
+// EventReceived marks that an event from the given source has just been received.
+func EventReceived(source string) {
+	lastEventsMu.Lock()
+	lastEvents[source] = time.Now()
+	lastEventsMu.Unlock()
+}
+
+// LastEventAt returns the time the last event from the given source was received.
+func LastEventAt(source string) time.Time {
+	lastEventsMu.RLock()
+	defer lastEventsMu.RUnlock()
+
+	return lastEvents[source]
+}
+
+// SetStreamConnected sets the connection state of an event stream.
+func SetStreamConnected(stream string, connected bool) {
+	streamConnected.WithLabelValues(stream).Set(boolToFloat(connected))
+
+	connectedSinceMu.Lock()
+	defer connectedSinceMu.Unlock()
+
+	if connected {
+		connectedSince[stream] = time.Now()
+	} else {
+		delete(connectedSince, stream)
+	}
+}
+
+// StreamConnectedSince returns the time the stream has been connected or the zero time if it is not connected.
+func StreamConnectedSince(stream string) time.Time {
+	connectedSinceMu.RLock()
+	defer connectedSinceMu.RUnlock()
+
+	return connectedSince[stream]
+}
+
+// StartChecks periodically checks the dependencies & updates the gauges.
+func StartChecks(rdb rueidis.Client, providerPool *provider.Pool) {
+	ticker := time.NewTicker(viper.GetDuration("metrics.health_interval"))
+
+	for ; true; <-ticker.C {
+		check(rdb, providerPool)
+	}
+}
+
+func check(rdb rueidis.Client, providerPool *provider.Pool) {
+	timeout := viper.GetDuration("metrics.health_timeout")
+
+	// redis
+	if rdb != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := rdb.Do(ctx, rdb.B().Ping().Build()).Error()
+
+		cancel()
+
+		if err != nil {
+			gbl.Log.Debugf("❤️‍🩹 redis health check failed: %s", err)
+		}
+
+		redisUp.Set(boolToFloat(err == nil))
+	}
+
+	// rpc endpoints
+	if providerPool != nil {
+		for _, p := range providerPool.GetProviders() {
+			if p.Client == nil {
+				rpcUp.WithLabelValues(p.Name).Set(0)
+
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			_, err := p.Client.BlockNumber(ctx)
+
+			cancel()
+
+			if err != nil {
+				gbl.Log.Debugf("❤️‍🩹 rpc health check for %s failed: %s", p.Name, err)
+			}
+
+			rpcUp.WithLabelValues(p.Name).Set(boolToFloat(err == nil))
+		}
+	}
+
+	// event ages
+	lastEventsMu.RLock()
+	for source, receivedAt := range lastEvents {
+		lastEventAge.WithLabelValues(source).Set(time.Since(receivedAt).Seconds())
+	}
+	lastEventsMu.RUnlock()
+}
+
+// StartMetricsServer serves the prometheus metrics on metrics.host:metrics.port.
+func StartMetricsServer() {
+	listenOn := net.JoinHostPort(viper.GetString("metrics.host"), fmt.Sprint(viper.GetUint16("metrics.port")))
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:              listenOn,
+		ReadHeaderTimeout: 2 * time.Second,
+		Handler:           mux,
+	}
+
+	gbl.Log.Infof("📊 serving metrics on %s/metrics", listenOn)
+
+	if err := server.ListenAndServe(); err != nil {
+		gbl.Log.Errorf("❌ metrics server failed: %s", err)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package keyboard
+
+import "errors"
+
+var ErrUnsupportedPlatform = errors.New("keybindings are not supported on this platform")
+
+func enableCbreak(_ int) (func(), error) {
+	return nil, ErrUnsupportedPlatform
+}
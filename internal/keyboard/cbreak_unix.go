@@ -0,0 +1,25 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package keyboard
+
+import "golang.org/x/sys/unix"
+
+// enableCbreak disables canonical mode & echo and returns a function to restore the previous state.
+func enableCbreak(fd int) (func(), error) {
+	termios, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	oldState := *termios
+
+	termios.Lflag &^= unix.ICANON | unix.ECHO
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, termios); err != nil {
+		return nil, err
+	}
+
+	return func() { _ = unix.IoctlSetTermios(fd, ioctlWriteTermios, &oldState) }, nil
+}
@@ -0,0 +1,69 @@
+package keyboard
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"golang.org/x/term"
+)
+
+var ErrNoTerminal = errors.New("stdin is not a terminal")
+
+var (
+	// restores the terminal state from before Listen, nil if not in cbreak mode
+	restoreTerminal   func()
+	restoreTerminalMu sync.Mutex
+)
+
+// IsTerminal returns true if stdin is a terminal.
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Restore restores the terminal state from before Listen (echo & line buffering), e.g. on shutdown
+// as Listen blocks until stdin is closed. it is a no-op if the terminal is not in cbreak mode.
+func Restore() {
+	restoreTerminalMu.Lock()
+	defer restoreTerminalMu.Unlock()
+
+	if restoreTerminal != nil {
+		restoreTerminal()
+		restoreTerminal = nil
+	}
+}
+
+// Listen puts the terminal into cbreak mode (no line buffering & echo, but still
+// handling signals like ctrl+c) and calls the handler for every key pressed.
+// it blocks until stdin is closed and restores the previous terminal state afterwards.
+func Listen(handler func(key rune)) error {
+	if !IsTerminal() {
+		return ErrNoTerminal
+	}
+
+	restore, err := enableCbreak(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+
+	restoreTerminalMu.Lock()
+	restoreTerminal = restore
+	restoreTerminalMu.Unlock()
+
+	defer Restore()
+
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		key, _, err := reader.ReadRune()
+		if err != nil {
+			gbl.Log.Debugf("⌨️ stopped listening for keys: %s", err)
+
+			return nil
+		}
+
+		handler(key)
+	}
+}
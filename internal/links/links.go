@@ -0,0 +1,239 @@
+package links
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/chains"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Marketplace is a site to link items & collections to.
+type Marketplace string
+
+const (
+	OpenSea   Marketplace = "opensea"
+	Blur      Marketplace = "blur"
+	LooksRare Marketplace = "looksrare"
+	Magically Marketplace = "magically"
+)
+
+// Link is a link to an item or collection on a marketplace.
+type Link struct {
+	Marketplace Marketplace
+	Name        string
+	Short       string
+	URL         string
+}
+
+type site struct {
+	name  string
+	short string
+
+	// fmt templates, item: contract & token id, collection: contract, profile: wallet
+	item       string
+	collection string
+	profile    string
+}
+
+var sites = map[Marketplace]*site{
+	OpenSea: {
+		name: "OpenSea", short: "OS",
+		item:       "https://opensea.io/assets/ethereum/%s/%s",
+		collection: "https://opensea.io/assets/ethereum/%s",
+		profile:    "https://opensea.io/%s",
+	},
+	Blur: {
+		name: "Blur", short: "BL",
+		item:       "https://blur.io/asset/%s/%s",
+		collection: "https://blur.io/collection/%s",
+		profile:    "https://blur.io/%s",
+	},
+	LooksRare: {
+		name: "LooksRare", short: "LR",
+		item:       "https://looksrare.org/collections/%s/%s",
+		collection: "https://looksrare.org/collections/%s",
+		profile:    "https://looksrare.org/accounts/%s",
+	},
+	Magically: {
+		name: "Magically", short: "MG",
+		item:       "https://magically.gg/collection/%s/%s",
+		collection: "https://magically.gg/collection/%s",
+		profile:    "https://magically.gg/profile/%s",
+	},
+}
+
+// Preferred returns the marketplace configured via links.marketplace (opensea if unknown).
+func Preferred() Marketplace {
+	if marketplace := Marketplace(strings.ToLower(viper.GetString("links.marketplace"))); sites[marketplace] != nil {
+		return marketplace
+	}
+
+	return OpenSea
+}
+
+// Marketplaces returns the preferred marketplace followed by the other ones from links.marketplaces.
+func Marketplaces() []Marketplace {
+	preferred := Preferred()
+	marketplaces := []Marketplace{preferred}
+
+	for _, name := range viper.GetStringSlice("links.marketplaces") {
+		if marketplace := Marketplace(strings.ToLower(name)); sites[marketplace] != nil && marketplace != preferred {
+			marketplaces = append(marketplaces, marketplace)
+		}
+	}
+
+	return marketplaces
+}
+
+// Name returns the display name of the marketplace.
+func (m Marketplace) Name() string {
+	if s := sites[m]; s != nil {
+		return s.name
+	}
+
+	return string(m)
+}
+
+// Short returns the abbreviation of the marketplace, e.g. "OS".
+func (m Marketplace) Short() string {
+	if s := sites[m]; s != nil {
+		return s.short
+	}
+
+	return strings.ToUpper(string(m))
+}
+
+// ItemOn returns the url of the token on the marketplace.
+func ItemOn(marketplace Marketplace, contractAddress common.Address, tokenID *big.Int) string {
+	s := sites[marketplace]
+	if s == nil {
+		s = sites[OpenSea]
+	}
+
+	id := "0"
+	if tokenID != nil {
+		id = tokenID.String()
+	}
+
+	return fmt.Sprintf(template(marketplace, "item", s.item), strings.ToLower(contractAddress.Hex()), id)
+}
+
+// CollectionOn returns the url of the collection on the marketplace.
+func CollectionOn(marketplace Marketplace, contractAddress common.Address) string {
+	s := sites[marketplace]
+	if s == nil {
+		s = sites[OpenSea]
+	}
+
+	return fmt.Sprintf(template(marketplace, "collection", s.collection), strings.ToLower(contractAddress.Hex()))
+}
+
+// ProfileOn returns the url of the wallets profile on the marketplace.
+func ProfileOn(marketplace Marketplace, address common.Address) string {
+	s := sites[marketplace]
+	if s == nil {
+		s = sites[OpenSea]
+	}
+
+	return fmt.Sprintf(template(marketplace, "profile", s.profile), strings.ToLower(address.Hex()))
+}
+
+// Item returns the url of the token on the preferred marketplace.
+func Item(contractAddress common.Address, tokenID *big.Int) string {
+	return ItemOn(Preferred(), contractAddress, tokenID)
+}
+
+// Collection returns the url of the collection on the preferred marketplace.
+func Collection(contractAddress common.Address) string {
+	return CollectionOn(Preferred(), contractAddress)
+}
+
+// Profile returns the url of the wallets profile on the preferred marketplace.
+func Profile(address common.Address) string {
+	return ProfileOn(Preferred(), address)
+}
+
+// ItemLinks returns the links to the token on all configured marketplaces, preferred first.
+func ItemLinks(contractAddress common.Address, tokenID *big.Int) []*Link {
+	itemLinks := make([]*Link, 0)
+
+	for _, marketplace := range Marketplaces() {
+		itemLinks = append(itemLinks, &Link{
+			Marketplace: marketplace,
+			Name:        marketplace.Name(),
+			Short:       marketplace.Short(),
+			URL:         ItemOn(marketplace, contractAddress, tokenID),
+		})
+	}
+
+	return itemLinks
+}
+
+// Tx returns the etherscan url of the transaction.
+func Tx(txHash common.Hash) string {
+	return "https://etherscan.io/tx/" + txHash.Hex()
+}
+
+// ChainTx returns the block explorer url of the transaction on the chain.
+func ChainTx(chainID uint64, txHash common.Hash) string {
+	if chain := chains.ByID(chainID); chain != nil && !chains.IsMainnet(chainID) {
+		return chain.TxURL(txHash)
+	}
+
+	return Tx(txHash)
+}
+
+// ChainItem returns the url of the token on the chain. the marketplaces besides opensea
+// are mainnet only, so tokens on other chains are always linked to opensea.
+func ChainItem(chainID uint64, contractAddress common.Address, tokenID *big.Int) string {
+	if chain := chains.ByID(chainID); chain != nil && !chains.IsMainnet(chainID) {
+		id := "0"
+		if tokenID != nil {
+			id = tokenID.String()
+		}
+
+		return fmt.Sprintf("https://opensea.io/assets/%s/%s/%s", chain.OpenSea, strings.ToLower(contractAddress.Hex()), id)
+	}
+
+	return Item(contractAddress, tokenID)
+}
+
+// ChainCollection returns the url of the collection on the chain (opensea for chains other than mainnet).
+func ChainCollection(chainID uint64, contractAddress common.Address) string {
+	if chain := chains.ByID(chainID); chain != nil && !chains.IsMainnet(chainID) {
+		return fmt.Sprintf("https://opensea.io/assets/%s/%s", chain.OpenSea, strings.ToLower(contractAddress.Hex()))
+	}
+
+	return Collection(contractAddress)
+}
+
+// ChainItemLinks returns the links to the token on the configured marketplaces available on the chain.
+func ChainItemLinks(chainID uint64, contractAddress common.Address, tokenID *big.Int) []*Link {
+	if chains.IsMainnet(chainID) {
+		return ItemLinks(contractAddress, tokenID)
+	}
+
+	return []*Link{{
+		Marketplace: OpenSea,
+		Name:        OpenSea.Name(),
+		Short:       OpenSea.Short(),
+		URL:         ChainItem(chainID, contractAddress, tokenID),
+	}}
+}
+
+// Address returns the etherscan url of the address.
+func Address(address common.Address) string {
+	return "https://etherscan.io/address/" + address.Hex()
+}
+
+// template returns the url template overridden via links.templates.<marketplace>.<kind> or the default.
+func template(marketplace Marketplace, kind string, defaultTemplate string) string {
+	if custom := viper.GetString("links.templates." + string(marketplace) + "." + kind); custom != "" {
+		return custom
+	}
+
+	return defaultTemplate
+}
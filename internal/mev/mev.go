@@ -0,0 +1,153 @@
+package mev
+
+import (
+	"sync"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// number of blocks we keep the nft transfers for to detect flips.
+const flipBlocks = 3
+
+// blockTransfer is a nft transfer seen in a block.
+type blockTransfer struct {
+	from common.Address
+	to   common.Address
+	tx   common.Hash
+}
+
+var (
+	// known bots with their label
+	bots   = make(map[common.Address]string)
+	botsMu sync.RWMutex
+
+	// nft transfers per block & nft id
+	blockTransfers   = make(map[uint64]map[string][]blockTransfer)
+	blockTransfersMu sync.Mutex
+)
+
+// Enabled returns true if mev bot labeling is enabled.
+func Enabled() bool {
+	return viper.GetBool("mev.enabled")
+}
+
+// LoadBots adds the bots from the config (mev.bots) & the addresses tagged as mev-bot in the given labels.
+func LoadBots(labels map[common.Address]*degendb.Address) {
+	botsMu.Lock()
+	defer botsMu.Unlock()
+
+	for _, address := range viper.GetStringSlice("mev.bots") {
+		if common.IsHexAddress(address) {
+			bots[common.HexToAddress(address)] = "mev bot"
+		}
+	}
+
+	for address, label := range labels {
+		if !label.HasTag(degendb.TagMEVBot) {
+			continue
+		}
+
+		name := label.Name
+		if name == "" {
+			name = "mev bot"
+		}
+
+		bots[address] = name
+	}
+
+	gbl.Log.Infof("🤖 %d known mev bots loaded", len(bots))
+}
+
+// IsBot returns true if the address is a known bot or was caught flipping nfts in the same block.
+func IsBot(address common.Address) bool {
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+
+	_, ok := bots[address]
+
+	return ok
+}
+
+// Label returns a label if one of the nft senders/receivers of the tx is a known bot or
+// if a nft of the tx was bought & sold again in the same block (sandwich/arb flip).
+func Label(ttx *totra.TokenTransaction) string {
+	if flipper := detectFlip(ttx); flipper != internal.ZeroAddress {
+		botsMu.Lock()
+		bots[flipper] = "flipper"
+		botsMu.Unlock()
+
+		return "🤖 same-block flip by " + style.ShortenAdressPTR(&flipper)
+	}
+
+	botsMu.RLock()
+	defer botsMu.RUnlock()
+
+	for address := range ttx.GetNFTSenderAndReceiverAddresses().Iter() {
+		if name, ok := bots[address]; ok {
+			return "🤖 " + name + " " + style.ShortenAdressPTR(&address)
+		}
+	}
+
+	return ""
+}
+
+// detectFlip remembers the nft transfers of the tx & returns the address that bought & sold
+// a nft in the same block. txs of a block are processed concurrently, so both directions are checked.
+func detectFlip(ttx *totra.TokenTransaction) common.Address {
+	if ttx.TxReceipt == nil || ttx.TxReceipt.BlockNumber == nil || !ttx.IsMovingNFTs() {
+		return internal.ZeroAddress
+	}
+
+	blockNumber := ttx.TxReceipt.BlockNumber.Uint64()
+
+	blockTransfersMu.Lock()
+	defer blockTransfersMu.Unlock()
+
+	transfersInBlock, ok := blockTransfers[blockNumber]
+	if !ok {
+		transfersInBlock = make(map[string][]blockTransfer)
+		blockTransfers[blockNumber] = transfersInBlock
+
+		// forget old blocks
+		for number := range blockTransfers {
+			if number+flipBlocks < blockNumber {
+				delete(blockTransfers, number)
+			}
+		}
+	}
+
+	var flipper common.Address
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.Token == nil {
+			continue
+		}
+
+		nftID := transfer.Token.NftID()
+
+		for _, previous := range transfersInBlock[nftID] {
+			if previous.tx == ttx.TxHash {
+				continue
+			}
+
+			switch {
+			// bought earlier in the block, sold now
+			case previous.to == transfer.From && transfer.From != internal.ZeroAddress:
+				flipper = transfer.From
+			// sold earlier in the block, bought now
+			case previous.from == transfer.To && transfer.To != internal.ZeroAddress:
+				flipper = transfer.To
+			}
+		}
+
+		transfersInBlock[nftID] = append(transfersInBlock[nftID], blockTransfer{from: transfer.From, to: transfer.To, tx: ttx.TxHash})
+	}
+
+	return flipper
+}
@@ -0,0 +1,240 @@
+package mintsigs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/external"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/abireg"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/viper"
+)
+
+// Stat are the mints using a function selector.
+type Stat struct {
+	Selector  [4]byte
+	Signature string
+
+	Mints     uint64
+	Contracts mapset.Set[common.Address]
+}
+
+// Label returns the signature like "mint(uint256)" or the hex selector if the signature is unknown (yet).
+func (s *Stat) Label() string {
+	if s.Signature != "" {
+		return s.Signature
+	}
+
+	return hexutil.Encode(s.Selector[:])
+}
+
+var (
+	// mint stats by selector
+	stats   = make(map[[4]byte]*Stat)
+	statsMu sync.RWMutex
+
+	// mint selectors by contract, used to find contracts with the same mint functions
+	contractSelectors   = make(map[common.Address]mapset.Set[[4]byte])
+	contractSelectorsMu sync.RWMutex
+
+	// selectors we are currently looking up or already failed to look up
+	lookups = mapset.NewSet[[4]byte]()
+)
+
+// Enabled returns true if the mint function signatures should be collected.
+func Enabled() bool {
+	return viper.GetBool("mintsigs.enabled")
+}
+
+// Track counts the function selector of a mint tx and returns its label.
+// unknown signatures are looked up in the background via the abi registry & 4byte.directory.
+func Track(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) string {
+	if ttx.Action != degendb.Mint || ttx.Tx == nil || ttx.Tx.To() == nil || len(ttx.Tx.Data()) < 4 {
+		return ""
+	}
+
+	contractAddress := *ttx.Tx.To()
+	selector := [4]byte(ttx.Tx.Data()[:4])
+
+	statsMu.Lock()
+
+	stat, ok := stats[selector]
+	if !ok {
+		stat = &Stat{Selector: selector, Contracts: mapset.NewSet[common.Address]()}
+		stats[selector] = stat
+	}
+
+	stat.Mints++
+	stat.Contracts.Add(contractAddress)
+
+	label := stat.Label()
+	isKnown := stat.Signature != ""
+
+	statsMu.Unlock()
+
+	contractSelectorsMu.Lock()
+	if _, ok := contractSelectors[contractAddress]; !ok {
+		contractSelectors[contractAddress] = mapset.NewSet[[4]byte]()
+	}
+
+	contractSelectors[contractAddress].Add(selector)
+	contractSelectorsMu.Unlock()
+
+	if !isKnown && lookups.Add(selector) {
+		go lookupSignature(gb, contractAddress, selector)
+	}
+
+	// calldata template for trending mints
+	if TemplatesEnabled() {
+		trackTemplate(gb, ttx, contractAddress)
+	}
+
+	return label
+}
+
+// IsMintSelector returns true if the function selector was used by a mint seen so far & its label.
+func IsMintSelector(selector [4]byte) (bool, string) {
+	statsMu.RLock()
+	defer statsMu.RUnlock()
+
+	stat, ok := stats[selector]
+	if !ok {
+		return false, ""
+	}
+
+	return true, stat.Label()
+}
+
+// Top returns the n most used mint function selectors, ordered by the number of contracts using them.
+func Top(n int) []*Stat {
+	statsMu.RLock()
+	defer statsMu.RUnlock()
+
+	top := make([]*Stat, 0, len(stats))
+	for _, stat := range stats {
+		top = append(top, stat)
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Contracts.Cardinality() != top[j].Contracts.Cardinality() {
+			return top[i].Contracts.Cardinality() > top[j].Contracts.Cardinality()
+		}
+
+		return top[i].Mints > top[j].Mints
+	})
+
+	return top[:min(max(n, 0), len(top))]
+}
+
+// SameMintFunctions returns the contracts using exactly the same mint selectors as the given contract.
+// a hint for copies/forks of a contract.
+func SameMintFunctions(contractAddress common.Address) []common.Address {
+	contractSelectorsMu.RLock()
+	defer contractSelectorsMu.RUnlock()
+
+	selectors, ok := contractSelectors[contractAddress]
+	if !ok {
+		return nil
+	}
+
+	same := make([]common.Address, 0)
+
+	for address, otherSelectors := range contractSelectors {
+		if address != contractAddress && otherSelectors.Equal(selectors) {
+			same = append(same, address)
+		}
+	}
+
+	return same
+}
+
+// CopyHint returns a hint like "same mint fns as Foo" if only a few other contracts use exactly the same (rare) mint
+// selectors as the given contract. common selectors like mint(uint256) are used by too many contracts to be a hint.
+func CopyHint(gb *gloomberg.Gloomberg, contractAddress common.Address) string {
+	same := SameMintFunctions(contractAddress)
+	if len(same) == 0 || len(same) > viper.GetInt("mintsigs.copy_max_contracts") {
+		return ""
+	}
+
+	names := make([]string, 0, len(same))
+
+	gb.CollectionDB.RWMu.RLock()
+	for _, address := range same {
+		if collection, ok := gb.CollectionDB.Collections[address]; ok && collection.Name != "" {
+			names = append(names, collection.Name)
+		} else {
+			names = append(names, style.ShortenAddress(address))
+		}
+	}
+	gb.CollectionDB.RWMu.RUnlock()
+
+	sort.Strings(names)
+
+	return "same mint fns as " + strings.Join(names, ", ")
+}
+
+// PrintStats prints the most used mint functions every mintsigs.interval.
+func PrintStats() {
+	ticker := time.NewTicker(viper.GetDuration("mintsigs.interval"))
+
+	for range ticker.C {
+		top := Top(viper.GetInt("mintsigs.top"))
+		if len(top) == 0 {
+			continue
+		}
+
+		fmtStats := make([]string, 0, len(top))
+
+		for _, stat := range top {
+			statsMu.RLock()
+			fmtStats = append(fmtStats, fmt.Sprintf("%s %s",
+				style.AlmostWhiteStyle.Render(stat.Label()),
+				style.GrayStyle.Render(fmt.Sprintf("%dx/%dc", stat.Mints, stat.Contracts.Cardinality())),
+			))
+			statsMu.RUnlock()
+		}
+
+		gloomberg.PrMod("mint", "top mint fns: "+strings.Join(fmtStats, style.DarkGrayStyle.Render(" · ")))
+	}
+}
+
+func lookupSignature(gb *gloomberg.Gloomberg, contractAddress common.Address, selector [4]byte) {
+	var signature string
+
+	// verified abi of the contract
+	if abireg.Enabled() {
+		if sig, err := gb.ABIs.MethodSignature(context.Background(), contractAddress, selector[:]); err == nil {
+			signature = sig
+		}
+	}
+
+	// 4byte.directory
+	if signature == "" {
+		if methodSignature, err := external.GetMethodSignature(hexutil.Encode(selector[:])); err == nil {
+			signature = methodSignature.TextSignature
+		}
+	}
+
+	if signature == "" {
+		gbl.Log.Debugf("🌱 no signature found for mint selector %s", hexutil.Encode(selector[:]))
+
+		return
+	}
+
+	statsMu.Lock()
+	stats[selector].Signature = signature
+	statsMu.Unlock()
+
+	gbl.Log.Debugf("🌱 mint selector %s → %s", hexutil.Encode(selector[:]), signature)
+}
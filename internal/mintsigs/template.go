@@ -0,0 +1,270 @@
+package mintsigs
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/viper"
+)
+
+// number of recent mint calls per contract compared to find the constant & varying args.
+const numRecentCalls = 8
+
+// mintCall is the calldata of a successful mint.
+type mintCall struct {
+	minter   common.Address
+	data     []byte
+	value    *big.Int
+	mintedAt time.Time
+}
+
+// contractMints are the recent mints of a contract.
+type contractMints struct {
+	calls     []*mintCall
+	mintTimes []time.Time
+
+	alertedAt time.Time
+}
+
+var (
+	recentMints   = make(map[common.Address]*contractMints)
+	recentMintsMu sync.Mutex
+)
+
+// TemplatesEnabled returns true if trending mints should be alerted with a calldata template.
+func TemplatesEnabled() bool {
+	return Enabled() && viper.GetBool("mintsigs.templates.enabled")
+}
+
+// trackTemplate remembers the calldata of a mint & prints a mint alert with a "cast send" template if at least
+// mintsigs.templates.min_mints mints of the contract were seen within mintsigs.templates.window.
+func trackTemplate(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, contractAddress common.Address) {
+	window := viper.GetDuration("mintsigs.templates.window")
+	now := time.Now()
+
+	recentMintsMu.Lock()
+
+	mints, ok := recentMints[contractAddress]
+	if !ok {
+		mints = &contractMints{}
+		recentMints[contractAddress] = mints
+	}
+
+	mints.calls = append(mints.calls, &mintCall{minter: ttx.From, data: ttx.Tx.Data(), value: ttx.Tx.Value(), mintedAt: now})
+	if len(mints.calls) > numRecentCalls {
+		mints.calls = mints.calls[len(mints.calls)-numRecentCalls:]
+	}
+
+	// mints within the window
+	mintTimes := make([]time.Time, 0, len(mints.mintTimes)+1)
+	for _, mintedAt := range mints.mintTimes {
+		if now.Sub(mintedAt) <= window {
+			mintTimes = append(mintTimes, mintedAt)
+		}
+	}
+
+	mints.mintTimes = append(mintTimes, now)
+
+	trending := len(mints.mintTimes) >= viper.GetInt("mintsigs.templates.min_mints") && now.Sub(mints.alertedAt) >= viper.GetDuration("mintsigs.templates.interval")
+	if trending {
+		mints.alertedAt = now
+	}
+
+	numMints := len(mints.mintTimes)
+	calls := append([]*mintCall{}, mints.calls...)
+
+	// forget contracts without mints in the window
+	for address, contract := range recentMints {
+		if address != contractAddress && now.Sub(contract.mintTimes[len(contract.mintTimes)-1]) > window && now.Sub(contract.alertedAt) > viper.GetDuration("mintsigs.templates.interval") {
+			delete(recentMints, address)
+		}
+	}
+
+	recentMintsMu.Unlock()
+
+	if !trending {
+		return
+	}
+
+	name := style.ShortenAddress(contractAddress)
+
+	gb.CollectionDB.RWMu.RLock()
+	if collection, ok := gb.CollectionDB.Collections[contractAddress]; ok && collection.Name != "" {
+		name = collection.Name
+	}
+	gb.CollectionDB.RWMu.RUnlock()
+
+	gloomberg.PrModf("mint", "%s %s %s\n    %s",
+		style.BoldAlmostWhite("trending mint"),
+		style.AlmostWhiteStyle.Render(name),
+		style.GrayStyle.Render(fmt.Sprintf("%d mints in %s", numMints, window)),
+		castTemplate(contractAddress, calls),
+	)
+}
+
+// castTemplate returns a "cast send" command minting like the given calls. args equal in all calls are kept,
+// the minters address is replaced by $WALLET & varying args by a <type> placeholder. if the signature of the
+// selector is unknown (yet), the calldata of the latest mint is used as is.
+func castTemplate(contractAddress common.Address, calls []*mintCall) string {
+	latest := calls[len(calls)-1]
+
+	selector := [4]byte(latest.data[:4])
+
+	statsMu.RLock()
+	signature := ""
+	if stat, ok := stats[selector]; ok {
+		signature = stat.Signature
+	}
+	statsMu.RUnlock()
+
+	command := []string{"cast send", contractAddress.Hex()}
+
+	if arguments := signatureArguments(signature); arguments != nil {
+		if args := templateArgs(arguments, selector, calls); args != nil {
+			command = append(command, fmt.Sprintf("%q", signature))
+			command = append(command, args...)
+		}
+	}
+
+	// raw calldata
+	if len(command) == 2 {
+		command = append(command, hexutil.Encode(latest.data))
+	}
+
+	if latest.value != nil && latest.value.Sign() > 0 {
+		command = append(command, "--value", formatEther(latest.value)+"ether")
+	}
+
+	return strings.Join(append(command, "--private-key", "$PRIVATE_KEY"), " ")
+}
+
+// templateArgs decodes the args of the calls with the selector & returns them formatted for cast.
+func templateArgs(arguments abi.Arguments, selector [4]byte, calls []*mintCall) []string {
+	decodedCalls := make([][]interface{}, 0, len(calls))
+	minters := make([]common.Address, 0, len(calls))
+
+	for _, call := range calls {
+		if len(call.data) < 4 || [4]byte(call.data[:4]) != selector {
+			continue
+		}
+
+		values, err := arguments.Unpack(call.data[4:])
+		if err != nil {
+			return nil
+		}
+
+		decodedCalls = append(decodedCalls, values)
+		minters = append(minters, call.minter)
+	}
+
+	if len(decodedCalls) == 0 {
+		return nil
+	}
+
+	latest := decodedCalls[len(decodedCalls)-1]
+	args := make([]string, 0, len(arguments))
+
+	for idx, argument := range arguments {
+		isMinter, isConstant := true, true
+
+		for callIdx, values := range decodedCalls {
+			if address, ok := values[idx].(common.Address); !ok || address != minters[callIdx] {
+				isMinter = false
+			}
+
+			if !reflect.DeepEqual(values[idx], latest[idx]) {
+				isConstant = false
+			}
+		}
+
+		switch {
+		case isMinter:
+			args = append(args, "$WALLET")
+		case isConstant || len(decodedCalls) == 1:
+			args = append(args, formatArg(latest[idx]))
+		default:
+			args = append(args, "<"+argument.Type.String()+">")
+		}
+	}
+
+	return args
+}
+
+// signatureArguments parses the arg types of a signature like "mint(uint256,bytes32[])", nil for tuples & unknown types.
+func signatureArguments(signature string) abi.Arguments {
+	start, end := strings.Index(signature, "("), strings.LastIndex(signature, ")")
+	if start < 0 || end < start || strings.Contains(signature[start+1:end], "(") {
+		return nil
+	}
+
+	arguments := make(abi.Arguments, 0)
+
+	if signature[start+1:end] == "" {
+		return arguments
+	}
+
+	for _, typeName := range strings.Split(signature[start+1:end], ",") {
+		argType, err := abi.NewType(strings.TrimSpace(typeName), "", nil)
+		if err != nil {
+			return nil
+		}
+
+		arguments = append(arguments, abi.Argument{Type: argType})
+	}
+
+	return arguments
+}
+
+// formatArg formats a decoded arg as cast expects it.
+func formatArg(value interface{}) string {
+	switch typedValue := value.(type) {
+	case common.Address:
+		return typedValue.Hex()
+	case []byte:
+		return hexutil.Encode(typedValue)
+	case string:
+		return fmt.Sprintf("%q", typedValue)
+	case *big.Int:
+		return typedValue.String()
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() { //nolint:exhaustive
+	case reflect.Array:
+		// fixed bytes like bytes32
+		if reflected.Type().Elem().Kind() == reflect.Uint8 {
+			fixedBytes := make([]byte, reflected.Len())
+			reflect.Copy(reflect.ValueOf(fixedBytes), reflected)
+
+			return hexutil.Encode(fixedBytes)
+		}
+
+		fallthrough
+	case reflect.Slice:
+		elements := make([]string, 0, reflected.Len())
+		for i := 0; i < reflected.Len(); i++ {
+			elements = append(elements, formatArg(reflected.Index(i).Interface()))
+		}
+
+		return "[" + strings.Join(elements, ",") + "]"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func formatEther(wei *big.Int) string {
+	ether := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18))
+
+	return strings.TrimRight(strings.TrimRight(ether.Text('f', 18), "0"), ".")
+}
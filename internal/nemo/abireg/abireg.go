@@ -0,0 +1,270 @@
+package abireg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/external"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/rueidica"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrNoABI           = errors.New("no abi available")
+	ErrUnknownMethod   = errors.New("method not found in abi")
+	ErrUnknownEvent    = errors.New("event not found in abi")
+	ErrInputTooShort   = errors.New("calldata too short")
+	ErrRegistryOffline = errors.New("abi registry not enabled")
+)
+
+// Registry fetches verified contract abis from etherscan and keeps them in memory & redis.
+// Used to decode calls & events from contracts/protocols we don't have bindings for.
+type Registry struct {
+	abis map[common.Address]*abi.ABI
+
+	// contracts without a verified abi, so we don't ask etherscan again and again
+	unavailable mapset.Set[common.Address]
+	// contracts whose abi could not be fetched (rate limit, invalid key, ...) & when, retried after abireg.retry_after
+	failed map[common.Address]time.Time
+
+	rueidi *rueidica.Rueidica
+	mu     *sync.RWMutex
+}
+
+func New(rueidi *rueidica.Rueidica) *Registry {
+	return &Registry{
+		abis:        make(map[common.Address]*abi.ABI),
+		unavailable: mapset.NewSet[common.Address](),
+		failed:      make(map[common.Address]time.Time),
+		rueidi:      rueidi,
+		mu:          &sync.RWMutex{},
+	}
+}
+
+// Enabled returns true if the registry is enabled and an etherscan api key is available.
+func Enabled() bool {
+	return viper.GetBool("abireg.enabled") && viper.IsSet("api_keys.etherscan")
+}
+
+// Register adds an abi for the given contract to the registry, e.g. from our own bindings.
+func (r *Registry) Register(contractAddress common.Address, contractABI *abi.ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.abis[contractAddress] = contractABI
+	r.unavailable.Remove(contractAddress)
+	delete(r.failed, contractAddress)
+}
+
+// failedRecently returns true if fetching the abi failed within abireg.retry_after.
+func (r *Registry) failedRecently(contractAddress common.Address) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	failedAt, ok := r.failed[contractAddress]
+	if !ok {
+		return false
+	}
+
+	if time.Since(failedAt) < viper.GetDuration("abireg.retry_after") {
+		return true
+	}
+
+	delete(r.failed, contractAddress)
+
+	return false
+}
+
+// GetABI returns the abi for the given contract. lookup order: memory -> redis -> etherscan.
+func (r *Registry) GetABI(ctx context.Context, contractAddress common.Address) (*abi.ABI, error) {
+	r.mu.RLock()
+	contractABI, ok := r.abis[contractAddress]
+	r.mu.RUnlock()
+
+	if ok {
+		return contractABI, nil
+	}
+
+	if r.unavailable.Contains(contractAddress) || r.failedRecently(contractAddress) {
+		return nil, ErrNoABI
+	}
+
+	if !Enabled() {
+		return nil, ErrRegistryOffline
+	}
+
+	// redis
+	rawABI, err := r.rueidi.GetCachedContractABI(ctx, contractAddress)
+	if err != nil || rawABI == "" {
+		// etherscan
+		rawABI, err = external.GetContractABI(contractAddress)
+		if err != nil {
+			if errors.Is(err, external.ErrContractNotVerified) {
+				r.unavailable.Add(contractAddress)
+			} else {
+				r.mu.Lock()
+				r.failed[contractAddress] = time.Now()
+				r.mu.Unlock()
+			}
+
+			return nil, err
+		}
+
+		if err := r.rueidi.StoreContractABI(ctx, contractAddress, rawABI); err != nil {
+			gbl.Log.Warnf("❗️ abireg | could not cache abi for %s: %s", contractAddress.Hex(), err)
+		}
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(rawABI))
+	if err != nil {
+		gbl.Log.Warnf("❗️ abireg | could not parse abi for %s: %s", contractAddress.Hex(), err)
+
+		r.unavailable.Add(contractAddress)
+
+		return nil, err
+	}
+
+	gbl.Log.Debugf("📜 abireg | loaded abi for %s | %d methods · %d events", contractAddress.Hex(), len(parsedABI.Methods), len(parsedABI.Events))
+
+	r.Register(contractAddress, &parsedABI)
+
+	return &parsedABI, nil
+}
+
+// DecodeCall decodes the calldata of a tx to the given contract to a human-readable string like "mint(uint256 3)".
+func (r *Registry) DecodeCall(ctx context.Context, contractAddress common.Address, input []byte) (string, error) {
+	if len(input) < 4 {
+		return "", ErrInputTooShort
+	}
+
+	contractABI, err := r.GetABI(ctx, contractAddress)
+	if err != nil {
+		return "", err
+	}
+
+	method, err := contractABI.MethodById(input[:4])
+	if err != nil {
+		return "", fmt.Errorf("%w: %x", ErrUnknownMethod, input[:4])
+	}
+
+	values, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return "", err
+	}
+
+	return method.RawName + "(" + formatArguments(method.Inputs, values) + ")", nil
+}
+
+// MethodSignature returns the signature like "mint(uint256)" of the method with the given selector.
+func (r *Registry) MethodSignature(ctx context.Context, contractAddress common.Address, selector []byte) (string, error) {
+	if len(selector) < 4 {
+		return "", ErrInputTooShort
+	}
+
+	contractABI, err := r.GetABI(ctx, contractAddress)
+	if err != nil {
+		return "", err
+	}
+
+	method, err := contractABI.MethodById(selector[:4])
+	if err != nil {
+		return "", fmt.Errorf("%w: %x", ErrUnknownMethod, selector[:4])
+	}
+
+	return method.Sig, nil
+}
+
+// DecodeLog decodes an event log emitted by a contract to a human-readable string like "Claimed(address 0x…, uint256 3)".
+func (r *Registry) DecodeLog(ctx context.Context, eventLog *types.Log) (string, error) {
+	if len(eventLog.Topics) == 0 {
+		return "", ErrUnknownEvent
+	}
+
+	contractABI, err := r.GetABI(ctx, eventLog.Address)
+	if err != nil {
+		return "", err
+	}
+
+	event, err := contractABI.EventByID(eventLog.Topics[0])
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownEvent, eventLog.Topics[0].Hex())
+	}
+
+	// unnamed arguments (common in verified abis) get positional names, otherwise they overwrite each other
+	inputs := make(abi.Arguments, len(event.Inputs))
+
+	for idx, argument := range event.Inputs {
+		if argument.Name == "" {
+			argument.Name = fmt.Sprintf("arg%d", idx)
+		}
+
+		inputs[idx] = argument
+	}
+
+	// indexed arguments are in the topics, the others in the data
+	indexed := make(abi.Arguments, 0)
+
+	for _, argument := range inputs {
+		if argument.Indexed {
+			indexed = append(indexed, argument)
+		}
+	}
+
+	decoded := make(map[string]interface{})
+
+	if err := inputs.UnpackIntoMap(decoded, eventLog.Data); err != nil {
+		return "", err
+	}
+
+	if len(eventLog.Topics) > 1 {
+		if err := abi.ParseTopicsIntoMap(decoded, indexed, eventLog.Topics[1:]); err != nil {
+			return "", err
+		}
+	}
+
+	values := make([]interface{}, 0, len(inputs))
+	for _, argument := range inputs {
+		values = append(values, decoded[argument.Name])
+	}
+
+	return event.RawName + "(" + formatArguments(event.Inputs, values) + ")", nil
+}
+
+func formatArguments(arguments abi.Arguments, values []interface{}) string {
+	formatted := make([]string, 0, len(arguments))
+
+	for idx, argument := range arguments {
+		if idx >= len(values) {
+			break
+		}
+
+		var value string
+
+		switch v := values[idx].(type) {
+		case []byte:
+			value = fmt.Sprintf("0x%x", v)
+		case [32]byte:
+			value = common.Hash(v).Hex()
+		default:
+			value = fmt.Sprint(v)
+		}
+
+		// shorten long values like arrays or bytes
+		if len(value) > 66 {
+			value = value[:63] + "…"
+		}
+
+		formatted = append(formatted, argument.Type.String()+" "+value)
+	}
+
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,51 @@
+package abireg
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestRegistry_DecodeLog(t *testing.T) {
+	const fragment = `[{"type":"event","name":"Claimed","inputs":[` +
+		`{"name":"","type":"address","indexed":true},` +
+		`{"name":"","type":"uint256","indexed":false},` +
+		`{"name":"","type":"uint256","indexed":false}]}]`
+
+	contractABI, err := abi.JSON(strings.NewReader(fragment))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contractAddress := common.HexToAddress("0x1")
+	claimer := common.HexToAddress("0xc1a1e5")
+
+	r := New(nil)
+	r.Register(contractAddress, &contractABI)
+
+	data, err := contractABI.Events["Claimed"].Inputs.NonIndexed().Pack(big.NewInt(3), big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventLog := &types.Log{
+		Address: contractAddress,
+		Topics:  []common.Hash{contractABI.Events["Claimed"].ID, common.BytesToHash(claimer.Bytes())},
+		Data:    data,
+	}
+
+	got, err := r.DecodeLog(context.Background(), eventLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Claimed(address " + claimer.Hex() + ", uint256 3, uint256 7)"
+	if got != want {
+		t.Errorf("DecodeLog() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var ErrNoRate = errors.New("no exchange rate available")
+
+// Currency is an erc20 token used as payment for nfts.
+type Currency struct {
+	Symbol   string
+	Address  common.Address
+	Decimals int
+
+	// number of decimals shown by default
+	ShownDecimals int
+
+	// chainlink <symbol>/eth price feed, empty if there is none
+	Feed common.Address
+}
+
+// Currencies are the known non-eth payment tokens. weth is handled like eth.
+var Currencies = map[common.Address]*Currency{
+	common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"): {
+		Symbol: "USDC", Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Decimals: 6, ShownDecimals: 0,
+		Feed: common.HexToAddress("0x986b5E1e1755e3C2440e960477f25201B0a8bbD4"),
+	},
+	common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"): {
+		Symbol: "USDT", Address: common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"), Decimals: 6, ShownDecimals: 0,
+		Feed: common.HexToAddress("0xEe9F2375b4bdF6387aa8265dD4FB8F16512A1d46"),
+	},
+	common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"): {
+		Symbol: "DAI", Address: common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"), Decimals: 18, ShownDecimals: 0,
+		Feed: common.HexToAddress("0x773616E4d11A78F511299002da57A0a94577F1f4"),
+	},
+	common.HexToAddress("0x4d224452801ACEd8B2F0aebE155379bb5D594381"): {
+		Symbol: "APE", Address: common.HexToAddress("0x4d224452801ACEd8B2F0aebE155379bb5D594381"), Decimals: 18, ShownDecimals: 1,
+		Feed: common.HexToAddress("0xc7de7f4d4C9c991fF62a07D18b3E31e349833A18"),
+	},
+	common.HexToAddress("0x5283D291DBCF85356A21bA090E6db59121208b44"): {
+		Symbol: "BLUR", Address: common.HexToAddress("0x5283D291DBCF85356A21bA090E6db59121208b44"), Decimals: 18, ShownDecimals: 0,
+	},
+}
+
+type rate struct {
+	// wei per whole token, nil if no rate was fetched yet
+	weiPerToken *big.Float
+	updatedAt   time.Time
+
+	// last failed fetch, no new fetch is tried for currencies.failure_ttl
+	failedAt time.Time
+}
+
+var (
+	rates   = make(map[common.Address]*rate)
+	ratesMu = &sync.Mutex{}
+
+	// fetches coalesces concurrent fetches of the same rate
+	fetches singleflight.Group
+)
+
+// Get returns the currency for the given token address or nil if it is unknown.
+func Get(address common.Address) *Currency {
+	return Currencies[address]
+}
+
+// Format formats an amount of the currency with thousands separators, e.g. "2,400 USDC".
+func (c *Currency) Format(amount *big.Int) string {
+	shownDecimals := c.ShownDecimals
+
+	if key := "currencies." + strings.ToLower(c.Symbol) + ".decimals"; viper.IsSet(key) {
+		shownDecimals = viper.GetInt(key)
+	}
+
+	value, _ := c.toFloat(amount).Float64()
+
+	return message.NewPrinter(language.English).Sprintf("%.*f %s", shownDecimals, value, c.Symbol)
+}
+
+// ToWei converts an amount of the currency to its eth equivalent in wei.
+func (c *Currency) ToWei(ctx context.Context, providerPool *provider.Pool, amount *big.Int) (*big.Int, error) {
+	weiPerToken, err := c.weiPerToken(ctx, providerPool)
+	if err != nil {
+		return nil, err
+	}
+
+	wei, _ := new(big.Float).Mul(c.toFloat(amount), weiPerToken).Int(nil)
+
+	return wei, nil
+}
+
+func (c *Currency) toFloat(amount *big.Int) *big.Float {
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Decimals)), nil)))
+}
+
+// weiPerToken returns the cached exchange rate or fetches it from the configured oracles.
+// the rate is fetched outside the lock & only once for concurrent callers.
+func (c *Currency) weiPerToken(ctx context.Context, providerPool *provider.Pool) (*big.Float, error) {
+	ratesMu.Lock()
+	cached := rates[c.Address]
+	ratesMu.Unlock()
+
+	if cached != nil {
+		if cached.weiPerToken != nil && time.Since(cached.updatedAt) < viper.GetDuration("currencies.rate_ttl") {
+			return cached.weiPerToken, nil
+		}
+
+		// failed recently, use the outdated rate if available
+		if time.Since(cached.failedAt) < viper.GetDuration("currencies.failure_ttl") {
+			if cached.weiPerToken != nil {
+				return cached.weiPerToken, nil
+			}
+
+			return nil, ErrNoRate
+		}
+	}
+
+	weiPerToken, err, _ := fetches.Do(c.Address.Hex(), func() (interface{}, error) {
+		// not canceled with the first caller as the result is shared
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+
+		return c.fetchAndCacheRate(fetchCtx, providerPool)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return weiPerToken.(*big.Float), nil
+}
+
+// fetchAndCacheRate fetches the exchange rate & caches it, failures are cached for currencies.failure_ttl.
+func (c *Currency) fetchAndCacheRate(ctx context.Context, providerPool *provider.Pool) (*big.Float, error) {
+	weiPerToken, oracle, err := fetchRate(ctx, providerPool, c)
+
+	ratesMu.Lock()
+	defer ratesMu.Unlock()
+
+	if err != nil {
+		gbl.Log.Debugf("💱 could not fetch %s/eth rate: %v", c.Symbol, err)
+
+		failed := &rate{failedAt: time.Now()}
+
+		// use the outdated rate if available
+		if cached, ok := rates[c.Address]; ok && cached.weiPerToken != nil {
+			failed.weiPerToken = cached.weiPerToken
+			failed.updatedAt = cached.updatedAt
+		}
+
+		rates[c.Address] = failed
+
+		if failed.weiPerToken != nil {
+			return failed.weiPerToken, nil
+		}
+
+		return nil, ErrNoRate
+	}
+
+	gbl.Log.Debugf("💱 %s/eth rate via %s: %s wei", c.Symbol, oracle, weiPerToken.Text('f', 0))
+
+	rates[c.Address] = &rate{weiPerToken: weiPerToken, updatedAt: time.Now()}
+
+	return weiPerToken, nil
+}
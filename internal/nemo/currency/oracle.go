@@ -0,0 +1,124 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Oracle provides the exchange rate of a currency as wei per whole token.
+type Oracle interface {
+	WeiPerToken(ctx context.Context, providerPool *provider.Pool, c *Currency) (*big.Float, error)
+}
+
+var (
+	// oracles by name, queried in the order of currencies.oracles until one returns a rate
+	oracles = map[string]Oracle{
+		"static":    &staticOracle{},
+		"chainlink": &chainlinkOracle{},
+		"coingecko": &coingeckoOracle{},
+	}
+	oraclesMu = &sync.RWMutex{}
+)
+
+// RegisterOracle adds (or replaces) an oracle which can then be used via currencies.oracles.
+func RegisterOracle(name string, oracle Oracle) {
+	oraclesMu.Lock()
+	defer oraclesMu.Unlock()
+
+	oracles[name] = oracle
+}
+
+// fetchRate asks the configured oracles for the rate of the currency.
+func fetchRate(ctx context.Context, providerPool *provider.Pool, c *Currency) (*big.Float, string, error) {
+	oraclesMu.RLock()
+	defer oraclesMu.RUnlock()
+
+	for _, name := range viper.GetStringSlice("currencies.oracles") {
+		oracle, ok := oracles[name]
+		if !ok {
+			continue
+		}
+
+		if weiPerToken, err := oracle.WeiPerToken(ctx, providerPool, c); err == nil && weiPerToken.Sign() > 0 {
+			return weiPerToken, name, nil
+		}
+	}
+
+	return nil, "", ErrNoRate
+}
+
+// staticOracle uses a fixed rate from the config (currencies.<symbol>.eth_rate, eth per token).
+type staticOracle struct{}
+
+func (o *staticOracle) WeiPerToken(_ context.Context, _ *provider.Pool, c *Currency) (*big.Float, error) {
+	key := "currencies." + strings.ToLower(c.Symbol) + ".eth_rate"
+	if !viper.IsSet(key) {
+		return nil, ErrNoRate
+	}
+
+	return new(big.Float).Mul(big.NewFloat(viper.GetFloat64(key)), big.NewFloat(1e18)), nil
+}
+
+// chainlinkOracle reads the latest answer of the chainlink <symbol>/eth feed.
+type chainlinkOracle struct{}
+
+// chainlink latestAnswer()
+var latestAnswerSelector = common.FromHex("0x50d25bcd")
+
+func (o *chainlinkOracle) WeiPerToken(ctx context.Context, providerPool *provider.Pool, c *Currency) (*big.Float, error) {
+	if providerPool == nil || c.Feed == (common.Address{}) {
+		return nil, ErrNoRate
+	}
+
+	result, err := providerPool.CallContract(ctx, ethereum.CallMsg{To: &c.Feed, Data: latestAnswerSelector}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) < 32 {
+		return nil, ErrNoRate
+	}
+
+	// <symbol>/eth feeds have 18 decimals -> the answer is the price of one token in wei
+	return new(big.Float).SetInt(new(big.Int).SetBytes(result[:32])), nil
+}
+
+// coingeckoOracle uses the public coingecko token price api, e.g. for tokens without chainlink feed.
+type coingeckoOracle struct{}
+
+const coingeckoTokenPriceURL = "https://api.coingecko.com/api/v3/simple/token_price/ethereum?contract_addresses=%s&vs_currencies=eth"
+
+func (o *coingeckoOracle) WeiPerToken(ctx context.Context, _ *provider.Pool, c *Currency) (*big.Float, error) {
+	response, err := utils.HTTP.GetWithTLS12(ctx, fmt.Sprintf(coingeckoTokenPriceURL, strings.ToLower(c.Address.Hex())))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("coingecko api returned %s", response.Status)
+	}
+
+	var prices map[string]map[string]float64
+	if err := json.NewDecoder(response.Body).Decode(&prices); err != nil {
+		return nil, err
+	}
+
+	ethPerToken, ok := prices[strings.ToLower(c.Address.Hex())]["eth"]
+	if !ok || ethPerToken <= 0 {
+		return nil, ErrNoRate
+	}
+
+	return new(big.Float).Mul(big.NewFloat(ethPerToken), big.NewFloat(1e18)), nil
+}
@@ -55,6 +55,9 @@ type eventChannelsIn struct {
 	TxWithLogs        chan *chawagoModels.TxWithLogs
 	TokenTransactions chan *totra.TokenTransaction
 
+	// token transactions of own & watched wallets, handed out before all other events
+	PriorityTokenTransactions chan *totra.TokenTransaction
+
 	ParsedEvents    chan *degendb.PreformattedEvent
 	RecentOwnEvents chan []*degendb.PreformattedEvent
 
@@ -113,6 +116,8 @@ func newEventHub() *eventHub {
 			TxWithLogs:        make(chan *chawagoModels.TxWithLogs, viper.GetInt("gloomberg.eventhub.inQueuesSize")),
 			TokenTransactions: make(chan *totra.TokenTransaction, viper.GetInt("gloomberg.eventhub.inQueuesSize")),
 
+			PriorityTokenTransactions: make(chan *totra.TokenTransaction, viper.GetInt("gloomberg.eventhub.inQueuesSize")),
+
 			ParsedEvents:    make(chan *degendb.PreformattedEvent, viper.GetInt("gloomberg.eventhub.inQueuesSize")),
 			RecentOwnEvents: make(chan []*degendb.PreformattedEvent, viper.GetInt("gloomberg.eventhub.inQueuesSize")),
 
@@ -334,7 +339,18 @@ func (eh *eventHub) SubscribNewBlocks() chan uint64 {
 
 func (eh *eventHub) worker(workerID int) {
 	for {
+		// events of own & watched wallets first
+		select {
+		case event := <-eh.In.PriorityTokenTransactions:
+			eh.pushTokenTransaction(workerID, event)
+
+			continue
+		default:
+		}
+
 		select {
+		case event := <-eh.In.PriorityTokenTransactions:
+			eh.pushTokenTransaction(workerID, event)
 		case event := <-eh.In.TxWithLogs:
 			log.Debugf("workerID: %d | len(eh.out.TxWithLogs): %d", workerID, len(eh.out.TxWithLogs))
 
@@ -344,13 +360,7 @@ func (eh *eventHub) worker(workerID int) {
 				ch <- event
 			}
 		case event := <-eh.In.TokenTransactions:
-			log.Debugf("TokenTransactions event | %d | pushing to %d receivers", workerID, len(eh.out.TokenTransactions))
-
-			atomic.AddInt64(eh.counters["TokenTransactions"], 1)
-
-			for _, ch := range eh.out.TokenTransactions {
-				ch <- event
-			}
+			eh.pushTokenTransaction(workerID, event)
 		case event := <-eh.In.ItemListed:
 			// log.Debugf("ItemListedEvents event | %d | pushing to %d receivers", workerID, len(eh.out.ItemListed))
 			log.Debugf("ItemListedEvents event | %d | pushing to %d receivers", workerID, eh.out.ItemListed.Cardinality())
@@ -435,3 +445,13 @@ func (eh *eventHub) worker(workerID int) {
 		}
 	}
 }
+
+func (eh *eventHub) pushTokenTransaction(workerID int, event *totra.TokenTransaction) {
+	log.Debugf("TokenTransactions event | %d | pushing to %d receivers", workerID, len(eh.out.TokenTransactions))
+
+	atomic.AddInt64(eh.counters["TokenTransactions"], 1)
+
+	for _, ch := range eh.out.TokenTransactions {
+		ch <- event
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strconv"
 
 	"github.com/benleb/gloomberg/internal"
@@ -12,6 +11,7 @@ import (
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/jobs"
+	"github.com/benleb/gloomberg/internal/nemo/abireg"
 	"github.com/benleb/gloomberg/internal/nemo/provider"
 	"github.com/benleb/gloomberg/internal/nemo/wallet"
 	"github.com/benleb/gloomberg/internal/nemo/watch"
@@ -29,7 +29,9 @@ import (
 type Gloomberg struct {
 	// Nodes        *nodes.Nodes
 	ProviderPool *provider.Pool
-	Watcher      *watch.Watcher
+	// provider pools of the additional chains (l2s) by chain id, set up before the subscriptions start
+	ChainPools map[uint64]*provider.Pool
+	Watcher    *watch.Watcher
 
 	CollectionDB *collections.CollectionDB
 	OwnWallets   *wallet.Wallets
@@ -42,6 +44,9 @@ type Gloomberg struct {
 	Rdb    rueidis.Client
 	Rueidi *rueidica.Rueidica
 
+	// verified contract abis to decode unknown calls/events
+	ABIs *abireg.Registry
+
 	QueueSlugs chan common.Address
 
 	CurrentGasPriceGwei   uint64
@@ -56,6 +61,15 @@ type Gloomberg struct {
 	PrintConfigurations map[string]*printConfig
 }
 
+// PoolFor returns the provider pool of the chain, the mainnet pool for mainnet & unknown chains.
+func (gb *Gloomberg) PoolFor(chainID uint64) *provider.Pool {
+	if pool, ok := gb.ChainPools[chainID]; ok {
+		return pool
+	}
+
+	return gb.ProviderPool
+}
+
 func (gb *Gloomberg) String() {
 	fmt.Println("gloomberg | " + internal.GloombergVersion)
 }
@@ -67,6 +81,21 @@ type printConfig struct {
 }
 
 var predefinedPrintConfigurations = []printConfig{
+	{
+		Icon:     "🚦",
+		Keywords: []string{"start", "startup"},
+		Color:    lipgloss.Color("#7aa2f7"),
+	},
+	{
+		Icon:     "⚠️",
+		Keywords: []string{"anomaly", "anomalies"},
+		Color:    lipgloss.Color("#e0af68"),
+	},
+	{
+		Icon:     "🐣",
+		Keywords: []string{"deploy", "deploywatch"},
+		Color:    lipgloss.Color("#9ece6a"),
+	},
 	{
 		Icon:     "🖥️",
 		Keywords: []string{"web", "ws"},
@@ -92,6 +121,96 @@ var predefinedPrintConfigurations = []printConfig{
 		Keywords: []string{"jobs", "job"},
 		Color:    lipgloss.Color("#4dc6e2"),
 	},
+	{
+		Icon:     "🏷️",
+		Keywords: []string{"deal", "floor"},
+		Color:    lipgloss.Color("#e2c44d"),
+	},
+	{
+		Icon:     "📜",
+		Keywords: []string{"abi", "abireg"},
+		Color:    lipgloss.Color("#b3a17f"),
+	},
+	{
+		Icon:     "🚨",
+		Keywords: []string{"scam", "security"},
+		Color:    lipgloss.Color("#ff2e4c"),
+	},
+	{
+		Icon:     "⌨️",
+		Keywords: []string{"keys"},
+		Color:    lipgloss.Color("#8a8fa3"),
+	},
+	{
+		Icon:     "🐕",
+		Keywords: []string{"watchdog", "wd"},
+		Color:    lipgloss.Color("#c27c3a"),
+	},
+	{
+		Icon:     "🧬",
+		Keywords: []string{"proxy", "upgrade"},
+		Color:    lipgloss.Color("#ff8c2e"),
+	},
+	{
+		Icon:     "🪓",
+		Keywords: []string{"blend"},
+		Color:    lipgloss.Color("#ff5f5f"),
+	},
+	{
+		Icon:     "🧊",
+		Keywords: []string{"freeze"},
+		Color:    lipgloss.Color("#5fd7ff"),
+	},
+	{
+		Icon:     "💸",
+		Keywords: []string{"profit"},
+		Color:    lipgloss.Color("#3ac27c"),
+	},
+	{
+		Icon:     "🪪",
+		Keywords: []string{"ens"},
+		Color:    lipgloss.Color("#5298ff"),
+	},
+	{
+		Icon:     "💤",
+		Keywords: []string{"idle"},
+		Color:    lipgloss.Color("#7aa2f7"),
+	},
+	{
+		Icon:     "🎟️",
+		Keywords: []string{"edition"},
+		Color:    lipgloss.Color("#e0af68"),
+	},
+	{
+		Icon:     "🌱",
+		Keywords: []string{"mint", "mintsigs"},
+		Color:    lipgloss.Color("#7bd66a"),
+	},
+	{
+		Icon:     "⏳",
+		Keywords: []string{"stale", "positions"},
+		Color:    lipgloss.Color("#c9a0dc"),
+	},
+	{
+		Icon:     "⌛️",
+		Keywords: []string{"expiry"},
+		Color:    lipgloss.Color("#e0af68"),
+	},
+	{
+		Icon:     "🔀",
+		Keywords: []string{"reorg"},
+		Color:    lipgloss.Color("#ff9e64"),
+	},
+	{
+		Icon:     "🏦",
+		Keywords: []string{"capflows"},
+		Color:    lipgloss.Color("#8fbcbb"),
+	},
+	{
+		Icon:     "🔮",
+		Keywords: []string{"mempool"},
+		Color:    lipgloss.Color("#b388ff"),
+	},
 }
 
 var GB *Gloomberg
@@ -99,34 +218,19 @@ var GB *Gloomberg
 func New() *Gloomberg {
 	// redis
 	// rueidis / new redis library
-	var connectAddr string
-
-	if viper.IsSet("redis.address") {
-		connectAddr = viper.GetString("redis.address")
-	} else {
-		// fallback to old config
-		connectAddr = fmt.Sprintf("%s:%d", viper.GetString("redis.host"), viper.GetInt("redis.port"))
-	}
-
-	// use hostname as client name
-	hostname, err := os.Hostname()
+	redisClientOptions, err := rueidica.ClientOptionFromConfig()
 	if err != nil {
-		log.Error(fmt.Sprintf("❗️ error getting hostname: %s", err))
-
-		hostname = "unknown"
-	}
-
-	clientName := hostname + "_gloomberg_v" + internal.GloombergVersion
-	redisClientOptions := rueidis.ClientOption{
-		InitAddress: []string{connectAddr},
-		ClientName:  clientName,
+		log.Fatalf("❌ invalid redis config: %s", err)
 	}
 
 	rdb := getRedisClient(redisClientOptions)
+	rueidi := rueidica.NewRueidica(rdb)
 
 	gb := &Gloomberg{
 		Rdb:    rdb,
-		Rueidi: rueidica.NewRueidica(rdb),
+		Rueidi: rueidi,
+
+		ABIs: abireg.New(rueidi),
 
 		CollectionDB: collections.New(),
 
@@ -136,6 +240,8 @@ func New() *Gloomberg {
 
 		QueueSlugs: make(chan common.Address, 1024),
 
+		ChainPools: make(map[uint64]*provider.Pool),
+
 		eventHub: newEventHub(),
 
 		// DegenDB:  degendb.NewDegenDB(),
@@ -147,20 +253,7 @@ func New() *Gloomberg {
 
 	// experimental: start multiple terminal printers
 	for i := 0; i < viper.GetInt("gloomberg.terminalPrinter.numWorker"); i++ {
-		go func() {
-			gbl.Log.Debug("starting terminal printer...")
-
-			for eventLine := range TerminalPrinterQueue {
-				gbl.Log.Debugf("terminal printer eventLine: %s", eventLine)
-
-				if viper.GetBool("log.debug") {
-					debugPrefix := fmt.Sprintf("%d | ", len(TerminalPrinterQueue))
-					eventLine = fmt.Sprint(debugPrefix, eventLine)
-				}
-
-				fmt.Println(eventLine)
-			}
-		}()
+		go runTerminalPrinter()
 	}
 
 	// load print configurations to pretty style prints from our different "modules"
@@ -189,6 +282,11 @@ func (gb *Gloomberg) PublishOwnSlubSubscription() {
 		if collection := gb.CollectionDB.GetCollectionForSlug(slug); collection != nil {
 			if collection.Source != degendb.FromStream {
 				eventTypes = append(eventTypes, degendb.Bid)
+
+				// merged with the sales from the chain
+				if viper.GetBool("seawatcher.sales") {
+					eventTypes = append(eventTypes, degendb.Sale)
+				}
 			}
 		}
 
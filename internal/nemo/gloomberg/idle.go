@@ -0,0 +1,96 @@
+package gloomberg
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/spf13/viper"
+)
+
+// idleTicker is a ticker slowed down while idle, interval returns its normal interval.
+type idleTicker struct {
+	ticker   *time.Ticker
+	interval func() time.Duration
+}
+
+var (
+	lastActivity atomic.Int64
+	idle         atomic.Bool
+
+	// tickers by name, re-registering a name replaces the ticker (e.g. after a restart by the watchdog)
+	idleTickers   = make(map[string]*idleTicker)
+	idleTickersMu sync.Mutex
+)
+
+// RegisterIdleTicker slows the ticker down by idle.slowdown while there is no watched activity.
+func RegisterIdleTicker(name string, ticker *time.Ticker, interval func() time.Duration) {
+	idleTickersMu.Lock()
+	defer idleTickersMu.Unlock()
+
+	idleTickers[name] = &idleTicker{ticker: ticker, interval: interval}
+
+	if IsIdle() {
+		ticker.Reset(IdleInterval(interval()))
+	}
+}
+
+// MarkActivity records watched activity (own & watched events) & restores the full cadence if idle.
+func MarkActivity() {
+	lastActivity.Store(time.Now().UnixNano())
+
+	if idle.CompareAndSwap(true, false) {
+		PrMod("idle", "activity detected, back to full speed")
+
+		resetIdleTickers()
+	}
+}
+
+// IsIdle returns true if there was no watched activity for idle.after.
+func IsIdle() bool {
+	return idle.Load()
+}
+
+// IdleInterval returns the interval, multiplied by idle.slowdown while idle.
+func IdleInterval(interval time.Duration) time.Duration {
+	if !IsIdle() {
+		return interval
+	}
+
+	return time.Duration(float64(interval) * max(viper.GetFloat64("idle.slowdown"), 1))
+}
+
+// WatchIdle switches to the idle mode after idle.after without watched activity.
+func WatchIdle() {
+	idleAfter := viper.GetDuration("idle.after")
+	if idleAfter <= 0 {
+		return
+	}
+
+	gbl.Log.Debugf("💤 idle mode after %s without watched activity", idleAfter)
+
+	lastActivity.Store(time.Now().UnixNano())
+
+	for range time.NewTicker(max(idleAfter/10, time.Second)).C {
+		if time.Since(time.Unix(0, lastActivity.Load())) < idleAfter || !idle.CompareAndSwap(false, true) {
+			continue
+		}
+
+		PrModf("idle", "no watched activity for %s, slowing down polling & tickers by %.0fx", idleAfter, viper.GetFloat64("idle.slowdown"))
+
+		resetIdleTickers()
+	}
+}
+
+// resetIdleTickers resets the registered tickers to their (idle) interval.
+func resetIdleTickers() {
+	idleTickersMu.Lock()
+	defer idleTickersMu.Unlock()
+
+	for _, t := range idleTickers {
+		if interval := t.interval(); interval > 0 {
+			t.ticker.Reset(IdleInterval(interval))
+		}
+	}
+}
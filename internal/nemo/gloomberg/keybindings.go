@@ -0,0 +1,114 @@
+package gloomberg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+
+	"github.com/benleb/gloomberg/internal/bookmarks"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/keyboard"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var (
+	outputPaused     atomic.Bool
+	numSkippedPaused atomic.Uint64
+)
+
+// OutputPaused returns true if the terminal output is paused and counts the skipped line.
+func OutputPaused() bool {
+	if outputPaused.Load() {
+		numSkippedPaused.Add(1)
+
+		return true
+	}
+
+	return false
+}
+
+// PauseOutput pauses or resumes the terminal printer, e.g. for modes printing on their own.
+func PauseOutput(paused bool) {
+	outputPaused.Store(paused)
+}
+
+// ListenForKeys applies the keybindings to the live viper/filter state without a restart.
+//
+//	m: toggle mints | t: toggle transfers | x: toggle burns | +/-: adjust min value | p: pause output | c: clear screen
+//	g: collapse/expand the wallet groups in the stats box | b: bookmark the latest event
+func ListenForKeys() {
+	err := keyboard.Listen(func(key rune) {
+		switch key {
+		case 'm':
+			viper.Set("show.mints", !viper.GetBool("show.mints"))
+			PrModf("keys", "show mints: %s", style.BoldAlmostWhite(fmt.Sprint(viper.GetBool("show.mints"))))
+
+		case 't':
+			viper.Set("show.transfers", !viper.GetBool("show.transfers"))
+			PrModf("keys", "show transfers: %s", style.BoldAlmostWhite(fmt.Sprint(viper.GetBool("show.transfers"))))
+
+		case 'x':
+			viper.Set("show.burns", !viper.GetBool("show.burns"))
+			PrModf("keys", "show burns: %s", style.BoldAlmostWhite(fmt.Sprint(viper.GetBool("show.burns"))))
+
+		case '+', '-':
+			step := viper.GetFloat64("ui.keybindings.min_value_step")
+			if key == '-' {
+				step = -step
+			}
+
+			// round to avoid floating point artifacts like 0.30000000000000004
+			minValue := math.Max(0, math.Round((viper.GetFloat64("show.min_value")+step)*1000)/1000)
+			viper.Set("show.min_value", minValue)
+
+			PrModf("keys", "min value: %sΞ", style.BoldAlmostWhite(fmt.Sprintf("%.3f", minValue)))
+
+		case 'p':
+			if outputPaused.Load() {
+				outputPaused.Store(false)
+				PrModf("keys", "output resumed | %s lines skipped", style.BoldAlmostWhite(fmt.Sprint(numSkippedPaused.Swap(0))))
+			} else {
+				outputPaused.Store(true)
+
+				// printed directly as the printers already skip queued lines
+				fmt.Println(terminalLine("⌨️", "keys", "output paused | press "+style.BoldAlmostWhite("p")+" to resume"))
+			}
+
+		case 'c':
+			fmt.Print("\033[H\033[2J")
+
+		case 'b':
+			// degendb might have to connect first
+			go func() {
+				bookmark, err := bookmarks.AddLatest("")
+				if err != nil {
+					PrModf("keys", "bookmarking failed: %s", err)
+
+					return
+				}
+
+				PrModf("keys", "🔖 bookmarked %s %s | %s", bookmark.Action, style.BoldAlmostWhite(strings.Join(bookmark.Collections, ", ")), style.ShortenHashStyled(common.HexToHash(bookmark.TxHash)))
+			}()
+
+		case 'g':
+			if GB == nil || GB.OwnWallets == nil {
+				return
+			}
+
+			// collapse all groups or expand all if some are collapsed already
+			collapsed := make([]string, 0)
+			if len(viper.GetStringSlice("stats.wallet_groups.collapsed")) == 0 {
+				_, collapsed = GB.OwnWallets.Groups()
+			}
+
+			viper.Set("stats.wallet_groups.collapsed", collapsed)
+			PrModf("keys", "wallet groups collapsed: %s", style.BoldAlmostWhite(fmt.Sprint(len(collapsed) > 0)))
+		}
+	})
+	if err != nil {
+		gbl.Log.Debugf("⌨️ keybindings not available: %s", err)
+	}
+}
@@ -0,0 +1,114 @@
+package gloomberg
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/spf13/viper"
+)
+
+// OutputPriority is the priority class of a terminal line, higher classes are printed first under heavy volume.
+type OutputPriority int
+
+const (
+	// PriorityTape are the general sales, listings, ... dropped first if the printer falls behind.
+	PriorityTape OutputPriority = iota
+
+	// PriorityAlert are events of watched collections & wallets, grails & the module messages.
+	PriorityAlert
+
+	// PriorityOwn are events of the own wallets.
+	PriorityOwn
+)
+
+// size of the priority queues, the alert queue is the TerminalPrinterQueue.
+const priorityQueueSize = 1024
+
+var (
+	ownPrinterQueue  = make(chan string, priorityQueueSize)
+	tapePrinterQueue = make(chan string, priorityQueueSize)
+
+	numSuppressedTape atomic.Uint64
+	lastSummaryAt     atomic.Int64
+)
+
+// PrintWithPriority queues a line for the terminal printer with the given priority.
+func PrintWithPriority(line string, priority OutputPriority) {
+	switch priority {
+	case PriorityOwn:
+		ownPrinterQueue <- line
+	case PriorityAlert:
+		TerminalPrinterQueue <- line
+	default:
+		tapePrinterQueue <- line
+	}
+}
+
+// runTerminalPrinter prints the queued lines, own events first, then alerts & the general tape. if the
+// tape queue exceeds output.shed_queue_size, tape lines are dropped & summarized instead of delaying the others.
+func runTerminalPrinter() {
+	gbl.Log.Debug("starting terminal printer...")
+
+	for {
+		var eventLine string
+
+		// prefer own events & alerts
+		select {
+		case eventLine = <-ownPrinterQueue:
+		default:
+			select {
+			case eventLine = <-ownPrinterQueue:
+			case eventLine = <-TerminalPrinterQueue:
+			default:
+				select {
+				case eventLine = <-ownPrinterQueue:
+				case eventLine = <-TerminalPrinterQueue:
+				case eventLine = <-tapePrinterQueue:
+					if shedTapeLine() {
+						continue
+					}
+				}
+			}
+		}
+
+		printLine(eventLine)
+	}
+}
+
+// shedTapeLine returns true if the tape line should be dropped. the dropped lines are summarized
+// once the tape caught up or every output.summary_interval while shedding.
+func shedTapeLine() bool {
+	shedSize := viper.GetInt("output.shed_queue_size")
+	shed := shedSize > 0 && len(tapePrinterQueue) >= shedSize
+
+	if shed && numSuppressedTape.Add(1) == 1 {
+		lastSummaryAt.Store(time.Now().UnixNano())
+	}
+
+	if numSuppressedTape.Load() > 0 && (!shed || time.Since(time.Unix(0, lastSummaryAt.Load())) >= viper.GetDuration("output.summary_interval")) {
+		lastSummaryAt.Store(time.Now().UnixNano())
+
+		summary := fmt.Sprintf("…suppressed %d generic sales", numSuppressedTape.Swap(0))
+		printLine(terminalLine("🧃", style.Gray5Style.Render("gb"), style.GrayStyle.Render(summary)))
+	}
+
+	return shed
+}
+
+func printLine(eventLine string) {
+	gbl.Log.Debugf("terminal printer eventLine: %s", eventLine)
+
+	if OutputPaused() {
+		return
+	}
+
+	if viper.GetBool("log.debug") {
+		debugPrefix := fmt.Sprintf("%d/%d/%d | ", len(ownPrinterQueue), len(TerminalPrinterQueue), len(tapePrinterQueue))
+		eventLine = fmt.Sprint(debugPrefix, eventLine)
+	}
+
+	fmt.Println(style.FitToTerminal(eventLine))
+}
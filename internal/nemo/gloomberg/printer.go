@@ -108,10 +108,21 @@ func printToTerminal(icon string, keyword string, message string) {
 		return
 	}
 
+	// gb.In.PrintToTerminal <- out.String()
+	TerminalPrinterQueue <- terminalLine(icon, keyword, message)
+}
+
+// terminalLine formats a message with timestamp, icon & keyword.
+func terminalLine(icon string, keyword string, message string) string {
 	// WEN...??
 	now := time.Now()
 	currentTime := now.Format("15:04:05")
 
+	// save some space on narrow terminals
+	if style.CurrentLayout() == style.LayoutNarrow {
+		currentTime = now.Format("15:04")
+	}
+
 	out := strings.Builder{}
 	out.WriteString(style.DarkGrayStyle.Render("|"))
 	out.WriteString(style.Gray4Style.Render(currentTime))
@@ -119,6 +130,5 @@ func printToTerminal(icon string, keyword string, message string) {
 	out.WriteString(" " + lipgloss.NewStyle().Width(6).Align(lipgloss.Right).Render(keyword))
 	out.WriteString("  " + message)
 
-	// gb.In.PrintToTerminal <- out.String()
-	TerminalPrinterQueue <- out.String()
+	return out.String()
 }
@@ -0,0 +1,201 @@
+package gloomberg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+)
+
+type stageState int
+
+const (
+	stageRunning stageState = iota
+	stageReady
+	stageFailed
+	stageSkipped
+)
+
+// StartupStages runs the initialization of independent modules (nodes, redis, stream, web, ...) concurrently
+// & tracks their readiness. a failing stage is shown as such instead of blocking or killing the start.
+type StartupStages struct {
+	stages []*Stage
+	mu     *sync.Mutex
+	wg     *sync.WaitGroup
+
+	// stages finishing after the wait timeout print their readiness themselves
+	waited bool
+}
+
+// Stage is a module initialized during the startup.
+type Stage struct {
+	name string
+
+	state stageState
+	err   error
+
+	// progress of stages with several parts (e.g. connected nodes)
+	done  int
+	total int
+
+	mu *sync.Mutex
+}
+
+func NewStartupStages() *StartupStages {
+	return &StartupStages{
+		stages: make([]*Stage, 0),
+		mu:     &sync.Mutex{},
+		wg:     &sync.WaitGroup{},
+	}
+}
+
+// Run initializes a module in the background. errors & panics mark the stage as failed.
+func (s *StartupStages) Run(name string, init func(stage *Stage) error) {
+	stage := &Stage{name: name, mu: &sync.Mutex{}}
+
+	s.mu.Lock()
+	s.stages = append(s.stages, stage)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		defer func() {
+			if r := recover(); r != nil {
+				stage.finish(fmt.Errorf("panic: %v", r))
+			}
+		}()
+
+		stage.finish(init(stage))
+
+		s.mu.Lock()
+		late := s.waited
+		s.mu.Unlock()
+
+		if late {
+			PrModf("start", "%s", stage.String())
+		}
+	}()
+}
+
+// Skip adds a disabled module to the readiness display.
+func (s *StartupStages) Skip(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stages = append(s.stages, &Stage{name: name, state: stageSkipped, mu: &sync.Mutex{}})
+}
+
+// Wait waits until all stages are finished or the timeout is reached & prints the readiness.
+// returns false if stages are still running, they keep initializing in the background.
+func (s *StartupStages) Wait(timeout time.Duration) bool {
+	finished := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+
+	allFinished := true
+
+	select {
+	case <-finished:
+	case <-time.After(timeout):
+		allFinished = false
+	}
+
+	s.mu.Lock()
+	s.waited = true
+	s.mu.Unlock()
+
+	PrModf("start", "%s", s.String())
+
+	for _, stage := range s.stageList() {
+		if err := stage.Err(); err != nil {
+			gbl.Log.Warnf("❗️ %s failed to start: %s", stage.name, err)
+		}
+	}
+
+	return allFinished
+}
+
+// String returns the readiness of all stages, e.g. "nodes 3/4 · redis ✓ · stream ✓ · web ✓".
+func (s *StartupStages) String() string {
+	fmtStages := make([]string, 0)
+
+	for _, stage := range s.stageList() {
+		fmtStages = append(fmtStages, stage.String())
+	}
+
+	return strings.Join(fmtStages, style.DarkGrayStyle.Render(" · "))
+}
+
+func (s *StartupStages) stageList() []*Stage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Stage{}, s.stages...)
+}
+
+// Progress sets the number of finished parts of the stage.
+func (st *Stage) Progress(done int, total int) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.done, st.total = done, total
+}
+
+// Err returns the error of a failed stage.
+func (st *Stage) Err() error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	return st.err
+}
+
+func (st *Stage) String() string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	name := style.GrayStyle.Render(st.name)
+
+	switch {
+	case st.state == stageSkipped:
+		return name + " " + style.DarkGrayStyle.Render("–")
+	case st.total > 0 && st.state != stageFailed:
+		progress := fmt.Sprintf("%d/%d", st.done, st.total)
+		if st.done == st.total && st.state == stageReady {
+			return name + " " + style.TrendLightGreenStyle.Render(progress)
+		}
+
+		return name + " " + style.BoldAlmostWhite(progress)
+	case st.state == stageReady:
+		return name + " " + style.TrendLightGreenStyle.Render("✓")
+	case st.state == stageFailed:
+		return name + " " + style.TrendLightRedStyle.Render("✗")
+	default:
+		return name + " " + style.DarkGrayStyle.Render("…")
+	}
+}
+
+func (st *Stage) finish(err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.state != stageRunning {
+		return
+	}
+
+	if err != nil {
+		st.state, st.err = stageFailed, err
+
+		return
+	}
+
+	st.state = stageReady
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/external"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/nemo/provider"
 	"github.com/benleb/gloomberg/internal/nemo/wallet"
@@ -23,7 +26,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	mapset "github.com/deckarep/golang-set/v2"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/redis/rueidis"
 	"github.com/spf13/viper"
 )
@@ -55,10 +57,19 @@ type Stats struct {
 
 	gasTicker *time.Ticker
 
+	// stops the running stats ticker loop, e.g. when it is restarted by the watchdog
+	stopTicker   context.CancelFunc
+	stopTickerMu sync.Mutex
+
 	NewLogs        uint64
 	NewListings    uint64
 	EventsToFormat uint64
 	OutputLines    uint64
+
+	// session totals of the eth/weth moved between own wallets & exchanges
+	deposited  *big.Int
+	withdrawn  *big.Int
+	capFlowsMu sync.Mutex
 }
 
 func NewStats(gb *Gloomberg, gasTicker *time.Ticker, wallets *wallet.Wallets, providerPool *provider.Pool, rdb rueidis.Client) *Stats {
@@ -72,6 +83,9 @@ func NewStats(gb *Gloomberg, gasTicker *time.Ticker, wallets *wallet.Wallets, pr
 
 		gasTicker: gasTicker,
 
+		deposited: big.NewInt(0),
+		withdrawn: big.NewInt(0),
+
 		interval:  viper.GetDuration("ticker.statsbox"),
 		timeframe: viper.GetDuration("stats.timeframe"),
 	}
@@ -79,15 +93,37 @@ func NewStats(gb *Gloomberg, gasTicker *time.Ticker, wallets *wallet.Wallets, pr
 	return stats
 }
 
-func (s *Stats) AddEvent(eventType degendb.EventType, amountTokens int64, value *big.Int) {
+func (s *Stats) AddEvent(eventType degendb.EventType, amountTokens int64, value *big.Int, isBot bool) {
 	s.RecentEvents.Add(&degendb.RecentEvent{
 		Timestamp:    time.Now(),
 		Type:         eventType,
 		AmountTokens: uint64(amountTokens),
 		AmountWei:    value,
+		IsBot:        isBot,
 	})
 }
 
+// AddCapitalFlow adds a deposit to or withdrawal from an exchange to the session totals.
+func (s *Stats) AddCapitalFlow(eventType degendb.EventType, value *big.Int) {
+	s.capFlowsMu.Lock()
+	defer s.capFlowsMu.Unlock()
+
+	switch eventType {
+	case degendb.Deposit:
+		s.deposited.Add(s.deposited, value)
+	case degendb.Withdrawal:
+		s.withdrawn.Add(s.withdrawn, value)
+	}
+}
+
+// CapitalFlows returns the session totals of the deposits to & withdrawals from exchanges.
+func (s *Stats) CapitalFlows() (*big.Int, *big.Int) {
+	s.capFlowsMu.Lock()
+	defer s.capFlowsMu.Unlock()
+
+	return new(big.Int).Set(s.deposited), new(big.Int).Set(s.withdrawn)
+}
+
 func (s *Stats) eventslastTimeframe(eventType degendb.EventType) uint64 {
 	count := uint64(0)
 
@@ -112,60 +148,101 @@ func (s *Stats) volumeLastTimeframe() *big.Int {
 	return volume
 }
 
+// botVolumeLastTimeframe returns the sales volume with (known) mev bots involved.
+func (s *Stats) botVolumeLastTimeframe() *big.Int {
+	volume := big.NewInt(0)
+
+	for _, event := range s.RecentEvents.ToSlice() {
+		if event.Type == degendb.Sale && event.IsBot && time.Since(event.Timestamp) < s.timeframe {
+			volume = volume.Add(volume, event.AmountWei)
+		}
+	}
+
+	return volume
+}
+
+// UpdateBalances fetches the balances of all wallets concurrently. wallets for which
+// the fetch fails keep their last known balance, an error is only returned if all fetches failed.
 func (s *Stats) UpdateBalances() (*wallet.Wallets, error) {
 	gbl.Log.Debugf("updating wallet balances...")
 
-	balances, err := external.GetBalances(s.wallets)
-	if err != nil || balances == nil {
-		gbl.Log.Warn("❌ error while fetching wallet balances")
+	var (
+		wg        sync.WaitGroup
+		numFailed atomic.Int64
+	)
 
-		return nil, ErrWalletBalance
+	// limit the concurrent requests to stay below the api rate limit
+	semaphore := make(chan struct{}, max(viper.GetInt("stats.balances_concurrency"), 1))
+
+	for _, w := range *s.wallets {
+		wg.Add(1)
+
+		go func(w *wallet.Wallet) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if err := s.updateWalletBalance(w); err != nil {
+				gbl.Log.Warnf("❌ could not update balance of %s, keeping last known balance: %s", w.Name, err)
+
+				numFailed.Add(1)
+			}
+		}(w)
 	}
 
-	if viper.GetBool("log.debug") {
-		for _, balance := range balances {
-			gbl.Log.Debugf("UpdateBalances| %+v\n", balance)
-		}
+	wg.Wait()
+
+	if len(*s.wallets) > 0 && numFailed.Load() == int64(len(*s.wallets)) {
+		return nil, ErrWalletBalance
 	}
 
-	for _, balance := range balances {
-		walletAddress := common.HexToAddress(balance.Account)
+	return s.wallets, nil
+}
 
-		// init with ETH Balance
-		balanceTotalWei := balance.BalanceETH
+func (s *Stats) updateWalletBalance(w *wallet.Wallet) error {
+	balance, err := external.GetBalance(w.Address)
+	if err != nil {
+		return err
+	}
 
-		// add WETH Balance
-		balanceTotalWei = big.NewInt(0).Add(balanceTotalWei, balance.BalanceWETH)
+	gbl.Log.Debugf("UpdateBalances| %+v\n", balance)
 
-		// add BlurPool Balance
-		if balance.BalanceBlurPool != nil {
-			balanceTotalWei = big.NewInt(0).Add(balanceTotalWei, balance.BalanceBlurPool)
-		}
+	// eth + weth + blur pool
+	balanceTotalWei := new(big.Int).Add(balance.BalanceETH, balance.BalanceWETH)
+	balanceTotalWei.Add(balanceTotalWei, balance.BalanceBlurPool)
 
-		gbl.Log.Debugf("%s: %6.3fΞ total || %6.3f ETH | %6.3f WETH | %6.3f BlurPool", balance.Account, balanceTotalWei, utils.WeiToEther(balance.BalanceETH), utils.WeiToEther(balance.BalanceWETH), utils.WeiToEther(balance.BalanceBlurPool))
+	gbl.Log.Debugf("%s: %6.3fΞ total || %6.3f ETH | %6.3f WETH | %6.3f BlurPool", balance.Account, utils.WeiToEther(balanceTotalWei), utils.WeiToEther(balance.BalanceETH), utils.WeiToEther(balance.BalanceWETH), utils.WeiToEther(balance.BalanceBlurPool))
 
-		(*s.wallets)[walletAddress].BalanceBefore = (*s.wallets)[walletAddress].Balance
-		(*s.wallets)[walletAddress].Balance = balanceTotalWei
+	w.BalanceBefore = w.Balance
+	w.Balance = balanceTotalWei
+	w.BalanceUpdatedAt = time.Now()
 
-		trendIndicator := style.CreateTrendIndicator(
-			float64((*s.wallets)[walletAddress].BalanceBefore.Int64()),
-			float64((*s.wallets)[walletAddress].Balance.Int64()),
-		)
+	trendIndicator := style.CreateTrendIndicator(
+		float64(w.BalanceBefore.Int64()),
+		float64(w.Balance.Int64()),
+	)
 
-		(*s.wallets)[walletAddress].BalanceTrend = trendIndicator.String()
+	w.BalanceTrend = trendIndicator.String()
 
-		gbl.Log.Debugf("  %s balance: %s %6.3f", balance.Account, trendIndicator, utils.WeiToEther((*s.wallets)[walletAddress].Balance))
-	}
+	gbl.Log.Debugf("  %s balance: %s %6.3f", balance.Account, trendIndicator, utils.WeiToEther(w.Balance))
 
-	return s.wallets, nil
+	return nil
 }
 
 func (s *Stats) Print(queueOutput chan string) {
-	var (
-		formattedStatsLists string
+	formattedStatsLists := s.Render()
 
-		statsLists []string
-	)
+	if s.gasTicker != nil {
+		s.gasTicker.Reset(IdleInterval(viper.GetDuration("ticker.gasline")))
+	}
+
+	queueOutput <- "\n" + formattedStatsLists + "\n"
+}
+
+// Render returns the stats box as shown in the terminal.
+func (s *Stats) Render() string {
+	var statsLists []string
 
 	if viper.GetBool("stats.balances") {
 		_, err := s.UpdateBalances()
@@ -187,18 +264,45 @@ func (s *Stats) Print(queueOutput chan string) {
 		statsLists = append(statsLists, listStyle.Render(lipgloss.JoinVertical(lipgloss.Left, walletBalancesList...)))
 	}
 
-	if s.gb.RecentOwnEvents.Cardinality() > 0 {
+	// the own events history is the widest list, skip it on narrow terminals
+	if s.gb.RecentOwnEvents.Cardinality() > 0 && style.CurrentLayout() != style.LayoutNarrow {
 		eventsList := listStyle // .Copy().UnsetWidth().PaddingLeft(0).Render
 		statsLists = append(statsLists, eventsList.Render(lipgloss.JoinVertical(lipgloss.Left, s.getOwnEventsHistoryList()...)))
 	}
 
-	formattedStatsLists = lipgloss.JoinHorizontal(lipgloss.Top, statsLists...)
+	return joinStatsLists(statsLists)
+}
 
-	if s.gasTicker != nil {
-		s.gasTicker.Reset(viper.GetDuration("ticker.gasline"))
+// joinStatsLists arranges the lists side by side or stacks them on narrow terminals.
+// lists that do not fit into the terminal width are moved to an additional row.
+func joinStatsLists(statsLists []string) string {
+	width := style.TerminalWidth()
+
+	if style.CurrentLayout() == style.LayoutNarrow {
+		return lipgloss.JoinVertical(lipgloss.Left, statsLists...)
 	}
 
-	queueOutput <- "\n" + formattedStatsLists + "\n"
+	if width == 0 {
+		return lipgloss.JoinHorizontal(lipgloss.Top, statsLists...)
+	}
+
+	rows := make([]string, 0)
+	currentRow := make([]string, 0)
+	currentRowWidth := 0
+
+	for _, list := range statsLists {
+		if listWidth := lipgloss.Width(list); len(currentRow) > 0 && currentRowWidth+listWidth > width {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, currentRow...))
+			currentRow, currentRowWidth = make([]string, 0), 0
+		}
+
+		currentRow = append(currentRow, list)
+		currentRowWidth += lipgloss.Width(list)
+	}
+
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, currentRow...))
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
 func (s *Stats) getPrimaryStatsLists() []string {
@@ -289,6 +393,14 @@ func (s *Stats) getPrimaryStatsLists() []string {
 		firstColumn = append(firstColumn, []string{listItem(fmt.Sprintf("%s %s", fmtSalesPerMin, salesLabel))}...)
 	}
 
+	// share of the volume churned by mev bots
+	if botVolumeWei := s.botVolumeLastTimeframe(); botVolumeWei.Sign() > 0 && volumeWei.Sign() > 0 {
+		botShare, _ := new(big.Float).Quo(new(big.Float).SetInt(botVolumeWei), new(big.Float).SetInt(volumeWei)).Float64()
+
+		botLabel := style.DarkGrayStyle.Render("% 🤖")
+		firstColumn = append(firstColumn, []string{listItem(fmt.Sprintf("%s%s", valueStyle.Render(fmt.Sprintf("%6.1f", botShare*100)), botLabel))}...)
+	}
+
 	if mintsCount := s.eventslastTimeframe(degendb.Mint); mintsCount > 0 {
 		mintsLabel := style.DarkGrayStyle.Render("m/" + unitOrWait)
 
@@ -327,6 +439,21 @@ func (s *Stats) getPrimaryStatsLists() []string {
 		secondcolumn = append(secondcolumn, []string{listItem(fmt.Sprintf("%s %s", label, value)), listItem("")}...)
 	}
 
+	// eth/weth moved between own wallets & exchanges this session
+	if deposited, withdrawn := s.CapitalFlows(); deposited.Sign() > 0 || withdrawn.Sign() > 0 {
+		for _, flow := range []struct {
+			label string
+			value *big.Int
+		}{{"deposited", deposited}, {"withdrawn", withdrawn}} {
+			label := style.DarkGrayStyle.Render(flow.label)
+			value := style.GrayStyle.Render(fmt.Sprint(fmt.Sprintf("%6.2f", price.NewPrice(flow.value).Ether()), style.DarkGrayStyle.Render("Ξ")))
+
+			secondcolumn = append(secondcolumn, listItem(fmt.Sprintf("%s %s", label, value)))
+		}
+
+		secondcolumn = append(secondcolumn, listItem(""))
+	}
+
 	// redis stats
 	if viper.GetBool("redis.enabled") {
 		if s.rdb != nil {
@@ -385,6 +512,34 @@ func (s *Stats) getPrimaryStatsLists() []string {
 		}
 	}
 
+	// opensea api keys
+	for _, apiKey := range health.APIKeys(health.SourceOpenSea) {
+		labelStyle := style.DarkGrayStyle
+		if apiKey.Active {
+			labelStyle = style.GrayStyle
+		}
+
+		label := labelStyle.Render(fmt.Sprintf("%7s", apiKey.Key))
+
+		var value string
+
+		switch {
+		case apiKey.Dead:
+			value = style.TrendLightRedStyle.Copy().Width(9).Align(lipgloss.Right).Render("dead")
+		case apiKey.Disconnects > 0:
+			value = style.GrayStyle.Copy().Width(9).Align(lipgloss.Right).Render(fmt.Sprintf("%d dc", apiKey.Disconnects))
+		default:
+			value = style.TrendLightGreenStyle.Copy().Width(9).Align(lipgloss.Right).Render("ok")
+		}
+
+		secondcolumn = append(secondcolumn, []string{listItem(label + " " + value)}...)
+	}
+
+	// apis with an open circuit breaker (paused calls, cached data only)
+	for _, source := range health.DegradedSources() {
+		secondcolumn = append(secondcolumn, []string{listItem(style.TrendLightRedStyle.Render(fmt.Sprintf("degraded (%s)", source)))}...)
+	}
+
 	// running for
 	labelRunningFor := style.DarkGrayStyle.Render("running")
 	valueRunningFor := style.GrayStyle.Copy().Width(9).Align(lipgloss.Right).Render(time.Since(internal.RunningSince).Truncate(time.Second).String())
@@ -414,7 +569,12 @@ func (s *Stats) getPrimaryStatsLists() []string {
 func (s *Stats) getWalletStatsList(maxWalletNameLength int) []string {
 	wallets := s.wallets.SortByBalance()
 
-	numberOfWalletsToShow := int(math.Min(float64(viper.GetInt("stats.lines")), float64(len(wallets))))
+	maxLines := viper.GetInt("stats.lines")
+
+	// there is enough space on wide terminals to show more wallets
+	if style.CurrentLayout() == style.LayoutWide {
+		maxLines *= 2
+	}
 
 	walletsList := make([]string, 0)
 
@@ -425,17 +585,81 @@ func (s *Stats) getWalletStatsList(maxWalletNameLength int) []string {
 	}
 	walletsList = append(walletsList, listItem(fmt.Sprintf("%s %s%s", style.DarkGrayStyle.Render("Total"), style.GrayStyle.Render(fmt.Sprintf("%15.2f", utils.WeiToEther(sumWalletBalances))), style.GrayStyle.Render("Ξ"))))
 
-	for _, w := range wallets[:numberOfWalletsToShow] {
-		balanceEther, _ := utils.WeiToEther(w.Balance).Float64()
-		balanceRounded := math.Floor(balanceEther*100.0) / 100.0
-		balance := fmt.Sprint(style.LightGrayStyle.Render(fmt.Sprintf("%5.2f", balanceRounded)), style.GrayStyle.Render("Ξ"))
-		walletBalance := fmt.Sprintf("%s %s %s", w.ColoredName(maxWalletNameLength), style.DarkGrayStyle.Render(w.BalanceTrend), balance)
-		walletsList = append(walletsList, listItem(walletBalance))
+	linesLeft := maxLines
+
+	// grouped wallets with the aggregated balance per group, collapsed groups show only the aggregate
+	groups, groupNames := s.wallets.Groups()
+	collapsedGroups := viper.GetStringSlice("stats.wallet_groups.collapsed")
+
+	for _, groupName := range groupNames {
+		if linesLeft <= 0 {
+			break
+		}
+
+		groupWallets := groups[groupName]
+		isCollapsed := slices.Contains(collapsedGroups, groupName)
+
+		groupBalance := big.NewInt(0)
+		for _, w := range groupWallets {
+			groupBalance.Add(groupBalance, w.Balance)
+		}
+
+		fmtGroupSize := style.DarkGrayStyle.Render(fmt.Sprintf(" (%d)", len(groupWallets)))
+		if isCollapsed {
+			fmtGroupSize = style.DarkGrayStyle.Render(fmt.Sprintf(" +%d", len(groupWallets)))
+		}
+
+		groupBalanceEther, _ := utils.WeiToEther(groupBalance).Float64()
+		fmtGroupName := style.GrayStyle.Copy().Bold(true).Width(maxWalletNameLength + 2).MaxWidth(maxWalletNameLength + 2).Render(groupName)
+
+		walletsList = append(walletsList, listItem(fmt.Sprintf("%s %s%s%s", fmtGroupName, style.LightGrayStyle.Render(fmt.Sprintf("%5.2f", math.Floor(groupBalanceEther*100.0)/100.0)), style.GrayStyle.Render("Ξ"), fmtGroupSize)))
+		linesLeft--
+
+		if isCollapsed {
+			continue
+		}
+
+		for _, w := range groupWallets {
+			if linesLeft <= 0 {
+				break
+			}
+
+			walletsList = append(walletsList, listItem(style.DarkGrayStyle.Render("· ")+formatWalletBalance(w, maxWalletNameLength)))
+			linesLeft--
+		}
+	}
+
+	// wallets without a group
+	for _, w := range wallets {
+		if linesLeft <= 0 {
+			break
+		}
+
+		if w.Group != "" {
+			continue
+		}
+
+		walletsList = append(walletsList, listItem(formatWalletBalance(w, maxWalletNameLength)))
+		linesLeft--
 	}
 
 	return walletsList
 }
 
+// formatWalletBalance formats the name, trend & balance of a wallet for the stats box.
+func formatWalletBalance(w *wallet.Wallet, maxWalletNameLength int) string {
+	balanceEther, _ := utils.WeiToEther(w.Balance).Float64()
+	balanceRounded := math.Floor(balanceEther*100.0) / 100.0
+	balance := fmt.Sprint(style.LightGrayStyle.Render(fmt.Sprintf("%5.2f", balanceRounded)), style.GrayStyle.Render("Ξ"))
+
+	// mark balances we could not update for a while
+	if balanceAge := time.Since(w.BalanceUpdatedAt); !w.BalanceUpdatedAt.IsZero() && balanceAge > viper.GetDuration("stats.balances_stale_after") {
+		balance += style.DarkGrayStyle.Copy().Faint(true).Render(fmt.Sprintf(" ~%dm", int(balanceAge.Minutes())))
+	}
+
+	return fmt.Sprintf("%s %s %s", w.ColoredName(maxWalletNameLength), style.DarkGrayStyle.Render(w.BalanceTrend), balance)
+}
+
 func (s *Stats) getOwnEventsHistoryList() []string {
 	eventsList := make([]string, 0)
 
@@ -544,23 +768,54 @@ func (s *Stats) getOwnEventsHistoryList() []string {
 	return eventsList
 }
 
+// StartTicker prints the stats box every intervalPrintStats, a running ticker loop is stopped first.
 func (s *Stats) StartTicker(intervalPrintStats time.Duration, queueOutput chan string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.stopTickerMu.Lock()
+	if s.stopTicker != nil {
+		s.stopTicker()
+	}
+
+	s.stopTicker = cancel
+	s.stopTickerMu.Unlock()
+
 	tickerPrintStats := time.NewTicker(time.Second * 7)
 
 	gbl.Log.Infof("starting stats ticker (%s)", intervalPrintStats)
 
-	time.Sleep(time.Until(time.Now().Truncate(intervalPrintStats).Add(intervalPrintStats)))
+	select {
+	case <-ctx.Done():
+		// restarted again while waiting for the first tick
+		tickerPrintStats.Stop()
 
-	tickerPrintStats.Reset(intervalPrintStats)
+		return
+	case <-time.After(time.Until(time.Now().Truncate(intervalPrintStats).Add(intervalPrintStats))):
+	}
+
+	tickerPrintStats.Reset(IdleInterval(intervalPrintStats))
+
+	// the balances are updated with the stats box, both are slowed down while idle
+	RegisterIdleTicker("statsbox", tickerPrintStats, func() time.Duration { return viper.GetDuration("ticker.statsbox") })
 
 	go func() {
-		for range tickerPrintStats.C {
+		defer tickerPrintStats.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tickerPrintStats.C:
+			}
+
+			health.EventReceived(health.SourceStatsTicker)
+
 			s.Print(queueOutput)
 
 			if newInterval := viper.GetDuration("ticker.statsbox"); newInterval != intervalPrintStats {
 				intervalPrintStats = newInterval
 
-				tickerPrintStats.Reset(intervalPrintStats)
+				tickerPrintStats.Reset(IdleInterval(intervalPrintStats))
 
 				log.Printf("👀 stats ticker interval changed to %s", intervalPrintStats)
 			}
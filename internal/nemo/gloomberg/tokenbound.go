@@ -0,0 +1,88 @@
+package gloomberg
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/benleb/gloomberg/internal/eip6551"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RegisterTokenboundAccounts registers the token bound accounts created in a tx
+// and prints the creations for watched collections.
+func (gb *Gloomberg) RegisterTokenboundAccounts(receipt *types.Receipt) {
+	if receipt == nil {
+		return
+	}
+
+	for _, txLog := range receipt.Logs {
+		account := eip6551.ParseAccountCreated(txLog)
+		if account == nil {
+			continue
+		}
+
+		eip6551.RegisterAccount(account)
+		eip6551TokenDiscoveredCounter.Inc()
+
+		// persist the account, only the most recent ones are kept in memory
+		if err := gb.Rueidi.StoreTokenboundAccount(context.Background(), account.Address, account.String()); err != nil {
+			gbl.Log.Debugf("❌ storing token bound account %s failed: %s", account.Address.Hex(), err)
+		}
+
+		if slices.Contains(gb.CollectionDB.UserCollectionsAddresses(), account.TokenContract) {
+			PrModf("e6551", "token bound account created for %s: %s", gb.FormatTokenboundAccountParent(account), style.ShortenAddress(account.Address))
+		}
+	}
+}
+
+// FormatTokenboundAccountParent returns the name of the parent nft of the account, e.g. "Sapienz #123".
+func (gb *Gloomberg) FormatTokenboundAccountParent(account *eip6551.Account) string {
+	name := style.ShortenAddress(account.TokenContract)
+
+	gb.CollectionDB.RWMu.RLock()
+	collection, ok := gb.CollectionDB.Collections[account.TokenContract]
+	gb.CollectionDB.RWMu.RUnlock()
+
+	if ok && collection != nil {
+		return collection.Style().Render(fmt.Sprintf("%s #%s", collection.Name, account.TokenID.String()))
+	}
+
+	return fmt.Sprintf("%s #%s", name, account.TokenID.String())
+}
+
+// FormatTokenboundAccount returns "<parent>'s TBA" if the address is a known token bound account.
+func (gb *Gloomberg) FormatTokenboundAccount(address common.Address) (string, bool) {
+	account := eip6551.LookupAccount(address)
+	if account == nil {
+		account = gb.lookupStoredTokenboundAccount(address)
+	}
+
+	if account == nil {
+		return "", false
+	}
+
+	return gb.FormatTokenboundAccountParent(account) + style.DarkGrayStyle.Render("'s TBA"), true
+}
+
+// lookupStoredTokenboundAccount returns the token bound account from redis, e.g. created before a restart.
+func (gb *Gloomberg) lookupStoredTokenboundAccount(address common.Address) *eip6551.Account {
+	parent, err := gb.Rueidi.GetCachedTokenboundAccount(context.Background(), address)
+	if err != nil || parent == "" {
+		return nil
+	}
+
+	account, err := eip6551.ParseAccount(address, parent)
+	if err != nil {
+		gbl.Log.Debugf("❌ invalid stored token bound account %s: %s", address.Hex(), err)
+
+		return nil
+	}
+
+	eip6551.RegisterAccount(account)
+
+	return account
+}
@@ -111,6 +111,7 @@ var OpenSea = MarketPlace{
 		common.HexToAddress("0x00000000006c3852cbef3e08e8df289169ede581"), // Seaport 1.1
 		common.HexToAddress("0x00005ea00ac477b1030ce78506496e8c2de24bf5"), // SeaDrop
 		common.HexToAddress("0x00000000000000adc04c56bf30ac9d3c0aaf14dc"), // Seaport 1.5
+		common.HexToAddress("0x0000000000000068f116a894984e2db1123eb395"), // Seaport 1.6
 	),
 
 	// ContractAddresses: map[common.Address]bool{
@@ -148,11 +149,14 @@ var X2Y2 = MarketPlace{
 }
 
 var LooksRare = MarketPlace{
-	ID:                "looksrare",
-	Name:              "LooksRare",
-	Color:             lipgloss.Color("#21E453"),
-	ContractAddresses: mapset.NewSet[common.Address](common.HexToAddress("0x59728544B08AB483533076417FbBB2fD0B17CE3a")),
-	Tag:               "|",
+	ID:    "looksrare",
+	Name:  "LooksRare",
+	Color: lipgloss.Color("#21E453"),
+	ContractAddresses: mapset.NewSet[common.Address](
+		common.HexToAddress("0x59728544B08AB483533076417FbBB2fD0B17CE3a"),
+		common.HexToAddress("0x0000000000E655fAe4d56241588680F86E3b2377"), // LooksRare v2
+	),
+	Tag: "|",
 }
 
 var SuperRare = MarketPlace{
@@ -199,3 +203,12 @@ var LoanContracts = mapset.NewSet[common.Address](
 	internal.NFTLoanTicketV2ContractAddress,
 	internal.BorrowerNoteTicket,
 )
+
+// Aggregators are the router contracts of marketplace aggregators buying across marketplaces & collections.
+var Aggregators = map[common.Address]string{
+	common.HexToAddress("0x39da41747a83aeE658334415666f3EF92DD0D541"): "Blur",      // BlurSwap
+	common.HexToAddress("0x83C8F28c26bF6aaca652Df1DbBE0e1b56F8baBa2"): "Gem",       // GemSwap v2
+	common.HexToAddress("0xC2c862322E9c97D6244a3506655DA95F05246Fd8"): "Reservoir", // ReservoirV6_0_1
+	common.HexToAddress("0x178A86D36D89c7FDeBeA90b739605da7B131ff6A"): "Reservoir", // ReservoirV6_0_0
+	internal.UniswapUniversalRouterContractAddress:                    "Uniswap",   // universal router (former Genie)
+}
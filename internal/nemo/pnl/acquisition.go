@@ -0,0 +1,83 @@
+package pnl
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/token"
+	"github.com/benleb/gloomberg/internal/rueidica"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AcquisitionContext describes how a sold token has been acquired.
+type AcquisitionContext struct {
+	HeldFor time.Duration
+
+	// per token
+	BoughtFor *price.Price
+	SoldFor   *price.Price
+	Profit    *price.Price
+}
+
+func (ac *AcquisitionContext) String() string {
+	profit := ac.Profit.Ether()
+
+	return fmt.Sprintf("held %s, bought for %.3fΞ, sold %.3fΞ (%+.3fΞ)", FormatHeldFor(ac.HeldFor), ac.BoughtFor.Ether(), ac.SoldFor.Ether(), profit)
+}
+
+// FormatHeldFor formats the duration a token was held, e.g. "12 days".
+func FormatHeldFor(heldFor time.Duration) string {
+	switch days := int(heldFor.Hours() / 24); {
+	case days > 1:
+		return fmt.Sprintf("%d days", days)
+	case heldFor.Hours() >= 1:
+		return fmt.Sprintf("%.0fh", heldFor.Hours())
+	default:
+		return fmt.Sprintf("%.0fmin", heldFor.Minutes())
+	}
+}
+
+// AcquisitionContext returns the acquisition context for a token sold for proceedsPerToken or nil if the token is not in the ledger.
+func (l *Ledger) AcquisitionContext(t *token.Token, proceedsPerToken *big.Int, soldAt time.Time) *AcquisitionContext {
+	holding := l.GetHolding(t)
+	if holding == nil || holding.AcquiredAt.IsZero() || proceedsPerToken == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	costPerToken := holding.AvgCost().Wei()
+
+	return &AcquisitionContext{
+		HeldFor:   soldAt.Sub(holding.AcquiredAt),
+		BoughtFor: price.NewPrice(costPerToken),
+		SoldFor:   price.NewPrice(proceedsPerToken),
+		Profit:    price.NewPrice(new(big.Int).Sub(proceedsPerToken, costPerToken)),
+	}
+}
+
+// live ledgers of the own wallets, loaded on first use.
+var (
+	ledgers   = make(map[common.Address]*Ledger)
+	ledgersMu = &sync.Mutex{}
+)
+
+// GetLedger returns the (cached) ledger of a wallet.
+func GetLedger(ctx context.Context, rueidi *rueidica.Rueidica, walletAddress common.Address) *Ledger {
+	ledgersMu.Lock()
+	defer ledgersMu.Unlock()
+
+	if ledger, ok := ledgers[walletAddress]; ok {
+		return ledger
+	}
+
+	ledger := LoadLedger(ctx, rueidi, walletAddress)
+	ledgers[walletAddress] = ledger
+
+	return ledger
+}
@@ -0,0 +1,222 @@
+package pnl
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/token"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrLedgerNotImported = errors.New("no history in the ledger, import the wallet first (gloomberg import-wallet)")
+
+// BlockAtTime returns the last block mined before or at the given time (binary search over the block headers).
+func BlockAtTime(ctx context.Context, pool *provider.Pool, at time.Time) (uint64, error) {
+	headBlock, err := pool.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	low, high := uint64(0), headBlock
+
+	for low < high {
+		mid := (low + high + 1) / 2
+
+		header, err := pool.HeaderByNumber(ctx, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+
+		if int64(header.Time) <= at.Unix() {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return low, nil
+}
+
+// HoldingsAt returns the holdings of the ledgers wallet at the given block by reverting the nft
+// transfers between the block & the last block of the (persisted) ledger.
+// tokens sent away after the block have no cost basis as the ledger doesn't know them anymore.
+func HoldingsAt(ctx context.Context, pool *provider.Pool, ledger *Ledger, blockNumber uint64, blockRange uint64) (map[string]*Holding, error) {
+	ledger.mu.RLock()
+
+	if ledger.NumTxs == 0 {
+		ledger.mu.RUnlock()
+
+		return nil, ErrLedgerNotImported
+	}
+
+	// copy the current holdings
+	holdings := make(map[string]*Holding, len(ledger.Holdings))
+	for shortID, holding := range ledger.Holdings {
+		holdingCopy := *holding
+		holdingCopy.CostBasis = big.NewInt(0)
+
+		if holding.CostBasis != nil {
+			holdingCopy.CostBasis.Set(holding.CostBasis)
+		}
+
+		holdings[shortID] = &holdingCopy
+	}
+
+	lastBlock := ledger.LastBlock
+
+	ledger.mu.RUnlock()
+
+	walletTopic := common.BytesToHash(ledger.Wallet.Bytes())
+
+	for start := blockNumber + 1; start <= lastBlock; start += blockRange {
+		end := min(start+blockRange-1, lastBlock)
+
+		for _, topics := range walletTopicFilters(ledger.Wallet) {
+			logs, err := pool.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Topics:    topics,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, txLog := range logs {
+				// erc20 transfers share the Transfer topic
+				if len(txLog.Topics) < 4 || txLog.Removed {
+					continue
+				}
+
+				from, to, moved := parseTransferLog(&txLog)
+
+				// revert the transfer
+				sign := int64(1)
+				if to == walletTopic {
+					sign = -1
+				} else if from != walletTopic {
+					continue
+				}
+
+				for _, m := range moved {
+					holding, ok := holdings[m.token.ShortID()]
+					if !ok {
+						holding = &Holding{Token: m.token, CostBasis: big.NewInt(0)}
+						holdings[m.token.ShortID()] = holding
+					}
+
+					holding.Amount += sign * m.amount
+				}
+			}
+		}
+	}
+
+	for shortID, holding := range holdings {
+		if holding.Amount <= 0 {
+			delete(holdings, shortID)
+		}
+	}
+
+	return holdings, nil
+}
+
+// SortedHoldings returns the holdings sorted by collection & token id.
+func SortedHoldings(holdings map[string]*Holding) []*Holding {
+	sorted := make([]*Holding, 0, len(holdings))
+	for _, holding := range holdings {
+		sorted = append(sorted, holding)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Token.Address == sorted[j].Token.Address {
+			return sorted[i].Token.ID.Cmp(sorted[j].Token.ID) < 0
+		}
+
+		return sorted[i].Token.Address.Hex() < sorted[j].Token.Address.Hex()
+	})
+
+	return sorted
+}
+
+// movedToken is a token & the amount moved by a transfer log.
+type movedToken struct {
+	token  *token.Token
+	amount int64
+}
+
+// batchArguments are the non-indexed args of TransferBatch: uint256[] ids, uint256[] values.
+var batchArguments = func() abi.Arguments {
+	uint256Array, _ := abi.NewType("uint256[]", "", nil)
+
+	return abi.Arguments{{Name: "ids", Type: uint256Array}, {Name: "values", Type: uint256Array}}
+}()
+
+// parseTransferLog returns the sender & receiver topics & the tokens moved by a Transfer/TransferSingle/TransferBatch log.
+func parseTransferLog(txLog *types.Log) (common.Hash, common.Hash, []movedToken) {
+	if len(txLog.Topics) < 4 {
+		return common.Hash{}, common.Hash{}, nil
+	}
+
+	switch txLog.Topics[0] {
+	case common.HexToHash(string(topic.Transfer)):
+		return txLog.Topics[1], txLog.Topics[2], []movedToken{{token: &token.Token{Address: txLog.Address, ID: txLog.Topics[3].Big()}, amount: 1}}
+
+	case common.HexToHash(string(topic.TransferSingle)):
+		// data: id, value
+		if len(txLog.Data) < 64 {
+			return common.Hash{}, common.Hash{}, nil
+		}
+
+		moved := movedToken{token: &token.Token{Address: txLog.Address, ID: new(big.Int).SetBytes(txLog.Data[:32])}, amount: new(big.Int).SetBytes(txLog.Data[32:64]).Int64()}
+
+		return txLog.Topics[2], txLog.Topics[3], []movedToken{moved}
+
+	case common.HexToHash(string(topic.TransferBatch)):
+		// data: ids[], values[]
+		values, err := batchArguments.Unpack(txLog.Data)
+		if err != nil || len(values) != 2 {
+			return common.Hash{}, common.Hash{}, nil
+		}
+
+		ids, okIDs := values[0].([]*big.Int)
+		amounts, okAmounts := values[1].([]*big.Int)
+
+		if !okIDs || !okAmounts || len(ids) != len(amounts) {
+			return common.Hash{}, common.Hash{}, nil
+		}
+
+		moved := make([]movedToken, 0, len(ids))
+		for idx, id := range ids {
+			moved = append(moved, movedToken{token: &token.Token{Address: txLog.Address, ID: id}, amount: amounts[idx].Int64()})
+		}
+
+		return txLog.Topics[2], txLog.Topics[3], moved
+	}
+
+	return common.Hash{}, common.Hash{}, nil
+}
+
+// walletTopicFilters returns the filters for all Transfer/TransferSingle/TransferBatch logs from/to the wallet.
+// Transfer: from = topic[1], to = topic[2] | TransferSingle/Batch: from = topic[2], to = topic[3].
+func walletTopicFilters(wallet common.Address) [][][]common.Hash {
+	walletTopic := common.BytesToHash(wallet.Bytes())
+	transferTopic := common.HexToHash(string(topic.Transfer))
+	transferSingleTopic := common.HexToHash(string(topic.TransferSingle))
+	transferBatchTopic := common.HexToHash(string(topic.TransferBatch))
+
+	return [][][]common.Hash{
+		{{transferTopic}, {walletTopic}},
+		{{transferTopic}, {}, {walletTopic}},
+		{{transferSingleTopic}, {}, {walletTopic}},
+		{{transferSingleTopic}, {}, {}, {walletTopic}},
+		{{transferBatchTopic}, {}, {walletTopic}},
+		{{transferBatchTopic}, {}, {}, {walletTopic}},
+	}
+}
@@ -0,0 +1,73 @@
+package pnl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func Test_parseTransferLog(t *testing.T) {
+	contract := common.HexToAddress("0x76be3b62873462d2142405439777e971754e8e77")
+	operator := common.BytesToHash(common.HexToAddress("0x1").Bytes())
+	from := common.BytesToHash(common.HexToAddress("0x2").Bytes())
+	to := common.BytesToHash(common.HexToAddress("0x3").Bytes())
+
+	batchData, err := batchArguments.Pack([]*big.Int{big.NewInt(10), big.NewInt(11)}, []*big.Int{big.NewInt(2), big.NewInt(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	singleData := append(common.BigToHash(big.NewInt(7)).Bytes(), common.BigToHash(big.NewInt(3)).Bytes()...)
+
+	tests := []struct {
+		name    string
+		txLog   *types.Log
+		wantIDs []int64
+		amounts []int64
+	}{
+		{
+			name:    "Transfer",
+			txLog:   &types.Log{Address: contract, Topics: []common.Hash{common.HexToHash(string(topic.Transfer)), from, to, common.BigToHash(big.NewInt(42))}},
+			wantIDs: []int64{42},
+			amounts: []int64{1},
+		},
+		{
+			name:    "TransferSingle",
+			txLog:   &types.Log{Address: contract, Topics: []common.Hash{common.HexToHash(string(topic.TransferSingle)), operator, from, to}, Data: singleData},
+			wantIDs: []int64{7},
+			amounts: []int64{3},
+		},
+		{
+			name:    "TransferBatch",
+			txLog:   &types.Log{Address: contract, Topics: []common.Hash{common.HexToHash(string(topic.TransferBatch)), operator, from, to}, Data: batchData},
+			wantIDs: []int64{10, 11},
+			amounts: []int64{2, 5},
+		},
+		{
+			name:  "TransferBatch with invalid data",
+			txLog: &types.Log{Address: contract, Topics: []common.Hash{common.HexToHash(string(topic.TransferBatch)), operator, from, to}, Data: []byte{0x1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFrom, gotTo, moved := parseTransferLog(tt.txLog)
+
+			if len(moved) != len(tt.wantIDs) {
+				t.Fatalf("parseTransferLog() moved %d tokens, want %d", len(moved), len(tt.wantIDs))
+			}
+
+			if len(moved) > 0 && (gotFrom != from || gotTo != to) {
+				t.Errorf("parseTransferLog() from/to = %s/%s, want %s/%s", gotFrom, gotTo, from, to)
+			}
+
+			for idx, m := range moved {
+				if m.token.Address != contract || m.token.ID.Int64() != tt.wantIDs[idx] || m.amount != tt.amounts[idx] {
+					t.Errorf("parseTransferLog() token %d = %s #%s x%d, want #%d x%d", idx, m.token.Address, m.token.ID, m.amount, tt.wantIDs[idx], tt.amounts[idx])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package pnl
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var ErrTxNotFound = errors.New("tx or receipt not found")
+
+// ImportProgress is reported after each processed block range.
+type ImportProgress struct {
+	FromBlock uint64
+	ToBlock   uint64
+	HeadBlock uint64
+	NumTxs    int
+}
+
+type txRef struct {
+	hash        common.Hash
+	blockNumber uint64
+	index       uint
+}
+
+// ImportWallet backfills the historical nft activity of the ledgers wallet via eth_getLogs.
+// it fetches all Transfer/TransferSingle/TransferBatch logs from/to the wallet in chunks of blockRange blocks,
+// parses the corresponding txs and books them to the ledger in chronological order.
+func ImportWallet(ctx context.Context, pool *provider.Pool, ledger *Ledger, fromBlock uint64, blockRange uint64, progress func(ImportProgress)) error {
+	headBlock, err := pool.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ledger.LastBlock >= fromBlock {
+		fromBlock = ledger.LastBlock + 1
+	}
+
+	topicFilters := walletTopicFilters(ledger.Wallet)
+
+	blockTimes := make(map[uint64]time.Time)
+
+	for start := fromBlock; start <= headBlock; start += blockRange {
+		end := start + blockRange - 1
+		if end > headBlock {
+			end = headBlock
+		}
+
+		txRefs := make(map[common.Hash]txRef)
+
+		for _, topics := range topicFilters {
+			logs, err := pool.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Topics:    topics,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, txLog := range logs {
+				// erc20 transfers share the Transfer topic
+				if len(txLog.Topics) < 4 || txLog.Removed {
+					continue
+				}
+
+				txRefs[txLog.TxHash] = txRef{hash: txLog.TxHash, blockNumber: txLog.BlockNumber, index: txLog.TxIndex}
+			}
+		}
+
+		// book txs in chronological order
+		sortedRefs := make([]txRef, 0, len(txRefs))
+		for _, ref := range txRefs {
+			sortedRefs = append(sortedRefs, ref)
+		}
+
+		sort.Slice(sortedRefs, func(i, j int) bool {
+			if sortedRefs[i].blockNumber == sortedRefs[j].blockNumber {
+				return sortedRefs[i].index < sortedRefs[j].index
+			}
+
+			return sortedRefs[i].blockNumber < sortedRefs[j].blockNumber
+		})
+
+		for _, ref := range sortedRefs {
+			ttx, err := getTokenTransaction(ctx, pool, ref.hash)
+			if err != nil {
+				gbl.Log.Warnf("❗️ pnl import | could not fetch tx %s: %s", ref.hash.Hex(), err)
+
+				continue
+			}
+
+			if _, ok := blockTimes[ref.blockNumber]; !ok {
+				if header, err := pool.HeaderByNumber(ctx, new(big.Int).SetUint64(ref.blockNumber)); err == nil {
+					blockTimes[ref.blockNumber] = time.Unix(int64(header.Time), 0)
+				}
+			}
+
+			ledger.AddTokenTransaction(ttx, ref.blockNumber, blockTimes[ref.blockNumber])
+		}
+
+		ledger.mu.Lock()
+		if end > ledger.LastBlock {
+			ledger.LastBlock = end
+		}
+		ledger.mu.Unlock()
+
+		if progress != nil {
+			progress(ImportProgress{FromBlock: start, ToBlock: end, HeadBlock: headBlock, NumTxs: len(sortedRefs)})
+		}
+	}
+
+	return nil
+}
+
+func getTokenTransaction(ctx context.Context, pool *provider.Pool, txHash common.Hash) (*totra.TokenTransaction, error) {
+	var (
+		tx      *types.Transaction
+		receipt *types.Receipt
+		err     error
+	)
+
+	if tx, err = pool.TransactionByHash(ctx, txHash); err != nil {
+		return nil, err
+	}
+
+	if receipt, err = pool.TransactionReceipt(ctx, txHash); err != nil {
+		return nil, err
+	}
+
+	if tx == nil || receipt == nil {
+		return nil, ErrTxNotFound
+	}
+
+	return totra.NewTokenTransaction(tx, receipt, pool), nil
+}
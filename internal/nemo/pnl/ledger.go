@@ -0,0 +1,233 @@
+package pnl
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/token"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/rueidica"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Holding is a token (or multiple of an erc1155 token) currently held by a wallet.
+type Holding struct {
+	Token  *token.Token `json:"token"`
+	Amount int64        `json:"amount"`
+
+	// total amount paid for all currently held tokens
+	CostBasis *big.Int `json:"cost_basis"`
+
+	AcquiredAt    time.Time   `json:"acquired_at"`
+	AcquiredBlock uint64      `json:"acquired_block"`
+	AcquiredTx    common.Hash `json:"acquired_tx"`
+	AcquiredVia   string      `json:"acquired_via"`
+}
+
+// AvgCost returns the average cost per held token.
+func (h *Holding) AvgCost() *price.Price {
+	if h.Amount <= 0 || h.CostBasis == nil {
+		return price.NewPrice(big.NewInt(0))
+	}
+
+	return price.NewPrice(new(big.Int).Div(h.CostBasis, big.NewInt(h.Amount)))
+}
+
+// Ledger keeps track of the holdings & realized profit/loss of a wallet.
+type Ledger struct {
+	Wallet common.Address `json:"wallet"`
+
+	Holdings map[string]*Holding `json:"holdings"`
+
+	// realized profit/loss from sold tokens
+	Realized *big.Int `json:"realized"`
+
+	// highest block processed, used to continue imports
+	LastBlock uint64 `json:"last_block"`
+
+	NumTxs uint64 `json:"num_txs"`
+
+	mu *sync.RWMutex
+}
+
+func NewLedger(walletAddress common.Address) *Ledger {
+	return &Ledger{
+		Wallet:   walletAddress,
+		Holdings: make(map[string]*Holding),
+		Realized: big.NewInt(0),
+		mu:       &sync.RWMutex{},
+	}
+}
+
+// LoadLedger loads the ledger for a wallet from redis or returns a new, empty one.
+func LoadLedger(ctx context.Context, rueidi *rueidica.Rueidica, walletAddress common.Address) *Ledger {
+	ledger := NewLedger(walletAddress)
+
+	rawLedger, err := rueidi.GetWalletLedger(ctx, walletAddress)
+	if err != nil || rawLedger == "" {
+		return ledger
+	}
+
+	if err := json.Unmarshal([]byte(rawLedger), ledger); err != nil {
+		gbl.Log.Warnf("❗️ pnl | could not decode ledger for %s: %s", walletAddress.Hex(), err)
+
+		return NewLedger(walletAddress)
+	}
+
+	if ledger.Holdings == nil {
+		ledger.Holdings = make(map[string]*Holding)
+	}
+
+	if ledger.Realized == nil {
+		ledger.Realized = big.NewInt(0)
+	}
+
+	return ledger
+}
+
+// Save stores the ledger in redis.
+func (l *Ledger) Save(ctx context.Context, rueidi *rueidica.Rueidica) error {
+	l.mu.RLock()
+	rawLedger, err := json.Marshal(l)
+	l.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	return rueidi.StoreWalletLedger(ctx, l.Wallet, string(rawLedger))
+}
+
+// GetHolding returns the holding for the given token or nil if the wallet doesn't hold it.
+func (l *Ledger) GetHolding(t *token.Token) *Holding {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.Holdings[t.ShortID()]
+}
+
+// CostBasis returns the total amount paid for all currently held tokens.
+func (l *Ledger) CostBasis() *price.Price {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	total := big.NewInt(0)
+	for _, holding := range l.Holdings {
+		total.Add(total, holding.CostBasis)
+	}
+
+	return price.NewPrice(total)
+}
+
+// RealizedPnL returns the realized profit/loss of sold tokens.
+func (l *Ledger) RealizedPnL() *price.Price {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return price.NewPrice(new(big.Int).Set(l.Realized))
+}
+
+// AddTokenTransaction books the nft transfers of a tx from/to the ledgers wallet.
+func (l *Ledger) AddTokenTransaction(ttx *totra.TokenTransaction, blockNumber uint64, blockTime time.Time) {
+	if ttx == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	received := make([]*totra.TokenTransfer, 0)
+	sent := make([]*totra.TokenTransfer, 0)
+	numReceived := int64(0)
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() {
+			continue
+		}
+
+		switch {
+		case transfer.To == l.Wallet && transfer.From != l.Wallet:
+			received = append(received, transfer)
+			numReceived += transfer.AmountTokens.Int64()
+
+		case transfer.From == l.Wallet && transfer.To != l.Wallet:
+			sent = append(sent, transfer)
+		}
+	}
+
+	if len(received) == 0 && len(sent) == 0 {
+		return
+	}
+
+	// the wallet only paid if it sent the tx (purchase, mint, ...)
+	costPerToken := big.NewInt(0)
+	if ttx.From == l.Wallet && numReceived > 0 && ttx.AmountPaid != nil {
+		costPerToken = new(big.Int).Div(ttx.AmountPaid, big.NewInt(numReceived))
+	}
+
+	for _, transfer := range received {
+		amount := transfer.AmountTokens.Int64()
+		cost := new(big.Int).Mul(costPerToken, big.NewInt(amount))
+
+		holding, ok := l.Holdings[transfer.Token.ShortID()]
+		if !ok {
+			holding = &Holding{Token: transfer.Token, CostBasis: big.NewInt(0)}
+			l.Holdings[transfer.Token.ShortID()] = holding
+		}
+
+		holding.Amount += amount
+		holding.CostBasis.Add(holding.CostBasis, cost)
+		holding.AcquiredAt = blockTime
+		holding.AcquiredBlock = blockNumber
+		holding.AcquiredTx = ttx.TxHash
+		holding.AcquiredVia = ttx.Action.String()
+	}
+
+	for _, transfer := range sent {
+		amount := transfer.AmountTokens.Int64()
+
+		// proceeds are only realized if the wallet got something in return
+		proceeds := big.NewInt(0)
+		if degendb.SaleTypes.Contains(ttx.Action) && transfer.AmountEtherReturned != nil {
+			proceeds = transfer.AmountEtherReturned
+		}
+
+		if proceeds.Sign() == 0 && degendb.SaleTypes.Contains(ttx.Action) && ttx.From != l.Wallet && len(sent) > 0 {
+			proceeds = new(big.Int).Div(ttx.AmountPaid, big.NewInt(int64(len(sent))))
+		}
+
+		holding, ok := l.Holdings[transfer.Token.ShortID()]
+		if !ok {
+			// token acquired before the imported range -> unknown cost basis
+			l.Realized.Add(l.Realized, proceeds)
+
+			continue
+		}
+
+		// remove the cost of the sent tokens proportionally
+		sentCost := new(big.Int).Mul(holding.AvgCost().Wei(), big.NewInt(amount))
+
+		if degendb.SaleTypes.Contains(ttx.Action) {
+			l.Realized.Add(l.Realized, new(big.Int).Sub(proceeds, sentCost))
+		}
+
+		holding.Amount -= amount
+		holding.CostBasis.Sub(holding.CostBasis, sentCost)
+
+		if holding.Amount <= 0 {
+			delete(l.Holdings, transfer.Token.ShortID())
+		}
+	}
+
+	if blockNumber > l.LastBlock {
+		l.LastBlock = blockNumber
+	}
+
+	l.NumTxs++
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// number of blocks fetched per eth_getLogs call while backfilling
+const backfillChunkSize = 10
+
+// forwardLogs passes the logs of a subscription to the queue & tracks the last received block.
+// if the subscription drops, it re-subscribes & backfills the missed blocks via eth_getLogs.
+// the logs of the last block are fetched again, already processed txs are skipped by the chain watcher.
+// if the context is canceled, it unsubscribes from the node.
+func (p *Provider) forwardLogs(ctx context.Context, sub ethereum.Subscription, filterQuery ethereum.FilterQuery, providerLogs chan types.Log, queueLogs chan types.Log) {
+	stalled := p.stalledSignal()
+
+	for {
+		select {
+		case <-ctx.Done():
+			sub.Unsubscribe()
+
+			gbl.Log.Debugf("🔌 unsubscribed from logs via %s", style.Bold(p.Name))
+
+			return
+
+		case txLog := <-providerLogs:
+			p.lastLogReceivedAt.Store(time.Now().UnixNano())
+
+			if txLog.BlockNumber > atomic.LoadUint64(&p.lastBlock) {
+				atomic.StoreUint64(&p.lastBlock, txLog.BlockNumber)
+			}
+
+			queueLogs <- txLog
+
+		case <-stalled:
+			// the subscription looks alive but no logs are delivered anymore
+			sub.Unsubscribe()
+
+			stalled = p.stalledSignal()
+
+			sub = p.resubscribe(ctx, filterQuery, providerLogs)
+			if sub == nil {
+				return
+			}
+
+			if viper.GetBool("chain.backfill.enabled") {
+				go p.backfill(filterQuery, queueLogs)
+			}
+
+		case err := <-sub.Err():
+			// unsubscribed
+			if err == nil {
+				return
+			}
+
+			gbl.Log.Warnf("🔌 log subscription via %s dropped: %s", style.Bold(p.Name), err)
+
+			sub = p.resubscribe(ctx, filterQuery, providerLogs)
+			if sub == nil {
+				return
+			}
+
+			if viper.GetBool("chain.backfill.enabled") {
+				go p.backfill(filterQuery, queueLogs)
+			}
+		}
+	}
+}
+
+// resubscribe retries to subscribe with an increasing delay until it succeeds or returns nil if the context is canceled.
+func (p *Provider) resubscribe(ctx context.Context, filterQuery ethereum.FilterQuery, providerLogs chan types.Log) ethereum.Subscription {
+	delay := time.Second
+
+	for {
+		sub, err := p.Client.SubscribeFilterLogs(ctx, filterQuery, providerLogs)
+		if err == nil {
+			gbl.Log.Infof("🔌 re-subscribed to logs via %s", style.Bold(p.Name))
+
+			return sub
+		}
+
+		gbl.Log.Debugf("🔌 re-subscribing via %s failed, retrying in %s: %s", p.Name, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, time.Minute)
+	}
+}
+
+// backfill fetches the logs of the blocks missed while the subscription was down.
+func (p *Provider) backfill(filterQuery ethereum.FilterQuery, queueLogs chan types.Log) {
+	lastBlock := atomic.LoadUint64(&p.lastBlock)
+	if lastBlock == 0 {
+		return
+	}
+
+	currentBlock, err := p.Client.BlockNumber(context.Background())
+	if err != nil || currentBlock <= lastBlock {
+		return
+	}
+
+	// limit the range to not flood the node & the output after long outages
+	fromBlock := max(lastBlock, currentBlock-min(currentBlock, viper.GetUint64("chain.backfill.max_blocks")))
+
+	gbl.Log.Infof("🔌 backfilling blocks %d-%d via %s", fromBlock, currentBlock, style.Bold(p.Name))
+
+	numLogs := 0
+
+	for chunkStart := fromBlock; chunkStart <= currentBlock; chunkStart += backfillChunkSize {
+		query := filterQuery
+		query.FromBlock = new(big.Int).SetUint64(chunkStart)
+		query.ToBlock = new(big.Int).SetUint64(min(chunkStart+backfillChunkSize-1, currentBlock))
+
+		logs, err := p.Client.FilterLogs(context.Background(), query)
+		if err != nil {
+			gbl.Log.Warnf("🔌 backfilling blocks %d-%d via %s failed: %s", query.FromBlock, query.ToBlock, p.Name, err)
+
+			continue
+		}
+
+		for _, txLog := range logs {
+			queueLogs <- txLog
+		}
+
+		numLogs += len(logs)
+	}
+
+	if currentBlock > atomic.LoadUint64(&p.lastBlock) {
+		atomic.StoreUint64(&p.lastBlock, currentBlock)
+	}
+
+	gbl.Log.Infof("🔌 backfilled %d logs from %d blocks via %s", numLogs, currentBlock-fromBlock+1, style.Bold(p.Name))
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Capabilities are the features of an endpoint beyond the basic eth_* calls.
+type Capabilities struct {
+	// Subscriptions is true if the endpoint supports eth_subscribe (ws/ipc)
+	Subscriptions bool `json:"subscriptions"`
+
+	// TxPool is true if the txpool_* namespace is available
+	TxPool bool `json:"txpool"`
+
+	// Trace is true if the trace_* namespace (erigon/nethermind/reth) is available
+	Trace bool `json:"trace"`
+
+	// Debug is true if the debug_* namespace (geth) is available
+	Debug bool `json:"debug"`
+
+	// Archive is true if the state of all blocks is available
+	Archive bool `json:"archive"`
+
+	// StateDepth is the number of blocks back the state is available for (if not archive)
+	StateDepth uint64 `json:"state_depth"`
+}
+
+// state depths probed if the endpoint is no archive node.
+var probedStateDepths = []uint64{100_000, 10_000, 1024, 128}
+
+// String returns the capabilities in a human readable format.
+func (c *Capabilities) String() string {
+	if c == nil {
+		return "unknown"
+	}
+
+	capabilities := make([]string, 0)
+
+	if c.Subscriptions {
+		capabilities = append(capabilities, "subscriptions")
+	}
+
+	if c.TxPool {
+		capabilities = append(capabilities, "txpool")
+	}
+
+	if c.Trace {
+		capabilities = append(capabilities, "trace")
+	}
+
+	if c.Debug {
+		capabilities = append(capabilities, "debug")
+	}
+
+	switch {
+	case c.Archive:
+		capabilities = append(capabilities, "archive")
+	case c.StateDepth > 0:
+		capabilities = append(capabilities, fmt.Sprintf("state %d blocks", c.StateDepth))
+	default:
+		capabilities = append(capabilities, "latest state only")
+	}
+
+	return strings.Join(capabilities, " · ")
+}
+
+// HasStateAt returns true if the state at the given block is available, nil is the latest block.
+func (c *Capabilities) HasStateAt(blockNumber *big.Int, currentBlock uint64) bool {
+	if c == nil || blockNumber == nil || c.Archive {
+		return true
+	}
+
+	return blockNumber.Uint64()+c.StateDepth >= currentBlock
+}
+
+// probeCapabilities checks which features the endpoint supports.
+func (p *Provider) probeCapabilities() *Capabilities {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	rpcClient := p.Client.Client()
+
+	capabilities := &Capabilities{
+		Subscriptions: rpcClient.SupportsSubscriptions(),
+		TxPool:        supportsMethod(ctx, rpcClient, "txpool_status"),
+		Trace:         supportsMethod(ctx, rpcClient, "trace_transaction", common.Hash{}),
+		Debug:         supportsMethod(ctx, rpcClient, "debug_traceTransaction", common.Hash{}, map[string]interface{}{}),
+	}
+
+	currentBlock, err := p.Client.BlockNumber(ctx)
+	if err != nil {
+		return capabilities
+	}
+
+	// genesis state available → archive node
+	if _, err := p.Client.BalanceAt(ctx, common.Address{}, big.NewInt(1)); err == nil {
+		capabilities.Archive = true
+
+		return capabilities
+	}
+
+	for _, depth := range probedStateDepths {
+		if depth >= currentBlock {
+			continue
+		}
+
+		if _, err := p.Client.BalanceAt(ctx, common.Address{}, new(big.Int).SetUint64(currentBlock-depth)); err == nil {
+			capabilities.StateDepth = depth
+
+			break
+		}
+	}
+
+	gbl.Log.Debugf("🔍 %s capabilities: %s", p.Name, capabilities)
+
+	return capabilities
+}
+
+// supportsMethod calls a method with (dummy) arguments. errors other than "method not found/not available"
+// (e.g. invalid params, tx not found) mean the method exists.
+func supportsMethod(ctx context.Context, rpcClient *rpc.Client, method string, args ...interface{}) bool {
+	var result interface{}
+
+	err := rpcClient.CallContext(ctx, &result, method, args...)
+	if err == nil {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		// timeouts, connection errors, ...
+		return false
+	}
+
+	if rpcErr.ErrorCode() == -32601 {
+		return false
+	}
+
+	message := strings.ToLower(rpcErr.Error())
+	for _, unsupported := range []string{"not supported", "not available", "not allowed", "does not exist", "not found: method", "unsupported method", "not whitelisted"} {
+		if strings.Contains(message, unsupported) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// block of the first .eth registrar controller (permanent registrar launch).
+const ensRegistrarStartBlock = 7_666_000
+
+var (
+	// namehash("eth")
+	ethNode = common.HexToHash("0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae")
+
+	// names(bytes32) of the name wrapper, returns the dns encoded name of a node
+	namesSelector = hexutil.MustDecode("0x20c38e2b")
+
+	ErrENSNameNotFound = errors.New("ens name not found on-chain")
+
+	// names by contract & token id, names of a labelhash/namehash never change
+	ensTokenNames   = make(map[string]string)
+	ensTokenNamesMu sync.RWMutex
+)
+
+// ENSNameByTokenID resolves the name of an ens registrar (token id = labelhash) or name wrapper
+// (token id = namehash) token on-chain, used if the ens metadata service is unavailable.
+func (pp *Pool) ENSNameByTokenID(ctx context.Context, contractAddress common.Address, tokenID *big.Int) (string, error) {
+	if tokenID == nil || (contractAddress != internal.ENSContractAddress && contractAddress != internal.ENSNameWrapperContractAddress) {
+		return "", ErrENSNameNotFound
+	}
+
+	cacheKey := contractAddress.Hex() + ":" + tokenID.String()
+
+	ensTokenNamesMu.RLock()
+	name, ok := ensTokenNames[cacheKey]
+	ensTokenNamesMu.RUnlock()
+
+	if ok {
+		return name, nil
+	}
+
+	tokenHash := common.BigToHash(tokenID)
+
+	node := tokenHash
+	if contractAddress == internal.ENSContractAddress {
+		node = crypto.Keccak256Hash(ethNode.Bytes(), tokenHash.Bytes())
+	}
+
+	// wrapped names are stored in the name wrapper
+	name = pp.wrappedENSName(ctx, node)
+
+	// unwrapped .eth names are only stored as labelhash, the label is in the logs of its registration/renewals
+	if name == "" && contractAddress == internal.ENSContractAddress {
+		name = pp.registeredENSName(ctx, tokenHash)
+	}
+
+	if name == "" {
+		return "", ErrENSNameNotFound
+	}
+
+	ensTokenNamesMu.Lock()
+	ensTokenNames[cacheKey] = name
+	ensTokenNamesMu.Unlock()
+
+	return name, nil
+}
+
+// wrappedENSName returns the name of the node from the name wrapper or an empty string.
+func (pp *Pool) wrappedENSName(ctx context.Context, node common.Hash) string {
+	callData := append(append([]byte{}, namesSelector...), node.Bytes()...)
+
+	nameWrapper := internal.ENSNameWrapperContractAddress
+
+	result, err := pp.CallContract(ctx, ethereum.CallMsg{To: &nameWrapper, Data: callData}, nil)
+	if err != nil {
+		return ""
+	}
+
+	return decodeDNSName(abiBytesArg(result, 0))
+}
+
+// registeredENSName returns the .eth name with the labelhash from the NameRegistered/NameRenewed logs or an empty string.
+func (pp *Pool) registeredENSName(ctx context.Context, labelHash common.Hash) string {
+	logs, err := pp.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(ensRegistrarStartBlock),
+		Topics: [][]common.Hash{
+			{common.HexToHash(string(topic.NameRegistered)), common.HexToHash(string(topic.NameRegisteredWithPremium)), common.HexToHash(string(topic.NameRenewed))},
+			{labelHash},
+		},
+	})
+	if err != nil {
+		gbl.Log.Debugf("🪪 getting the registration logs of %s failed: %s", labelHash.Hex(), err)
+
+		return ""
+	}
+
+	for _, txLog := range logs {
+		// the name is the first non-indexed arg
+		label := string(abiBytesArg(txLog.Data, 0))
+
+		// logs can be emitted by any contract, only trust labels matching the hash
+		if crypto.Keccak256Hash([]byte(label)) == labelHash {
+			return label + ".eth"
+		}
+	}
+
+	return ""
+}
+
+// abiBytesArg returns the dynamic bytes/string arg at the given head position of abi encoded data or nil.
+func abiBytesArg(data []byte, position int) []byte {
+	if len(data) < (position+1)*32 {
+		return nil
+	}
+
+	offset := new(big.Int).SetBytes(data[position*32 : (position+1)*32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return nil
+	}
+
+	start := offset.Uint64() + 32
+	length := new(big.Int).SetBytes(data[offset.Uint64():start])
+
+	if !length.IsUint64() || start+length.Uint64() > uint64(len(data)) {
+		return nil
+	}
+
+	return data[start : start+length.Uint64()]
+}
+
+// decodeDNSName decodes a dns wire format name (length-prefixed labels, zero terminated).
+func decodeDNSName(encoded []byte) string {
+	labels := make([]string, 0)
+
+	for idx := 0; idx < len(encoded) && encoded[idx] != 0; {
+		length := int(encoded[idx])
+		if idx+1+length > len(encoded) {
+			return ""
+		}
+
+		labels = append(labels, string(encoded[idx+1:idx+1+length]))
+		idx += 1 + length
+	}
+
+	return strings.Join(labels, ".")
+}
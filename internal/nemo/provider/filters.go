@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/eip6551"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// transfersFilter matches the nft transfers & further events with 4 topics.
+func transfersFilter() ethereum.FilterQuery {
+	topics := [][]common.Hash{
+		{
+			common.HexToHash(string(topic.Transfer)), common.HexToHash(string(topic.TransferSingle)), common.HexToHash(string(topic.TransferBatch)),
+			common.HexToHash(string(topic.BuyPriceSet)), common.HexToHash(string(topic.ERC6551AccountCreated)),
+		},
+		{},
+		{},
+		{},
+	}
+
+	return ethereum.FilterQuery{Topics: topics}
+}
+
+// subscriptionFilters returns the filters to subscribe to, the transfers & the events of the enabled watchers.
+// nodes only deliver logs with at least as many topics as the filter has positions, so events with less
+// indexed args than a transfer get their own filter. the watcher events are only subscribed to if withWatchers
+// is set (mainnet), the events of watched collections only if there are any.
+func subscriptionFilters(watchedCollections []common.Address, withWatchers bool) []ethereum.FilterQuery {
+	filters := []ethereum.FilterQuery{
+		transfersFilter(),
+		// token bound accounts created via the v0.2 registry (AccountCreated has no indexed args)
+		{
+			Addresses: []common.Address{eip6551.TokenboundERC6551Registry},
+			Topics:    [][]common.Hash{{common.HexToHash(string(topic.AccountCreated))}},
+		},
+	}
+
+	if !withWatchers {
+		return filters
+	}
+
+	// proxy implementation/admin changes of watched collections
+	if viper.GetBool("proxywatch.enabled") && len(watchedCollections) > 0 {
+		filters = append(filters, ethereum.FilterQuery{
+			Addresses: watchedCollections,
+			Topics: [][]common.Hash{{
+				common.HexToHash(string(topic.Upgraded)), common.HexToHash(string(topic.AdminChanged)), common.HexToHash(string(topic.BeaconUpgraded)),
+			}},
+		})
+	}
+
+	// paused/unpaused transfers of watched collections (Paused(address account) has no indexed args)
+	if viper.GetBool("freezewatch.enabled") && len(watchedCollections) > 0 {
+		filters = append(filters, ethereum.FilterQuery{
+			Addresses: watchedCollections,
+			Topics:    [][]common.Hash{{common.HexToHash(string(topic.Paused)), common.HexToHash(string(topic.Unpaused))}},
+		})
+	}
+
+	// blend auctions & refinances (ending auctions) of loans, no tokens are moved & the events have no indexed args
+	if viper.GetBool("blendwatch.enabled") {
+		filters = append(filters, ethereum.FilterQuery{
+			Addresses: []common.Address{internal.BlurBlendContractAddress},
+			Topics:    [][]common.Hash{{common.HexToHash(string(topic.StartAuction)), common.HexToHash(string(topic.Refinance))}},
+		})
+	}
+
+	// primary ens name changes (NameChanged(bytes32 indexed node, string name)), filtered to the
+	// reverse nodes of the own & watched wallets when received
+	if viper.GetBool("enswatch.enabled") {
+		filters = append(filters, ethereum.FilterQuery{
+			Topics: [][]common.Hash{{common.HexToHash(string(topic.NameChanged))}, {}},
+		})
+	}
+
+	return filters
+}
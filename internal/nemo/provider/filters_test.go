@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// filterFor returns the filter with topic as topic0.
+func filterFor(filters []ethereum.FilterQuery, eventTopic topic.Topic) *ethereum.FilterQuery {
+	for idx := range filters {
+		if len(filters[idx].Topics) == 0 {
+			continue
+		}
+
+		for _, topic0 := range filters[idx].Topics[0] {
+			if topic0 == common.HexToHash(string(eventTopic)) {
+				return &filters[idx]
+			}
+		}
+	}
+
+	return nil
+}
+
+func Test_subscriptionFilters(t *testing.T) {
+	viper.Set("proxywatch.enabled", true)
+	defer viper.Set("proxywatch.enabled", nil)
+
+	viper.Set("enswatch.enabled", true)
+	defer viper.Set("enswatch.enabled", nil)
+
+	viper.Set("blendwatch.enabled", true)
+	defer viper.Set("blendwatch.enabled", nil)
+
+	viper.Set("freezewatch.enabled", true)
+	defer viper.Set("freezewatch.enabled", nil)
+
+	watched := []common.Address{common.HexToAddress("0xa23a9e6002cebb284e1797be6e0cad201d13c2f2")}
+
+	tests := []struct {
+		name         string
+		watched      []common.Address
+		withWatchers bool
+		topic        topic.Topic
+		// number of topic positions of the filter, 0 = not subscribed
+		positions int
+		// filter scoped to the watched collections
+		scoped bool
+	}{
+		{name: "Transfer", watched: watched, withWatchers: true, topic: topic.Transfer, positions: 4},
+		{name: "TransferSingle", watched: watched, withWatchers: true, topic: topic.TransferSingle, positions: 4},
+		{name: "TransferBatch", watched: watched, withWatchers: true, topic: topic.TransferBatch, positions: 4},
+		{name: "Upgraded", watched: watched, withWatchers: true, topic: topic.Upgraded, positions: 1, scoped: true},
+		{name: "AdminChanged", watched: watched, withWatchers: true, topic: topic.AdminChanged, positions: 1, scoped: true},
+		{name: "BeaconUpgraded", watched: watched, withWatchers: true, topic: topic.BeaconUpgraded, positions: 1, scoped: true},
+		{name: "Upgraded without watched collections", watched: nil, withWatchers: true, topic: topic.Upgraded, positions: 0},
+		{name: "Upgraded without watchers (l2)", watched: watched, withWatchers: false, topic: topic.Upgraded, positions: 0},
+		{name: "NameChanged", watched: watched, withWatchers: true, topic: topic.NameChanged, positions: 2},
+		{name: "NameChanged without watched collections", watched: nil, withWatchers: true, topic: topic.NameChanged, positions: 2},
+		{name: "NameChanged without watchers (l2)", watched: watched, withWatchers: false, topic: topic.NameChanged, positions: 0},
+		{name: "StartAuction", watched: watched, withWatchers: true, topic: topic.StartAuction, positions: 1, scoped: true},
+		{name: "Refinance", watched: watched, withWatchers: true, topic: topic.Refinance, positions: 1, scoped: true},
+		{name: "StartAuction without watchers (l2)", watched: watched, withWatchers: false, topic: topic.StartAuction, positions: 0},
+		{name: "Paused", watched: watched, withWatchers: true, topic: topic.Paused, positions: 1, scoped: true},
+		{name: "Unpaused", watched: watched, withWatchers: true, topic: topic.Unpaused, positions: 1, scoped: true},
+		{name: "Paused without watched collections", watched: nil, withWatchers: true, topic: topic.Paused, positions: 0},
+		{name: "Transfer without watchers (l2)", watched: watched, withWatchers: false, topic: topic.Transfer, positions: 4},
+		{name: "AccountCreated (v0.2)", watched: watched, withWatchers: true, topic: topic.AccountCreated, positions: 1, scoped: true},
+		{name: "AccountCreated (v0.2) without watchers (l2)", watched: watched, withWatchers: false, topic: topic.AccountCreated, positions: 1, scoped: true},
+		{name: "ERC6551AccountCreated (v0.3)", watched: watched, withWatchers: true, topic: topic.ERC6551AccountCreated, positions: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := filterFor(subscriptionFilters(tt.watched, tt.withWatchers), tt.topic)
+
+			if tt.positions == 0 {
+				if filter != nil {
+					t.Errorf("subscriptionFilters() subscribed to %s: %+v", tt.name, filter)
+				}
+
+				return
+			}
+
+			if filter == nil {
+				t.Fatalf("subscriptionFilters() not subscribed to %s", tt.name)
+			}
+
+			if len(filter.Topics) != tt.positions {
+				t.Errorf("subscriptionFilters() %s filter has %d topic positions, want %d", tt.name, len(filter.Topics), tt.positions)
+			}
+
+			if scoped := len(filter.Addresses) > 0; scoped != tt.scoped {
+				t.Errorf("subscriptionFilters() %s filter addresses = %v, want scoped %v", tt.name, filter.Addresses, tt.scoped)
+			}
+		})
+	}
+}
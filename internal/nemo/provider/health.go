@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/viper"
+)
+
+const (
+	// weight of the latest call in the moving averages
+	healthAlpha = 0.2
+	// latency penalty per block a provider is behind the others
+	blockBehindPenalty = 250 * time.Millisecond
+)
+
+// health is the rolling health of a provider, used to order the providers for rpc calls.
+type health struct {
+	// moving averages of the latency & the error rate of the calls
+	latency   time.Duration
+	errorRate float64
+	calls     uint64
+
+	// latest block number of the provider & the number of blocks it is behind the other providers
+	head         uint64
+	blocksBehind uint64
+
+	// consecutive failures & the end of the quarantine, the backoff doubles with each failure while quarantined
+	failures         int
+	quarantinedUntil time.Time
+
+	mu sync.Mutex
+}
+
+// isRPC returns true for plain json-rpc calls. errors of the contract & ens helpers are mostly caused by the
+// contracts & names and are not counted against the health of the node.
+func (m methodCall) isRPC() bool {
+	switch m {
+	case BlockNumber, TransactionByHash, TransactionReceipt, GasInfo, CodeAt, NonceAt:
+		return true
+	}
+
+	return false
+}
+
+// isNodeFailure returns true if the error is caused by the node (unreachable, timeouts, rate limits, server errors)
+// and not by the request, like unknown txs or reverted calls.
+func isNodeFailure(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) || errors.Is(err, bind.ErrNoCode) {
+		return false
+	}
+
+	// the node answered, only rate limits & internal errors count
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == -32005 || rpcErr.ErrorCode() == -32603
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return !strings.Contains(err.Error(), "execution reverted")
+}
+
+// recordCall updates the health of the provider with the result of a call started at startedAt.
+// calls canceled by the caller are not counted.
+func (p *Provider) recordCall(ctx context.Context, startedAt time.Time, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	failed := isNodeFailure(err)
+	latency := time.Since(startedAt)
+
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	errorValue := 0.0
+	if failed {
+		errorValue = 1.0
+	}
+
+	if p.health.calls == 0 {
+		p.health.latency = latency
+		p.health.errorRate = errorValue
+	} else {
+		p.health.latency = time.Duration(healthAlpha*float64(latency) + (1-healthAlpha)*float64(p.health.latency))
+		p.health.errorRate = healthAlpha*errorValue + (1-healthAlpha)*p.health.errorRate
+	}
+
+	p.health.calls++
+
+	quarantineAfter := viper.GetInt("chain.health.quarantine_after")
+
+	if !failed {
+		if quarantineAfter > 0 && p.health.failures >= quarantineAfter {
+			gbl.Log.Infof("💚 %s recovered after %d failed calls", style.Bold(p.Name), p.health.failures)
+		}
+
+		p.health.failures = 0
+		p.health.quarantinedUntil = time.Time{}
+
+		return
+	}
+
+	p.health.failures++
+
+	if quarantineAfter <= 0 || p.health.failures < quarantineAfter {
+		return
+	}
+
+	// 1x, 2x, 4x, ... the backoff, capped at the max backoff
+	backoff := viper.GetDuration("chain.health.backoff") << min(p.health.failures-quarantineAfter, 16)
+	if maxBackoff := viper.GetDuration("chain.health.max_backoff"); backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	p.health.quarantinedUntil = time.Now().Add(backoff)
+
+	gbl.Log.Warnf("🚧 %s quarantined for %s after %d failed calls: %s", style.Bold(p.Name), backoff, p.health.failures, err)
+}
+
+// setHead updates the latest block number of the provider.
+func (p *Provider) setHead(head uint64) {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	p.health.head = max(p.health.head, head)
+}
+
+// quarantined returns true if the provider failed too often & its backoff is not over yet.
+func (p *Provider) quarantined(now time.Time) bool {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	return now.Before(p.health.quarantinedUntil)
+}
+
+// score returns the expected latency of a call, penalized by the error rate & the blocks behind (lower is better).
+// providers without calls yet score 0 to be tried early.
+func (p *Provider) score() time.Duration {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	return time.Duration(float64(p.health.latency)*(1+10*p.health.errorRate)) + time.Duration(p.health.blocksBehind)*blockBehindPenalty
+}
+
+// Health returns the health of the provider in a human readable format.
+func (p *Provider) Health() string {
+	p.health.mu.Lock()
+	defer p.health.mu.Unlock()
+
+	status := fmt.Sprintf("%s latency · %.0f%% errors · %d blocks behind", p.health.latency.Round(time.Millisecond), p.health.errorRate*100, p.health.blocksBehind)
+
+	if time.Now().Before(p.health.quarantinedUntil) {
+		status += fmt.Sprintf(" · quarantined for %s", time.Until(p.health.quarantinedUntil).Round(100*time.Millisecond))
+	}
+
+	return status
+}
+
+// updateBlocksBehind compares the heads of the providers with the most recent one.
+func (pp *Pool) updateBlocksBehind() {
+	var latestHead uint64
+
+	for _, provider := range pp.GetProviders() {
+		provider.health.mu.Lock()
+		latestHead = max(latestHead, provider.health.head)
+		provider.health.mu.Unlock()
+	}
+
+	for _, provider := range pp.GetProviders() {
+		provider.health.mu.Lock()
+		if provider.health.head > 0 {
+			provider.health.blocksBehind = latestHead - min(latestHead, provider.health.head)
+		}
+		provider.health.mu.Unlock()
+	}
+}
+
+// probeHealth fetches the block number of all providers every chain.health.interval to keep the latencies &
+// blocks behind up to date, including the providers not used for calls because of their score.
+// quarantined providers are probed after their backoff only.
+func (pp *Pool) probeHealth(ctx context.Context) {
+	interval := viper.GetDuration("chain.health.interval")
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var wg sync.WaitGroup
+
+		for _, provider := range pp.GetProviders() {
+			if provider.quarantined(time.Now()) {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(provider *Provider) {
+				defer wg.Done()
+
+				probeCtx, cancel := context.WithTimeout(ctx, min(interval, 10*time.Second))
+				defer cancel()
+
+				startedAt := time.Now()
+
+				head, err := provider.Client.BlockNumber(probeCtx)
+				if errors.Is(err, context.DeadlineExceeded) {
+					// timed out probes count as failure, canceled ones (shutdown) don't
+					provider.recordCall(ctx, startedAt, err)
+
+					return
+				}
+
+				provider.recordCall(probeCtx, startedAt, err)
+
+				if err == nil {
+					provider.setHead(head)
+				}
+			}(provider)
+		}
+
+		wg.Wait()
+
+		pp.updateBlocksBehind()
+
+		for _, provider := range pp.GetProviders() {
+			gbl.Log.Debugf("🩺 %s: %s", provider.Name, provider.Health())
+		}
+	}
+}
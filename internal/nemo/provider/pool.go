@@ -1,12 +1,15 @@
 package provider
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"math/rand"
+	"slices"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -34,7 +37,18 @@ type Pool struct {
 
 	providers []*Provider
 
+	// number of providers in the config, incl. the ones that could not be connected
+	numConfigured int
+
 	queueLogs chan types.Log
+	// context of the log subscriptions, canceling it unsubscribes from the nodes
+	subscriptionCtx context.Context
+
+	// own collections (wallets & config). set for the mainnet pool to also subscribe to the events of
+	// the watchers not moving tokens (proxy upgrades, pauses, ...), returns the current collections on each (re-)subscribe
+	WatchedCollections func() []common.Address
+	// stall watcher & health probes, started with the first subscription
+	watchersOnce sync.Once
 
 	Rueidi *rueidica.Rueidica
 
@@ -49,6 +63,11 @@ func (pp *Pool) GetProviders() []*Provider {
 	return pp.providers
 }
 
+// NumConfigured returns the number of configured providers, connected or not.
+func (pp *Pool) NumConfigured() int {
+	return pp.numConfigured
+}
+
 type methodCall string
 
 const (
@@ -64,6 +83,8 @@ const (
 	ERC1155TokenName   methodCall = "erc1155_token_name" //nolint:gosec
 	ERC1155TotalSupply methodCall = "erc1155_total_supply"
 
+	ERC2981RoyaltyInfo methodCall = "erc2981_royalty_info"
+
 	ReverseResolveENS methodCall = "resolve_ens_address"
 	ResolveENS        methodCall = "resolve_ens"
 
@@ -121,23 +142,60 @@ func FromConfig(config interface{}) (*Pool, error) {
 		return nil, err
 	}
 
+	providerPool.numConfigured = len(rawPool)
+
 	//
-	// initialize the providers and connect to the endpoints
-	for _, provider := range rawPool {
-		// hash the endpoint to get a unique id for the provider
-		provider.PID = common.BytesToHash([]byte(provider.Endpoint))
+	// initialize the providers and connect to the endpoints concurrently, the configured order is kept
+	connected := make([]bool, len(rawPool))
 
-		// connect to the endpoint
-		if err := provider.connect(); err != nil {
-			gbl.Log.Warnf("❔ not adding %s: %s", style.BoldStyle.Render(provider.Name), err)
+	var wg sync.WaitGroup
 
-			continue
+	for idx, provider := range rawPool {
+		wg.Add(1)
+
+		go func(idx int, provider *Provider) {
+			defer wg.Done()
+
+			// hash the endpoint to get a unique id for the provider
+			provider.PID = common.BytesToHash([]byte(provider.Endpoint))
+
+			// connect to the endpoint
+			if err := provider.connect(); err != nil {
+				gbl.Log.Warnf("❔ not adding %s: %s", style.BoldStyle.Render(provider.Name), err)
+
+				return
+			}
+
+			gbl.Log.Infof("✅ added node %s", style.BoldStyle.Render(provider.Name))
+
+			connected[idx] = true
+		}(idx, provider)
+	}
+
+	wg.Wait()
+
+	for idx, provider := range rawPool {
+		if connected[idx] {
+			providerPool.providers = append(providerPool.providers, provider)
 		}
+	}
+
+	// probe the endpoints for subscriptions, txpool, trace/debug apis & archive depth
+
+	for _, provider := range providerPool.providers {
+		wg.Add(1)
+
+		go func(provider *Provider) {
+			defer wg.Done()
 
-		gbl.Log.Infof("✅ added node %s", style.BoldStyle.Render(provider.Name))
-		providerPool.providers = append(providerPool.providers, provider)
+			provider.Capabilities = provider.probeCapabilities()
+
+			gbl.Log.Infof("🔍 %s: %s", style.BoldStyle.Render(provider.Name), provider.Capabilities)
+		}(provider)
 	}
 
+	wg.Wait()
+
 	// handle reconnects
 	go func() {
 		// reconnect if no logs received for a while
@@ -197,8 +255,10 @@ func (pp *Pool) ReconnectProviders() {
 		return
 	}
 
-	// store the current queueLogs channel
+	// store the current queueLogs channel, subscription context & watched collections
 	queueLogs := pp.queueLogs
+	subscriptionCtx := pp.subscriptionCtx
+	watchedCollections := pp.WatchedCollections
 
 	gbl.Log.Infof("🔌 trying to re-connect to %s at %s", providerConfig)
 
@@ -209,11 +269,12 @@ func (pp *Pool) ReconnectProviders() {
 		pp = pool
 	}
 
-	// restore the queueLogs channel
+	// restore the queueLogs channel & watched collections
 	pp.queueLogs = queueLogs
+	pp.WatchedCollections = watchedCollections
 
 	// re-subscribe
-	if _, err := pp.Subscribe(pp.queueLogs); err != nil {
+	if _, err := pp.Subscribe(subscriptionCtx, pp.queueLogs); err != nil {
 		gbl.Log.Fatalf("❌ subscribing to logs failed: %s", err)
 
 		return
@@ -231,7 +292,12 @@ func (pp *Pool) GetLogsByBlockNumber(blockNumber int64) []types.Log {
 	}
 
 	for _, provider := range pp.getProviders() {
-		if logs, err := provider.Client.FilterLogs(context.Background(), filterQuery); err == nil {
+		startedAt := time.Now()
+
+		logs, err := provider.Client.FilterLogs(context.Background(), filterQuery)
+		provider.recordCall(context.Background(), startedAt, err)
+
+		if err == nil {
 			return logs
 		}
 	}
@@ -239,6 +305,97 @@ func (pp *Pool) GetLogsByBlockNumber(blockNumber int64) []types.Log {
 	return nil
 }
 
+// FilterLogs returns the logs matching the given filter query from the first provider answering successfully.
+func (pp *Pool) FilterLogs(ctx context.Context, filterQuery ethereum.FilterQuery) ([]types.Log, error) {
+	err := errors.New("no provider available")
+
+	for _, provider := range pp.getProviders() {
+		var logs []types.Log
+
+		startedAt := time.Now()
+
+		logs, err = provider.Client.FilterLogs(ctx, filterQuery)
+		provider.recordCall(ctx, startedAt, err)
+
+		if err == nil {
+			return logs, nil
+		}
+
+		gbl.Log.Debugf("filter logs failed on %s: %s", provider.Name, err)
+	}
+
+	return nil, err
+}
+
+// HeaderByNumber returns the block header for the given block number.
+func (pp *Pool) HeaderByNumber(ctx context.Context, blockNumber *big.Int) (*types.Header, error) {
+	err := errors.New("no provider available")
+
+	for _, provider := range pp.getProviders() {
+		var header *types.Header
+
+		startedAt := time.Now()
+
+		header, err = provider.Client.HeaderByNumber(ctx, blockNumber)
+		provider.recordCall(ctx, startedAt, err)
+
+		if err == nil {
+			return header, nil
+		}
+	}
+
+	return nil, err
+}
+
+// BlockByNumber returns the full block (including its txs) for the given block number.
+func (pp *Pool) BlockByNumber(ctx context.Context, blockNumber *big.Int) (*types.Block, error) {
+	err := errors.New("no provider available")
+
+	for _, provider := range pp.getProviders() {
+		var block *types.Block
+
+		startedAt := time.Now()
+
+		block, err = provider.Client.BlockByNumber(ctx, blockNumber)
+		provider.recordCall(ctx, startedAt, err)
+
+		if err == nil {
+			return block, nil
+		}
+	}
+
+	return nil, err
+}
+
+// CallContract executes a message call (eth_call) on the first provider answering successfully.
+func (pp *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	err := errors.New("no provider available")
+
+	providers := pp.getProviders()
+
+	// historical state is only available on archive nodes or nodes with enough state depth
+	if blockNumber != nil {
+		if currentBlock, err := pp.BlockNumber(ctx); err == nil {
+			providers = pp.providersWith(providers, func(c *Capabilities) bool { return c.HasStateAt(blockNumber, currentBlock) })
+		}
+	}
+
+	for _, provider := range providers {
+		var result []byte
+
+		startedAt := time.Now()
+
+		result, err = provider.Client.CallContract(ctx, msg, blockNumber)
+		provider.recordCall(ctx, startedAt, err)
+
+		if err == nil {
+			return result, nil
+		}
+	}
+
+	return nil, err
+}
+
 // IsContract returns true if the given address is a contract address.
 // to resource intensive to check this for every address we encounter, so we cache the result.
 func (pp *Pool) IsContract(address common.Address) bool {
@@ -269,26 +426,33 @@ func (pp *Pool) IsContract(address common.Address) bool {
 	return len(codeAt) > 0
 }
 
-func (pp *Pool) Subscribe(queueLogs chan types.Log) (uint64, error) {
+// Subscribe subscribes to the transfers (& further watched events) via all subscription capable providers.
+// canceling the context unsubscribes from the nodes.
+func (pp *Pool) Subscribe(ctx context.Context, queueLogs chan types.Log) (uint64, error) {
 	if queueLogs == nil {
 		return 0, errors.New("queueLogs channel is nil")
 	}
 
-	// store channel for later use/reconnects
+	// store channel & context for later use/reconnects
 	pp.queueLogs = queueLogs
+	pp.subscriptionCtx = ctx
 
 	// subscribe
-	availableProvider := pp.getProviders()
-	if len(pp.getPreferredProviders()) > 0 {
-		availableProvider = pp.getPreferredProviders()
-	}
+	availableProvider := pp.subscriptionProviders()
 
 	subscribedTo := uint64(0)
 
+	// transfers & the events of the watchers
+	var watchedCollections []common.Address
+	if pp.WatchedCollections != nil {
+		watchedCollections = pp.WatchedCollections()
+	}
+
+	filters := subscriptionFilters(watchedCollections, pp.WatchedCollections != nil)
+
 	for _, provider := range availableProvider {
-		// subscribe to all logs with "Tranfer" or "TransferSingle" as first topic
-		if _, err := provider.subscribeToAllTransfers(pp.queueLogs); err != nil {
-			gbl.Log.Warnf("subscribe to topic TransferSingle via node %s failed: %s", provider.Name, err)
+		if err := provider.subscribeToFilters(ctx, pp.queueLogs, filters); err != nil {
+			gbl.Log.Warnf("subscribe to transfers via node %s failed: %s", provider.Name, err)
 		} else {
 			subscribedTo++
 			gbl.Log.Infof("✍️ subscribed to all transfer topics via node %s", style.Bold(provider.Name))
@@ -299,6 +463,12 @@ func (pp *Pool) Subscribe(queueLogs chan types.Log) (uint64, error) {
 		return 0, errors.New("no provider available")
 	}
 
+	// re-subscribe via nodes whose subscription went silent
+	pp.watchersOnce.Do(func() {
+		go pp.watchStalledSubscriptions(ctx)
+		go pp.probeHealth(ctx)
+	})
+
 	return subscribedTo, nil
 }
 
@@ -311,16 +481,13 @@ func (pp *Pool) SubscribeToEverything(queueLogs chan types.Log) (uint64, error)
 	pp.queueLogs = queueLogs
 
 	// subscribe
-	availableProvider := pp.getProviders()
-	if len(pp.getPreferredProviders()) > 0 {
-		availableProvider = pp.getPreferredProviders()
-	}
+	availableProvider := pp.subscriptionProviders()
 
 	subscribedTo := uint64(0)
 
 	for _, provider := range availableProvider {
 		// subscribe to all logs with "Tranfer" or "TransferSingle" as first topic
-		if _, err := provider.subscribeTo(pp.queueLogs, [][]common.Hash{}, []common.Address{}); err != nil {
+		if _, err := provider.subscribeTo(context.Background(), pp.queueLogs, [][]common.Hash{}, []common.Address{}); err != nil {
 			gbl.Log.Warnf("subscribe to everything via node %s failed: %s", provider.Name, err)
 		} else {
 			subscribedTo++
@@ -344,16 +511,13 @@ func (pp *Pool) SubscribeToAddresses(queueLogs chan types.Log, addresses []commo
 	pp.queueLogs = queueLogs
 
 	// subscribe
-	availableProvider := pp.getProviders()
-	if len(pp.getPreferredProviders()) > 0 {
-		availableProvider = pp.getPreferredProviders()
-	}
+	availableProvider := pp.subscriptionProviders()
 
 	subscribedTo := uint64(0)
 
 	for _, provider := range availableProvider {
 		// subscribe to all logs with "Tranfer" or "TransferSingle" as first topic
-		if _, err := provider.subscribeTo(pp.queueLogs, [][]common.Hash{}, addresses); err != nil {
+		if _, err := provider.subscribeTo(context.Background(), pp.queueLogs, [][]common.Hash{}, addresses); err != nil {
 			gbl.Log.Warnf("subscribe to addresses failed: %v | %v", addresses, err)
 		} else {
 			subscribedTo++
@@ -377,16 +541,13 @@ func (pp *Pool) SubscribeToTopics(queueLogs chan types.Log, topics [][]common.Ha
 	pp.queueLogs = queueLogs
 
 	// subscribe
-	availableProvider := pp.getProviders()
-	if len(pp.getPreferredProviders()) > 0 {
-		availableProvider = pp.getPreferredProviders()
-	}
+	availableProvider := pp.subscriptionProviders()
 
 	subscribedTo := uint64(0)
 
 	for _, provider := range availableProvider {
 		// subscribe to all logs with "Tranfer" or "TransferSingle" as first topic
-		if _, err := provider.subscribeTo(pp.queueLogs, topics, nil); err != nil {
+		if _, err := provider.subscribeTo(context.Background(), pp.queueLogs, topics, nil); err != nil {
 			gbl.Log.Warnf("subscribe to topic TransferSingle via node %s failed: %s", provider.Name, err)
 		} else {
 			subscribedTo++
@@ -403,15 +564,16 @@ func (pp *Pool) SubscribeToTopics(queueLogs chan types.Log, topics [][]common.Ha
 
 func (pp *Pool) SubscribeToEverythingPending(queuePendingTx chan *types.Transaction) (uint64, error) {
 	// subscribe
-	availableProvider := pp.getProviders()
-	if len(pp.getPreferredProviders()) > 0 {
-		availableProvider = pp.getPreferredProviders()
-	}
+	availableProvider := pp.subscriptionProviders()
 
 	subscribedTo := uint64(0)
 
 	for _, provider := range availableProvider {
-		// subscribe to all logs with "Tranfer" or "TransferSingle" as first topic
+		// full pending txs are only available via the geth client of preferred providers
+		if provider.GethClient == nil {
+			continue
+		}
+
 		if _, err := provider.GethClient.SubscribeFullPendingTransactions(context.TODO(), queuePendingTx); err != nil {
 			gbl.Log.Warnf("subscribe to pending transactions via node %s failed: %s", provider.Name, err)
 		} else {
@@ -427,6 +589,30 @@ func (pp *Pool) SubscribeToEverythingPending(queuePendingTx chan *types.Transact
 	return subscribedTo, nil
 }
 
+// subscriptionProviders returns the (preferred if available) providers supporting subscriptions.
+func (pp *Pool) subscriptionProviders() []*Provider {
+	availableProvider := pp.getProviders()
+	if len(pp.getPreferredProviders()) > 0 {
+		availableProvider = pp.getPreferredProviders()
+	}
+
+	return pp.providersWith(availableProvider, func(c *Capabilities) bool { return c.Subscriptions })
+}
+
+// providersWith returns the providers with the required capability, providers
+// not probed (yet) are assumed to be capable.
+func (pp *Pool) providersWith(providers []*Provider, capable func(c *Capabilities) bool) []*Provider {
+	capableProviders := make([]*Provider, 0, len(providers))
+
+	for _, provider := range providers {
+		if provider.Capabilities == nil || capable(provider.Capabilities) {
+			capableProviders = append(capableProviders, provider)
+		}
+	}
+
+	return capableProviders
+}
+
 func (pp *Pool) getPreferredProviders() []*Provider {
 	if pp.providers != nil && len(pp.providers) == 0 {
 		return nil
@@ -443,6 +629,8 @@ func (pp *Pool) getPreferredProviders() []*Provider {
 	return providers
 }
 
+// getProviders returns the providers to try for a call, preferred (formerly 'local') providers first & the
+// others ordered by their health score. quarantined providers are skipped unless all providers are quarantined.
 func (pp *Pool) getProviders() []*Provider {
 	providers := make([]*Provider, 0)
 
@@ -451,20 +639,41 @@ func (pp *Pool) getProviders() []*Provider {
 		return providers
 	}
 
-	providers = append(providers, pp.providers...)
+	now := time.Now()
+
+	for _, provider := range pp.providers {
+		if !provider.quarantined(now) {
+			providers = append(providers, provider)
+		}
+	}
 
-	// shuffle provider to avoid hitting the same node over and over again
+	if len(providers) == 0 {
+		providers = append(providers, pp.providers...)
+	}
+
+	// shuffle provider to avoid hitting the same node over and over again if their scores are equal
 	rand.Shuffle(len(providers), func(i, j int) {
 		providers[i], providers[j] = providers[j], providers[i]
 	})
 
-	// prefer preferred (formerly 'local') providers if available
-	preferredProviders := make([]*Provider, 0)
-	if prefProviders := pp.getPreferredProviders(); len(prefProviders) > 0 {
-		preferredProviders = append(preferredProviders, prefProviders...)
+	scores := make(map[*Provider]time.Duration, len(providers))
+	for _, provider := range providers {
+		scores[provider] = provider.score()
 	}
 
-	return append(preferredProviders, providers...)
+	slices.SortStableFunc(providers, func(a, b *Provider) int {
+		if a.Preferred != b.Preferred {
+			if a.Preferred {
+				return -1
+			}
+
+			return 1
+		}
+
+		return cmp.Compare(scores[a], scores[b])
+	})
+
+	return providers
 }
 
 func (pp *Pool) GetWETHABI(contractAddress common.Address) (*abis.WETH, error) {
@@ -498,134 +707,149 @@ func (pp *Pool) GetERC1155ABI(contractAddress common.Address) (*abis.ERC1155, er
 }
 
 func (pp *Pool) callMethod(ctx context.Context, method methodCall, params methodCallParams) (interface{}, error) {
-	var err error
-
 	atomic.AddUint64(&callMethodCounter, 1)
 
 	if callMethodCounter%100 == 0 {
 		gbl.Log.Debugf("callMethodCounter: %d", callMethodCounter)
 	}
 
+	if err := params.validate(method); err != nil {
+		return nil, err
+	}
+
+	if method == ReverseResolveENS && pp.Rueidi != nil {
+		if ensAddress, err := pp.Rueidi.GetCachedENSName(ctx, params.Address); err == nil {
+			return ensAddress, nil
+		}
+	}
+
+	err := errors.New("no provider available")
+
 	for _, provider := range pp.getProviders() {
-		switch method {
-		case TransactionByHash:
-			if params.TxHash == (common.Hash{}) {
-				return nil, errors.New("invalid transaction hash")
-			}
+		var result interface{}
 
-			if tx, _, err := provider.Client.TransactionByHash(ctx, params.TxHash); err == nil {
-				return tx, nil
-			}
+		startedAt := time.Now()
 
-		case BlockNumber:
-			if blockNumber, err := provider.Client.BlockNumber(ctx); err == nil {
-				return blockNumber, nil
-			}
+		result, err = provider.call(ctx, method, params)
 
-		case TransactionReceipt:
-			if params.TxHash == (common.Hash{}) {
-				return nil, errors.New("invalid transaction hash")
-			}
+		if err == nil || method.isRPC() {
+			provider.recordCall(ctx, startedAt, err)
+		}
 
-			if receipt, err := provider.Client.TransactionReceipt(ctx, params.TxHash); err == nil {
-				return receipt, nil
+		if err == nil {
+			if method == BlockNumber {
+				if blockNumber, ok := result.(uint64); ok {
+					provider.setHead(blockNumber)
+				}
 			}
 
-		case TokenImageURI:
-			if params.Address == (common.Address{}) || params.TokenID == nil {
-				return nil, errors.New("invalid contract address or token id")
-			}
+			return result, nil
+		}
+	}
 
-			if uri, err := provider.getTokenImageURI(ctx, params.Address, params.TokenID); err == nil {
-				return uri, nil
-			}
+	return nil, err
+}
 
-		case ERC721CollectionName:
-			if params.Address == (common.Address{}) {
-				return nil, errors.New("invalid contract address")
-			}
+// validate checks the params required by the method.
+func (params methodCallParams) validate(method methodCall) error {
+	switch method {
+	case TransactionByHash, TransactionReceipt:
+		if params.TxHash == (common.Hash{}) {
+			return errors.New("invalid transaction hash")
+		}
 
-			if collectionName, err := provider.getERC721CollectionName(params.Address); err == nil {
-				return collectionName, nil
-			}
+	case TokenImageURI, ERC1155TokenName, ERC1155TotalSupply, ERC2981RoyaltyInfo:
+		if params.Address == (common.Address{}) || params.TokenID == nil {
+			return errors.New("invalid contract address or token id")
+		}
 
-		case ERC721CollectionMetadata:
-			if params.Address == (common.Address{}) {
-				return nil, errors.New("invalid contract address")
-			}
+	case ERC721CollectionName, ERC721CollectionMetadata, ReverseResolveENS, NonceAt:
+		if params.Address == (common.Address{}) {
+			return errors.New("invalid contract address")
+		}
 
-			if metadata, err := provider.getERC721CollectionMetadata(params.Address); err == nil {
-				return metadata, nil
-			}
+	case CodeAt:
+		if params.Address == (common.Address{}) {
+			return errors.New("invalid contract address: " + params.Address.Hex())
+		}
 
-		case ERC1155TokenName:
-			if params.Address == (common.Address{}) || params.TokenID == nil {
-				return nil, errors.New("invalid contract address or token id")
-			}
+	case BlockNumber, ResolveENS, GasInfo:
 
-			if tokenName, err := provider.getERC1155TokenName(ctx, params.Address, params.TokenID); err == nil {
-				return tokenName, nil
-			}
+	default:
+		return errors.New("invalid method")
+	}
 
-		case ERC1155TotalSupply:
-			if params.Address == (common.Address{}) || params.TokenID == nil {
-				return nil, errors.New("invalid contract address or token id")
-			}
+	return nil
+}
 
-			// bind erc1155 abi
-			if contractERC1155, err := abis.NewERC1155(params.Address, provider.Client); err == nil {
-				// call totalSupply
-				if totalSupply, err := contractERC1155.TotalSupply(&bind.CallOpts{}, params.TokenID); err == nil {
-					return totalSupply, nil
-				}
-			}
+// call runs the method via the provider.
+func (p *Provider) call(ctx context.Context, method methodCall, params methodCallParams) (interface{}, error) {
+	switch method {
+	case TransactionByHash:
+		tx, _, err := p.Client.TransactionByHash(ctx, params.TxHash)
 
-		case ReverseResolveENS:
-			if params.Address == (common.Address{}) {
-				return nil, errors.New("invalid contract address")
-			}
+		return tx, err
 
-			if ensAddress, err := pp.Rueidi.GetCachedENSName(ctx, params.Address); err == nil {
-				return ensAddress, nil
-			}
+	case BlockNumber:
+		return p.Client.BlockNumber(ctx)
 
-			if ensAddress, err := provider.reverseLookupAndValidate(params.Address); err == nil {
-				return ensAddress, nil
-			}
+	case TransactionReceipt:
+		return p.Client.TransactionReceipt(ctx, params.TxHash)
 
-		case ResolveENS:
-			if ensAddress, err := provider.ensLookup(params.EnsName); err == nil {
-				return ensAddress, nil
-			}
+	case TokenImageURI:
+		return p.getTokenImageURI(ctx, params.Address, params.TokenID)
 
-		case GasInfo:
-			if gasInfo, err := provider.getGasInfo(ctx); err == nil {
-				return gasInfo, nil
-			}
+	case ERC721CollectionName:
+		return p.getERC721CollectionName(params.Address)
 
-		case CodeAt:
-			if params.Address == (common.Address{}) {
-				return nil, errors.New("invalid contract address: " + params.Address.Hex())
-			}
+	case ERC721CollectionMetadata:
+		return p.getERC721CollectionMetadata(params.Address)
 
-			if codeAt, err := provider.codeAt(ctx, params.Address); err == nil {
-				return codeAt, nil
-			}
+	case ERC1155TokenName:
+		return p.getERC1155TokenName(ctx, params.Address, params.TokenID)
 
-		case NonceAt:
-			if params.Address == (common.Address{}) {
-				return nil, errors.New("invalid contract address")
-			}
+	case ERC1155TotalSupply:
+		// bind erc1155 abi
+		contractERC1155, err := abis.NewERC1155(params.Address, p.Client)
+		if err != nil {
+			return nil, err
+		}
 
-			if nonceAt, err := provider.nonceAt(ctx, params.Address); err == nil {
-				return nonceAt, nil
-			}
-		default:
-			return nil, errors.New("invalid method")
+		// call totalSupply
+		return contractERC1155.TotalSupply(&bind.CallOpts{}, params.TokenID)
+
+	case ERC2981RoyaltyInfo:
+		// the royaltyInfo function of the erc1155 abi is the erc2981 one
+		contractERC2981, err := abis.NewERC1155(params.Address, p.Client)
+		if err != nil {
+			return nil, err
+		}
+
+		// call royaltyInfo with a sale price of 10000 to get the royalty in basis points
+		receiver, royaltyBps, err := contractERC2981.RoyaltyInfo(&bind.CallOpts{Context: ctx}, params.TokenID, big.NewInt(10_000))
+		if err != nil {
+			return nil, err
 		}
+
+		return &Royalty{Receiver: receiver, BasisPoints: royaltyBps.Int64()}, nil
+
+	case ReverseResolveENS:
+		return p.reverseLookupAndValidate(params.Address)
+
+	case ResolveENS:
+		return p.ensLookup(params.EnsName)
+
+	case GasInfo:
+		return p.getGasInfo(ctx)
+
+	case CodeAt:
+		return p.codeAt(ctx, params.Address)
+
+	case NonceAt:
+		return p.nonceAt(ctx, params.Address)
 	}
 
-	return nil, err
+	return nil, errors.New("invalid method")
 }
 
 // BlockNumber returns the most recent block number.
@@ -708,6 +932,30 @@ func (pp *Pool) ERC1155TotalSupply(ctx context.Context, contractAddress common.A
 	return nil, err
 }
 
+// Royalty is the erc2981 royalty of a token.
+type Royalty struct {
+	Receiver    common.Address
+	BasisPoints int64
+}
+
+// RoyaltyInfo returns the erc2981 royalty receiver & basis points of a token.
+func (pp *Pool) RoyaltyInfo(ctx context.Context, contractAddress common.Address, tokenID *big.Int) (*Royalty, error) {
+	if tokenID == nil {
+		return nil, errors.New("tokenID is nil")
+	}
+
+	info, err := pp.callMethod(ctx, ERC2981RoyaltyInfo, methodCallParams{Address: contractAddress, TokenID: tokenID})
+	if royalty, ok := info.(*Royalty); err == nil && ok {
+		return royalty, nil
+	}
+
+	if err == nil {
+		err = errors.New("royaltyInfo not supported")
+	}
+
+	return nil, err
+}
+
 //
 // ens related
 //
@@ -721,27 +969,23 @@ func (pp *Pool) ReverseResolveAddressToENS(ctx context.Context, address common.A
 		return "", errors.New("address is zero address")
 	}
 
-	// if cachedName, err := cache.GetENSName(ctx, address); err == nil && cachedName != "" {
-	if cachedName, err := pp.Rueidi.GetCachedENSName(ctx, address); err == nil && cachedName != "" {
-		gbl.Log.Debugf("ens ensName for address %s is cached: %s", address.Hex(), cachedName)
-
-		return cachedName, nil
-	}
-
-	name, err := pp.callMethod(ctx, ReverseResolveENS, methodCallParams{Address: address})
-	gbl.Log.Debugf("pp.callMethod result - ens ensName for address %s is %+v", address.Hex(), name)
+	// concurrent lookups of the same address are coalesced into a single call
+	ensName, err := pp.Rueidi.ENSName(ctx, address, func(ctx context.Context) (string, error) {
+		name, err := pp.callMethod(ctx, ReverseResolveENS, methodCallParams{Address: address})
+		gbl.Log.Debugf("pp.callMethod result - ens ensName for address %s is %+v", address.Hex(), name)
 
-	if ensName, ok := name.(string); err == nil && ok && ensName != "" {
-		// cache.StoreENSName(ctx, address, ensName)
-		err := pp.Rueidi.StoreENSName(ctx, address, ensName)
-		if err != nil {
-			gbl.Log.Errorf("error storing ensName %s for address %s: %s", ensName, address.Hex(), err)
+		if ensName, ok := name.(string); err == nil && ok && ensName != "" {
+			return ensName, nil
 		}
 
-		return ensName, nil
+		return "", errors.New("ens ensName not found")
+	})
+
+	if err != nil || ensName == "" {
+		return "", errors.New("ens ensName not found")
 	}
 
-	return "", errors.New("ens ensName not found")
+	return ensName, nil
 }
 
 func (pp *Pool) ResolveENS(ctx context.Context, ensName string) (common.Address, error) {
@@ -838,19 +1082,6 @@ func (pp *Pool) GetNonceAt(ctx context.Context, address common.Address) (uint64,
 // 	return append(localNodeclients, clients...)
 // }
 
-// // BlockByNumber returns the given full block.
-// func (pp *Pool) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
-// 	var err error
-
-// 	for _, client := range pp.getClients() {
-// 		if block, err := client.BlockByNumber(ctx, number); err == nil {
-// 			return block, nil
-// 		}
-// 	}
-
-// 	return nil, err
-// }
-
 //
 // token related methods
 //
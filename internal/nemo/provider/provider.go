@@ -11,13 +11,14 @@ import (
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/benleb/gloomberg/internal/abis"
 	"github.com/benleb/gloomberg/internal/abis/erc20"
 	"github.com/benleb/gloomberg/internal/external"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/nemo"
-	"github.com/benleb/gloomberg/internal/nemo/topic"
 	"github.com/benleb/gloomberg/internal/utils"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ethereum/go-ethereum"
@@ -36,13 +37,34 @@ type Provider struct {
 	Endpoint  string `json:"endpoint"  mapstructure:"endpoint"`
 	Preferred bool   `json:"preferred" mapstructure:"preferred"`
 
+	// custom http headers, basic auth or a bearer token for private endpoints & proxies (http & ws)
+	Headers     map[string]string `json:"-" mapstructure:"headers"`
+	Username    string            `json:"-" mapstructure:"username"`
+	Password    string            `json:"-" mapstructure:"password"`
+	BearerToken string            `json:"-" mapstructure:"bearer_token"`
+
 	Color lipgloss.Color `json:"color" mapstructure:"color"`
 	// Marker string         `json:"marker" mapstructure:"marker"`
 
 	PID common.Hash `json:"pid" mapstructure:"pid"`
 
+	// features of the endpoint, probed after connecting
+	Capabilities *Capabilities `json:"capabilities" mapstructure:"-"`
+
 	Client     *ethclient.Client  `json:"-" mapstructure:"-"`
 	GethClient *gethclient.Client `json:"-" mapstructure:"-"`
+
+	// last block a log was received for via the subscription, used to backfill gaps after reconnects
+	lastBlock uint64
+
+	// unix nanos of the last log received via a subscription, used to detect stalled subscriptions
+	lastLogReceivedAt atomic.Int64
+	// closed to make the subscriptions of the provider re-subscribe
+	stalled   chan struct{}
+	stalledMu sync.Mutex
+
+	// latency, error rate & blocks behind, used to prefer healthy providers
+	health health
 }
 
 // // newProvider creates a new provider.
@@ -311,7 +333,7 @@ func (p *Provider) connect() error {
 			return err
 		}
 	} else {
-		rpcClient, err = rpc.DialContext(context.Background(), p.Endpoint)
+		rpcClient, err = rpc.DialOptions(context.Background(), p.Endpoint, p.clientOptions()...)
 		if err != nil {
 			gbl.Log.Debugf("Failed to connect to node %s: %s", p.Name, err)
 
@@ -350,20 +372,41 @@ func (p *Provider) connect() error {
 	return err
 }
 
-func (p *Provider) subscribeToAllTransfers(queueLogs chan types.Log) (ethereum.Subscription, error) {
-	subscribeTopics := [][]common.Hash{
-		{common.HexToHash(string(topic.Transfer)), common.HexToHash(string(topic.TransferSingle)), common.HexToHash(string(topic.BuyPriceSet))},
-		{},
-		{},
-		{},
+// clientOptions returns the rpc client options for the custom headers & authentication of the endpoint.
+func (p *Provider) clientOptions() []rpc.ClientOption {
+	headers := make(http.Header)
+
+	for key, value := range p.Headers {
+		headers.Set(key, value)
 	}
 
-	return p.subscribeTo(queueLogs, subscribeTopics, nil)
+	switch {
+	case p.BearerToken != "":
+		headers.Set("Authorization", "Bearer "+p.BearerToken)
+	case p.Username != "" || p.Password != "":
+		headers.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(p.Username+":"+p.Password)))
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	return []rpc.ClientOption{rpc.WithHeaders(headers)}
 }
 
-func (p *Provider) subscribeTo(queueLogs chan types.Log, topics [][]common.Hash, contractAddresses []common.Address) (ethereum.Subscription, error) {
-	ctx := context.Background()
+// subscribeToFilters subscribes to the logs matching each of the filters until the context is canceled.
+func (p *Provider) subscribeToFilters(ctx context.Context, queueLogs chan types.Log, filters []ethereum.FilterQuery) error {
+	for _, filter := range filters {
+		if _, err := p.subscribeTo(ctx, queueLogs, filter.Topics, filter.Addresses); err != nil {
+			return err
+		}
+	}
 
+	return nil
+}
+
+// subscribeTo subscribes to the logs matching the topics & addresses until the context is canceled.
+func (p *Provider) subscribeTo(ctx context.Context, queueLogs chan types.Log, topics [][]common.Hash, contractAddresses []common.Address) (ethereum.Subscription, error) {
 	if topics == nil && contractAddresses == nil {
 		return nil, nil
 	}
@@ -373,7 +416,16 @@ func (p *Provider) subscribeTo(queueLogs chan types.Log, topics [][]common.Hash,
 		Topics:    topics,
 	}
 
-	return p.Client.SubscribeFilterLogs(ctx, filterQuery, queueLogs)
+	providerLogs := make(chan types.Log, 1024)
+
+	sub, err := p.Client.SubscribeFilterLogs(ctx, filterQuery, providerLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	go p.forwardLogs(ctx, sub, filterQuery, providerLogs, queueLogs)
+
+	return sub, nil
 }
 
 func (p *Provider) getERC721ABI(contractAddress common.Address) (*abis.ERC721v3, error) {
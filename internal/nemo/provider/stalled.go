@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/spf13/viper"
+)
+
+// stalledSignal returns the channel closed on the next re-subscribe request.
+func (p *Provider) stalledSignal() <-chan struct{} {
+	p.stalledMu.Lock()
+	defer p.stalledMu.Unlock()
+
+	if p.stalled == nil {
+		p.stalled = make(chan struct{})
+	}
+
+	return p.stalled
+}
+
+// signalStalled makes all subscriptions of the provider re-subscribe.
+func (p *Provider) signalStalled() {
+	p.stalledMu.Lock()
+	defer p.stalledMu.Unlock()
+
+	if p.stalled != nil {
+		close(p.stalled)
+	}
+
+	p.stalled = make(chan struct{})
+}
+
+// watchStalledSubscriptions re-subscribes via providers not delivering logs for chain.stall_timeout
+// while other providers still do. a single provider can't be compared & is covered by the watchdog.
+func (pp *Pool) watchStalledSubscriptions(ctx context.Context) {
+	stallTimeout := viper.GetDuration("chain.stall_timeout")
+	if stallTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(max(stallTimeout/4, 10*time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		providers := pp.subscriptionProviders()
+
+		// most recent log received via any provider
+		var freshest time.Time
+
+		for _, provider := range providers {
+			if lastLog := provider.lastLogReceivedAt.Load(); lastLog > 0 && time.Unix(0, lastLog).After(freshest) {
+				freshest = time.Unix(0, lastLog)
+			}
+		}
+
+		if freshest.IsZero() || time.Since(freshest) > stallTimeout {
+			continue
+		}
+
+		for _, provider := range providers {
+			// no logs received yet, nothing to compare
+			if provider.lastLogReceivedAt.Load() == 0 {
+				continue
+			}
+
+			lastLog := time.Unix(0, provider.lastLogReceivedAt.Load())
+			if time.Since(lastLog) < stallTimeout {
+				continue
+			}
+
+			gbl.Log.Warnf("🔌 subscription via %s stalled: no logs since %s while other nodes deliver, re-subscribing...", style.Bold(provider.Name), time.Since(lastLog).Truncate(time.Second))
+
+			// give the new subscription a full timeout before checking again
+			provider.lastLogReceivedAt.Store(time.Now().UnixNano())
+
+			provider.signalStalled()
+		}
+	}
+}
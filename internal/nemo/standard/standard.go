@@ -58,7 +58,7 @@ func GetStandard(txLog *types.Log) Standard {
 		logStandard = ERC721
 
 	// erc1155
-	case topic0 == topic.TransferSingle && len(txLog.Topics) >= 4:
+	case (topic0 == topic.TransferSingle || topic0 == topic.TransferBatch) && len(txLog.Topics) >= 4:
 		logStandard = ERC1155
 
 	default:
@@ -5,6 +5,7 @@ type Topic string
 const (
 	Transfer       Topic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
 	TransferSingle Topic = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
+	TransferBatch  Topic = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
 	ApprovalForAll Topic = "0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31"
 
 	// opensea.
@@ -13,25 +14,108 @@ const (
 	// blur.
 	OrdersMatched Topic = "0x61cbb2a3dee0b6064c2e681aadd61677fb4ef319f0b547508d495626f5a62f64"
 
+	// blur v2.
+	Execution721Packed         Topic = "0x1d5e12b51dee5e4d34434576c3fb99714a85f57b0fd546ada4b0bddd736d12b2"
+	Execution721TakerFeePacked Topic = "0x0fcf17fac114131b10f37b183c6a60f905911e52802caeeb3e6ea210398b81ab"
+	Execution721MakerFeePacked Topic = "0x7dc5c0699ac8dd5250cbe368a2fc3b4a2daadb120ad07f6cccea29f83482686e"
+
+	// looksrare v2.
+	TakerAsk Topic = "0x9aaa45d6db2ef74ead0751ea9113263d1dec1b50cea05f0ca2002cb8063564a4"
+	TakerBid Topic = "0x3ee3de4684413690dee6fff1a0a4f92916a1b97d1c5a83cdf24671844306b2e3"
+
+	// x2y2.
+	EvInventory Topic = "0x3cbb63f144840e5b1b0a38a7c19211d2e89de4d7c5faf8b2d3c1776c302d1d33"
+
 	// manifold.
 	ClaimMint      Topic = "0x5d404f369772cfab2b65717fca9bc2077efeab89a0dbec036bf0c13783154eb1"
 	ClaimMintBatch Topic = "0x74f5d3254dfa39a7b1217a27d5d9b3e061eafe11720eca1cf499da2dc1eb1259"
 
+	// zora 1155.
+	ZoraPurchased Topic = "0xb362243af1e2070d7d5bf8d713f2e0fab64203f1b71462afbe20572909788c5e"
+
 	// foundation.
 	BuyPriceSet Topic = "0xfcc77ea8bdcce862f43b7fb00fe6b0eb90d6aeead27d3800d9257cf7a05f9d96"
+
+	// erc-6551 registry v0.2 & v0.3.
+	AccountCreated        Topic = "0x07fba7bba1191da7ee1155dcfa0030701c9c9a9cc34a93b991fc6fd0c9268d8f"
+	ERC6551AccountCreated Topic = "0x79f19b3655ee38b1ce526556b7731a20c8f218fbda4a3990b6cc4172fdf88722"
+
+	// erc-1967 proxies.
+	Upgraded       Topic = "0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b"
+	AdminChanged   Topic = "0x7e644d79422f17c01e4894b5f4f588d331ebfa28653d42ae832dc59e38c9798f"
+	BeaconUpgraded Topic = "0x1cf3b03a6cf19fa2baba4df148e9dcabedea7f8a5c07840e207e5c089be95d3e"
+
+	// blur blend (lending).
+	LoanOfferTaken Topic = "0x06a333c2d6fe967ca967f7a35be2eb45e8caeb6cf05e16f55d42b91b5fe31255"
+	Repay          Topic = "0x2469cc9e12e74c63438d5b1117b318cd3a4cdaf9d659d9eac6d975d14d963254"
+	Refinance      Topic = "0x558a9295c62e9e1b12a21c8fe816f4816a2e0269a53157edbfa16017b11b9ac9"
+	Seize          Topic = "0xb71caf41fe0e019dbe21a1ae3493f11a729c31548ed1e304ae7f6e8c8df275de"
+	StartAuction   Topic = "0xe5095dc360d1a56740c946cccc76520c1a1a57381c950520062adeda68dbf572"
+
+	// nftfi v2 direct loans.
+	NFTfiLoanStarted    Topic = "0x42cc7f53ef7b494c5dd6f0095175f7d07b5d3d7b2a03f34389fea445ba4a3a8b"
+	NFTfiLoanRepaid     Topic = "0x3687d64f40b11dd1c102a76882ac1735891c546a96ae27935eb5c7865b9d86fa"
+	NFTfiLoanLiquidated Topic = "0x4fac0ff43299a330bce57d0579985305af580acf256a6d7977083ede81be1326"
+
+	// arcade loan core.
+	ArcadeLoanStarted Topic = "0x80058ebfadf3969c68465e37d0ca6f1647cd2ef5b7a73f5bd388f25be77ed981"
+	ArcadeLoanRepaid  Topic = "0x9a7851747cd7ffb3fe0a32caf3da48b31f27cebe131267051640f8b72fc47186"
+	ArcadeLoanClaimed Topic = "0xb15e438728b48d46c9a5505713e60ff50c80559f4523c8f99a246a2069a8684a"
+
+	// ens (reverse) resolver.
+	NameChanged Topic = "0xb7d29e911041e8d9b843369e890bcb72c9388692ba48b65ac54e7214c4c348f7"
+
+	// ens .eth registrar controllers (legacy & current).
+	NameRegistered            Topic = "0xca6abbe9d7f11422cb6ca7629fbf6fe9efb1c621f71ce8f02b9f2a230097404f"
+	NameRegisteredWithPremium Topic = "0x69e37f151eb98a09618ddaa80c8cfaf1ce5996867c489f45b555b412271ebf27"
+	NameRenewed               Topic = "0x3da24c024582931cfaf8267d8ed24d13a82a8068d5bd337d30ec45cea4e506ae"
+
+	// openzeppelin pausable.
+	Paused   Topic = "0x62e78cea01bee320cd4e420270b5ea74000d11b0c9f74754ebdbfc544b05a258"
+	Unpaused Topic = "0x5db9ee0a495bf2e6ff9c91a7834c1ba4fdd244a5e8aa4e537bd38aeae4b073aa"
 )
 
 func (t Topic) String() string {
 	var topicName string
 	if tName := map[Topic]string{
-		OrdersMatched:  "OrdersMatched",
-		Transfer:       "Transfer",
-		TransferSingle: "TransferSingle",
-		ApprovalForAll: "ApprovalForAll",
-		OrderFulfilled: "OrderFulfilled",
-		ClaimMint:      "ClaimMint",
-		ClaimMintBatch: "ClaimMintBatch",
-		BuyPriceSet:    "BuyPriceSet",
+		OrdersMatched:              "OrdersMatched",
+		Execution721Packed:         "Execution721Packed",
+		Execution721TakerFeePacked: "Execution721TakerFeePacked",
+		Execution721MakerFeePacked: "Execution721MakerFeePacked",
+		TakerAsk:                   "TakerAsk",
+		TakerBid:                   "TakerBid",
+		EvInventory:                "EvInventory",
+		Transfer:                   "Transfer",
+		TransferSingle:             "TransferSingle",
+		TransferBatch:              "TransferBatch",
+		ApprovalForAll:             "ApprovalForAll",
+		OrderFulfilled:             "OrderFulfilled",
+		ClaimMint:                  "ClaimMint",
+		ClaimMintBatch:             "ClaimMintBatch",
+		ZoraPurchased:              "Purchased",
+		BuyPriceSet:                "BuyPriceSet",
+		AccountCreated:             "AccountCreated",
+		ERC6551AccountCreated:      "ERC6551AccountCreated",
+		Upgraded:                   "Upgraded",
+		AdminChanged:               "AdminChanged",
+		BeaconUpgraded:             "BeaconUpgraded",
+		NameChanged:                "NameChanged",
+		NameRegistered:             "NameRegistered",
+		NameRegisteredWithPremium:  "NameRegistered",
+		NameRenewed:                "NameRenewed",
+		Paused:                     "Paused",
+		Unpaused:                   "Unpaused",
+		LoanOfferTaken:             "LoanOfferTaken",
+		Repay:                      "Repay",
+		Refinance:                  "Refinance",
+		Seize:                      "Seize",
+		StartAuction:               "StartAuction",
+		NFTfiLoanStarted:           "LoanStarted",
+		NFTfiLoanRepaid:            "LoanRepaid",
+		NFTfiLoanLiquidated:        "LoanLiquidated",
+		ArcadeLoanStarted:          "LoanStarted",
+		ArcadeLoanRepaid:           "LoanRepaid",
+		ArcadeLoanClaimed:          "LoanClaimed",
 	}[t]; tName != "" {
 		topicName = tName
 	} else {
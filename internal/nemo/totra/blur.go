@@ -0,0 +1,124 @@
+package totra
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/abis/blur"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/marketplace"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// blur v2 order types.
+const (
+	blurOrderAsk uint64 = iota
+	blurOrderBid
+)
+
+// fee rates are given in basis points.
+var basisPoints = big.NewInt(10_000)
+
+var (
+	// the abi is parsed once, the address is irrelevant for unpacking logs
+	blurFilterer     *blur.BlurFilterer
+	blurFiltererOnce sync.Once
+)
+
+// parseBlurOrdersMatched decodes an OrdersMatched log of the blur v1 exchange.
+// the sell order contains the nft, the price & the fees paid by the seller.
+func (ttx *TokenTransaction) parseBlurOrdersMatched(txLog *types.Log, providerPool *provider.Pool) *marketOrder {
+	if !marketplace.Blur.ContractAddresses.Contains(txLog.Address) {
+		return nil
+	}
+
+	blurFiltererOnce.Do(func() {
+		var err error
+		if blurFilterer, err = blur.NewBlurFilterer(common.Address{}, nil); err != nil {
+			gbl.Log.Errorf("❗️ error binding blur abi: %s", err)
+		}
+	})
+
+	if blurFilterer == nil {
+		return nil
+	}
+
+	ordersMatched, err := blurFilterer.ParseOrdersMatched(*txLog)
+	if err != nil {
+		gbl.Log.Debugf("❗️ error parsing OrdersMatched log of %s: %s", ttx.TxHash.Hex(), err)
+
+		return nil
+	}
+
+	sell := ordersMatched.Sell
+	if sell.Price == nil || sell.TokenId == nil {
+		return nil
+	}
+
+	amountPaid := orderAmountWei(sell.PaymentToken, sell.Price, providerPool)
+
+	feeRate := big.NewInt(0)
+	for _, fee := range sell.Fees {
+		feeRate.Add(feeRate, big.NewInt(int64(fee.Rate)))
+	}
+
+	fees := new(big.Int).Div(new(big.Int).Mul(amountPaid, feeRate), basisPoints)
+
+	return &marketOrder{
+		marketplace: &marketplace.Blur,
+		nfts:        []string{orderNFTID(sell.Collection, sell.TokenId)},
+		amountPaid:  amountPaid,
+		proceeds:    new(big.Int).Sub(amountPaid, fees),
+	}
+}
+
+// parseBlurExecution721 decodes the packed Execution721* logs of the blur v2 exchange.
+//
+//	tokenIdListingIndexTrader: tokenId (11 bytes) | listingIndex (1 byte) | trader (20 bytes)
+//	collectionPriceSide:       orderType (1 byte) | price (11 bytes) | collection (20 bytes)
+//	makerFee/takerFee:         rate (12 bytes) | recipient (20 bytes)
+//
+// asks are paid in eth, bids in blur pool tokens. the maker fee (royalties) is paid by the seller,
+// the taker fee by the taker: on top of the price for asks, from the proceeds for bids.
+func parseBlurExecution721(txLog *types.Log) *marketOrder {
+	if !marketplace.Blur.ContractAddresses.Contains(txLog.Address) || len(txLog.Data) < 3*32 {
+		return nil
+	}
+
+	tokenIdListingIndexTrader := new(big.Int).SetBytes(txLog.Data[32:64])
+	collectionPriceSide := new(big.Int).SetBytes(txLog.Data[64:96])
+
+	tokenID := new(big.Int).Rsh(tokenIdListingIndexTrader, 168)
+	orderType := new(big.Int).Rsh(collectionPriceSide, 248).Uint64()
+	price := new(big.Int).And(new(big.Int).Rsh(collectionPriceSide, 160), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 88), big.NewInt(1)))
+	collection := common.BytesToAddress(txLog.Data[96-common.AddressLength : 96])
+
+	amountPaid := new(big.Int).Set(price)
+	proceeds := new(big.Int).Set(price)
+
+	if len(txLog.Data) >= 4*32 {
+		feeRate := new(big.Int).Rsh(new(big.Int).SetBytes(txLog.Data[96:128]), 160)
+		fee := new(big.Int).Div(new(big.Int).Mul(price, feeRate), basisPoints)
+
+		switch {
+		case topic.Topic(txLog.Topics[0].Hex()) == topic.Execution721TakerFeePacked && orderType == blurOrderAsk:
+			amountPaid.Add(amountPaid, fee)
+		default:
+			proceeds.Sub(proceeds, fee)
+		}
+	}
+
+	if proceeds.Sign() < 0 {
+		proceeds = big.NewInt(0)
+	}
+
+	return &marketOrder{
+		marketplace: &marketplace.Blur,
+		nfts:        []string{orderNFTID(collection, tokenID)},
+		amountPaid:  amountPaid,
+		proceeds:    proceeds,
+	}
+}
@@ -0,0 +1,253 @@
+package totra
+
+import (
+	"math/big"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LoanEvent is a loan origination, repayment or liquidation of a nft lending protocol (blend, nftfi, arcade).
+type LoanEvent struct {
+	Protocol string `json:"protocol"`
+
+	// Loan, RepayLoan or Liquidation
+	Action *degendb.GBEventType `json:"action"`
+
+	// principal of the loan in wei or in the smallest unit of the currency, nil if unknown
+	Principal *big.Int `json:"principal,omitempty"`
+	// erc20 token the loan is denominated in, zero address for eth/weth/blur pool
+	Currency common.Address `json:"currency,omitempty"`
+
+	Borrower common.Address `json:"borrower"`
+	Lender   common.Address `json:"lender"`
+
+	// collateral
+	Collection common.Address `json:"collection"`
+	TokenID    *big.Int       `json:"token_id,omitempty"`
+}
+
+// parseLoans decodes the logs of the supported lending protocols. the first loan event found is used.
+func (ttx *TokenTransaction) parseLoans() {
+	if ttx.TxReceipt == nil {
+		return
+	}
+
+	for _, txLog := range ttx.TxReceipt.Logs {
+		if len(txLog.Topics) == 0 {
+			continue
+		}
+
+		var loanEvent *LoanEvent
+
+		switch topic.Topic(txLog.Topics[0].Hex()) {
+		case topic.LoanOfferTaken, topic.Refinance, topic.Repay, topic.Seize:
+			loanEvent = parseBlendLog(txLog)
+		case topic.NFTfiLoanStarted, topic.NFTfiLoanRepaid, topic.NFTfiLoanLiquidated:
+			loanEvent = parseNFTfiLog(txLog)
+		case topic.ArcadeLoanStarted, topic.ArcadeLoanRepaid, topic.ArcadeLoanClaimed:
+			loanEvent = ttx.parseArcadeLog(txLog)
+		}
+
+		if loanEvent == nil {
+			continue
+		}
+
+		gbl.Log.Debugf("💸 %s %s in %s | principal: %s", loanEvent.Protocol, loanEvent.Action, ttx.TxHash.Hex(), loanEvent.Principal)
+
+		ttx.LoanEvent = loanEvent
+
+		return
+	}
+}
+
+// parseBlendLog decodes the events of blur blend, all fields are non-indexed.
+//
+//	LoanOfferTaken(offerHash, lienId, collection, lender, borrower, loanAmount, rate, tokenId, auctionDuration)
+//	Refinance(lienId, collection, newLender, newAmount, newRate, newAuctionDuration)
+//	Repay(lienId, collection) | Seize(lienId, collection)
+func parseBlendLog(txLog *types.Log) *LoanEvent {
+	if txLog.Address != internal.BlurBlendContractAddress {
+		return nil
+	}
+
+	loanEvent := &LoanEvent{Protocol: "Blend"}
+
+	switch topic.Topic(txLog.Topics[0].Hex()) {
+	case topic.LoanOfferTaken:
+		if len(txLog.Data) < 9*32 {
+			return nil
+		}
+
+		loanEvent.Action = degendb.Loan
+		loanEvent.Collection = dataAddress(txLog.Data, 2)
+		loanEvent.Lender = dataAddress(txLog.Data, 3)
+		loanEvent.Borrower = dataAddress(txLog.Data, 4)
+		loanEvent.Principal = dataWord(txLog.Data, 5)
+		loanEvent.TokenID = dataWord(txLog.Data, 7)
+
+	case topic.Refinance:
+		if len(txLog.Data) < 6*32 {
+			return nil
+		}
+
+		loanEvent.Protocol = "Blend refi"
+		loanEvent.Action = degendb.Loan
+		loanEvent.Collection = dataAddress(txLog.Data, 1)
+		loanEvent.Lender = dataAddress(txLog.Data, 2)
+		loanEvent.Principal = dataWord(txLog.Data, 3)
+
+	case topic.Repay, topic.Seize:
+		if len(txLog.Data) < 2*32 {
+			return nil
+		}
+
+		loanEvent.Action = degendb.RepayLoan
+		if topic.Topic(txLog.Topics[0].Hex()) == topic.Seize {
+			loanEvent.Action = degendb.Liquidation
+		}
+
+		loanEvent.Collection = dataAddress(txLog.Data, 1)
+	}
+
+	return loanEvent
+}
+
+// parseNFTfiLog decodes the events of the nftfi v2 direct loans. loanId, borrower & lender are indexed.
+//
+//	LoanStarted(loanTerms(loanPrincipalAmount, maximumRepaymentAmount, nftCollateralId, ..., nftCollateralContract, borrower), loanExtras)
+//	LoanRepaid(loanPrincipalAmount, nftCollateralId, amountPaidToLender, adminFee, revenueShare, revenueSharePartner, nftCollateralContract, loanERC20Denomination)
+//	LoanLiquidated(loanPrincipalAmount, nftCollateralId, loanMaturityDate, loanLiquidationDate, nftCollateralContract)
+func parseNFTfiLog(txLog *types.Log) *LoanEvent {
+	if len(txLog.Topics) != 4 {
+		return nil
+	}
+
+	loanEvent := &LoanEvent{
+		Protocol: "NFTfi",
+		Borrower: common.BytesToAddress(txLog.Topics[2].Bytes()),
+		Lender:   common.BytesToAddress(txLog.Topics[3].Bytes()),
+	}
+
+	switch topic.Topic(txLog.Topics[0].Hex()) {
+	case topic.NFTfiLoanStarted:
+		if len(txLog.Data) < 11*32 {
+			return nil
+		}
+
+		loanEvent.Action = degendb.Loan
+		loanEvent.Principal = dataWord(txLog.Data, 0)
+		loanEvent.TokenID = dataWord(txLog.Data, 2)
+		loanEvent.Currency = loanCurrency(dataAddress(txLog.Data, 3))
+		loanEvent.Collection = dataAddress(txLog.Data, 9)
+
+	case topic.NFTfiLoanRepaid:
+		if len(txLog.Data) < 8*32 {
+			return nil
+		}
+
+		loanEvent.Action = degendb.RepayLoan
+		loanEvent.Principal = dataWord(txLog.Data, 0)
+		loanEvent.TokenID = dataWord(txLog.Data, 1)
+		loanEvent.Collection = dataAddress(txLog.Data, 6)
+		loanEvent.Currency = loanCurrency(dataAddress(txLog.Data, 7))
+
+	case topic.NFTfiLoanLiquidated:
+		if len(txLog.Data) < 5*32 {
+			return nil
+		}
+
+		loanEvent.Action = degendb.Liquidation
+		loanEvent.Principal = dataWord(txLog.Data, 0)
+		loanEvent.TokenID = dataWord(txLog.Data, 1)
+		loanEvent.Collection = dataAddress(txLog.Data, 4)
+	}
+
+	return loanEvent
+}
+
+// parseArcadeLog decodes the events of the arcade loan core. the events contain no amounts,
+// the principal is taken from the erc20 transfers to (loans) or from (repayments) the borrower.
+//
+//	LoanStarted(loanId, lender, borrower) | LoanRepaid(loanId) | LoanClaimed(loanId)
+func (ttx *TokenTransaction) parseArcadeLog(txLog *types.Log) *LoanEvent {
+	if txLog.Address != internal.ArcadeLoanCoreV2 && txLog.Address != internal.ArcadeLoanCoreV3 {
+		return nil
+	}
+
+	loanEvent := &LoanEvent{Protocol: "Arcade"}
+
+	switch topic.Topic(txLog.Topics[0].Hex()) {
+	case topic.ArcadeLoanStarted:
+		if len(txLog.Data) < 3*32 {
+			return nil
+		}
+
+		loanEvent.Action = degendb.Loan
+		loanEvent.Lender = dataAddress(txLog.Data, 1)
+		loanEvent.Borrower = dataAddress(txLog.Data, 2)
+		loanEvent.Principal, loanEvent.Currency = ttx.erc20Amount(func(transfer *TokenTransfer) bool { return transfer.To == loanEvent.Borrower })
+
+	case topic.ArcadeLoanRepaid:
+		loanEvent.Action = degendb.RepayLoan
+		loanEvent.Borrower = ttx.From
+		loanEvent.Principal, loanEvent.Currency = ttx.erc20Amount(func(transfer *TokenTransfer) bool { return transfer.From == loanEvent.Borrower })
+
+	case topic.ArcadeLoanClaimed:
+		loanEvent.Action = degendb.Liquidation
+		loanEvent.Lender = ttx.From
+	}
+
+	return loanEvent
+}
+
+// erc20Amount sums the parsed erc20 transfers matching the filter & returns the amount with its currency.
+// the amount is nil if there are no matching transfers.
+func (ttx *TokenTransaction) erc20Amount(filter func(*TokenTransfer) bool) (*big.Int, common.Address) {
+	var amount *big.Int
+
+	var currency common.Address
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC20() || transfer.AmountTokens == nil || !filter(transfer) {
+			continue
+		}
+
+		// loans are paid in a single currency
+		if amount != nil && loanCurrency(transfer.Token.Address) != currency {
+			continue
+		}
+
+		if amount == nil {
+			amount = big.NewInt(0)
+			currency = loanCurrency(transfer.Token.Address)
+		}
+
+		amount.Add(amount, transfer.AmountTokens)
+	}
+
+	return amount, currency
+}
+
+// loanCurrency returns the zero address for weth (handled like eth) & the token address otherwise.
+func loanCurrency(tokenAddress common.Address) common.Address {
+	if tokenAddress == internal.WETHContractAddress || tokenAddress == internal.BlurPoolTokenContractAddress {
+		return internal.ZeroAddress
+	}
+
+	return tokenAddress
+}
+
+// dataWord returns the nth 32 byte word of the log data as integer.
+func dataWord(data []byte, n int) *big.Int {
+	return new(big.Int).SetBytes(data[n*32 : (n+1)*32])
+}
+
+// dataAddress returns the nth 32 byte word of the log data as address.
+func dataAddress(data []byte, n int) common.Address {
+	return common.BytesToAddress(data[n*32 : (n+1)*32])
+}
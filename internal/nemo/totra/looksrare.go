@@ -0,0 +1,97 @@
+package totra
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/marketplace"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// looksRareV2EventsABI contains the TakerAsk & TakerBid events of the looksrare v2 exchange.
+const looksRareV2EventsABI = `[
+	{"anonymous":false,"name":"TakerAsk","type":"event","inputs":[
+		{"indexed":false,"name":"nonceInvalidationParameters","type":"tuple","components":[{"name":"orderHash","type":"bytes32"},{"name":"orderNonce","type":"uint256"},{"name":"isNonceInvalidated","type":"bool"}]},
+		{"indexed":false,"name":"askUser","type":"address"},
+		{"indexed":false,"name":"bidUser","type":"address"},
+		{"indexed":false,"name":"strategyId","type":"uint256"},
+		{"indexed":false,"name":"currency","type":"address"},
+		{"indexed":false,"name":"collection","type":"address"},
+		{"indexed":false,"name":"itemIds","type":"uint256[]"},
+		{"indexed":false,"name":"amounts","type":"uint256[]"},
+		{"indexed":false,"name":"feeRecipients","type":"address[2]"},
+		{"indexed":false,"name":"feeAmounts","type":"uint256[3]"}
+	]},
+	{"anonymous":false,"name":"TakerBid","type":"event","inputs":[
+		{"indexed":false,"name":"nonceInvalidationParameters","type":"tuple","components":[{"name":"orderHash","type":"bytes32"},{"name":"orderNonce","type":"uint256"},{"name":"isNonceInvalidated","type":"bool"}]},
+		{"indexed":false,"name":"bidUser","type":"address"},
+		{"indexed":false,"name":"bidRecipient","type":"address"},
+		{"indexed":false,"name":"strategyId","type":"uint256"},
+		{"indexed":false,"name":"currency","type":"address"},
+		{"indexed":false,"name":"collection","type":"address"},
+		{"indexed":false,"name":"itemIds","type":"uint256[]"},
+		{"indexed":false,"name":"amounts","type":"uint256[]"},
+		{"indexed":false,"name":"feeRecipients","type":"address[2]"},
+		{"indexed":false,"name":"feeAmounts","type":"uint256[3]"}
+	]}
+]`
+
+var (
+	// the abi is parsed once
+	looksRareV2ABI     abi.ABI
+	looksRareV2ABIErr  error
+	looksRareV2ABIOnce sync.Once
+)
+
+// parseLooksRareTakerOrder decodes a TakerAsk (accepted bid) or TakerBid (bought listing) log of the looksrare v2 exchange.
+// feeAmounts are the proceeds of the seller, the creator fee & the protocol fee - their sum is the price paid.
+func (ttx *TokenTransaction) parseLooksRareTakerOrder(txLog *types.Log, eventName string, providerPool *provider.Pool) *marketOrder {
+	if !marketplace.LooksRare.ContractAddresses.Contains(txLog.Address) {
+		return nil
+	}
+
+	looksRareV2ABIOnce.Do(func() {
+		if looksRareV2ABI, looksRareV2ABIErr = abi.JSON(strings.NewReader(looksRareV2EventsABI)); looksRareV2ABIErr != nil {
+			gbl.Log.Errorf("❗️ error parsing looksrare v2 abi: %s", looksRareV2ABIErr)
+		}
+	})
+
+	if looksRareV2ABIErr != nil {
+		return nil
+	}
+
+	values, err := looksRareV2ABI.Unpack(eventName, txLog.Data)
+	if err != nil || len(values) != 10 {
+		gbl.Log.Debugf("❗️ error parsing %s log of %s: %v", eventName, ttx.TxHash.Hex(), err)
+
+		return nil
+	}
+
+	currency, okCurrency := values[4].(common.Address)
+	collection, okCollection := values[5].(common.Address)
+	itemIDs, okItemIDs := values[6].([]*big.Int)
+	feeAmounts, okFeeAmounts := values[9].([3]*big.Int)
+
+	if !okCurrency || !okCollection || !okItemIDs || !okFeeAmounts || len(itemIDs) == 0 {
+		return nil
+	}
+
+	nfts := make([]string, 0, len(itemIDs))
+	for _, itemID := range itemIDs {
+		nfts = append(nfts, orderNFTID(collection, itemID))
+	}
+
+	total := new(big.Int).Add(feeAmounts[0], new(big.Int).Add(feeAmounts[1], feeAmounts[2]))
+
+	return &marketOrder{
+		marketplace: &marketplace.LooksRare,
+		nfts:        nfts,
+		amountPaid:  orderAmountWei(currency, total, providerPool),
+		proceeds:    orderAmountWei(currency, feeAmounts[0], providerPool),
+	}
+}
@@ -1,6 +1,7 @@
 package totra
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/benleb/gloomberg/internal/nemo/standard"
@@ -26,4 +27,24 @@ type TokenTransfer struct {
 
 	// the amount of eth/weth transferred in the same tx to the sender of the nft
 	AmountEtherReturned *big.Int `json:"amount_ether_returned"`
+
+	// remaining quantity of the listing filled by this transfer (erc1155), 0 if unknown
+	ListedQuantity int64 `json:"listed_quantity,omitempty"`
+
+	// how the sender acquired the token, set for sales of own wallets (held x days, bought for ...)
+	AcquisitionContext string `json:"acquisition_context,omitempty"`
+}
+
+// FormatQuantity returns the number of transferred tokens, including the listed quantity on partial fills (e.g. "5/20").
+// returns an empty string for single tokens.
+func (t *TokenTransfer) FormatQuantity() string {
+	if t.AmountTokens == nil || (t.AmountTokens.Cmp(big.NewInt(1)) <= 0 && t.ListedQuantity <= 1) {
+		return ""
+	}
+
+	if t.ListedQuantity > t.AmountTokens.Int64() {
+		return fmt.Sprintf("%s/%d", t.AmountTokens.String(), t.ListedQuantity)
+	}
+
+	return t.AmountTokens.String()
 }
@@ -0,0 +1,222 @@
+package totra
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/currency"
+	"github.com/benleb/gloomberg/internal/nemo/marketplace"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// marketOrder is a fulfilled marketplace order with the nfts & the amount paid for them.
+type marketOrder struct {
+	// the marketplace the order was executed on, nil if the protocol is used by several marketplaces (seaport)
+	marketplace *marketplace.MarketPlace
+
+	nfts []string
+
+	// the amount paid by the buyer (incl. fees) & the proceeds of the seller in wei
+	amountPaid *big.Int
+	proceeds   *big.Int
+
+	// currency paid to other recipients than the seller (fees & royalties) in wei, nil if unknown
+	payments map[common.Address]*big.Int
+
+	// the seller & buyer of the nfts, zero if unknown (e.g. the recipient of matched seaport orders)
+	seller common.Address
+	buyer  common.Address
+
+	// contracts the nfts are routed through (zones), they are not the counterparty of the trade
+	intermediaries []common.Address
+}
+
+// parseMarketplaceOrders decodes the order logs of seaport, blur, looksrare & x2y2 & replaces the amount paid (tx value + weth transfers)
+// with the amounts of the orders. this gives the real price for weth/blur pool bids (accepted offers), bundles &
+// aggregator txs. only used if the orders cover all transferred nfts.
+func (ttx *TokenTransaction) parseMarketplaceOrders(providerPool *provider.Pool) {
+	orders := make([]*marketOrder, 0)
+	parties := make([]*marketOrder, 0)
+	pricedNFTs := make(map[string]bool)
+
+	for _, txLog := range ttx.TxReceipt.Logs {
+		if len(txLog.Topics) == 0 {
+			continue
+		}
+
+		var order *marketOrder
+
+		if parser := getParser(txLog); parser != nil && parser.parseOrder != nil {
+			order = parser.parseOrder(ttx, txLog, providerPool)
+		}
+
+		if order == nil || len(order.nfts) == 0 {
+			continue
+		}
+
+		// both sides of matched orders are used to find the parties
+		parties = append(parties, order)
+
+		// matched orders emit an event for each side of the trade
+		if pricedNFTs[order.nfts[0]] {
+			continue
+		}
+
+		for _, nftID := range order.nfts {
+			pricedNFTs[nftID] = true
+		}
+
+		orders = append(orders, order)
+	}
+
+	if len(orders) == 0 {
+		return
+	}
+
+	ttx.resolveOrderParties(parties)
+
+	// keep the generic price discovery if nfts were traded elsewhere in the same tx
+	for _, transfer := range ttx.Transfers {
+		if transfer.Standard.IsERC721orERC1155() && transfer.From != internal.ZeroAddress && !pricedNFTs[orderNFTID(transfer.Token.Address, transfer.Token.ID)] {
+			return
+		}
+	}
+
+	amountPaid := big.NewInt(0)
+
+	for _, order := range orders {
+		amountPaid.Add(amountPaid, order.amountPaid)
+
+		for recipient, amount := range order.payments {
+			if ttx.OrderPayments == nil {
+				ttx.OrderPayments = make(map[common.Address]*big.Int)
+			}
+
+			if ttx.OrderPayments[recipient] == nil {
+				ttx.OrderPayments[recipient] = big.NewInt(0)
+			}
+
+			ttx.OrderPayments[recipient].Add(ttx.OrderPayments[recipient], amount)
+		}
+
+		// bundle proceeds & payments are split evenly
+		proceedsPerNFT := new(big.Int).Div(order.proceeds, big.NewInt(int64(len(order.nfts))))
+		paidPerNFT := new(big.Int).Div(order.amountPaid, big.NewInt(int64(len(order.nfts))))
+
+		for _, transfer := range ttx.Transfers {
+			if !transfer.Standard.IsERC721orERC1155() {
+				continue
+			}
+
+			for _, nftID := range order.nfts {
+				if orderNFTID(transfer.Token.Address, transfer.Token.ID) == nftID {
+					transfer.AmountEtherReturned = proceedsPerNFT
+
+					if ttx.AmountPaidByContract == nil {
+						ttx.AmountPaidByContract = make(map[common.Address]*big.Int)
+					}
+
+					if ttx.AmountPaidByContract[transfer.Token.Address] == nil {
+						ttx.AmountPaidByContract[transfer.Token.Address] = big.NewInt(0)
+					}
+
+					ttx.AmountPaidByContract[transfer.Token.Address].Add(ttx.AmountPaidByContract[transfer.Token.Address], paidPerNFT)
+				}
+			}
+		}
+	}
+
+	gbl.Log.Debugf("🧾 %d marketplace orders in %s | amount paid: %s (was %s)", len(orders), ttx.TxHash.Hex(), amountPaid, ttx.AmountPaid)
+
+	ttx.AmountPaid = amountPaid
+
+	// txs via aggregators or unknown contracts are attributed to the marketplace of the orders
+	if ttx.Marketplace == nil || ttx.Marketplace == &marketplace.Unknown {
+		if orderMarketplace := orders[0].marketplace; orderMarketplace != nil {
+			for _, order := range orders[1:] {
+				if order.marketplace != orderMarketplace {
+					return
+				}
+			}
+
+			ttx.Marketplace = orderMarketplace
+		}
+	}
+}
+
+// resolveOrderParties replaces the conduits, zones & marketplace contracts the nfts were routed through with
+// the seller & buyer of the orders. nfts of private/otc sales & advanced orders are often moved via
+// such intermediaries & would show them as buyer otherwise.
+func (ttx *TokenTransaction) resolveOrderParties(orders []*marketOrder) {
+	sellers := make(map[string]common.Address)
+	buyers := make(map[string]common.Address)
+	intermediaries := orderIntermediaries.Clone()
+
+	for _, order := range orders {
+		for _, nftID := range order.nfts {
+			if _, ok := sellers[nftID]; !ok && order.seller != internal.ZeroAddress {
+				sellers[nftID] = order.seller
+			}
+
+			if _, ok := buyers[nftID]; !ok && order.buyer != internal.ZeroAddress {
+				buyers[nftID] = order.buyer
+			}
+		}
+
+		for _, intermediary := range order.intermediaries {
+			if intermediary != internal.ZeroAddress {
+				intermediaries.Add(intermediary)
+			}
+		}
+	}
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() {
+			continue
+		}
+
+		nftID := orderNFTID(transfer.Token.Address, transfer.Token.ID)
+
+		if seller, ok := sellers[nftID]; ok && intermediaries.Contains(transfer.From) {
+			gbl.Log.Debugf("🧾 %s: seller %s instead of intermediary %s", ttx.TxHash.Hex(), seller.Hex(), transfer.From.Hex())
+
+			transfer.From = seller
+		}
+
+		if buyer, ok := buyers[nftID]; ok && intermediaries.Contains(transfer.To) {
+			gbl.Log.Debugf("🧾 %s: buyer %s instead of intermediary %s", ttx.TxHash.Hex(), buyer.Hex(), transfer.To.Hex())
+
+			transfer.To = buyer
+		}
+	}
+}
+
+// orderAmountWei returns the amount in wei, known currencies (usdc, ape, ...) are converted to their eth equivalent.
+func orderAmountWei(tokenAddress common.Address, amount *big.Int, providerPool *provider.Pool) *big.Int {
+	// eth, weth & blur pool tokens
+	if tokenAddress == (common.Address{}) || tokenAddress == internal.WETHContractAddress || tokenAddress == internal.BlurPoolTokenContractAddress {
+		return amount
+	}
+
+	paymentCurrency := currency.Get(tokenAddress)
+	if paymentCurrency == nil {
+		return big.NewInt(0)
+	}
+
+	amountWei, err := paymentCurrency.ToWei(context.Background(), providerPool, amount)
+	if err != nil {
+		gbl.Log.Debugf("💱 could not convert %s to eth: %s", paymentCurrency.Format(amount), err)
+
+		return big.NewInt(0)
+	}
+
+	return amountWei
+}
+
+func orderNFTID(tokenAddress common.Address, tokenID *big.Int) string {
+	return fmt.Sprintf("%s_%s", tokenAddress.Hex(), tokenID.String())
+}
@@ -0,0 +1,328 @@
+package totra
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/standard"
+	"github.com/benleb/gloomberg/internal/nemo/token"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrNoTopic    = errors.New("parser needs a topic or an event")
+	ErrNoCallback = errors.New("parser needs a parse & standard function")
+	ErrNoEvent    = errors.New("abi fragment contains no event")
+	ErrNoMapping  = errors.New("mapping needs at least the to & token_id args")
+)
+
+// LogParser parses the logs of an event into token transfers. the built-in parsers for Transfer, TransferSingle/Batch &
+// the marketplace orders (OrderFulfilled, ...) are registered the same way.
+type LogParser struct {
+	// name of the event, used in logs
+	Name string
+
+	// topic0 of the event, taken from Event if not set
+	Topic common.Hash
+
+	// abi fragment of the event, if set the log args are decoded & passed to Parse
+	Event *abi.Event
+
+	// Standard returns the standard of the tokens transferred by the log (e.g. erc20 & erc721 share the Transfer topic)
+	Standard func(txLog *types.Log) standard.Standard
+
+	// Parse returns the transfers of the log, args contains the decoded event args if Event is set
+	Parse func(txLog *types.Log, args map[string]interface{}, providerPool *provider.Pool) []*TokenTransfer
+
+	// parseOrder decodes marketplace orders, only used by the built-in parsers
+	parseOrder func(ttx *TokenTransaction, txLog *types.Log, providerPool *provider.Pool) *marketOrder
+}
+
+// ParserMapping maps the args of an event to a token transfer, used to parse events of new protocols defined in the config.
+type ParserMapping struct {
+	// abi fragment of the event, e.g. {"type":"event","name":"Minted","inputs":[...]}
+	Event string `mapstructure:"event"`
+
+	// standard of the transferred tokens, erc721 (default) or erc1155
+	Standard string `mapstructure:"standard"`
+
+	// names of the event args, from defaults to the zero address (mints) & amount to 1
+	From    string `mapstructure:"from"`
+	To      string `mapstructure:"to"`
+	TokenID string `mapstructure:"token_id"`
+	Amount  string `mapstructure:"amount"`
+}
+
+var (
+	logParsers   = builtinParsers()
+	logParsersMu sync.RWMutex
+)
+
+// RegisterParser adds a parser for the logs with its topic, a previously registered parser for the topic is replaced.
+func RegisterParser(parser *LogParser) error {
+	if parser.Topic == (common.Hash{}) && parser.Event != nil {
+		parser.Topic = parser.Event.ID
+	}
+
+	if parser.Topic == (common.Hash{}) {
+		return ErrNoTopic
+	}
+
+	if parser.Parse == nil || parser.Standard == nil {
+		return ErrNoCallback
+	}
+
+	if parser.Name == "" && parser.Event != nil {
+		parser.Name = parser.Event.Name
+	}
+
+	logParsersMu.Lock()
+	defer logParsersMu.Unlock()
+
+	if previous, ok := logParsers[parser.Topic]; ok {
+		gbl.Log.Infof("🧱 replacing %s parser for %s", previous.Name, parser.Topic.Hex())
+	}
+
+	logParsers[parser.Topic] = parser
+
+	return nil
+}
+
+// RegisterMapping registers a parser for an event defined by an abi fragment & the names of its transfer args.
+func RegisterMapping(mapping *ParserMapping) error {
+	event, err := parseEventFragment(mapping.Event)
+	if err != nil {
+		return err
+	}
+
+	if mapping.To == "" || mapping.TokenID == "" {
+		return ErrNoMapping
+	}
+
+	tokenStandard := standard.ERC721
+	if strings.EqualFold(mapping.Standard, "erc1155") {
+		tokenStandard = standard.ERC1155
+	}
+
+	return RegisterParser(&LogParser{
+		Event:    event,
+		Standard: func(_ *types.Log) standard.Standard { return tokenStandard },
+		Parse: func(txLog *types.Log, args map[string]interface{}, _ *provider.Pool) []*TokenTransfer {
+			from, _ := args[mapping.From].(common.Address)
+
+			to, ok := args[mapping.To].(common.Address)
+			if !ok {
+				return nil
+			}
+
+			tokenID, ok := args[mapping.TokenID].(*big.Int)
+			if !ok {
+				return nil
+			}
+
+			amount := big.NewInt(1)
+			if value, ok := args[mapping.Amount].(*big.Int); ok {
+				amount = value
+			}
+
+			return []*TokenTransfer{{
+				From:                from,
+				To:                  to,
+				AmountTokens:        amount,
+				AmountEtherReturned: big.NewInt(0),
+				Standard:            tokenStandard,
+				Token:               &token.Token{Address: txLog.Address, ID: tokenID},
+			}}
+		},
+	})
+}
+
+// LoadParsers registers the parsers mapped in the config (parsers).
+func LoadParsers() error {
+	var mappings []*ParserMapping
+	if err := viper.UnmarshalKey("parsers", &mappings); err != nil {
+		return err
+	}
+
+	for idx, mapping := range mappings {
+		if err := RegisterMapping(mapping); err != nil {
+			return fmt.Errorf("loading parser %d failed: %w", idx+1, err)
+		}
+	}
+
+	if len(mappings) > 0 {
+		gbl.Log.Infof("🧱 loaded %d custom log parsers", len(mappings))
+	}
+
+	return nil
+}
+
+// getParser returns the registered parser for the topic of the log or nil.
+func getParser(txLog *types.Log) *LogParser {
+	if len(txLog.Topics) == 0 {
+		return nil
+	}
+
+	logParsersMu.RLock()
+	defer logParsersMu.RUnlock()
+
+	return logParsers[txLog.Topics[0]]
+}
+
+// parseTransfers decodes the log with the event of the parser (if set) & returns its transfers.
+func (p *LogParser) parseTransfers(txLog *types.Log, providerPool *provider.Pool) []*TokenTransfer {
+	if p.Parse == nil || p.Standard(txLog) == standard.UNKNOWN {
+		return nil
+	}
+
+	args := make(map[string]interface{})
+
+	if p.Event != nil {
+		if err := p.Event.Inputs.NonIndexed().UnpackIntoMap(args, txLog.Data); err != nil {
+			gbl.Log.Debugf("❗️ error decoding %s log data: %s", p.Name, err)
+
+			return nil
+		}
+
+		indexed := make(abi.Arguments, 0)
+
+		for _, input := range p.Event.Inputs {
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+
+		if len(txLog.Topics)-1 != len(indexed) {
+			return nil
+		}
+
+		if err := abi.ParseTopicsIntoMap(args, indexed, txLog.Topics[1:]); err != nil {
+			gbl.Log.Debugf("❗️ error decoding %s log topics: %s", p.Name, err)
+
+			return nil
+		}
+	}
+
+	return p.Parse(txLog, args, providerPool)
+}
+
+// parseEventFragment parses the abi fragment of a single event.
+func parseEventFragment(fragment string) (*abi.Event, error) {
+	fragment = strings.TrimSpace(fragment)
+	if !strings.HasPrefix(fragment, "[") {
+		fragment = "[" + fragment + "]"
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(fragment))
+	if err != nil {
+		return nil, fmt.Errorf("parsing abi fragment failed: %w", err)
+	}
+
+	for _, event := range parsedABI.Events {
+		return &event, nil
+	}
+
+	return nil, ErrNoEvent
+}
+
+// builtinParsers returns the parsers for token transfers & marketplace orders.
+func builtinParsers() map[common.Hash]*LogParser {
+	parsers := []*LogParser{
+		{
+			Name:  "Transfer",
+			Topic: common.HexToHash(string(topic.Transfer)),
+			Standard: func(txLog *types.Log) standard.Standard {
+				if len(txLog.Topics) >= 4 {
+					return standard.ERC721
+				}
+
+				return standard.ERC20
+			},
+			Parse: func(txLog *types.Log, _ map[string]interface{}, providerPool *provider.Pool) []*TokenTransfer {
+				if len(txLog.Topics) >= 4 {
+					return []*TokenTransfer{parseERC721TransferLog(txLog)}
+				}
+
+				if transfer := parseERC20TransferLog(txLog, providerPool); transfer != nil {
+					return []*TokenTransfer{transfer}
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:  "TransferSingle",
+			Topic: common.HexToHash(string(topic.TransferSingle)),
+			Standard: func(txLog *types.Log) standard.Standard {
+				if len(txLog.Topics) >= 4 {
+					return standard.ERC1155
+				}
+
+				return standard.UNKNOWN
+			},
+			Parse: func(txLog *types.Log, _ map[string]interface{}, providerPool *provider.Pool) []*TokenTransfer {
+				if transfer := parseERC1155TransferLog(txLog, providerPool); transfer != nil {
+					return []*TokenTransfer{transfer}
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:  "TransferBatch",
+			Topic: common.HexToHash(string(topic.TransferBatch)),
+			Standard: func(txLog *types.Log) standard.Standard {
+				if len(txLog.Topics) >= 4 {
+					return standard.ERC1155
+				}
+
+				return standard.UNKNOWN
+			},
+			Parse: func(txLog *types.Log, _ map[string]interface{}, providerPool *provider.Pool) []*TokenTransfer {
+				return parseERC1155TransferBatchLog(txLog, providerPool)
+			},
+		},
+
+		// marketplace orders
+		{Name: "OrderFulfilled", Topic: common.HexToHash(string(topic.OrderFulfilled)), parseOrder: (*TokenTransaction).parseSeaportOrder},
+		{Name: "OrdersMatched", Topic: common.HexToHash(string(topic.OrdersMatched)), parseOrder: (*TokenTransaction).parseBlurOrdersMatched},
+		{Name: "Execution721Packed", Topic: common.HexToHash(string(topic.Execution721Packed)), parseOrder: parseBlurExecution721Order},
+		{Name: "Execution721TakerFeePacked", Topic: common.HexToHash(string(topic.Execution721TakerFeePacked)), parseOrder: parseBlurExecution721Order},
+		{Name: "Execution721MakerFeePacked", Topic: common.HexToHash(string(topic.Execution721MakerFeePacked)), parseOrder: parseBlurExecution721Order},
+		{
+			Name:  "TakerAsk",
+			Topic: common.HexToHash(string(topic.TakerAsk)),
+			parseOrder: func(ttx *TokenTransaction, txLog *types.Log, providerPool *provider.Pool) *marketOrder {
+				return ttx.parseLooksRareTakerOrder(txLog, "TakerAsk", providerPool)
+			},
+		},
+		{
+			Name:  "TakerBid",
+			Topic: common.HexToHash(string(topic.TakerBid)),
+			parseOrder: func(ttx *TokenTransaction, txLog *types.Log, providerPool *provider.Pool) *marketOrder {
+				return ttx.parseLooksRareTakerOrder(txLog, "TakerBid", providerPool)
+			},
+		},
+		{Name: "EvInventory", Topic: common.HexToHash(string(topic.EvInventory)), parseOrder: (*TokenTransaction).parseX2Y2Inventory},
+	}
+
+	registry := make(map[common.Hash]*LogParser, len(parsers))
+	for _, parser := range parsers {
+		registry[parser.Topic] = parser
+	}
+
+	return registry
+}
+
+func parseBlurExecution721Order(_ *TokenTransaction, txLog *types.Log, _ *provider.Pool) *marketOrder {
+	return parseBlurExecution721(txLog)
+}
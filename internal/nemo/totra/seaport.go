@@ -0,0 +1,140 @@
+package totra
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/abis"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// seaport item types.
+const (
+	seaportItemNative uint8 = iota
+	seaportItemERC20
+	seaportItemERC721
+	seaportItemERC1155
+	seaportItemERC721WithCriteria
+	seaportItemERC1155WithCriteria
+)
+
+// orderIntermediaries are the seaport deployments & the opensea conduit. nfts moved to or from them
+// are attributed to the parties of the orders.
+var orderIntermediaries = mapset.NewSet[common.Address](
+	common.HexToAddress("0x00000000006c3852cbEf3e08E8dF289169EdE581"), // seaport 1.1
+	common.HexToAddress("0x00000000000006c7676171937C444f6BDe3D6282"), // seaport 1.2
+	common.HexToAddress("0x0000000000000aD24e80fd803C6ac37206a45f15"), // seaport 1.4
+	common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"), // seaport 1.5
+	common.HexToAddress("0x0000000000000068F116a894984e2DB1123eB395"), // seaport 1.6
+	common.HexToAddress("0x1E0049783F008A0085193E00003D00cd54003c71"), // opensea conduit
+)
+
+var (
+	// the abi is parsed once, the address is irrelevant for unpacking logs
+	seaportFilterer     *abis.SeaportFilterer
+	seaportFiltererOnce sync.Once
+)
+
+// parseSeaportOrder decodes an OrderFulfilled log. returns nil if it is not an nft ↔ currency trade.
+func (ttx *TokenTransaction) parseSeaportOrder(txLog *types.Log, providerPool *provider.Pool) *marketOrder {
+	seaportFiltererOnce.Do(func() {
+		var err error
+		if seaportFilterer, err = abis.NewSeaportFilterer(common.Address{}, nil); err != nil {
+			gbl.Log.Errorf("❗️ error binding seaport abi: %s", err)
+		}
+	})
+
+	if seaportFilterer == nil {
+		return nil
+	}
+
+	orderFulfilled, err := seaportFilterer.ParseOrderFulfilled(*txLog)
+	if err != nil {
+		gbl.Log.Debugf("❗️ error parsing OrderFulfilled log of %s: %s", ttx.TxHash.Hex(), err)
+
+		return nil
+	}
+
+	return newSeaportOrder(orderFulfilled, providerPool)
+}
+
+// newSeaportOrder returns the nfts & amounts of an order or nil if it is not an nft ↔ currency trade.
+func newSeaportOrder(orderFulfilled *abis.SeaportOrderFulfilled, providerPool *provider.Pool) *marketOrder {
+	nftsOffered := make([]string, 0)
+	currencyOffered := big.NewInt(0)
+
+	for _, item := range orderFulfilled.Offer {
+		switch {
+		case isSeaportNFT(item.ItemType):
+			nftsOffered = append(nftsOffered, orderNFTID(item.Token, item.Identifier))
+		case isSeaportCurrency(item.ItemType):
+			currencyOffered.Add(currencyOffered, orderAmountWei(item.Token, item.Amount, providerPool))
+		}
+	}
+
+	nftsConsidered := make([]string, 0)
+	currencyConsidered := big.NewInt(0)
+	currencyToOfferer := big.NewInt(0)
+	payments := make(map[common.Address]*big.Int)
+
+	for _, item := range orderFulfilled.Consideration {
+		switch {
+		case isSeaportNFT(item.ItemType):
+			nftsConsidered = append(nftsConsidered, orderNFTID(item.Token, item.Identifier))
+		case isSeaportCurrency(item.ItemType):
+			amount := orderAmountWei(item.Token, item.Amount, providerPool)
+
+			currencyConsidered.Add(currencyConsidered, amount)
+
+			if item.Recipient == orderFulfilled.Offerer {
+				currencyToOfferer.Add(currencyToOfferer, amount)
+
+				continue
+			}
+
+			if payments[item.Recipient] == nil {
+				payments[item.Recipient] = big.NewInt(0)
+			}
+
+			payments[item.Recipient].Add(payments[item.Recipient], amount)
+		}
+	}
+
+	// restricted orders are validated by their zone, the nfts may be routed through it
+	intermediaries := []common.Address{orderFulfilled.Zone}
+
+	switch {
+	// listing: the offerer sells nfts, the consideration is the price incl. fees
+	case len(nftsOffered) > 0 && currencyConsidered.Sign() > 0:
+		return &marketOrder{
+			nfts: nftsOffered, amountPaid: currencyConsidered, proceeds: currencyToOfferer, payments: payments,
+			seller: orderFulfilled.Offerer, buyer: orderFulfilled.Recipient, intermediaries: intermediaries,
+		}
+
+	// (collection) offer: the offerer pays with weth, fees are paid from the offered amount
+	case len(nftsConsidered) > 0 && currencyOffered.Sign() > 0:
+		proceeds := new(big.Int).Sub(currencyOffered, currencyConsidered)
+		if proceeds.Sign() < 0 {
+			proceeds = big.NewInt(0)
+		}
+
+		return &marketOrder{
+			nfts: nftsConsidered, amountPaid: currencyOffered, proceeds: proceeds, payments: payments,
+			seller: orderFulfilled.Recipient, buyer: orderFulfilled.Offerer, intermediaries: intermediaries,
+		}
+	}
+
+	return nil
+}
+
+func isSeaportNFT(itemType uint8) bool {
+	return itemType >= seaportItemERC721 && itemType <= seaportItemERC1155WithCriteria
+}
+
+func isSeaportCurrency(itemType uint8) bool {
+	return itemType == seaportItemNative || itemType == seaportItemERC20
+}
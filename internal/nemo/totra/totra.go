@@ -1,6 +1,7 @@
 package totra
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -9,12 +10,13 @@ import (
 	"github.com/benleb/gloomberg/internal"
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/currency"
 	"github.com/benleb/gloomberg/internal/nemo/marketplace"
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/nemo/provider"
 	"github.com/benleb/gloomberg/internal/nemo/standard"
 	"github.com/benleb/gloomberg/internal/nemo/token"
-	"github.com/benleb/gloomberg/internal/nemo/topic"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
@@ -39,12 +41,23 @@ type TokenTransaction struct {
 	// the sender of the tx
 	From common.Address `json:"from"`
 
+	// chain the tx was sent on, 0 for mainnet
+	ChainID uint64 `json:"chain_id,omitempty"`
+
 	// signature of the called contract function
 	MethodID [4]byte `json:"function_signature"`
 
 	// the amount of eth/weth transferred in the tx
 	AmountPaid *big.Int `json:"amount_paid"`
 
+	// the amount paid per collection, taken from the marketplace orders of bundles. nil if there are no
+	// orders, use AmountPaidForCollection to get the (proportionally split) amount
+	AmountPaidByContract map[common.Address]*big.Int `json:"amount_paid_by_contract,omitempty"`
+
+	// amounts paid in other currencies (usdc, ape, ...) in their original denomination by token address
+	// the eth equivalent of these amounts is included in AmountPaid
+	PaymentsERC20 map[common.Address]*big.Int `json:"payments_erc20,omitempty"`
+
 	Marketplace *marketplace.MarketPlace `json:"marketplace"`
 
 	// token transfers parsed from the tx logs
@@ -61,6 +74,26 @@ type TokenTransaction struct {
 
 	DoNotPrint bool `json:"do_not_print"`
 	Highlight  bool `json:"highlight"`
+
+	// added by user plugins, shown in the output & notifications
+	Annotations []string `json:"annotations,omitempty"`
+
+	// a known mev bot is involved or a nft of the tx was flipped in the same block
+	IsMEV bool `json:"is_mev,omitempty"`
+
+	// buyer & seller are linked (same wallet, funding relationship or token sold back & forth)
+	IsWash bool `json:"is_wash,omitempty"`
+
+	// an own or watched wallet sends or receives a token (nft or erc20), set by the log handler.
+	// these txs are queued with priority & shown regardless of the filters
+	Watched bool `json:"-"`
+
+	// loan origination, repayment or liquidation of a lending protocol
+	LoanEvent *LoanEvent `json:"loan_event,omitempty"`
+
+	// currency paid by the marketplace orders to other recipients than the sellers (fees & royalties) in wei.
+	// only known for seaport orders, nil otherwise
+	OrderPayments map[common.Address]*big.Int `json:"order_payments,omitempty"`
 }
 
 // var methodSignaturesTransfers = map[[4]byte]string{
@@ -136,7 +169,16 @@ func NewTokenTransaction(tx *types.Transaction, receipt *types.Receipt, provider
 	ttx.parseERC20Transfers(providerPool)
 
 	// connect nft transfers and erc20 transfers
-	ttx.discoverItemPrices()
+	ttx.discoverItemPrices(providerPool)
+
+	// real prices & marketplace of seaport/blur orders (weth/blur pool bids, bundles, aggregators)
+	ttx.parseMarketplaceOrders(providerPool)
+
+	// accepted offers settled in weth not covered by the transfers above
+	ttx.parseWETHSettlement()
+
+	// loans, repayments & liquidations of nft lending protocols
+	ttx.parseLoans()
 
 	// action performed by the tx
 	ttx.Action = ttx.getAction()
@@ -150,8 +192,9 @@ func NewTokenTransaction(tx *types.Transaction, receipt *types.Receipt, provider
 	return ttx
 }
 
+// GetEtherscanTxURL returns the block explorer url of the tx (etherscan or the explorer of the chain).
 func (ttx *TokenTransaction) GetEtherscanTxURL() string {
-	return fmt.Sprintf("https://etherscan.io/tx/%s", ttx.TxHash)
+	return links.ChainTx(ttx.ChainID, ttx.TxHash)
 }
 
 func (ttx *TokenTransaction) GetTransferredTokenContractAdresses() mapset.Set[common.Address] {
@@ -176,6 +219,64 @@ func (ttx *TokenTransaction) GetPrice() *price.Price {
 	return price.NewPrice(ttx.AmountPaid)
 }
 
+// AmountPaidForCollection returns the part of the amount paid for the tokens of a collection. bundles of
+// several collections are split by the prices of their marketplace orders or proportionally to the number of tokens.
+func (ttx *TokenTransaction) AmountPaidForCollection(contractAddress common.Address) *big.Int {
+	if ttx.AmountPaid == nil {
+		return big.NewInt(0)
+	}
+
+	if amount, ok := ttx.AmountPaidByContract[contractAddress]; ok {
+		return amount
+	}
+
+	numCollectionTokens, numTokens := big.NewInt(0), big.NewInt(0)
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.AmountTokens == nil {
+			continue
+		}
+
+		numTokens.Add(numTokens, transfer.AmountTokens)
+
+		if transfer.Token.Address == contractAddress {
+			numCollectionTokens.Add(numCollectionTokens, transfer.AmountTokens)
+		}
+	}
+
+	if numTokens.Sign() == 0 || numCollectionTokens.Cmp(numTokens) == 0 {
+		return ttx.AmountPaid
+	}
+
+	// collections priced by orders are already accounted for
+	amountPaid := new(big.Int).Set(ttx.AmountPaid)
+
+	for orderContract, amount := range ttx.AmountPaidByContract {
+		amountPaid.Sub(amountPaid, amount)
+
+		for _, transfer := range ttx.Transfers {
+			if transfer.Token.Address == orderContract && transfer.Standard.IsERC721orERC1155() && transfer.AmountTokens != nil {
+				numTokens.Sub(numTokens, transfer.AmountTokens)
+			}
+		}
+	}
+
+	if numTokens.Sign() <= 0 || amountPaid.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+
+	return amountPaid.Div(amountPaid.Mul(amountPaid, numCollectionTokens), numTokens)
+}
+
+// PricePerItem returns the amount paid divided by the number of sold or minted tokens.
+func (ttx *TokenTransaction) PricePerItem() *price.Price {
+	if ttx.TotalTokens <= 1 {
+		return ttx.GetPrice()
+	}
+
+	return price.NewPrice(new(big.Int).Div(ttx.GetPrice().Wei(), big.NewInt(ttx.TotalTokens)))
+}
+
 func (ttx *TokenTransaction) GetNFTReceivers() map[common.Address][]*TokenTransfer {
 	nftReceivers := make(map[common.Address][]*TokenTransfer)
 
@@ -232,43 +333,58 @@ func (ttx *TokenTransaction) parseTransfersFromReceipt(providerPool *provider.Po
 	// assuming every nft is just sold once per tx
 	uniqueTransfers := make(map[string][]*TokenTransfer)
 
-	for logStandard, txLogs := range ttx.logsByStandard {
+	for _, txLogs := range ttx.logsByStandard {
 		gbl.Log.Debugf("  🧱 ttx logs to parse: %+v", len(txLogs))
 
 		for _, txLog := range txLogs {
-			// parse Transfer & TransferSingle logs
-			var transfer *TokenTransfer
-
-			switch logStandard {
-			case standard.ERC20:
-				transfer = parseERC20TransferLog(txLog, providerPool)
-
-			case standard.ERC721:
-				transfer = parseERC721TransferLog(txLog)
-
-			case standard.ERC1155:
-				transfer = parseERC1155TransferLog(txLog, providerPool)
-				// if transfer != nil {
-				// 	out := strings.Builder{}
-				// 	out.WriteString(fmt.Sprintf("from: %+v | to: %+v | amount: %+v | tokenid: %+v | addr: %+v\n", style.ShortenAddress(&transfer.OldFrom), style.ShortenAddress(transfer.To()), transfer.AmountTokens(), transfer.Token.ID, transfer.Token.Address))
-				// 	parsedLog := lopas.ParseTransferLog(txLog, ethNode)
-				// 	out.WriteString(fmt.Sprintf("from: %+v | to: %+v | amount: %+v | tokenid: %+v | addr: %+v\n\n", style.ShortenAddress(parsedLog.From()), style.ShortenAddress(parsedLog.To()), parsedLog.AmountTokens(), parsedLog.TokenID(), parsedLog.Token.Address))
-				// }
-			}
-
-			if transfer == nil {
+			// parse Transfer, TransferSingle & the logs of registered parsers
+			parser := getParser(txLog)
+			if parser == nil {
 				continue
 			}
 
-			nftID := transfer.Token.NftID()
+			for _, transfer := range parser.parseTransfers(txLog, providerPool) {
+				if transfer == nil {
+					continue
+				}
+
+				nftID := transfer.Token.NftID()
+
+				switch {
+				case len(uniqueTransfers[nftID]) == 0 || transfer.Standard.IsERC20():
+					ttx.Transfers = append(ttx.Transfers, transfer)
+
+				case transfer.Standard == standard.ERC1155:
+					// erc1155 editions can be bought from multiple sellers in one tx (partial fills of several listings)
+					if previous := mergeEditionTransfer(uniqueTransfers[nftID], transfer); previous == nil {
+						ttx.Transfers = append(ttx.Transfers, transfer)
+					}
+				}
 
-			if len(uniqueTransfers[nftID]) == 0 || transfer.Standard.IsERC20() {
-				ttx.Transfers = append(ttx.Transfers, transfer)
+				uniqueTransfers[nftID] = append(uniqueTransfers[nftID], transfer)
 			}
+		}
+	}
+}
 
-			uniqueTransfers[nftID] = append(uniqueTransfers[nftID], transfer)
+// mergeEditionTransfer adds the amount of an erc1155 transfer to a previous transfer between the same
+// addresses & returns it. hops (e.g. via a marketplace) are ignored, returns nil for transfers from other senders.
+func mergeEditionTransfer(previousTransfers []*TokenTransfer, transfer *TokenTransfer) *TokenTransfer {
+	for _, previous := range previousTransfers {
+		if previous.To == transfer.From {
+			return previous
 		}
 	}
+
+	for _, previous := range previousTransfers {
+		if previous.From == transfer.From && previous.To == transfer.To {
+			previous.AmountTokens = new(big.Int).Add(previous.AmountTokens, transfer.AmountTokens)
+
+			return previous
+		}
+	}
+
+	return nil
 }
 
 // func (ttx *TokenTransaction) parseTransfersFromReceipt(ethNode *nodes.Node) {
@@ -318,13 +434,36 @@ func (ttx *TokenTransaction) parseERC20Transfers(providerPool *provider.Pool) {
 				continue
 			}
 
+			amountWei := transfer.AmountTokens
+
+			// non-eth currencies are converted to their eth equivalent
+			// the original payment is only recorded if converted, so it matches the eth equivalent in AmountPaid
+			if paymentCurrency := currency.Get(transfer.Token.Address); paymentCurrency != nil {
+				var err error
+				if amountWei, err = paymentCurrency.ToWei(context.Background(), providerPool, transfer.AmountTokens); err != nil {
+					gbl.Log.Debugf("💱 could not convert %s to eth: %s", paymentCurrency.Format(transfer.AmountTokens), err)
+
+					continue
+				}
+
+				if ttx.PaymentsERC20 == nil {
+					ttx.PaymentsERC20 = make(map[common.Address]*big.Int)
+				}
+
+				if _, ok := ttx.PaymentsERC20[transfer.Token.Address]; !ok {
+					ttx.PaymentsERC20[transfer.Token.Address] = big.NewInt(0)
+				}
+
+				ttx.PaymentsERC20[transfer.Token.Address].Add(ttx.PaymentsERC20[transfer.Token.Address], transfer.AmountTokens)
+			}
+
 			if _, ok := ttx.sentMoney[transfer.From]; !ok {
 				ttx.sentMoney[transfer.From] = big.NewInt(0)
 			}
 
-			ttx.sentMoney[transfer.From].Add(ttx.sentMoney[transfer.From], transfer.AmountTokens)
+			ttx.sentMoney[transfer.From].Add(ttx.sentMoney[transfer.From], amountWei)
 
-			amountPaidERC20.Add(amountPaidERC20, transfer.AmountTokens)
+			amountPaidERC20.Add(amountPaidERC20, amountWei)
 		}
 
 		if transfer.Standard == standard.ERC721 || transfer.Standard == standard.ERC1155 {
@@ -341,13 +480,13 @@ func (ttx *TokenTransaction) parseERC20Transfers(providerPool *provider.Pool) {
 
 // discoverItemPrices tries to find the price of single nfts in a transaction
 // it does so by looking at the amount of money someone who sent nfts received in return.
-func (ttx *TokenTransaction) discoverItemPrices() {
+func (ttx *TokenTransaction) discoverItemPrices(providerPool *provider.Pool) {
 	for _, tokenTransfer := range ttx.Transfers {
 		if tokenTransfer.Standard.IsERC721orERC1155() {
 			for _, moneyTransfer := range ttx.Transfers {
 				if moneyTransfer.Standard == standard.ERC20 {
 					if tokenTransfer.From == moneyTransfer.To && moneyTransfer.AmountTokens.Cmp(big.NewInt(0)) > 0 {
-						tokenTransfer.AmountEtherReturned = moneyTransfer.AmountTokens
+						tokenTransfer.AmountEtherReturned = orderAmountWei(moneyTransfer.Token.Address, moneyTransfer.AmountTokens, providerPool)
 
 						// remove money transfer from list
 						moneyTransfer.AmountTokens = big.NewInt(0)
@@ -532,22 +671,32 @@ func (ttx *TokenTransaction) IsReBurn() bool {
 }
 
 func (ttx *TokenTransaction) IsBurn() bool {
-	// a burn is a costless transfer/tx that moves one or more nfts to the zero address
+	// a burn is a costless transfer/tx that moves one or more nfts to the zero or the dEaD address
 	if ttx.AmountPaid.Cmp(big.NewInt(0)) != 0 {
 		return false
 	}
 
 	receivers := ttx.GetNFTReceivers()
 
-	// there must be exactly one receiver and it must be the zero address
-	if len(receivers) != 1 || receivers[internal.ZeroAddress] == nil {
+	// there must be exactly one receiver and it must be a burn address
+	if len(receivers) != 1 {
 		return false
 	}
 
+	for receiver := range receivers {
+		if !internal.IsBurnAddress(receiver) {
+			return false
+		}
+	}
+
 	return true
 }
 
 func (ttx *TokenTransaction) IsLoan() bool {
+	if ttx.LoanEvent != nil {
+		return ttx.LoanEvent.Action == degendb.Loan
+	}
+
 	// if no nfts are moved, this is not a mint
 	if !ttx.IsMovingNFTs() {
 		return false
@@ -563,6 +712,10 @@ func (ttx *TokenTransaction) IsLoan() bool {
 }
 
 func (ttx *TokenTransaction) IsLoanPayback() bool {
+	if ttx.LoanEvent != nil {
+		return ttx.LoanEvent.Action == degendb.RepayLoan
+	}
+
 	// if no nfts are moved, this is not a mint
 	if !ttx.IsMovingNFTs() {
 		return false
@@ -577,6 +730,15 @@ func (ttx *TokenTransaction) IsLoanPayback() bool {
 	return false
 }
 
+// Aggregator returns the name of the marketplace aggregator the tx was sent to, empty if none.
+func (ttx *TokenTransaction) Aggregator() string {
+	if ttx.Tx == nil || ttx.Tx.To() == nil {
+		return ""
+	}
+
+	return marketplace.Aggregators[*ttx.Tx.To()]
+}
+
 func (ttx *TokenTransaction) IsTransfer() bool {
 	// opensea transfer helper contract
 	if ttx.Tx == nil || ttx.Tx.To() == nil || (*ttx.Tx.To() == common.HexToAddress("0x0000000000c2d145a2526bd8c716263bfebe1a72")) {
@@ -597,7 +759,7 @@ func (ttx *TokenTransaction) IsTransfer() bool {
 
 	for _, transfer := range ttx.Transfers {
 		if transfer.Standard.IsERC721orERC1155() {
-			if transfer.To == internal.ZeroAddress || transfer.From == internal.ZeroAddress {
+			if internal.IsBurnAddress(transfer.To) || transfer.From == internal.ZeroAddress {
 				return false
 			}
 		}
@@ -620,6 +782,8 @@ func (ttx *TokenTransaction) getAction() *degendb.GBEventType {
 	}
 
 	switch {
+	case ttx.LoanEvent != nil:
+		return ttx.LoanEvent.Action
 	case ttx.IsMint():
 		return degendb.Mint
 	case ttx.IsLoan():
@@ -642,28 +806,13 @@ func (ttx *TokenTransaction) getAction() *degendb.GBEventType {
 }
 
 func getTransferLogStandard(log *types.Log) standard.Standard {
-	logStandard := standard.UNKNOWN
-
-	topic0 := topic.Topic(log.Topics[0].String())
-
-	switch {
-	// erc20
-	case topic0 == topic.Transfer && len(log.Topics) <= 3:
-		logStandard = standard.ERC20
-
-	// erc721
-	case topic0 == topic.Transfer && len(log.Topics) >= 4:
-		logStandard = standard.ERC721
-
-	// erc1155
-	case topic0 == topic.TransferSingle && len(log.Topics) >= 4:
-		logStandard = standard.ERC1155
-
-	default:
-		gbl.Log.Debugf("unknown log standard | len(log.Topics): %d | topic0: %s", len(log.Topics), topic0)
+	if parser := getParser(log); parser != nil && parser.Standard != nil {
+		return parser.Standard(log)
 	}
 
-	return logStandard
+	gbl.Log.Debugf("unknown log standard | len(log.Topics): %d | topic0: %s", len(log.Topics), log.Topics[0].Hex())
+
+	return standard.UNKNOWN
 }
 
 func parseERC721TransferLog(txLog *types.Log) *TokenTransfer {
@@ -709,6 +858,47 @@ func parseERC1155TransferLog(txLog *types.Log, providerPool *provider.Pool) *Tok
 	}
 }
 
+// parseERC1155TransferBatchLog returns a transfer per token id of the batch.
+func parseERC1155TransferBatchLog(txLog *types.Log, providerPool *provider.Pool) []*TokenTransfer {
+	abiERC1155, err := providerPool.GetERC1155ABI(txLog.Address)
+	if err != nil {
+		gbl.Log.Errorf("❗️ error binding erc1155 contract abi: %s", err)
+
+		return nil
+	}
+
+	transferLog, err := abiERC1155.ParseTransferBatch(*txLog)
+	if err != nil {
+		gbl.Log.Errorf("❗️ error parsing TransferBatch log: %s", err)
+
+		return nil
+	}
+
+	if len(transferLog.Ids) != len(transferLog.Values) {
+		gbl.Log.Warnf("❗️ TransferBatch log with %d ids but %d values", len(transferLog.Ids), len(transferLog.Values))
+
+		return nil
+	}
+
+	transfers := make([]*TokenTransfer, 0, len(transferLog.Ids))
+
+	for idx, tokenID := range transferLog.Ids {
+		transfers = append(transfers, &TokenTransfer{
+			From:                transferLog.From,
+			To:                  transferLog.To,
+			AmountTokens:        transferLog.Values[idx],
+			AmountEtherReturned: big.NewInt(0),
+			Standard:            standard.ERC1155,
+			Token: &token.Token{
+				Address: transferLog.Raw.Address,
+				ID:      tokenID,
+			},
+		})
+	}
+
+	return transfers
+}
+
 func parseERC20TransferLog(txLog *types.Log, providerPool *provider.Pool) *TokenTransfer {
 	abiWETH, err := providerPool.GetWETHABI(txLog.Address)
 	if err != nil {
@@ -724,8 +914,8 @@ func parseERC20TransferLog(txLog *types.Log, providerPool *provider.Pool) *Token
 		return nil
 	}
 
-	// we only care about certain tokens like WETH and Blur Pool Token
-	if transferLog.Raw.Address != internal.WETHContractAddress && transferLog.Raw.Address != internal.BlurPoolTokenContractAddress {
+	// we only care about certain tokens like WETH, Blur Pool Token & the known payment currencies (usdc, ape, ...)
+	if transferLog.Raw.Address != internal.WETHContractAddress && transferLog.Raw.Address != internal.BlurPoolTokenContractAddress && currency.Get(transferLog.Raw.Address) == nil {
 		gbl.Log.Debugf("❗️ non-WETH ERC20 token, ignoring: %s", transferLog.Raw.Address.String())
 
 		return nil
@@ -0,0 +1,85 @@
+package totra
+
+import (
+	"math/big"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// parseWETHSettlement detects sales paid in weth (accepted offers) which are not covered by the
+// erc20 transfers from wallets, e.g. if the weth is routed through a marketplace contract. without
+// it, these sales have no value (tx.Value() == 0) & are classified as transfers.
+func (ttx *TokenTransaction) parseWETHSettlement() {
+	if ttx.TxReceipt == nil || (ttx.AmountPaid != nil && ttx.AmountPaid.Sign() != 0) || !ttx.IsMovingNFTs() {
+		return
+	}
+
+	sellers := ttx.GetNonZeroNFTSenders()
+	buyers := ttx.GetNFTReceivers()
+
+	// weth received by the sellers & sent by the buyers (incl. fees)
+	received := make(map[common.Address]*big.Int)
+	paid := big.NewInt(0)
+
+	for _, txLog := range ttx.TxReceipt.Logs {
+		if txLog.Address != internal.WETHContractAddress || len(txLog.Topics) != 3 || len(txLog.Data) != 32 {
+			continue
+		}
+
+		if topic.Topic(txLog.Topics[0].Hex()) != topic.Transfer {
+			continue
+		}
+
+		from := common.BytesToAddress(txLog.Topics[1].Bytes())
+		to := common.BytesToAddress(txLog.Topics[2].Bytes())
+		amount := new(big.Int).SetBytes(txLog.Data)
+
+		if _, ok := buyers[from]; ok && from != to {
+			paid.Add(paid, amount)
+		}
+
+		// weth moved between sellers is no payment
+		if _, ok := sellers[from]; ok {
+			continue
+		}
+
+		if _, ok := sellers[to]; ok {
+			if _, ok := received[to]; !ok {
+				received[to] = big.NewInt(0)
+			}
+
+			received[to].Add(received[to], amount)
+		}
+	}
+
+	if len(received) == 0 {
+		return
+	}
+
+	totalReceived := big.NewInt(0)
+
+	for seller, amount := range received {
+		totalReceived.Add(totalReceived, amount)
+
+		// split evenly over the nfts of the seller without a price yet
+		perNFT := new(big.Int).Div(amount, big.NewInt(int64(len(sellers[seller]))))
+
+		for _, transfer := range sellers[seller] {
+			if transfer.AmountEtherReturned == nil || transfer.AmountEtherReturned.Sign() == 0 {
+				transfer.AmountEtherReturned = perNFT
+			}
+		}
+	}
+
+	// the buyers payment includes the fees, the sellers proceeds are the fallback if the weth
+	// was pulled from somewhere else (e.g. a marketplace pool)
+	ttx.AmountPaid = totalReceived
+	if paid.Cmp(totalReceived) > 0 {
+		ttx.AmountPaid = paid
+	}
+
+	gbl.Log.Debugf("🤝 %s settled in weth: %s wei", ttx.TxHash.Hex(), ttx.AmountPaid)
+}
@@ -0,0 +1,206 @@
+package totra
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/marketplace"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// x2y2EventsABI contains the EvInventory event of the x2y2 exchange & the encodings of the traded items.
+const x2y2EventsABI = `[
+	{"anonymous":false,"name":"EvInventory","type":"event","inputs":[
+		{"indexed":true,"name":"itemHash","type":"bytes32"},
+		{"indexed":false,"name":"maker","type":"address"},
+		{"indexed":false,"name":"taker","type":"address"},
+		{"indexed":false,"name":"orderSalt","type":"uint256"},
+		{"indexed":false,"name":"settleSalt","type":"uint256"},
+		{"indexed":false,"name":"intent","type":"uint256"},
+		{"indexed":false,"name":"delegateType","type":"uint256"},
+		{"indexed":false,"name":"deadline","type":"uint256"},
+		{"indexed":false,"name":"currency","type":"address"},
+		{"indexed":false,"name":"dataMask","type":"bytes"},
+		{"indexed":false,"name":"item","type":"tuple","components":[{"name":"price","type":"uint256"},{"name":"data","type":"bytes"}]},
+		{"indexed":false,"name":"detail","type":"tuple","components":[
+			{"name":"op","type":"uint8"},
+			{"name":"orderIdx","type":"uint256"},
+			{"name":"itemIdx","type":"uint256"},
+			{"name":"price","type":"uint256"},
+			{"name":"itemHash","type":"bytes32"},
+			{"name":"executionDelegate","type":"address"},
+			{"name":"dataReplacement","type":"bytes"},
+			{"name":"bidIncentivePct","type":"uint256"},
+			{"name":"aucMinIncrementPct","type":"uint256"},
+			{"name":"aucIncDurationSecs","type":"uint256"},
+			{"name":"fees","type":"tuple[]","components":[{"name":"percentage","type":"uint256"},{"name":"to","type":"address"}]}
+		]}
+	]},
+	{"name":"pairs721","type":"function","inputs":[
+		{"name":"pairs","type":"tuple[]","components":[{"name":"token","type":"address"},{"name":"tokenId","type":"uint256"}]}
+	]},
+	{"name":"pairs1155","type":"function","inputs":[
+		{"name":"pairs","type":"tuple[]","components":[{"name":"token","type":"address"},{"name":"tokenId","type":"uint256"},{"name":"amount","type":"uint256"}]}
+	]}
+]`
+
+// x2y2 fee percentages are given in 1/1_000_000.
+var x2y2FeeBase = big.NewInt(1_000_000)
+
+type x2y2EvInventory struct {
+	Maker        common.Address
+	Taker        common.Address
+	OrderSalt    *big.Int
+	SettleSalt   *big.Int
+	Intent       *big.Int
+	DelegateType *big.Int
+	Deadline     *big.Int
+	Currency     common.Address
+	DataMask     []byte
+	Item         struct {
+		Price *big.Int
+		Data  []byte
+	}
+	Detail struct {
+		Op                 uint8
+		OrderIdx           *big.Int
+		ItemIdx            *big.Int
+		Price              *big.Int
+		ItemHash           [32]byte
+		ExecutionDelegate  common.Address
+		DataReplacement    []byte
+		BidIncentivePct    *big.Int
+		AucMinIncrementPct *big.Int
+		AucIncDurationSecs *big.Int
+		Fees               []struct {
+			Percentage *big.Int
+			To         common.Address
+		}
+	}
+}
+
+type x2y2Pair struct {
+	Token   common.Address
+	TokenID *big.Int `abi:"tokenId"`
+}
+
+var (
+	// the abi is parsed once
+	x2y2ABI     abi.ABI
+	x2y2ABIErr  error
+	x2y2ABIOnce sync.Once
+)
+
+// parseX2Y2Inventory decodes an EvInventory log of the x2y2 exchange. the price is paid in eth or weth,
+// the fees (royalties & marketplace fee) are deducted from the proceeds of the seller.
+func (ttx *TokenTransaction) parseX2Y2Inventory(txLog *types.Log, providerPool *provider.Pool) *marketOrder {
+	if !marketplace.X2Y2.ContractAddresses.Contains(txLog.Address) {
+		return nil
+	}
+
+	x2y2ABIOnce.Do(func() {
+		if x2y2ABI, x2y2ABIErr = abi.JSON(strings.NewReader(x2y2EventsABI)); x2y2ABIErr != nil {
+			gbl.Log.Errorf("❗️ error parsing x2y2 abi: %s", x2y2ABIErr)
+		}
+	})
+
+	if x2y2ABIErr != nil {
+		return nil
+	}
+
+	var inventory x2y2EvInventory
+	if err := x2y2ABI.UnpackIntoInterface(&inventory, "EvInventory", txLog.Data); err != nil {
+		gbl.Log.Debugf("❗️ error parsing EvInventory log of %s: %s", ttx.TxHash.Hex(), err)
+
+		return nil
+	}
+
+	if inventory.Detail.Price == nil {
+		return nil
+	}
+
+	nfts := make([]string, 0)
+	for _, pair := range x2y2Pairs(inventory.Item.Data, inventory.DataMask, inventory.Detail.DataReplacement) {
+		nfts = append(nfts, orderNFTID(pair.Token, pair.TokenID))
+	}
+
+	if len(nfts) == 0 {
+		return nil
+	}
+
+	feePercentage := big.NewInt(0)
+	for _, fee := range inventory.Detail.Fees {
+		feePercentage.Add(feePercentage, fee.Percentage)
+	}
+
+	amountPaid := orderAmountWei(inventory.Currency, inventory.Detail.Price, providerPool)
+	fees := new(big.Int).Div(new(big.Int).Mul(amountPaid, feePercentage), x2y2FeeBase)
+
+	return &marketOrder{
+		marketplace: &marketplace.X2Y2,
+		nfts:        nfts,
+		amountPaid:  amountPaid,
+		proceeds:    new(big.Int).Sub(amountPaid, fees),
+	}
+}
+
+// x2y2Pairs returns the traded tokens encoded in the item data. the masked bytes of the data are
+// replaced by the taker (e.g. the token id for collection offers).
+func x2y2Pairs(data []byte, dataMask []byte, dataReplacement []byte) []x2y2Pair {
+	if len(dataReplacement) > 0 && len(dataReplacement) == len(data) && len(dataMask) == len(data) {
+		replaced := make([]byte, len(data))
+		for i := range data {
+			replaced[i] = data[i]&^dataMask[i] | dataReplacement[i]&dataMask[i]
+		}
+
+		data = replaced
+	}
+
+	if len(data) < 64 {
+		return nil
+	}
+
+	// erc721 pairs are (token, tokenId), erc1155 pairs (token, tokenId, amount)
+	numPairs := new(big.Int).SetBytes(data[32:64]).Uint64()
+
+	method := "pairs721"
+	if numPairs > 0 && uint64(len(data)-64) == numPairs*3*32 {
+		method = "pairs1155"
+	}
+
+	values, err := x2y2ABI.Methods[method].Inputs.Unpack(data)
+	if err != nil || len(values) != 1 {
+		return nil
+	}
+
+	if method == "pairs1155" {
+		var pairs []struct {
+			Token   common.Address
+			TokenID *big.Int `abi:"tokenId"`
+			Amount  *big.Int
+		}
+
+		if err := x2y2ABI.Methods[method].Inputs.Copy(&pairs, values); err != nil {
+			return nil
+		}
+
+		pairs721 := make([]x2y2Pair, 0, len(pairs))
+		for _, pair := range pairs {
+			pairs721 = append(pairs721, x2y2Pair{Token: pair.Token, TokenID: pair.TokenID})
+		}
+
+		return pairs721
+	}
+
+	var pairs []x2y2Pair
+	if err := x2y2ABI.Methods[method].Inputs.Copy(&pairs, values); err != nil {
+		return nil
+	}
+
+	return pairs
+}
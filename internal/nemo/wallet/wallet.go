@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/benleb/gloomberg/internal/nemo/token"
 	"github.com/charmbracelet/lipgloss"
@@ -16,10 +17,13 @@ type Wallet struct {
 	ENS           *ens.Name      `mapstructure:"ens"`
 	ENSName       string         `mapstructure:"ens_name"`
 	Color         lipgloss.Color `mapstructure:"color"`
+	Group         string         `mapstructure:"group"`
 	Balance       *big.Int
 	BalanceBefore *big.Int
 	BalanceTrend  string
-	Tokens        map[common.Address]map[string]*token.Token
+	// time of the last successful balance update
+	BalanceUpdatedAt time.Time
+	Tokens           map[common.Address]map[string]*token.Token
 }
 
 func (w *Wallet) ColoredName(maxWalletNameLength int) string {
@@ -61,6 +61,28 @@ func (ws *Wallets) SortByBalance() []*Wallet {
 	return slice
 }
 
+// Groups returns the wallets by group & the sorted group names. wallets without a group are not included.
+func (ws *Wallets) Groups() (map[string][]*Wallet, []string) {
+	groups := make(map[string][]*Wallet)
+	names := make([]string, 0)
+
+	for _, w := range ws.SortByBalance() {
+		if w.Group == "" {
+			continue
+		}
+
+		if _, ok := groups[w.Group]; !ok {
+			names = append(names, w.Group)
+		}
+
+		groups[w.Group] = append(groups[w.Group], w)
+	}
+
+	sort.Strings(names)
+
+	return groups, names
+}
+
 func (ws *Wallets) ContainsToken(tokenAddress common.Address, tokenID string) bool {
 	if ws == nil {
 		return false
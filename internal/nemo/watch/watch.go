@@ -30,7 +30,8 @@ type Watcher struct {
 }
 
 type ChatID struct {
-	ChatID int64 `mapstructure:"chat_id"`
+	ChatID   int64  `mapstructure:"chat_id"`
+	Language string `mapstructure:"language"`
 }
 
 type WUser struct {
@@ -48,6 +49,7 @@ type WGroup struct {
 	TelegramChatID    int64            `mapstructure:"telegram_chat_id"`
 	ReplyToMessageID  int              `mapstructure:"telegram_reply_to_message_id"`
 	AdditionalChatIDs []*ChatID        `mapstructure:"telegram_chat_ids"`
+	Language          string           `mapstructure:"language"`
 	Users             []*WUser         `mapstructure:"users"`
 	Wallets           []*wallet.Wallet `mapstructure:"wallets"`
 
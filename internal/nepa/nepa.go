@@ -1,15 +1,22 @@
 package nepa
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/blendwatch"
+	"github.com/benleb/gloomberg/internal/chains"
 	"github.com/benleb/gloomberg/internal/chawago"
 	chawagoModels "github.com/benleb/gloomberg/internal/chawago/models"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/enswatch"
+	"github.com/benleb/gloomberg/internal/freezewatch"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/proxywatch"
 	"github.com/benleb/gloomberg/internal/pusu"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/charmbracelet/log"
@@ -31,10 +38,11 @@ type (
 		Transactions           chan *types.Transaction
 		QueueTokenTransactions chan *totra.TokenTransaction
 
-		gb *gloomberg.Gloomberg
+		// workers fetching the txs of received logs & handling them, waited for on shutdown
+		fetchers sync.WaitGroup
+		handlers sync.WaitGroup
 
-		knownTransactions   map[common.Hash]bool
-		knownTransactionsMu *sync.RWMutex
+		gb *gloomberg.Gloomberg
 	}
 )
 
@@ -49,69 +57,193 @@ func NewNePa(gb *gloomberg.Gloomberg) *NePa {
 		// QueueTokenTransactions: queueTokenTransactions,
 		QueueTokenTransactions: gb.In.TokenTransactions,
 
-		knownTransactions:   make(map[common.Hash]bool),
-		knownTransactionsMu: &sync.RWMutex{},
-
 		gb: gb,
 	}
 
 	return np
 }
 
-func (np *NePa) Run() {
+// Run subscribes to the chain and/or redis & handles the received events until the context is canceled.
+// it then unsubscribes, handles the events still queued & returns.
+func (np *NePa) Run(ctx context.Context) {
+	// the handlers run until the fetchers handled their queued logs
+	handlersCtx, stopHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopHandlers()
+
+	// in client mode the events are received from a remote gloomberg server
+	if viper.GetBool("chain.enabled") {
+		np.subscribeToChain(ctx, handlersCtx)
+	}
+
+	//
+	// subscribe via redis pubsub
+	if viper.GetBool("pubsub.sales.subscribe") {
+		gbl.Log.Infof("🚇 subscribing to sales via redis on channel %s", internal.PubSubChannelSales)
+
+		for workerID := 1; workerID <= viper.GetInt("server.workers.subscription_logs"); workerID++ {
+			np.fetchers.Add(1)
+
+			go func() {
+				defer np.fetchers.Done()
+
+				pusu.SubscribeToSales(ctx, np.gb, internal.PubSubChannelSales, np.QueueTokenTransactions)
+			}()
+		}
+	}
+
+	<-ctx.Done()
+
+	gbl.Log.Info("🧱 unsubscribed, handling the queued events...")
+
+	np.fetchers.Wait()
+
+	stopHandlers()
+
+	np.handlers.Wait()
+
+	gbl.Log.Info("🧱 queued events handled")
+}
+
+// subscribeToChain subscribes to the logs via websocket/rpc & starts the handlers for the received transactions.
+func (np *NePa) subscribeToChain(ctx context.Context, handlersCtx context.Context) {
 	newLogs := make(chan types.Log, 10240)
 
+	// events of the watched collections not moving tokens (proxy upgrades, pauses, ...)
+	np.gb.ProviderPool.WatchedCollections = np.watchedCollections
+
 	//
 	// subscribe via websocket/rpc
-	subscribedTo, err := np.gb.ProviderPool.Subscribe(newLogs)
+	subscribedTo, err := np.gb.ProviderPool.Subscribe(ctx, newLogs)
 	if err != nil {
 		gbl.Log.Fatalf("❌ subscribing to logs failed: %s", err)
 
 		return
 	}
 
-	np.newTransactions = chawago.GetTransactionsForLogs(np.gb, newLogs)
+	np.newTransactions = chawago.GetTransactionsForLogs(ctx, np.gb, chawago.WatchReorgs(np.gb, newLogs), &np.fetchers)
 
 	// handle received transactions
 	qTxsWithLogs := np.gb.SubscribeTxWithLogs()
 	for workerID := 1; workerID <= viper.GetInt("server.workers.newLogHandler"); workerID++ {
-		go np.newLogHandler(qTxsWithLogs)
+		np.handlers.Add(1)
+
+		go np.newLogHandler(handlersCtx, qTxsWithLogs)
 	}
 
 	gbl.Log.Debugf("✍️ subscribed to logs via %d nodes", subscribedTo)
 
-	//
-	// subscribe via redis pubsub
-	if viper.GetBool("pubsub.sales.subscribe") {
-		gbl.Log.Infof("🚇 subscribing to sales via redis on channel %s", internal.PubSubChannelSales)
+	// additional chains (l2s), their txs are handled by the same handlers tagged with the chain id
+	for chainID, pool := range np.gb.ChainPools {
+		chainLogs := make(chan types.Log, 10240)
 
-		for workerID := 1; workerID <= viper.GetInt("server.workers.subscription_logs"); workerID++ {
-			go pusu.SubscribeToSales(np.gb, internal.PubSubChannelSales, np.QueueTokenTransactions)
+		subscribedTo, err := pool.Subscribe(ctx, chainLogs)
+		if err != nil {
+			gbl.Log.Errorf("❌ subscribing to logs on %s failed: %s", chains.ByID(chainID).Name, err)
+
+			continue
 		}
-	}
 
-	select {}
+		chawago.GetTransactionsForChainLogs(ctx, np.gb, chainID, chainLogs, &np.fetchers)
+
+		gbl.Log.Infof("✍️ subscribed to %s logs via %d nodes", chains.ByID(chainID).Name, subscribedTo)
+	}
 }
 
 // newLogHandler handles new logs from an ethNode and fetches the complete tx for it.
-func (np *NePa) newLogHandler(qTxsWithLogs chan *chawagoModels.TxWithLogs) {
+// if the context is canceled, the txs still queued are handled before it returns.
+func (np *NePa) newLogHandler(ctx context.Context, qTxsWithLogs chan *chawagoModels.TxWithLogs) {
+	defer np.handlers.Done()
+
 	gbl.Log.Debugf("🧱 starting newLogHandler")
 
-	// for tx := range np.newTransactions {
-	for tx := range qTxsWithLogs {
-		log.Debugf("📝 %s", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), tx.Hash().String()))
+	for {
+		select {
+		case <-ctx.Done():
+			chawago.Drain(qTxsWithLogs, np.handleTxWithLogs)
 
-		//
-		// create a TokenTransaction
-		if ttx := totra.NewTokenTransaction(tx.Transaction, tx.Receipt, np.gb.ProviderPool); ttx != nil && ttx.IsMovingNFTs() {
-			np.QueueTokenTransactions <- ttx
+			return
 
-			// publish ttx via redis
-			if viper.GetBool("pubsub.sales.publish") {
-				go pusu.Publish(np.gb, internal.PubSubChannelSales, ttx)
+		case tx, ok := <-qTxsWithLogs:
+			if !ok {
+				return
 			}
+
+			np.handleTxWithLogs(tx)
+		}
+	}
+}
+
+// handleTxWithLogs creates the token transaction of a tx & queues it for the printer.
+func (np *NePa) handleTxWithLogs(tx *chawagoModels.TxWithLogs) {
+	log.Debugf("📝 %s", style.TerminalLink("https://etherscan.io/tx/"+tx.Hash().String(), tx.Hash().String()))
+
+	// mainnet only (tbas, proxies & ens are looked up via the mainnet pool)
+	if chains.IsMainnet(tx.ChainID) {
+		// erc-6551 token bound account creations
+		np.gb.RegisterTokenboundAccounts(tx.Receipt)
+
+		// implementation/admin changes of watched proxy contracts
+		proxywatch.HandleReceipt(np.gb, tx.Receipt)
+
+		// primary ens name changes of own & watched wallets
+		enswatch.HandleReceipt(np.gb, tx.Receipt)
+
+		// blend auctions & seizures of collateral from own collections
+		blendwatch.HandleReceipt(np.gb, tx.Receipt)
+
+		// paused transfers of watched collections
+		freezewatch.HandleReceipt(np.gb, tx.Receipt)
+	}
+
+	//
+	// create a TokenTransaction
+	if ttx := totra.NewTokenTransaction(tx.Transaction, tx.Receipt, np.gb.PoolFor(tx.ChainID)); ttx != nil && ttx.IsMovingNFTs() {
+		ttx.ChainID = tx.ChainID
+
+		// own & watched wallets bypass the queue of all other txs
+		if np.isWatched(ttx) {
+			ttx.Watched = true
+
+			np.gb.In.PriorityTokenTransactions <- ttx
+		} else {
+			np.QueueTokenTransactions <- ttx
 		}
 
-		np.gb.ProviderPool.LastLogReceivedAt = time.Now()
+		// publish ttx via redis
+		if viper.GetBool("pubsub.sales.publish") {
+			go pusu.Publish(np.gb, internal.PubSubChannelSales, ttx)
+		}
+	}
+
+	np.gb.ProviderPool.LastLogReceivedAt = time.Now()
+}
+
+// isWatched returns true if an own or watched wallet sends or receives a token (nft or erc20) in the tx.
+func (np *NePa) isWatched(ttx *totra.TokenTransaction) bool {
+	addresses := make([]common.Address, 0, len(ttx.Transfers)*2)
+	for _, transfer := range ttx.Transfers {
+		addresses = append(addresses, transfer.From, transfer.To)
+	}
+
+	if np.gb.OwnWallets != nil && np.gb.OwnWallets.ContainsAddressFromSlice(addresses) != internal.ZeroAddress {
+		return true
 	}
+
+	return np.gb.Watcher != nil && np.gb.Watcher.ContainsAddressFromSlice(addresses) != internal.ZeroAddress
+}
+
+// watchedCollections returns the addresses of the collections from the own wallets & the config.
+func (np *NePa) watchedCollections() []common.Address {
+	np.gb.CollectionDB.RWMu.RLock()
+	defer np.gb.CollectionDB.RWMu.RUnlock()
+
+	addresses := make([]common.Address, 0)
+
+	for address, collection := range np.gb.CollectionDB.Collections {
+		if collection != nil && (collection.Source == degendb.FromWallet || collection.Source == degendb.FromConfiguration) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
 }
@@ -0,0 +1,113 @@
+package notes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var (
+	// notes attached to wallets & collections by their address
+	notes   = make(map[common.Address]string)
+	notesMu sync.RWMutex
+)
+
+// Enabled returns true if notes should be loaded & shown.
+func Enabled() bool {
+	return viper.GetBool("notes.enabled") && viper.GetString("mongodb.uri") != ""
+}
+
+// ShowInTerminal returns true if notes should be appended to the terminal lines (verbose mode only).
+func ShowInTerminal() bool {
+	return viper.GetBool("log.verbose") && viper.GetBool("notes.terminal")
+}
+
+// Start loads the notes from degendb & reloads them every notes.refresh to pick up notes
+// added with 'gloomberg note add' while running.
+func Start() {
+	ddb := degendb.NewDegenDB()
+	if ddb == nil {
+		return
+	}
+
+	for {
+		Load(ddb)
+
+		time.Sleep(viper.GetDuration("notes.refresh"))
+	}
+}
+
+// Load replaces the cached notes with the notes stored in degendb.
+func Load(ddb *degendb.DegenDB) {
+	storedNotes, err := ddb.Notes(context.Background())
+	if err != nil {
+		gbl.Log.Warnf("❌ loading notes from degendb failed: %s", err)
+
+		return
+	}
+
+	loaded := make(map[common.Address]string, len(storedNotes))
+	for _, note := range storedNotes {
+		loaded[note.Address()] = note.Text
+	}
+
+	notesMu.Lock()
+	notes = loaded
+	notesMu.Unlock()
+
+	gbl.Log.Debugf("📝 %d notes loaded", len(loaded))
+}
+
+// Get returns the note attached to the given wallet or collection.
+func Get(address common.Address) string {
+	notesMu.RLock()
+	defer notesMu.RUnlock()
+
+	return notes[address]
+}
+
+// Label returns the notes of the collections & wallets involved in a transaction.
+func Label(ttx *totra.TokenTransaction) string {
+	notesMu.RLock()
+	defer notesMu.RUnlock()
+
+	if len(notes) == 0 {
+		return ""
+	}
+
+	seen := make(map[common.Address]bool)
+	label := ""
+
+	addNote := func(address common.Address) {
+		if seen[address] {
+			return
+		}
+
+		seen[address] = true
+
+		if note, ok := notes[address]; ok {
+			if label != "" {
+				label += " · "
+			}
+
+			label += "📝 " + note
+		}
+	}
+
+	for _, transfer := range ttx.Transfers {
+		if transfer.Token != nil {
+			addNote(transfer.Token.Address)
+		}
+
+		addNote(transfer.From)
+		addNote(transfer.To)
+	}
+
+	return label
+}
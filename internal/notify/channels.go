@@ -0,0 +1,474 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/collections"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/nemo/watch"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+// default templates of the channels without markdown support.
+const (
+	defaultDiscordTemplate = `{{.Icon}} **{{.User}}** {{.Action}} {{.Amount}}**{{.Token}}** {{.For}} **{{.Price}}Ξ**{{if .PricePerItem}} (@{{.PricePerItem}}Ξ){{end}}{{if .AcquisitionContext}}
+_{{.AcquisitionContext}}_{{end}}{{range .Annotations}}
+_{{.}}_{{end}}
+{{.Wallet}} | <{{.TxURL}}>`
+
+	defaultWebhookTemplate = `{{.Icon}} {{.User}} {{.Action}} {{.Amount}}{{.Token}} {{.For}} {{.Price}}Ξ{{if .PricePerItem}} (@{{.PricePerItem}}Ξ){{end}}`
+)
+
+var notificationsSentCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_notifications_sent_total",
+	Help: "The number of notifications by channel & result (sent, duplicate or failed).",
+}, []string{"channel", "result"})
+
+// Notification is a single event fanned out to all enabled channels.
+type Notification struct {
+	// shared by all channels, every channel target gets the notification at most once per key
+	IdempotencyKey string
+
+	User     *watch.WUser
+	ImageURI string
+
+	messages []*notificationMessage
+}
+
+// notificationMessage is a single transfer of the event that triggered the notification.
+type notificationMessage struct {
+	ttx            *totra.TokenTransaction
+	transfer       *totra.TokenTransfer
+	collection     *collections.Collection
+	userName       string
+	triggerAddress common.Address
+}
+
+// TemplateData is available in the channel templates, texts are localized to the channel language.
+type TemplateData struct {
+	Icon   string
+	User   string
+	Action string
+	For    string
+
+	// e.g. "5x " or "5/20x " (partial fill of a listing) for erc1155 tokens, empty otherwise
+	Amount string
+
+	Collection string
+	TokenID    string
+	Token      string
+	Price      string
+
+	// price per edition if more than one erc1155 token was transferred
+	PricePerItem string
+
+	Wallet             string
+	AcquisitionContext string
+	Annotations        []string
+
+	TxHash     string
+	TxURL      string
+	OpenSeaURL string
+	BlurURL    string
+
+	// item on the preferred marketplace (links.marketplace)
+	MarketplaceName string
+	MarketplaceURL  string
+}
+
+// Channel delivers notifications to its targets (chats, webhooks, ...).
+type Channel interface {
+	Name() string
+	Send(gb *gloomberg.Gloomberg, notification *Notification)
+}
+
+// enabledChannels returns the notification channels enabled in the config.
+func enabledChannels() []Channel {
+	channels := make([]Channel, 0)
+
+	if viper.GetBool("notifications.telegram.enabled") {
+		channels = append(channels, &telegramChannel{template: parseTemplate("telegram", "")})
+	}
+
+	if viper.GetBool("notifications.discord.enabled") && viper.GetString("notifications.discord.webhook_url") != "" {
+		channels = append(channels, &discordChannel{
+			webhookURL: viper.GetString("notifications.discord.webhook_url"),
+			template:   parseTemplate("discord", defaultDiscordTemplate),
+		})
+	}
+
+	if viper.GetBool("notifications.webhook.enabled") && viper.GetString("notifications.webhook.url") != "" {
+		channels = append(channels, &webhookChannel{
+			url:      viper.GetString("notifications.webhook.url"),
+			template: parseTemplate("webhook", defaultWebhookTemplate),
+		})
+	}
+
+	return channels
+}
+
+// Enabled returns true if at least one notification channel is enabled.
+func Enabled() bool {
+	return len(enabledChannels()) > 0
+}
+
+var (
+	templates   = make(map[string]*template.Template)
+	templatesMu = &sync.Mutex{}
+)
+
+// parseTemplate returns the template from notifications.<channel>.template or the fallback, nil if both are empty.
+func parseTemplate(channel string, fallback string) *template.Template {
+	text := viper.GetString("notifications." + channel + ".template")
+	if text == "" {
+		text = fallback
+	}
+
+	if text == "" {
+		return nil
+	}
+
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+
+	if tmpl, ok := templates[channel+text]; ok {
+		return tmpl
+	}
+
+	tmpl, err := template.New(channel).Parse(text)
+	if err != nil {
+		gbl.Log.Errorf("❌ invalid %s notification template: %s", channel, err)
+
+		return nil
+	}
+
+	templates[channel+text] = tmpl
+
+	return tmpl
+}
+
+// render renders all messages of the notification with the template or, if there is none, the markdown builder.
+func (n *Notification) render(tmpl *template.Template, lang Language) string {
+	rendered := make([]string, 0, len(n.messages))
+
+	for _, msg := range n.messages {
+		if tmpl == nil {
+			msgMarkdown := buildNotificationMessage(msg.ttx, msg.transfer, msg.collection, msg.userName, msg.triggerAddress, lang)
+			rendered = append(rendered, msgMarkdown.String())
+
+			continue
+		}
+
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, msg.templateData(lang)); err != nil {
+			gbl.Log.Warnf("❌ rendering %s notification template failed: %s", tmpl.Name(), err)
+
+			continue
+		}
+
+		rendered = append(rendered, out.String())
+	}
+
+	return strings.Join(rendered, "\n")
+}
+
+func (m *notificationMessage) templateData(lang Language) *TemplateData {
+	tokenID := int64(0)
+	if m.transfer.Token.ID != nil {
+		tokenID = m.transfer.Token.ID.Int64()
+	}
+
+	etherscanURL, openseaURL, blurURL := utils.GetLinks(m.ttx.TxHash, m.transfer.Token.Address, tokenID)
+
+	action := m.ttx.Action
+	if action == degendb.Sale && m.transfer.To == m.triggerAddress {
+		action = degendb.Purchase
+	}
+
+	tokenPrice := m.ttx.GetPrice()
+	if m.transfer.AmountEtherReturned != nil && m.transfer.AmountEtherReturned.Sign() > 0 {
+		tokenPrice = price.NewPrice(m.transfer.AmountEtherReturned)
+	}
+
+	data := &TemplateData{
+		Icon:        action.Icon(),
+		User:        m.userName,
+		Action:      lang.Action(action),
+		For:         lang.T("for", "for"),
+		Collection:  m.collection.Name,
+		TokenID:     strconv.FormatInt(tokenID, 10),
+		Token:       fmt.Sprintf("%s #%d", m.collection.Name, tokenID),
+		Price:       lang.Sprintf("%.3f", tokenPrice.Ether()),
+		Wallet:      style.ShortenAdressPTR(&m.triggerAddress),
+		Annotations: m.ttx.Annotations,
+		TxHash:      m.ttx.TxHash.Hex(),
+		TxURL:       etherscanURL,
+		OpenSeaURL:  openseaURL,
+		BlurURL:     blurURL,
+
+		MarketplaceName: links.Preferred().Name(),
+		MarketplaceURL:  links.ItemOn(links.Preferred(), m.transfer.Token.Address, big.NewInt(tokenID)),
+	}
+
+	if quantity := m.transfer.FormatQuantity(); quantity != "" {
+		data.Amount = quantity + "x "
+	}
+
+	if m.transfer.AmountTokens != nil && m.transfer.AmountTokens.Int64() > 1 {
+		data.PricePerItem = lang.Sprintf("%.3f", tokenPrice.Ether()/float64(m.transfer.AmountTokens.Int64()))
+	}
+
+	if m.transfer.From == m.triggerAddress {
+		data.AcquisitionContext = m.transfer.AcquisitionContext
+	}
+
+	return data
+}
+
+// deliverOnce sends to a channel target only if it was not sent there already, by this or
+// another gloomberg instance sharing the same redis. failed sends are released to allow retries.
+func deliverOnce(gb *gloomberg.Gloomberg, notification *Notification, channel string, target string, send func() error) {
+	identifier := strings.Join([]string{"notification", notification.IdempotencyKey, channel, target}, ":")
+
+	claimed, err := claimNotification(gb, identifier)
+	if err != nil {
+		gbl.Log.Warnf("❌ claiming notification %s failed, sending anyway: %s", identifier, err)
+	}
+
+	if !claimed && err == nil {
+		gbl.Log.Infof("🔒 notification %s already sent", identifier)
+		notificationsSentCounter.WithLabelValues(channel, "duplicate").Inc()
+
+		return
+	}
+
+	if err := send(); err != nil {
+		gbl.Log.Warnf("❌ sending %s notification to %s failed: %s", channel, target, err)
+		notificationsSentCounter.WithLabelValues(channel, "failed").Inc()
+
+		releaseNotification(gb, identifier)
+
+		return
+	}
+
+	notificationsSentCounter.WithLabelValues(channel, "sent").Inc()
+}
+
+// locally sent notifications if redis is not available.
+var (
+	sentLocally   = make(map[string]time.Time)
+	sentLocallyMu = &sync.Mutex{}
+)
+
+func claimNotification(gb *gloomberg.Gloomberg, identifier string) (bool, error) {
+	ttl := viper.GetDuration("cache.notifications_lock_ttl")
+
+	if viper.GetBool("redis.enabled") && gb.Rueidi != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		return gb.Rueidi.ClaimOnce(ctx, identifier, ttl)
+	}
+
+	sentLocallyMu.Lock()
+	defer sentLocallyMu.Unlock()
+
+	for id, sentAt := range sentLocally {
+		if time.Since(sentAt) > ttl {
+			delete(sentLocally, id)
+		}
+	}
+
+	if _, ok := sentLocally[identifier]; ok {
+		return false, nil
+	}
+
+	sentLocally[identifier] = time.Now()
+
+	return true, nil
+}
+
+func releaseNotification(gb *gloomberg.Gloomberg, identifier string) {
+	if viper.GetBool("redis.enabled") && gb.Rueidi != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		if err := gb.Rueidi.ReleaseClaim(ctx, identifier); err != nil {
+			gbl.Log.Warnf("❌ releasing notification claim %s failed: %s", identifier, err)
+		}
+
+		return
+	}
+
+	sentLocallyMu.Lock()
+	delete(sentLocally, identifier)
+	sentLocallyMu.Unlock()
+}
+
+//
+// telegram
+
+type telegramChannel struct {
+	// optional, the markdown message is used if not set
+	template *template.Template
+}
+
+func (c *telegramChannel) Name() string { return "telegram" }
+
+// Send sends the notification to the chat of the users group & the additional chats in their languages.
+func (c *telegramChannel) Send(gb *gloomberg.Gloomberg, notification *Notification) {
+	user := notification.User
+
+	chatID := viper.GetInt64("notifications.telegram.chat_id")
+
+	var replyToMessageID int
+
+	if user.Group != nil && user.Group.TelegramChatID != 0 {
+		chatID = user.Group.TelegramChatID
+		replyToMessageID = user.Group.ReplyToMessageID
+	}
+
+	var groupLanguage string
+	if user.Group != nil {
+		groupLanguage = user.Group.Language
+	}
+
+	deliverOnce(gb, notification, c.Name(), strconv.FormatInt(chatID, 10), func() error {
+		return sendTelegram(notification.render(c.template, parseLanguage(groupLanguage)), chatID, notification.ImageURI, replyToMessageID)
+	})
+
+	if user.Group == nil {
+		return
+	}
+
+	for _, additionalChatID := range user.Group.AdditionalChatIDs {
+		// fall back to the group language for additional chats
+		lang := additionalChatID.Language
+		if lang == "" {
+			lang = groupLanguage
+		}
+
+		deliverOnce(gb, notification, c.Name(), strconv.FormatInt(additionalChatID.ChatID, 10), func() error {
+			return sendTelegram(notification.render(c.template, parseLanguage(lang)), additionalChatID.ChatID, "", 0)
+		})
+	}
+}
+
+// sendTelegram sends a message & retries without the image if that fails.
+func sendTelegram(message string, chatID int64, imageURI string, replyToMessageID int) error {
+	if _, err := sendTelegramMessageWithMarkup(chatID, message, imageURI, replyToMessageID, nil); err == nil {
+		return nil
+	}
+
+	_, err := sendTelegramMessageWithMarkup(chatID, message, "", replyToMessageID, nil)
+
+	return err
+}
+
+//
+// discord
+
+type discordChannel struct {
+	webhookURL string
+	template   *template.Template
+}
+
+func (c *discordChannel) Name() string { return "discord" }
+
+func (c *discordChannel) Send(gb *gloomberg.Gloomberg, notification *Notification) {
+	payload := map[string]interface{}{
+		"content": notification.render(c.template, parseLanguage(viper.GetString("notifications.discord.language"))),
+	}
+
+	if notification.ImageURI != "" {
+		payload["embeds"] = []map[string]interface{}{{"image": map[string]string{"url": notification.ImageURI}}}
+	}
+
+	deliverOnce(gb, notification, c.Name(), "webhook", func() error {
+		return postJSON(c.webhookURL, payload, nil)
+	})
+}
+
+//
+// generic webhook
+
+type webhookChannel struct {
+	url      string
+	template *template.Template
+}
+
+func (c *webhookChannel) Name() string { return "webhook" }
+
+// Send posts the rendered text & the raw template data of all messages as json.
+func (c *webhookChannel) Send(gb *gloomberg.Gloomberg, notification *Notification) {
+	lang := parseLanguage(viper.GetString("notifications.webhook.language"))
+
+	events := make([]*TemplateData, 0, len(notification.messages))
+	for _, msg := range notification.messages {
+		events = append(events, msg.templateData(lang))
+	}
+
+	payload := map[string]interface{}{
+		"idempotency_key": notification.IdempotencyKey,
+		"user":            notification.User.Name,
+		"text":            notification.render(c.template, lang),
+		"image":           notification.ImageURI,
+		"events":          events,
+	}
+
+	// receivers can use the header to deduplicate on their side too
+	header := http.Header{}
+	header.Set("Idempotency-Key", notification.IdempotencyKey)
+
+	deliverOnce(gb, notification, c.Name(), "url", func() error {
+		return postJSON(c.url, payload, header)
+	})
+}
+
+func postJSON(url string, payload interface{}, header http.Header) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	if header == nil {
+		header = http.Header{}
+	}
+
+	header.Set("Content-Type", "application/json")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	response, err := utils.HTTP.PostWithHeader(ctx, url, header, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, response.Status)
+	}
+
+	return nil
+}
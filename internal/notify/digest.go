@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/ansimg"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/spf13/viper"
+)
+
+const digestImageName = "gloomberg-stats.png"
+
+// StartDailyDigest sends the daily digest at notifications.digest.time (local time, e.g. "22:00").
+func StartDailyDigest(gb *gloomberg.Gloomberg) {
+	for {
+		next, err := nextDigestTime(viper.GetString("notifications.digest.time"), time.Now())
+		if err != nil {
+			gbl.Log.Errorf("❌ invalid notifications.digest.time: %s", err)
+
+			return
+		}
+
+		gbl.Log.Debugf("📊 next daily digest at %s", next.Format(time.DateTime))
+
+		time.Sleep(time.Until(next))
+
+		SendDigest(gb)
+	}
+}
+
+// SendDigest sends the session summary (with the stats box as image if notifications.digest.image is set)
+// to telegram & discord.
+func SendDigest(gb *gloomberg.Gloomberg) {
+	message := fmt.Sprintf("📊 *gloomberg daily digest* · %s\nrunning since %s · %d own events",
+		time.Now().Format("Mon, 02 Jan"),
+		internal.RunningSince.Format("02 Jan 15:04"),
+		gb.RecentOwnEvents.Cardinality(),
+	)
+
+	// deposits & withdrawals between own wallets & exchanges
+	if gb.Stats != nil {
+		if deposited, withdrawn := gb.Stats.CapitalFlows(); deposited.Sign() > 0 || withdrawn.Sign() > 0 {
+			message += fmt.Sprintf("\n🏦 deposited %.2fΞ · 🏧 withdrawn %.2fΞ", price.NewPrice(deposited).Ether(), price.NewPrice(withdrawn).Ether())
+		}
+	}
+
+	var statsImage []byte
+
+	if viper.GetBool("notifications.digest.image") && gb.Stats != nil {
+		image, err := ansimg.PNG(gb.Stats.Render())
+		if err != nil {
+			gbl.Log.Warnf("❌ rendering the stats box failed: %s", err)
+		}
+
+		statsImage = image
+	}
+
+	if viper.GetBool("notifications.telegram.enabled") {
+		if err := sendDigestViaTelegram(message, statsImage); err != nil {
+			gbl.Log.Warnf("❌ sending the daily digest via telegram failed: %s", err)
+		}
+	}
+
+	if viper.GetBool("notifications.discord.enabled") && viper.GetString("notifications.discord.webhook_url") != "" {
+		if err := sendDigestViaDiscord(viper.GetString("notifications.discord.webhook_url"), message, statsImage); err != nil {
+			gbl.Log.Warnf("❌ sending the daily digest via discord failed: %s", err)
+		}
+	}
+}
+
+func sendDigestViaTelegram(message string, statsImage []byte) error {
+	bot, err := GetBot()
+	if err != nil {
+		return err
+	}
+
+	chatID := viper.GetInt64("notifications.telegram.chat_id")
+
+	if len(statsImage) == 0 {
+		msg := tgbotapi.NewMessage(chatID, message)
+		msg.ParseMode = "markdown"
+
+		_, err = bot.Send(msg)
+
+		return err
+	}
+
+	msg := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: digestImageName, Bytes: statsImage})
+	msg.ParseMode = "markdown"
+	msg.Caption = message
+
+	_, err = bot.Send(msg)
+
+	return err
+}
+
+// sendDigestViaDiscord posts the message with the image attached to a discord webhook.
+func sendDigestViaDiscord(webhookURL string, message string, statsImage []byte) error {
+	if len(statsImage) == 0 {
+		return postJSON(webhookURL, map[string]interface{}{"content": message}, nil)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	payload, err := json.Marshal(map[string]interface{}{"content": message})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.WriteField("payload_json", string(payload)); err != nil {
+		return err
+	}
+
+	file, err := writer.CreateFormFile("files[0]", digestImageName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(statsImage); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", writer.FormDataContentType())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	response, err := utils.HTTP.PostWithHeader(ctx, webhookURL, header, strings.NewReader(body.String()))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, response.StatusCode)
+	}
+
+	return nil
+}
+
+// nextDigestTime returns the next occurrence of the given time of day ("15:04") after now.
+func nextDigestTime(timeOfDay string, now time.Time) (time.Time, error) {
+	parsed, err := time.Parse("15:04", timeOfDay)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), parsed.Hour(), parsed.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next, nil
+}
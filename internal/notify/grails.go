@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/grails"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/spf13/viper"
+)
+
+// SendGrailAlert sends a telegram message for every event touching a watched grail,
+// independent of the wallets of the watched users.
+func SendGrailAlert(ttx *totra.TokenTransaction, label string) {
+	if !viper.GetBool("notifications.telegram.enabled") {
+		return
+	}
+
+	message := strings.Builder{}
+	message.WriteString(fmt.Sprintf("%s *%s* · %.3fΞ\n", ttx.Action.Icon(), label, ttx.GetPrice().Ether()))
+
+	for _, transfer := range ttx.Transfers {
+		if transfer.Token == nil || !grails.Contains(transfer.Token.Address, transfer.Token.ID) {
+			continue
+		}
+
+		message.WriteString(fmt.Sprintf("[%s](%s)", strings.ToLower(links.Preferred().Name()), links.Item(transfer.Token.Address, transfer.Token.ID)))
+
+		break
+	}
+
+	if ttx.Tx != nil {
+		message.WriteString(fmt.Sprintf(" · [tx](%s)", utils.GetEtherscanTxURL(ttx.TxHash.Hex())))
+	}
+
+	SendMessageViaTelegram(message.String(), viper.GetInt64("notifications.telegram.chat_id"), "", 0, nil)
+}
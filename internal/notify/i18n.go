@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/spf13/viper"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Language of the notification texts.
+type Language string
+
+const (
+	English Language = "en"
+	German  Language = "de"
+)
+
+// translations of the notification texts, english is the fallback for missing keys.
+var translations = map[Language]map[string]string{
+	German: {
+		"for": "für",
+
+		// actions by event type name
+		degendb.Transfer.String():                "transferierte",
+		degendb.Sale.String():                    "verkaufte",
+		degendb.Purchase.String():                "kaufte",
+		degendb.Mint.String():                    "mintete",
+		degendb.Airdrop.String():                 "bekam per Airdrop",
+		degendb.Burn.String():                    "verbrannte",
+		degendb.BurnRedeem.String():              "löste verbrannte ein",
+		degendb.Loan.String():                    "beleihte",
+		degendb.RepayLoan.String():               "tilgte Kredit für",
+		degendb.Liquidation.String():             "pfändete",
+		degendb.Listing.String():                 "listete",
+		degendb.Bid.String():                     "bekam Gebot für",
+		degendb.OwnBid.String():                  "bot auf",
+		degendb.AcceptedOffer.String():           "nahm Angebot an für",
+		degendb.CollectionOffer.String():         "bekam Kollektionsangebot für",
+		degendb.AcceptedCollectionOffer.String(): "nahm Kollektionsangebot an für",
+		degendb.Cancelled.String():               "stornierte",
+	},
+}
+
+var languageTags = map[Language]language.Tag{
+	English: language.English,
+	German:  language.German,
+}
+
+// parseLanguage returns the language for a config value, falling back to notifications.language.
+func parseLanguage(lang string) Language {
+	if lang == "" {
+		lang = viper.GetString("notifications.language")
+	}
+
+	if l := Language(strings.ToLower(lang)); l == German {
+		return l
+	}
+
+	return English
+}
+
+// T returns the translation of key or the fallback if there is none.
+func (l Language) T(key string, fallback string) string {
+	if translated, ok := translations[l][key]; ok {
+		return translated
+	}
+
+	return fallback
+}
+
+// Action returns the localized action name of an event type.
+func (l Language) Action(action degendb.EventType) string {
+	return l.T(action.String(), action.ActionName())
+}
+
+// Sprintf formats numbers according to the language, e.g. 1.234,5 in german.
+func (l Language) Sprintf(format string, a ...interface{}) string {
+	tag, ok := languageTags[l]
+	if !ok {
+		tag = language.English
+	}
+
+	return message.NewPrinter(tag).Sprintf(format, a...)
+}
@@ -7,7 +7,6 @@ package notify
 import (
 	"context"
 	"encoding/base64"
-	"fmt"
 	"math/big" //nolint:gci
 	"strings"
 
@@ -19,6 +18,7 @@ import (
 	"github.com/benleb/gloomberg/internal/collections"
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/nemo/standard"
@@ -27,46 +27,28 @@ import (
 	"github.com/benleb/gloomberg/internal/nemo/watch"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/benleb/gloomberg/internal/utils"
-	"github.com/charmbracelet/log"
 	"github.com/ethereum/go-ethereum/common"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/spf13/viper"
 )
 
 // func TestMsg() {
 // 	sendTelegramMessage(1320669206, "test", utils.PrepareURL("https://ipfs.io/ipfs/QmRuj3fqWkZkuruTkPgGSvSdTdjyAMiXyBDPQ5oFer43Rq/6351.gif"))
 // }
 
+// SendNotification fans the token transaction out to all enabled channels. the tx hash & user
+// are used as idempotency key, so every channel target gets it only once, even with multiple instances.
 func SendNotification(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
-	var fmtHash string
 	if ttx.TxHash == (common.Hash{}) {
 		gbl.Log.Warnf("❌ no tx hash in token transaction")
-
-		fmtHash = "unknown"
-	} else {
-		fmtHash = style.ShortenHashStyled(ttx.TxHash)
 	}
 
-	// try to acquire the lock
-	if viper.GetBool("redis.enabled") {
-		notificationLock, err := gb.Rueidi.NotificationLock(ttx.TxHash)
-		// if !notificationLock || err != nil {
-		if notificationLock == nil || err != nil {
-			gbl.Log.Infof("🔒 notification lock for %s already exists", fmtHash)
-			log.Printf("🔒 %s | notification already locked!", style.ShortenHashStyled(ttx.TxHash))
-
-			return
-		}
-
-		// release the lock
-		defer notificationLock()
-
-		gbl.Log.Infof("🔐 notification lock for %s acquired (%.0fsec)", fmtHash, viper.GetDuration("cache.notifications_lock_ttl").Seconds())
-		log.Debugf("🔒 %s | notification lock acquired (%.0fsec)", style.ShortenHashStyled(ttx.TxHash), viper.GetDuration("cache.notifications_lock_ttl").Seconds())
+	channels := enabledChannels()
+	if len(channels) == 0 {
+		return
 	}
 
-	messagesPerUserMap := make(map[*watch.WUser]*strings.Builder)
-	imagesPerUserMap := make(map[*watch.WUser]string)
+	// messages are collected per user & rendered per channel/chat to use their templates & languages
+	notificationsPerUser := make(map[*watch.WUser]*Notification)
 
 	for contractAddress, transfers := range ttx.GetTransfersByContract() {
 		for _, transfer := range transfers {
@@ -103,55 +85,39 @@ func SendNotification(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
 				userName = triggerUser.Name
 			}
 
-			// get the image uri of the token
-			imageURI := getImageURI(gb, collection, transfer.Token.ID.Int64())
-			imagesPerUserMap[triggerUser] = imageURI
-
-			gbl.Log.Debugf("📸 imageURI: %s", imageURI)
-
-			gbl.Log.Debugf("ttx: %+v | transfer: %+v | collection: %+v | userName: %s | triggerAddress: %s", ttx, transfer, collection, userName, triggerAddress.String())
+			notification, ok := notificationsPerUser[triggerUser]
+			if !ok {
+				notification = &Notification{
+					IdempotencyKey: ttx.TxHash.Hex() + ":" + triggerUser.Name,
+					User:           triggerUser,
+					messages:       make([]*notificationMessage, 0),
+				}
 
-			// collect telegram messages per user
-			msgTelegram := buildNotificationMessage(ttx, transfer, collection, userName, triggerAddress)
-
-			// collect messages per user / append additional messages
-			var builder *strings.Builder
-			if existingBuilder, ok := messagesPerUserMap[triggerUser]; ok {
-				builder = existingBuilder
-				builder.WriteString("\n")
-			} else {
-				builder = &strings.Builder{}
+				notificationsPerUser[triggerUser] = notification
 			}
 
-			builder.WriteString(msgTelegram.String())
-
-			messagesPerUserMap[triggerUser] = builder
-
-			gbl.Log.Debugf("📢 notification | %s", builder.String())
-		}
-	}
-
-	for user, msgTelegram := range messagesPerUserMap {
-		chatID := viper.GetInt64("notifications.telegram.chat_id")
+			// get the image uri of the token
+			notification.ImageURI = getImageURI(gb, collection, transfer.Token.ID.Int64())
 
-		var replyToMessageID int
+			gbl.Log.Debugf("📸 imageURI: %s", notification.ImageURI)
 
-		if user != nil && user.Group.TelegramChatID != 0 {
-			chatID = user.Group.TelegramChatID
-			replyToMessageID = user.Group.ReplyToMessageID
-		}
+			gbl.Log.Debugf("ttx: %+v | transfer: %+v | collection: %+v | userName: %s | triggerAddress: %s", ttx, transfer, collection, userName, triggerAddress.String())
 
-		var imageURI string
-		if uri, ok := imagesPerUserMap[user]; ok {
-			imageURI = uri
+			notification.messages = append(notification.messages, &notificationMessage{
+				ttx:            ttx,
+				transfer:       transfer,
+				collection:     collection,
+				userName:       userName,
+				triggerAddress: triggerAddress,
+			})
 		}
+	}
 
-		SendMessageViaTelegram(msgTelegram.String(), chatID, imageURI, replyToMessageID, nil)
+	for _, notification := range notificationsPerUser {
+		gbl.Log.Debugf("📢 notification | %s", notification.render(nil, English))
 
-		if user.Group.AdditionalChatIDs != nil {
-			for _, additionalChatID := range user.Group.AdditionalChatIDs {
-				SendMessageViaTelegram(msgTelegram.String(), additionalChatID.ChatID, "", 0, nil)
-			}
+		for _, channel := range channels {
+			channel.Send(gb, notification)
 		}
 	}
 }
@@ -181,13 +147,13 @@ func SendMessageViaTelegram(message string, chatID int64, imageURI string, reply
 	gbl.Log.Infof("📫 msg sent | %s", strings.ReplaceAll(sentMsg, "\n", " | "))
 }
 
-func buildNotificationMessage(ttx *totra.TokenTransaction, transfer *totra.TokenTransfer, collection *collections.Collection, userName string, triggerAddress common.Address) strings.Builder {
+func buildNotificationMessage(ttx *totra.TokenTransaction, transfer *totra.TokenTransfer, collection *collections.Collection, userName string, triggerAddress common.Address, lang Language) strings.Builder {
 	// prepare links
 	tokenID := int64(0)
 	if transfer.Token.ID != nil {
 		tokenID = transfer.Token.ID.Int64()
 	}
-	etherscanURL, openseaURL, blurURL := utils.GetLinks(ttx.TxHash, transfer.Token.Address, tokenID)
+	etherscanURL := utils.GetEtherscanTxURL(ttx.TxHash.Hex())
 
 	action := ttx.Action
 
@@ -204,19 +170,36 @@ func buildNotificationMessage(ttx *totra.TokenTransaction, transfer *totra.Token
 	msgTelegram := strings.Builder{}
 	msgTelegram.WriteString(action.Icon())
 	msgTelegram.WriteString(" " + strings.ReplaceAll(userName, "_", "\\_"))
-	msgTelegram.WriteString(" " + action.ActionName())
+	msgTelegram.WriteString(" " + lang.Action(action))
 
-	if transfer.AmountTokens != nil && transfer.AmountTokens.Cmp(big.NewInt(1)) > 1 {
-		msgTelegram.WriteString(" " + transfer.AmountTokens.String() + "x") // erc1155 token value/amounts
+	if quantity := transfer.FormatQuantity(); quantity != "" {
+		msgTelegram.WriteString(" " + quantity + "x") // erc1155 token value/amounts
 	}
 
 	msgTelegram.WriteString(" *" + style.FormatTokenInfo(transfer.Token.ID, collection.Name, collection.Style(), collection.StyleSecondary(), false, false) + "*")
-	msgTelegram.WriteString(" for *" + fmt.Sprintf("%.3f", tokenPrice.Ether()) + "*Ξ")
+	msgTelegram.WriteString(" " + lang.T("for", "for") + " *" + lang.Sprintf("%.3f", tokenPrice.Ether()) + "*Ξ")
+
+	// price per edition
+	if transfer.AmountTokens != nil && transfer.AmountTokens.Cmp(big.NewInt(1)) > 0 {
+		msgTelegram.WriteString(" (@" + lang.Sprintf("%.3f", tokenPrice.Ether()/float64(transfer.AmountTokens.Int64())) + "Ξ)")
+	}
 	msgTelegram.WriteString("\n")
+
+	// held x days, bought for ...
+	if transfer.AcquisitionContext != "" && transfer.From == triggerAddress {
+		msgTelegram.WriteString(" _" + transfer.AcquisitionContext + "_\n")
+	}
+	// annotations from user plugins
+	for _, annotation := range ttx.Annotations {
+		msgTelegram.WriteString(" _" + strings.ReplaceAll(annotation, "_", "\\_") + "_\n")
+	}
+
 	msgTelegram.WriteString(" " + style.ShortenAdressPTR(&triggerAddress) + " |")
 	msgTelegram.WriteString(" [Tx](" + etherscanURL + ")")
-	msgTelegram.WriteString(" · [Blur](" + blurURL + ")")
-	msgTelegram.WriteString(" · [Opensea](" + openseaURL + ")")
+
+	for _, link := range links.ItemLinks(transfer.Token.Address, big.NewInt(tokenID)) {
+		msgTelegram.WriteString(" · [" + link.Name + "](" + link.URL + ")")
+	}
 
 	return msgTelegram
 }
@@ -0,0 +1,200 @@
+package notify
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/standard"
+	"github.com/benleb/gloomberg/internal/nemo/tokencollections"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/spf13/viper"
+)
+
+// tape is a telegram message continuously edited to show the latest events of a collection.
+type tape struct {
+	name  string
+	lines []string
+
+	messageID int
+	startedAt time.Time
+	dirty     bool
+}
+
+var (
+	tapes   = make(map[common.Address]*tape)
+	tapesMu sync.Mutex
+)
+
+// TapeEnabled returns true if the telegram live tape is configured.
+func TapeEnabled() bool {
+	return viper.GetBool("notifications.telegram.tape.enabled") && len(viper.GetStringSlice("notifications.telegram.tape.collections")) > 0
+}
+
+// AddToTape adds the events of the tape collections to their tapes. the messages are
+// updated by StartTape to stay within the telegram rate limits.
+func AddToTape(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
+	if !tapeAction(ttx) {
+		return
+	}
+
+	size := max(viper.GetInt("notifications.telegram.tape.size"), 1)
+
+	for contractAddress, transfers := range ttx.GetTransfersByContract() {
+		if len(transfers) == 0 || transfers[0].Standard == standard.ERC20 || !isTapeCollection(contractAddress) {
+			continue
+		}
+
+		line := formatTapeLine(ttx, transfers)
+
+		name := contractAddress.Hex()
+		if collection := tokencollections.GetCollection(gb, contractAddress, 0); collection != nil && collection.Name != "" {
+			name = collection.Name
+		}
+
+		tapesMu.Lock()
+
+		currentTape, ok := tapes[contractAddress]
+		if !ok {
+			currentTape = &tape{name: name}
+			tapes[contractAddress] = currentTape
+		}
+
+		// newest first
+		currentTape.lines = append([]string{line}, currentTape.lines...)
+		currentTape.lines = currentTape.lines[:min(len(currentTape.lines), size)]
+		currentTape.dirty = true
+
+		tapesMu.Unlock()
+	}
+}
+
+// StartTape sends/edits the tape messages with new events every notifications.telegram.tape.interval.
+// after notifications.telegram.tape.rotate a new message is started so the tape stays at the bottom of the chat.
+func StartTape() {
+	chatID := viper.GetInt64("notifications.telegram.tape.chat_id")
+	if chatID == 0 {
+		chatID = viper.GetInt64("notifications.telegram.chat_id")
+	}
+
+	ticker := time.NewTicker(max(viper.GetDuration("notifications.telegram.tape.interval"), time.Second*3))
+
+	for range ticker.C {
+		// render under the lock, send without blocking new events
+		texts := make(map[*tape]string)
+
+		tapesMu.Lock()
+		for _, currentTape := range tapes {
+			if currentTape.dirty {
+				texts[currentTape] = currentTape.render()
+				currentTape.dirty = false
+			}
+		}
+		tapesMu.Unlock()
+
+		for currentTape, text := range texts {
+			if err := currentTape.flush(chatID, text); err != nil {
+				gbl.Log.Warnf("❌ updating the telegram tape of %s failed: %s", currentTape.name, err)
+			}
+		}
+	}
+}
+
+// flush edits the current tape message or sends a new one.
+// messageID & startedAt are only used by the StartTape goroutine.
+func (t *tape) flush(chatID int64, text string) error {
+	bot, err := GetBot()
+	if err != nil {
+		return err
+	}
+
+	rotate := viper.GetDuration("notifications.telegram.tape.rotate")
+	isRotationDue := rotate > 0 && time.Since(t.startedAt) > rotate
+
+	if t.messageID != 0 && !isRotationDue {
+		edit := tgbotapi.NewEditMessageText(chatID, t.messageID, text)
+		edit.ParseMode = tgbotapi.ModeHTML
+		edit.DisableWebPagePreview = true
+
+		if _, err := bot.Send(edit); err == nil {
+			return nil
+		}
+
+		// the message may have been deleted, start a new one
+		gbl.Log.Debugf("📼 editing tape message %d failed, sending a new one", t.messageID)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = true
+	msg.DisableNotification = true
+
+	sent, err := bot.Send(msg)
+	if err != nil {
+		return err
+	}
+
+	t.messageID = sent.MessageID
+	t.startedAt = time.Now()
+
+	return nil
+}
+
+func (t *tape) render() string {
+	text := strings.Builder{}
+
+	text.WriteString(fmt.Sprintf("📼 <b>%s</b> · live\n\n", html.EscapeString(t.name)))
+	text.WriteString(strings.Join(t.lines, "\n"))
+	text.WriteString(fmt.Sprintf("\n\n<i>updated %s</i>", time.Now().Format("15:04:05")))
+
+	return text.String()
+}
+
+func formatTapeLine(ttx *totra.TokenTransaction, transfers []*totra.TokenTransfer) string {
+	tokenIDs := make([]string, 0, len(transfers))
+
+	for _, transfer := range transfers[:min(len(transfers), 3)] {
+		if transfer.Token != nil && transfer.Token.ID != nil {
+			tokenIDs = append(tokenIDs, "#"+transfer.Token.ID.String())
+		}
+	}
+
+	if len(transfers) > 3 {
+		tokenIDs = append(tokenIDs, fmt.Sprintf("+%d", len(transfers)-3))
+	}
+
+	return fmt.Sprintf("<code>%s</code> %s <b>%.3fΞ</b> %s · <a href=\"%s\">tx</a>",
+		time.Now().Format("15:04"),
+		ttx.Action.Icon(),
+		ttx.GetPrice().Ether(),
+		html.EscapeString(strings.Join(tokenIDs, " ")),
+		utils.GetEtherscanTxURL(ttx.TxHash.Hex()),
+	)
+}
+
+func isTapeCollection(contractAddress common.Address) bool {
+	for _, address := range viper.GetStringSlice("notifications.telegram.tape.collections") {
+		if common.HexToAddress(address) == contractAddress {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tapeAction(ttx *totra.TokenTransaction) bool {
+	for _, action := range viper.GetStringSlice("notifications.telegram.tape.actions") {
+		if strings.EqualFold(action, ttx.Action.String()) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -14,6 +14,9 @@ import (
 // ErrNoTelegramAPIToken given if no telegram API token is found in the config file.
 var ErrNoTelegramAPIToken = fmt.Errorf("no telegram API token found in config file")
 
+// ErrUnexpectedStatus is returned for non-2xx responses of webhooks.
+var ErrUnexpectedStatus = fmt.Errorf("unexpected status code")
+
 // ErrPhotoURLInvalid if the provided photo url could not be fetched (non-200 status code).
 // var ErrPhotoURLInvalid = fmt.Errorf("photoURL invalid (non-200 http status code)")
 
@@ -14,6 +14,7 @@ import (
 	"github.com/benleb/gloomberg/internal/collections"
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/osmodels"
 	"github.com/benleb/gloomberg/internal/nemo/provider"
@@ -33,6 +34,29 @@ func openSeaHeader() http.Header {
 	return header
 }
 
+// get requests the OpenSea api through the circuit breaker, rate limits & server errors count as failures.
+func get(url string) (*http.Response, error) {
+	breaker := health.Breaker(health.SourceOpenSea)
+	if !breaker.Allow() {
+		return nil, health.ErrCircuitOpen
+	}
+
+	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	if err != nil {
+		breaker.Failure()
+
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError {
+		breaker.Failure()
+	} else {
+		breaker.Success()
+	}
+
+	return response, nil
+}
+
 // GetWalletCollections returns the collections a wallet owns at least one item of.
 // func GetWalletCollections(wallets *wallet.Wallets, userCollections *collections.CollectionDB, nodes *nodes.Nodes) []*collections.Collection {.
 func GetWalletCollections(gb *gloomberg.Gloomberg) []*collections.Collection {
@@ -55,7 +79,7 @@ func GetTokensFor(walletAddress common.Address, try int, cursor string) []*token
 	receivedNFTs := make([]*token.Token, 0)
 	limit := 70
 	url := fmt.Sprintf("https://api.opensea.io/api/v1/assets?owner=%s&limit=%d&cursor=%s", walletAddress, limit, cursor)
-	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	response, err := get(url)
 
 	if os.IsTimeout(err) {
 		backoffSeconds := try * 2
@@ -119,7 +143,7 @@ func GetCollectionsFor(walletAddress common.Address, userCollections *collection
 
 	url := fmt.Sprintf("https://api.opensea.io/api/v1/collections?asset_owner=%s&offset=0&limit=300", walletAddress)
 
-	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	response, err := get(url)
 	if os.IsTimeout(err) {
 		backoffSeconds := try * 2
 		sleepTime := time.Duration(backoffSeconds) * time.Second
@@ -198,7 +222,7 @@ func GetCollectionSlug(collectionAddress common.Address) string {
 func GetCollection(slug string) *osmodels.CollectionResponse {
 	url := fmt.Sprintf("https://api.opensea.io/api/v1/collection/%s", slug)
 
-	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	response, err := get(url)
 	if os.IsTimeout(err) {
 		gbl.Log.Warnf("⌛️ timeout while fetching wallet collections for %s...", slug)
 
@@ -230,7 +254,7 @@ func GetCollection(slug string) *osmodels.CollectionResponse {
 func GetAssetContract(contractAddress common.Address) *osmodels.AssetContract {
 	url := fmt.Sprintf("https://api.opensea.io/api/v1/asset_contract/%s", contractAddress.String())
 
-	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	response, err := get(url)
 	if err != nil {
 		// if os.IsTimeout(err) {
 		// 	// dalog.Warn("TIMEOUT while fetching listings, trying again next round... ", collectionSlug)
@@ -269,7 +293,7 @@ func GetAssetContract(contractAddress common.Address) *osmodels.AssetContract {
 func GetListings(contractAddress common.Address, tokenID int64) []osmodels.SeaportOrder {
 	url := fmt.Sprintf("https://api.opensea.io/v2/orders/ethereum/seaport/listings?asset_contract_address=%s&token_ids=%d&order_by=created_date&order_direction=desc", contractAddress.String(), tokenID)
 
-	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	response, err := get(url)
 	if err != nil {
 		gbl.Log.Errorf("❌ error while fetching listings for %s/%d: %s", contractAddress.Hex(), tokenID, err)
 		// if os.IsTimeout(err) {
@@ -324,7 +348,7 @@ func GetListings(contractAddress common.Address, tokenID int64) []osmodels.Seapo
 func GetCollectionStats(collectionSlug string) *osmodels.FullCollectionStats {
 	url := fmt.Sprintf("https://api.opensea.io/api/v1/collection/%s/stats", collectionSlug)
 
-	response, err := utils.HTTP.GetWithHeader(context.Background(), url, openSeaHeader())
+	response, err := get(url)
 	if err != nil {
 		if os.IsTimeout(err) {
 			gbl.Log.Warn("TIMEOUT while fetching listings, trying again next round... ", collectionSlug)
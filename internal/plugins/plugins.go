@@ -0,0 +1,231 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var ErrNoRunner = errors.New("plugin needs either exec or wasm configured")
+
+// Event is the json passed to the plugins on stdin.
+type Event struct {
+	Type        string     `json:"type"`
+	TxHash      string     `json:"tx_hash"`
+	From        string     `json:"from"`
+	Marketplace string     `json:"marketplace,omitempty"`
+	PriceWei    string     `json:"price_wei,omitempty"`
+	PriceEther  float64    `json:"price_ether"`
+	TotalTokens int64      `json:"total_tokens"`
+	Transfers   []Transfer `json:"transfers"`
+
+	// involved wallets are own or watched wallets
+	OwnWallet     bool `json:"own_wallet"`
+	WatchedWallet bool `json:"watched_wallet"`
+}
+
+// Transfer is a single token transfer of an event.
+type Transfer struct {
+	Contract   string `json:"contract"`
+	Collection string `json:"collection,omitempty"`
+	Standard   string `json:"standard"`
+	TokenID    string `json:"token_id,omitempty"`
+	Amount     string `json:"amount,omitempty"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// Result is the json a plugin writes to stdout, an empty output is fine too.
+type Result struct {
+	// suppress the event (no output & notification)
+	Veto   bool   `json:"veto"`
+	Reason string `json:"reason,omitempty"`
+
+	// short texts added to the event line & notification
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// Plugin runs a user supplied script with the event json as input & returns its output.
+type Plugin interface {
+	Name() string
+	Run(ctx context.Context, input []byte) ([]byte, error)
+}
+
+// Config of a single plugin in plugins.hooks.
+type Config struct {
+	Name string `mapstructure:"name"`
+
+	// command & args of an executable
+	Exec []string `mapstructure:"exec"`
+
+	// path to a wasi module
+	Wasm string `mapstructure:"wasm"`
+}
+
+var pluginRunsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_plugin_runs_total",
+	Help: "The number of plugin runs by plugin & result (ok, veto or error).",
+}, []string{"plugin", "result"})
+
+var loadedPlugins = make([]Plugin, 0)
+
+// Load creates the plugins configured in plugins.hooks.
+func Load() error {
+	var configs []Config
+	if err := viper.UnmarshalKey("plugins.hooks", &configs); err != nil {
+		return err
+	}
+
+	for idx, config := range configs {
+		if config.Name == "" {
+			config.Name = fmt.Sprintf("plugin-%d", idx+1)
+		}
+
+		var (
+			plugin Plugin
+			err    error
+		)
+
+		switch {
+		case len(config.Exec) > 0:
+			plugin = newExecPlugin(config.Name, config.Exec)
+		case config.Wasm != "":
+			plugin, err = newWasmPlugin(config.Name, config.Wasm)
+		default:
+			err = ErrNoRunner
+		}
+
+		if err != nil {
+			return fmt.Errorf("loading plugin %s failed: %w", config.Name, err)
+		}
+
+		loadedPlugins = append(loadedPlugins, plugin)
+
+		gbl.Log.Infof("🧩 loaded plugin %s", config.Name)
+	}
+
+	return nil
+}
+
+// Enabled returns true if at least one plugin is loaded.
+func Enabled() bool {
+	return len(loadedPlugins) > 0
+}
+
+// Run passes the token transaction to all plugins & collects their annotations.
+// the first veto suppresses the event, failing plugins are ignored.
+func Run(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, ownWallet bool, watchedWallet bool) *Result {
+	combined := &Result{Annotations: make([]string, 0)}
+
+	input, err := json.Marshal(newEvent(gb, ttx, ownWallet, watchedWallet))
+	if err != nil {
+		gbl.Log.Warnf("🧩 marshalling event for plugins failed: %s", err)
+
+		return combined
+	}
+
+	for _, plugin := range loadedPlugins {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout())
+		output, err := plugin.Run(ctx, input)
+
+		cancel()
+
+		if err != nil {
+			gbl.Log.Warnf("🧩 plugin %s failed: %s", plugin.Name(), err)
+			pluginRunsCounter.WithLabelValues(plugin.Name(), "error").Inc()
+
+			continue
+		}
+
+		var result Result
+
+		if len(strings.TrimSpace(string(output))) > 0 {
+			if err := json.Unmarshal(output, &result); err != nil {
+				gbl.Log.Warnf("🧩 invalid output of plugin %s: %s", plugin.Name(), err)
+				pluginRunsCounter.WithLabelValues(plugin.Name(), "error").Inc()
+
+				continue
+			}
+		}
+
+		if result.Veto {
+			gbl.Log.Debugf("🧩 plugin %s vetoed %s: %s", plugin.Name(), ttx.TxHash.Hex(), result.Reason)
+			pluginRunsCounter.WithLabelValues(plugin.Name(), "veto").Inc()
+
+			return &result
+		}
+
+		pluginRunsCounter.WithLabelValues(plugin.Name(), "ok").Inc()
+
+		combined.Annotations = append(combined.Annotations, result.Annotations...)
+	}
+
+	return combined
+}
+
+func newEvent(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, ownWallet bool, watchedWallet bool) *Event {
+	event := &Event{
+		Type:          strings.ToLower(ttx.Action.String()),
+		TxHash:        ttx.TxHash.Hex(),
+		From:          ttx.From.Hex(),
+		TotalTokens:   ttx.TotalTokens,
+		Transfers:     make([]Transfer, 0, len(ttx.Transfers)),
+		OwnWallet:     ownWallet,
+		WatchedWallet: watchedWallet,
+	}
+
+	if ttx.Marketplace != nil {
+		event.Marketplace = ttx.Marketplace.Name
+	}
+
+	if ttx.AmountPaid != nil {
+		event.PriceWei = ttx.AmountPaid.String()
+		event.PriceEther = ttx.GetPrice().Ether()
+	}
+
+	for _, transfer := range ttx.Transfers {
+		if transfer.Token == nil {
+			continue
+		}
+
+		t := Transfer{
+			Contract: transfer.Token.Address.Hex(),
+			Standard: transfer.Standard.String(),
+			From:     transfer.From.Hex(),
+			To:       transfer.To.Hex(),
+		}
+
+		if transfer.Token.ID != nil {
+			t.TokenID = transfer.Token.ID.String()
+		}
+
+		if transfer.AmountTokens != nil {
+			t.Amount = transfer.AmountTokens.String()
+		}
+
+		gb.CollectionDB.RWMu.RLock()
+		if collection, ok := gb.CollectionDB.Collections[transfer.Token.Address]; ok && collection != nil {
+			t.Collection = collection.Name
+		}
+		gb.CollectionDB.RWMu.RUnlock()
+
+		event.Transfers = append(event.Transfers, t)
+	}
+
+	return event
+}
+
+// timeout returns the configured plugin timeout with a sane minimum.
+func timeout() time.Duration {
+	return max(viper.GetDuration("plugins.timeout"), time.Millisecond*10)
+}
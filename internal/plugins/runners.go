@@ -0,0 +1,88 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+//
+// exec
+
+// execPlugin starts the command for every event, the event is written to its stdin.
+type execPlugin struct {
+	name    string
+	command []string
+}
+
+func newExecPlugin(name string, command []string) *execPlugin {
+	return &execPlugin{name: name, command: command}
+}
+
+func (p *execPlugin) Name() string { return p.name }
+
+func (p *execPlugin) Run(ctx context.Context, input []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.command[0], p.command[1:]...) //nolint:gosec
+	cmd.Stdin = bytes.NewReader(input)
+
+	return cmd.Output()
+}
+
+//
+// wasm
+
+// wasmPlugin runs the _start function of a wasi module for every event.
+// the module is compiled once, every run gets a fresh instance.
+type wasmPlugin struct {
+	name string
+
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+func newWasmPlugin(name string, path string) (*wasmPlugin, error) {
+	wasm, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+
+	// close the module when the context is done to enforce the timeout
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true))
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return nil, err
+	}
+
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wasmPlugin{name: name, runtime: runtime, compiled: compiled}, nil
+}
+
+func (p *wasmPlugin) Name() string { return p.name }
+
+func (p *wasmPlugin) Run(ctx context.Context, input []byte) ([]byte, error) {
+	var stdout bytes.Buffer
+
+	// anonymous modules can be instantiated concurrently
+	config := wazero.NewModuleConfig().WithName("").WithStdin(bytes.NewReader(input)).WithStdout(&stdout)
+
+	module, err := p.runtime.InstantiateModule(ctx, p.compiled, config)
+	if module != nil {
+		defer module.Close(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
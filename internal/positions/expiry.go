@@ -0,0 +1,88 @@
+package positions
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/tokencollections"
+	"github.com/benleb/gloomberg/internal/notify"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/spf13/viper"
+)
+
+// checkExpiring alerts once about positions expiring within positions.expiry_alert: received offers to
+// accept & own listings/offers to renew or outbid before they are gone.
+func checkExpiring(gb *gloomberg.Gloomberg) {
+	alertBefore := viper.GetDuration("positions.expiry_alert")
+	if alertBefore <= 0 {
+		return
+	}
+
+	expiringPositions := make([]*Position, 0)
+
+	positionsMu.Lock()
+	for _, position := range positions {
+		if position.ExpiresAt.IsZero() || !position.ExpiryAlertedAt.IsZero() {
+			continue
+		}
+
+		if untilExpiry := time.Until(position.ExpiresAt); untilExpiry <= 0 || untilExpiry > alertBefore {
+			continue
+		}
+
+		position.ExpiryAlertedAt = time.Now()
+		expiringPositions = append(expiringPositions, position)
+	}
+	positionsMu.Unlock()
+
+	sortByAge(expiringPositions)
+
+	for _, position := range expiringPositions {
+		store(gb, position)
+
+		alertExpiry(gb, position)
+	}
+}
+
+func alertExpiry(gb *gloomberg.Gloomberg, position *Position) {
+	item := position.Contract.Hex()
+
+	tokenID, _ := strconv.ParseInt(position.TokenID, 10, 64)
+	if collection := tokencollections.GetCollection(gb, position.Contract, tokenID); collection != nil && collection.Name != "" {
+		item = collection.Name
+	}
+
+	if position.TokenID != "" {
+		item += " #" + position.TokenID
+	}
+
+	var line string
+
+	switch {
+	case position.Kind == KindReceivedOffer && position.IsCollectionOffer():
+		line = fmt.Sprintf("best collection offer for %s at %.3fΞ expires in %s → accept?", item, position.Price, formatExpiry(position.ExpiresAt))
+	case position.Kind == KindReceivedOffer:
+		line = fmt.Sprintf("best offer for %s at %.3fΞ expires in %s → accept?", item, position.Price, formatExpiry(position.ExpiresAt))
+	case position.Kind == KindListing:
+		line = fmt.Sprintf("your listing %s at %.3fΞ expires in %s", item, position.Price, formatExpiry(position.ExpiresAt))
+	default:
+		line = fmt.Sprintf("your offer for %s at %.3fΞ expires in %s → renew or outbid?", item, position.Price, formatExpiry(position.ExpiresAt))
+	}
+
+	gloomberg.PrModf("expiry", "%s", style.AlmostWhiteStyle.Render(line))
+
+	if viper.GetBool("positions.notify") && viper.GetBool("notifications.telegram.enabled") {
+		go notify.SendMessageViaTelegram("⌛️ "+line, viper.GetInt64("notifications.telegram.chat_id"), "", 0, nil)
+	}
+}
+
+func formatExpiry(expiresAt time.Time) string {
+	untilExpiry := time.Until(expiresAt)
+	if untilExpiry < time.Hour {
+		return fmt.Sprintf("%dmin", max(1, int(untilExpiry.Minutes())))
+	}
+
+	return formatAge(untilExpiry)
+}
@@ -0,0 +1,296 @@
+package positions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/seawa/models"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Kind of a position.
+type Kind string
+
+const (
+	KindListing Kind = "listing"
+	KindOffer   Kind = "offer"
+	// best offer received for a token (or collection) held by an own wallet
+	KindReceivedOffer Kind = "received_offer"
+)
+
+// Position is an open listing or offer of an own wallet or the best offer received for an own token.
+type Position struct {
+	Kind     Kind           `json:"kind"`
+	Contract common.Address `json:"contract"`
+	// empty for collection offers
+	TokenID string         `json:"token_id,omitempty"`
+	Maker   common.Address `json:"maker"`
+
+	// price per item in ether
+	Price float64 `json:"price"`
+
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	AlertedAt time.Time `json:"alerted_at,omitempty"`
+	// alerted about the upcoming expiry
+	ExpiryAlertedAt time.Time `json:"expiry_alerted_at,omitempty"`
+}
+
+// Key identifies the position, a new order for the same token & maker replaces the previous one.
+// only the best received offer is kept per token, regardless of the maker.
+func (p *Position) Key() string {
+	if p.Kind == KindReceivedOffer {
+		return fmt.Sprintf("%s:%s:%s:", p.Kind, p.Contract.Hex(), p.TokenID)
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%s", p.Kind, p.Contract.Hex(), p.TokenID, p.Maker.Hex())
+}
+
+// IsCollectionOffer returns true if the offer is for any token of the collection.
+func (p *Position) IsCollectionOffer() bool {
+	return (p.Kind == KindOffer || p.Kind == KindReceivedOffer) && p.TokenID == ""
+}
+
+var (
+	positions   = make(map[string]*Position)
+	positionsMu sync.Mutex
+)
+
+// Start tracks the listings & offers of the own wallets & alerts if they are live for
+// positions.stale_after without being filled, replaced or cancelled.
+func Start(gb *gloomberg.Gloomberg) {
+	load(gb)
+
+	chanItemListed := gb.SubscribeItemListed()
+	chanItemReceivedBid := gb.SubscribeItemReceivedBid()
+	chanCollectionOffer := gb.SubscribeCollectionOffer()
+	chanTokenTransactions := gb.SubscribeTokenTransactions()
+
+	ticker := time.NewTicker(max(viper.GetDuration("positions.check_interval"), time.Minute))
+	expiryTicker := time.NewTicker(time.Minute)
+
+	for {
+		select {
+		case event := <-chanItemListed:
+			nftID := event.Payload.Item.NftID
+			track(gb, KindListing, nftID.ContractAddress(), nftID.TokenID().String(), &event.Payload.EventPayload)
+
+		case event := <-chanItemReceivedBid:
+			nftID := event.Payload.NftID
+			track(gb, KindOffer, nftID.ContractAddress(), nftID.TokenID().String(), &event.Payload.EventPayload)
+
+			if gb.OwnWallets.ContainsToken(nftID.ContractAddress(), nftID.TokenID().String()) {
+				trackReceived(gb, nftID.ContractAddress(), nftID.TokenID().String(), &event.Payload.EventPayload)
+			}
+
+		case event := <-chanCollectionOffer:
+			track(gb, KindOffer, event.Payload.ContractCriteria.Address, "", &event.Payload.EventPayload)
+
+			if gb.OwnWallets != nil && len(gb.OwnWallets.GetCollectionTokens(event.Payload.ContractCriteria.Address)) > 0 {
+				trackReceived(gb, event.Payload.ContractCriteria.Address, "", &event.Payload.EventPayload)
+			}
+
+		case ttx := <-chanTokenTransactions:
+			closeFilled(gb, ttx)
+
+		case <-ticker.C:
+			checkStale(gb)
+
+		case <-expiryTicker.C:
+			checkExpiring(gb)
+		}
+	}
+}
+
+// Cancel removes the position of a cancelled order (only received if cancellations are subscribed).
+func Cancel(gb *gloomberg.Gloomberg, event *models.GeneralEvent) {
+	if !viper.GetBool("positions.enabled") || degendb.GetEventType(event.EventType) != degendb.Cancelled {
+		return
+	}
+
+	maker := event.Payload.Maker.Address
+	if !isOwnWallet(gb, maker) {
+		return
+	}
+
+	nftID := event.Payload.Item.NftID
+	if len(nftID) == 0 {
+		return
+	}
+
+	for _, kind := range []Kind{KindListing, KindOffer} {
+		position := &Position{Kind: kind, Contract: nftID.ContractAddress(), TokenID: nftID.TokenID().String(), Maker: maker}
+		remove(gb, position.Key())
+	}
+}
+
+func track(gb *gloomberg.Gloomberg, kind Kind, contractAddress common.Address, tokenID string, payload *models.EventPayload) {
+	if !isOwnWallet(gb, payload.Maker.Address) || payload.BasePrice == nil {
+		return
+	}
+
+	position := newPosition(kind, contractAddress, tokenID, payload)
+
+	positionsMu.Lock()
+	positions[position.Key()] = position
+	positionsMu.Unlock()
+
+	gbl.Log.Debugf("⏳ tracking %s %s", position.Kind, position.Key())
+
+	store(gb, position)
+}
+
+// trackReceived keeps the offer for an own token (or collection) if it is the best live offer received for it.
+func trackReceived(gb *gloomberg.Gloomberg, contractAddress common.Address, tokenID string, payload *models.EventPayload) {
+	if isOwnWallet(gb, payload.Maker.Address) || payload.BasePrice == nil || payload.ExpirationDate.IsZero() {
+		return
+	}
+
+	position := newPosition(KindReceivedOffer, contractAddress, tokenID, payload)
+
+	positionsMu.Lock()
+	if current, ok := positions[position.Key()]; ok && current.Price >= position.Price && time.Now().Before(current.ExpiresAt) {
+		positionsMu.Unlock()
+
+		return
+	}
+
+	positions[position.Key()] = position
+	positionsMu.Unlock()
+
+	gbl.Log.Debugf("⏳ tracking best received offer %s", position.Key())
+
+	store(gb, position)
+}
+
+func newPosition(kind Kind, contractAddress common.Address, tokenID string, payload *models.EventPayload) *Position {
+	pricePerItem := new(big.Int).Set(payload.BasePrice)
+	if payload.Quantity > 1 {
+		pricePerItem.Div(pricePerItem, big.NewInt(int64(payload.Quantity)))
+	}
+
+	priceEther, _ := utils.WeiToEther(pricePerItem).Float64()
+
+	createdAt := payload.EventTimestamp
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	return &Position{
+		Kind:      kind,
+		Contract:  contractAddress,
+		TokenID:   tokenID,
+		Maker:     payload.Maker.Address,
+		Price:     priceEther,
+		CreatedAt: createdAt,
+		ExpiresAt: payload.ExpirationDate,
+	}
+}
+
+// closeFilled removes the listings of sold tokens & the offers of the buyers.
+func closeFilled(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.Token == nil || transfer.Token.ID == nil {
+			continue
+		}
+
+		tokenID := transfer.Token.ID.String()
+
+		closed := []*Position{
+			{Kind: KindListing, Contract: transfer.Token.Address, TokenID: tokenID, Maker: transfer.From},
+			{Kind: KindOffer, Contract: transfer.Token.Address, TokenID: tokenID, Maker: transfer.To},
+			{Kind: KindOffer, Contract: transfer.Token.Address, Maker: transfer.To},
+			// the token is gone, so are the offers for it
+			{Kind: KindReceivedOffer, Contract: transfer.Token.Address, TokenID: tokenID, Maker: transfer.From},
+		}
+
+		for _, position := range closed {
+			if position.Maker == internal.ZeroAddress {
+				continue
+			}
+
+			remove(gb, position.Key())
+		}
+	}
+}
+
+func remove(gb *gloomberg.Gloomberg, key string) {
+	positionsMu.Lock()
+	_, ok := positions[key]
+	delete(positions, key)
+	positionsMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	gbl.Log.Debugf("⏳ closed position %s", key)
+
+	if useRedis(gb) {
+		if err := gb.Rueidi.DeletePosition(context.Background(), key); err != nil {
+			gbl.Log.Debugf("⏳ error deleting position %s: %s", key, err)
+		}
+	}
+}
+
+func store(gb *gloomberg.Gloomberg, position *Position) {
+	if !useRedis(gb) {
+		return
+	}
+
+	encoded, err := json.Marshal(position)
+	if err != nil {
+		return
+	}
+
+	if err := gb.Rueidi.StorePosition(context.Background(), position.Key(), string(encoded)); err != nil {
+		gbl.Log.Debugf("⏳ error storing position %s: %s", position.Key(), err)
+	}
+}
+
+// load restores the positions of previous runs.
+func load(gb *gloomberg.Gloomberg) {
+	if !useRedis(gb) {
+		return
+	}
+
+	encodedPositions, err := gb.Rueidi.GetPositions(context.Background())
+	if err != nil {
+		gbl.Log.Debugf("⏳ error loading positions: %s", err)
+
+		return
+	}
+
+	positionsMu.Lock()
+	defer positionsMu.Unlock()
+
+	for key, encoded := range encodedPositions {
+		var position *Position
+		if err := json.Unmarshal([]byte(encoded), &position); err != nil || position == nil {
+			continue
+		}
+
+		positions[key] = position
+	}
+
+	gbl.Log.Infof("⏳ %d open listings & offers of own wallets loaded", len(positions))
+}
+
+func isOwnWallet(gb *gloomberg.Gloomberg, address common.Address) bool {
+	return gb.OwnWallets != nil && gb.OwnWallets.ContainsAddressFromSlice([]common.Address{address}) != internal.ZeroAddress
+}
+
+func useRedis(gb *gloomberg.Gloomberg) bool {
+	return viper.GetBool("redis.enabled") && gb.Rueidi != nil
+}
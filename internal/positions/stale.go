@@ -0,0 +1,146 @@
+package positions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/tokencollections"
+	"github.com/benleb/gloomberg/internal/notify"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/spf13/viper"
+)
+
+// checkStale alerts about positions live for positions.stale_after, repeated every stale_after.
+func checkStale(gb *gloomberg.Gloomberg) {
+	staleAfter := viper.GetDuration("positions.stale_after")
+
+	stalePositions := make([]*Position, 0)
+
+	positionsMu.Lock()
+	for key, position := range positions {
+		if !position.ExpiresAt.IsZero() && time.Now().After(position.ExpiresAt) {
+			delete(positions, key)
+
+			continue
+		}
+
+		// received offers are not ours to reprice
+		if position.Kind == KindReceivedOffer {
+			continue
+		}
+
+		if time.Since(position.CreatedAt) < staleAfter || (!position.AlertedAt.IsZero() && time.Since(position.AlertedAt) < staleAfter) {
+			continue
+		}
+
+		position.AlertedAt = time.Now()
+		stalePositions = append(stalePositions, position)
+	}
+	positionsMu.Unlock()
+
+	sortByAge(stalePositions)
+
+	for _, position := range stalePositions {
+		store(gb, position)
+
+		alert(gb, position)
+	}
+}
+
+func alert(gb *gloomberg.Gloomberg, position *Position) {
+	name := position.Contract.Hex()
+
+	tokenID, _ := strconv.ParseInt(position.TokenID, 10, 64)
+	collection := tokencollections.GetCollection(gb, position.Contract, tokenID)
+
+	if collection != nil && collection.Name != "" {
+		name = collection.Name
+	}
+
+	item := name
+	if position.TokenID != "" {
+		item += " #" + position.TokenID
+	}
+
+	kind := string(position.Kind)
+	if position.IsCollectionOffer() {
+		kind = "collection offer"
+	}
+
+	line := fmt.Sprintf("%s %s at %.3fΞ live for %s without action", kind, item, position.Price, formatAge(time.Since(position.CreatedAt)))
+
+	if collection != nil {
+		line += " · " + suggestion(position, collection.GetFloorEstimate().Floor, salira(collection.GetSaLiCount()))
+	}
+
+	gloomberg.PrModf("stale", "%s", style.AlmostWhiteStyle.Render(line))
+
+	if viper.GetBool("positions.notify") && viper.GetBool("notifications.telegram.enabled") {
+		go notify.SendMessageViaTelegram("⏳ "+line, viper.GetInt64("notifications.telegram.chat_id"), "", 0, nil)
+	}
+}
+
+// suggestion returns a reprice hint based on the current floor & sales/listings ratio.
+// listings are moved to the floor (undercut if there are more listings than sales),
+// offers are raised to positions.offer_floor_ratio of the floor.
+func suggestion(position *Position, floor float64, saLiRa float64) string {
+	if floor <= 0 {
+		return "no floor data"
+	}
+
+	market := fmt.Sprintf("floor %.3fΞ", floor)
+	if saLiRa > 0 {
+		market += fmt.Sprintf(" · salira %.2f", saLiRa)
+	}
+
+	switch position.Kind {
+	case KindListing:
+		target := floor
+		if saLiRa > 0 && saLiRa < 1 {
+			target = floor * (1 - viper.GetFloat64("positions.undercut"))
+		}
+
+		if position.Price > target {
+			return fmt.Sprintf("%s → consider relisting at %.3fΞ", market, target)
+		}
+
+	case KindOffer:
+		target := floor * viper.GetFloat64("positions.offer_floor_ratio")
+
+		if position.Price < target {
+			return fmt.Sprintf("%s → consider raising to %.3fΞ", market, target)
+		}
+	}
+
+	return market + " → price still competitive"
+}
+
+func salira(sales int, listings int) float64 {
+	if listings == 0 {
+		return 0
+	}
+
+	return float64(sales) / float64(listings)
+}
+
+func formatAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	if days == 0 {
+		return age.Truncate(time.Minute).String()
+	}
+
+	return fmt.Sprintf("%dd %dh", days, int(age.Hours())%24)
+}
+
+func sortByAge(sorted []*Position) {
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].Key() < sorted[j].Key()
+		}
+
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+}
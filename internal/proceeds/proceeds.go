@@ -0,0 +1,123 @@
+package proceeds
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var basisPoints = big.NewInt(10_000)
+
+var (
+	// creator royalties in basis points by collection
+	royalties   = make(map[common.Address]int64)
+	royaltiesMu sync.RWMutex
+)
+
+// Enabled returns true if the net proceeds of collection offers should be shown.
+func Enabled() bool {
+	return viper.GetBool("proceeds.enabled")
+}
+
+// Net returns the amount a seller receives for accepting an offer after the creator royalty & marketplace fee.
+func Net(offer *big.Int, royaltyBps int64, marketplaceFeeBps int64) *big.Int {
+	fees := new(big.Int).Mul(offer, big.NewInt(royaltyBps+marketplaceFeeBps))
+	fees.Div(fees, basisPoints)
+
+	return new(big.Int).Sub(offer, fees)
+}
+
+// Label returns the net proceeds of accepting a collection offer if the own wallets hold tokens of the collection.
+func Label(gb *gloomberg.Gloomberg, contractAddress common.Address, offer *big.Int) string {
+	if gb.OwnWallets == nil || offer == nil || offer.Sign() <= 0 {
+		return ""
+	}
+
+	numHeld := len(gb.OwnWallets.GetCollectionTokens(contractAddress))
+	if numHeld == 0 {
+		return ""
+	}
+
+	marketplaceFeeBps := viper.GetInt64("proceeds.marketplace_fee_bps")
+
+	// unknown royalties are shown as such instead of silently assuming none
+	royaltyBps, ok := Royalty(gb, contractAddress)
+
+	fmtRoyalty := "-" + formatBps(royaltyBps) + "% royalty"
+	if !ok {
+		fmtRoyalty = "royalty unknown"
+	}
+
+	return fmt.Sprintf("💰 net %sΞ %s",
+		style.BoldAlmostWhite(fmt.Sprintf("%.3f", price.NewPrice(Net(offer, royaltyBps, marketplaceFeeBps)).Ether())),
+		style.DarkGrayStyle.Render(fmt.Sprintf("(%s -%s%% fee · %d held)", fmtRoyalty, formatBps(marketplaceFeeBps), numHeld)),
+	)
+}
+
+// Royalty returns the creator royalty of a collection in basis points & false if it is unknown. configured
+// royalties (proceeds.royalties) take precedence, otherwise the creator fee is fetched from opensea & cached.
+func Royalty(gb *gloomberg.Gloomberg, contractAddress common.Address) (int64, bool) {
+	for address, bps := range viper.GetStringMap("proceeds.royalties") {
+		if common.HexToAddress(address) == contractAddress {
+			if royaltyBps, err := strconv.ParseInt(fmt.Sprint(bps), 10, 64); err == nil {
+				return royaltyBps, true
+			}
+		}
+	}
+
+	royaltiesMu.RLock()
+	royaltyBps, ok := royalties[contractAddress]
+	royaltiesMu.RUnlock()
+
+	if ok {
+		return royaltyBps, true
+	}
+
+	useRedis := viper.GetBool("redis.enabled") && gb.Rueidi != nil
+
+	if useRedis {
+		if cached, err := gb.Rueidi.GetCachedRoyalty(context.Background(), contractAddress); err == nil {
+			return remember(contractAddress, int64(cached)), true
+		}
+	}
+
+	assetContract := opensea.GetAssetContract(contractAddress)
+	if assetContract == nil {
+		// not cached to retry with the next offer
+		return 0, false
+	}
+
+	royaltyBps = int64(assetContract.DevSellerFeeBasisPoints)
+
+	if useRedis {
+		if err := gb.Rueidi.StoreRoyalty(context.Background(), contractAddress, royaltyBps); err != nil {
+			gbl.Log.Debugf("❌ caching royalty of %s failed: %s", contractAddress.Hex(), err)
+		}
+	}
+
+	return remember(contractAddress, royaltyBps), true
+}
+
+// remember caches the royalty of a collection in memory.
+func remember(contractAddress common.Address, royaltyBps int64) int64 {
+	royaltiesMu.Lock()
+	royalties[contractAddress] = royaltyBps
+	royaltiesMu.Unlock()
+
+	return royaltyBps
+}
+
+func formatBps(bps int64) string {
+	return strings.TrimSuffix(strings.TrimRight(strconv.FormatFloat(float64(bps)/100, 'f', 2, 64), "0"), ".")
+}
@@ -0,0 +1,265 @@
+package profittaking
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// maximum number of blocks fetched per check.
+const maxBlocksPerCheck = 5
+
+// Deposit is a transfer of (sale) proceeds to a known exchange address.
+type Deposit struct {
+	Wallet   common.Address
+	Exchange *degendb.Address
+
+	Value  *big.Int
+	Token  string
+	TxHash common.Hash
+	Time   time.Time
+}
+
+var (
+	// exchange addresses from the degendb address labels.
+	exchanges = make(map[common.Address]*degendb.Address)
+
+	// sellers we are watching for deposits, mapped to the end of their tracking window.
+	tracked = make(map[common.Address]time.Time)
+
+	// wallets that deposited their proceeds to an exchange.
+	takingProfit = make(map[common.Address]*Deposit)
+
+	mu sync.RWMutex
+)
+
+// Enabled returns true if profit taking detection is enabled & exchange labels are available.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return viper.GetBool("profittaking.enabled") && len(exchanges) > 0
+}
+
+// Start sets the exchange addresses from the given labels & starts watching new blocks for deposits of tracked sellers.
+func Start(gb *gloomberg.Gloomberg, labels map[common.Address]*degendb.Address) {
+	mu.Lock()
+
+	for address, label := range labels {
+		if label.HasTag(degendb.TagExchange, degendb.TagExchangeDeposit) {
+			exchanges[address] = label
+		}
+	}
+
+	numExchanges := len(exchanges)
+
+	mu.Unlock()
+
+	if numExchanges == 0 {
+		gbl.Log.Warn("💸 no exchange addresses found in the address labels, profit taking detection disabled")
+
+		return
+	}
+
+	gloomberg.PrModf("profit", "watching sellers for deposits to %s exchange addresses", style.AlmostWhiteStyle.Render(fmt.Sprint(numExchanges)))
+
+	go watchBlocks(gb)
+}
+
+// Track starts watching the given sellers for deposits to exchanges within the configured window.
+func Track(sellers []common.Address) {
+	until := time.Now().Add(viper.GetDuration("profittaking.window"))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, seller := range sellers {
+		tracked[seller] = until
+	}
+}
+
+// Get returns the latest exchange deposit of the wallet if it is (still) tagged as taking profit.
+func Get(wallet common.Address) *Deposit {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	deposit, ok := takingProfit[wallet]
+	if !ok || time.Since(deposit.Time) > viper.GetDuration("profittaking.tag_duration") {
+		return nil
+	}
+
+	return deposit
+}
+
+// Format returns a short description of the deposit, e.g. "💸 taking profit: 1.20 ETH → Binance".
+func (d *Deposit) Format() string {
+	exchange := d.Exchange.Name
+	if exchange == "" {
+		exchange = style.ShortenAdressPTR(&d.Exchange.Address)
+	}
+
+	return fmt.Sprintf("💸 taking profit: %.2f %s → %s", price.NewPrice(d.Value).Ether(), d.Token, exchange)
+}
+
+// watchBlocks checks the txs & weth transfers of new blocks for deposits of the tracked sellers.
+func watchBlocks(gb *gloomberg.Gloomberg) {
+	var lastBlock uint64
+
+	ticker := time.NewTicker(internal.BlockTime)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), internal.BlockTime)
+
+		headBlock, err := gb.ProviderPool.BlockNumber(ctx)
+		if err != nil {
+			gbl.Log.Debugf("💸 failed to get current block number: %s", err)
+			cancel()
+
+			continue
+		}
+
+		sellers := activeSellers()
+
+		// nothing to watch, just follow the chain
+		if len(sellers) == 0 || lastBlock == 0 || headBlock <= lastBlock {
+			lastBlock = headBlock
+			cancel()
+
+			continue
+		}
+
+		// catch up at most a few blocks to avoid hammering the nodes after downtimes
+		fromBlock := lastBlock + 1
+		if headBlock-lastBlock > maxBlocksPerCheck {
+			fromBlock = headBlock - maxBlocksPerCheck + 1
+		}
+
+		for blockNumber := fromBlock; blockNumber <= headBlock; blockNumber++ {
+			checkBlock(ctx, gb, blockNumber, sellers)
+		}
+
+		checkWETHTransfers(ctx, gb, fromBlock, headBlock, sellers)
+
+		lastBlock = headBlock
+
+		cancel()
+	}
+}
+
+// activeSellers returns the tracked sellers & removes the ones with an expired window.
+func activeSellers() map[common.Address]bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	sellers := make(map[common.Address]bool)
+
+	for seller, until := range tracked {
+		if time.Now().After(until) {
+			delete(tracked, seller)
+
+			continue
+		}
+
+		sellers[seller] = true
+	}
+
+	return sellers
+}
+
+// checkBlock checks the eth transfers in the block for deposits of the sellers.
+func checkBlock(ctx context.Context, gb *gloomberg.Gloomberg, blockNumber uint64, sellers map[common.Address]bool) {
+	block, err := gb.ProviderPool.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		gbl.Log.Debugf("💸 failed to get block %d: %s", blockNumber, err)
+
+		return
+	}
+
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || tx.Value().Sign() == 0 {
+			continue
+		}
+
+		exchange := getExchange(*tx.To())
+		if exchange == nil {
+			continue
+		}
+
+		sender, err := types.LatestSignerForChainID(tx.ChainId()).Sender(tx)
+		if err != nil || !sellers[sender] {
+			continue
+		}
+
+		addDeposit(&Deposit{Wallet: sender, Exchange: exchange, Value: tx.Value(), Token: "ETH", TxHash: tx.Hash(), Time: time.Unix(int64(block.Time()), 0)})
+	}
+}
+
+// checkWETHTransfers checks the weth transfers in the block range for deposits of the sellers.
+func checkWETHTransfers(ctx context.Context, gb *gloomberg.Gloomberg, fromBlock uint64, toBlock uint64, sellers map[common.Address]bool) {
+	sellerTopics := make([]common.Hash, 0, len(sellers))
+	for seller := range sellers {
+		sellerTopics = append(sellerTopics, common.BytesToHash(seller.Bytes()))
+	}
+
+	logs, err := gb.ProviderPool.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{internal.WETHContractAddress},
+		Topics:    [][]common.Hash{{common.HexToHash(string(topic.Transfer))}, sellerTopics},
+	})
+	if err != nil {
+		gbl.Log.Debugf("💸 failed to get weth transfers: %s", err)
+
+		return
+	}
+
+	for _, txLog := range logs {
+		if len(txLog.Topics) < 3 || len(txLog.Data) < 32 {
+			continue
+		}
+
+		exchange := getExchange(common.BytesToAddress(txLog.Topics[2].Bytes()))
+		if exchange == nil {
+			continue
+		}
+
+		addDeposit(&Deposit{
+			Wallet:   common.BytesToAddress(txLog.Topics[1].Bytes()),
+			Exchange: exchange,
+			Value:    new(big.Int).SetBytes(txLog.Data[:32]),
+			Token:    "WETH",
+			TxHash:   txLog.TxHash,
+			Time:     time.Now(),
+		})
+	}
+}
+
+func getExchange(address common.Address) *degendb.Address {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return exchanges[address]
+}
+
+func addDeposit(deposit *Deposit) {
+	mu.Lock()
+	takingProfit[deposit.Wallet] = deposit
+	mu.Unlock()
+
+	gloomberg.PrModf("profit", "%s %s", style.BoldAlmostWhite(style.ShortenAdressPTR(&deposit.Wallet)), deposit.Format())
+}
@@ -0,0 +1,64 @@
+package proxywatch
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// selector of supportsInterface(bytes4).
+var supportsInterfaceSelector = hexutil.MustDecode("0x01ffc9a7")
+
+// erc-165 interface ids we check for.
+var knownInterfaces = map[string][4]byte{
+	"ERC721":             {0x80, 0xac, 0x58, 0xcd},
+	"ERC721Metadata":     {0x5b, 0x5e, 0x13, 0x9f},
+	"ERC721Enumerable":   {0x78, 0x0e, 0x9d, 0x63},
+	"ERC1155":            {0xd9, 0xb6, 0x7a, 0x26},
+	"ERC1155MetadataURI": {0x0e, 0x89, 0x34, 0x1c},
+	"ERC2981":            {0x2a, 0x55, 0x20, 0x5a},
+	"ERC4906":            {0x49, 0x06, 0x49, 0x06},
+	"ERC4907":            {0xad, 0x09, 0x2b, 0x5c},
+	"ERC5192":            {0xb4, 0x5a, 0x3c, 0x0e},
+	"ERC173":             {0x7f, 0x58, 0x28, 0xd0},
+	"AccessControl":      {0x79, 0x65, 0xdb, 0x0b},
+}
+
+// supportedInterfaces returns the known interfaces the contract supports at the given block (nil = latest).
+func supportedInterfaces(ctx context.Context, providerPool *provider.Pool, contractAddress common.Address, blockNumber *big.Int) mapset.Set[string] {
+	supported := mapset.NewSet[string]()
+
+	for name, interfaceID := range knownInterfaces {
+		callData := make([]byte, 0, 4+32)
+		callData = append(callData, supportsInterfaceSelector...)
+		callData = append(callData, common.RightPadBytes(interfaceID[:], 32)...)
+
+		result, err := providerPool.CallContract(ctx, ethereum.CallMsg{To: &contractAddress, Data: callData}, blockNumber)
+		if err != nil || len(result) < 32 {
+			continue
+		}
+
+		if new(big.Int).SetBytes(result[:32]).Sign() != 0 {
+			supported.Add(name)
+		}
+	}
+
+	return supported
+}
+
+// diffInterfaces returns the added & removed interfaces sorted by name.
+func diffInterfaces(before mapset.Set[string], after mapset.Set[string]) ([]string, []string) {
+	added := after.Difference(before).ToSlice()
+	removed := before.Difference(after).ToSlice()
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
@@ -0,0 +1,110 @@
+package proxywatch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/notify"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var (
+	topicUpgraded       = common.HexToHash(string(topic.Upgraded))
+	topicAdminChanged   = common.HexToHash(string(topic.AdminChanged))
+	topicBeaconUpgraded = common.HexToHash(string(topic.BeaconUpgraded))
+)
+
+var proxyChangesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_proxy_changes_total",
+	Help: "The number of detected implementation/admin changes of watched proxy contracts.",
+}, []string{"event"})
+
+// HandleReceipt checks the logs of a tx for implementation or admin changes
+// of watched collections behind erc-1967 proxies and raises an alert.
+func HandleReceipt(gb *gloomberg.Gloomberg, receipt *types.Receipt) {
+	if receipt == nil || !viper.GetBool("proxywatch.enabled") {
+		return
+	}
+
+	for _, txLog := range receipt.Logs {
+		if len(txLog.Topics) == 0 {
+			continue
+		}
+
+		var change string
+
+		switch {
+		case txLog.Topics[0] == topicUpgraded && len(txLog.Topics) > 1:
+			change = "implementation changed to " + style.ShortenAddress(common.BytesToAddress(txLog.Topics[1].Bytes()))
+		case txLog.Topics[0] == topicBeaconUpgraded && len(txLog.Topics) > 1:
+			change = "beacon changed to " + style.ShortenAddress(common.BytesToAddress(txLog.Topics[1].Bytes()))
+		case txLog.Topics[0] == topicAdminChanged && len(txLog.Data) >= 64:
+			change = "admin changed to " + style.ShortenAddress(common.BytesToAddress(txLog.Data[32:64]))
+		default:
+			continue
+		}
+
+		gb.CollectionDB.RWMu.RLock()
+		collection, ok := gb.CollectionDB.Collections[txLog.Address]
+		gb.CollectionDB.RWMu.RUnlock()
+
+		if !ok || collection == nil {
+			continue
+		}
+
+		proxyChangesCounter.WithLabelValues(topic.Topic(txLog.Topics[0].Hex()).String()).Inc()
+
+		// the supported interfaces only change with the implementation
+		var added, removed []string
+
+		if txLog.Topics[0] != topicAdminChanged && txLog.BlockNumber > 0 && gb.ProviderPool != nil {
+			ctx := context.Background()
+			blockNumber := new(big.Int).SetUint64(txLog.BlockNumber)
+
+			before := supportedInterfaces(ctx, gb.ProviderPool, txLog.Address, new(big.Int).Sub(blockNumber, big.NewInt(1)))
+			after := supportedInterfaces(ctx, gb.ProviderPool, txLog.Address, blockNumber)
+
+			added, removed = diffInterfaces(before, after)
+		}
+
+		fmtInterfaces := make([]string, 0, len(added)+len(removed))
+		for _, name := range added {
+			fmtInterfaces = append(fmtInterfaces, "+"+name)
+		}
+
+		for _, name := range removed {
+			fmtInterfaces = append(fmtInterfaces, "-"+name)
+		}
+
+		fmtDiff := "no interface changes detected"
+		if len(fmtInterfaces) > 0 {
+			fmtDiff = strings.Join(fmtInterfaces, " ")
+		}
+
+		gloomberg.PrModf("proxy", "%s %s | %s | %s",
+			collection.Style().Render(collection.Name),
+			style.BoldAlmostWhite(change),
+			fmtDiff,
+			style.TerminalLink(utils.GetEtherscanTxURL(txLog.TxHash.Hex()), style.ShortenHashStyled(txLog.TxHash)),
+		)
+
+		if viper.GetBool("notifications.telegram.enabled") {
+			message := strings.Builder{}
+			message.WriteString(fmt.Sprintf("🧬 *%s* %s\n", collection.Name, change))
+			message.WriteString(fmtDiff + "\n")
+			message.WriteString(fmt.Sprintf("[tx](%s) · [contract](%s)", utils.GetEtherscanTxURL(txLog.TxHash.Hex()), utils.GetEtherscanAddressURL(&txLog.Address)))
+
+			go notify.SendMessageViaTelegram(message.String(), viper.GetInt64("proxywatch.telegram_chat_id"), "", 0, nil)
+		}
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"strings"
 
 	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/crossdedupe"
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
 	"github.com/benleb/gloomberg/internal/seawa/models"
 	"github.com/benleb/gloomberg/internal/style"
@@ -19,8 +22,11 @@ import (
 	"github.com/spf13/viper"
 )
 
-func SubscribeToSales(gb *gloomberg.Gloomberg, channel string, queueTokenTransactions chan *totra.TokenTransaction) {
-	err := gb.Rdb.Receive(context.Background(), gb.Rdb.B().Subscribe().Channel(channel).Build(), func(msg rueidis.PubSubMessage) {
+// SubscribeToSales queues the sales received via the redis channel until the context is canceled.
+func SubscribeToSales(ctx context.Context, gb *gloomberg.Gloomberg, channel string, queueTokenTransactions chan *totra.TokenTransaction) {
+	err := gb.Rdb.Receive(ctx, gb.Rdb.B().Subscribe().Channel(channel).Build(), func(msg rueidis.PubSubMessage) {
+		health.EventReceived(health.SourcePubSub)
+
 		// validate json
 		if !json.Valid([]byte(msg.Message)) {
 			gbl.Log.Warnf("❗️ invalid json: %s", msg.Message)
@@ -41,7 +47,7 @@ func SubscribeToSales(gb *gloomberg.Gloomberg, channel string, queueTokenTransac
 
 		queueTokenTransactions <- &ttx
 	})
-	if err != nil {
+	if err != nil && ctx.Err() == nil {
 		gbl.Log.Errorf("❌ error subscribing to redis channel %s: %s", channel, err.Error())
 
 		return
@@ -100,6 +106,8 @@ func eventHandler(gb *gloomberg.Gloomberg, eventMessages *chan rueidis.PubSubMes
 }
 
 func handleEvent(gb *gloomberg.Gloomberg, msg rueidis.PubSubMessage) {
+	health.EventReceived(health.SourcePubSub)
+
 	var rawEvent map[string]interface{}
 
 	// validate json
@@ -200,6 +208,32 @@ func handleEvent(gb *gloomberg.Gloomberg, msg rueidis.PubSubMessage) {
 		// push to event hub
 		gb.In.ItemMetadataUpdated <- &itemMetadataUpdated
 
+	case degendb.Sale:
+		if !crossdedupe.Enabled() {
+			break
+		}
+
+		var itemSold models.ItemSold
+
+		decoderConfig.Result = &itemSold
+		decoder, _ := mapstructure.NewDecoder(&decoderConfig)
+
+		err := decoder.Decode(rawEvent)
+		if err != nil {
+			log.Infof("⚓️❌ decoding incoming %v event failed: %s", generalEvent, err)
+
+			return
+		}
+
+		// only merged into the chain event, stream events are not printed on the clients
+		crossdedupe.AddStreamSale(&crossdedupe.StreamSale{
+			TxHash:          itemSold.Payload.Transaction.Hash,
+			ContractAddress: *generalEvent.ContractAddress(),
+			TokenID:         itemSold.Payload.Item.NftID.TokenID(),
+			Price:           price.NewPrice(itemSold.Payload.PerItemPrice()),
+			PaymentSymbol:   itemSold.Payload.PaymentToken.Symbol,
+		}, nil)
+
 	default:
 		gbl.Log.Warnf("❗️ unknown event type: %s", generalEvent.EventType)
 		gbl.Log.Warnf("❗️         %#v", generalEvent)
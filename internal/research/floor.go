@@ -0,0 +1,160 @@
+package research
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/parquet-go/parquet-go"
+	"github.com/spf13/viper"
+)
+
+// listings older than this are not considered for the floor if they have no expiration date.
+const floorListingLookback = time.Hour * 24 * 30
+
+// HistoricFloor is the floor of a collection at a past date reconstructed from the recorded events.
+type HistoricFloor struct {
+	Contract common.Address
+	Floor    *price.Price
+
+	// "listing" if the floor is the cheapest active listing, "sale" if it is the last sale price
+	Source string
+	At     time.Time
+}
+
+type activeListing struct {
+	price     *big.Int
+	nftID     string
+	listedAt  time.Time
+	expiresAt time.Time
+}
+
+// FloorsAt replays the recorded events in research.directory up to the given time and returns the
+// cheapest active listing (or the last sale if there is no listing) for each of the given contracts.
+func FloorsAt(at time.Time, contracts []common.Address) (map[common.Address]*HistoricFloor, error) {
+	wanted := make(map[string]common.Address, len(contracts))
+	for _, contract := range contracts {
+		wanted[strings.ToLower(contract.Hex())] = contract
+	}
+
+	fileNames, err := filepath.Glob(filepath.Join(viper.GetString("research.directory"), "microstructure_*.parquet"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(fileNames)
+
+	events := make([]Event, 0)
+
+	for _, fileName := range fileNames {
+		// files are named by their start time
+		startedAt, err := time.Parse("20060102T150405", strings.TrimSuffix(strings.TrimPrefix(filepath.Base(fileName), "microstructure_"), ".parquet"))
+		if err == nil && (startedAt.After(at) || startedAt.Before(at.Add(-floorListingLookback*2))) {
+			continue
+		}
+
+		fileEvents, err := parquet.ReadFile[Event](fileName)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, event := range fileEvents {
+			if _, ok := wanted[strings.ToLower(event.Contract)]; ok || event.Type == "cancel" {
+				events = append(events, event)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].ReceivedAtUs < events[j].ReceivedAtUs })
+
+	// active listings per contract by order hash
+	listings := make(map[string]map[string]*activeListing)
+	lastSales := make(map[string]*HistoricFloor)
+
+	for _, event := range events {
+		receivedAt := time.UnixMicro(event.ReceivedAtUs)
+		if receivedAt.After(at) {
+			break
+		}
+
+		contract := strings.ToLower(event.Contract)
+		nftID := contract + ":" + event.TokenID
+
+		switch event.Type {
+		case "listing":
+			eventPrice, ok := new(big.Int).SetString(event.PriceWei, 10)
+			if !ok || event.OrderHash == "" {
+				continue
+			}
+
+			if listings[contract] == nil {
+				listings[contract] = make(map[string]*activeListing)
+			}
+
+			listing := &activeListing{price: eventPrice, nftID: nftID, listedAt: receivedAt}
+			if event.ExpiresAtUs > 0 {
+				listing.expiresAt = time.UnixMicro(event.ExpiresAtUs)
+			}
+
+			listings[contract][event.OrderHash] = listing
+
+		case "cancel":
+			for _, contractListings := range listings {
+				delete(contractListings, event.OrderHash)
+			}
+
+		case "sale":
+			eventPrice, ok := new(big.Int).SetString(event.PriceWei, 10)
+			if !ok {
+				continue
+			}
+
+			// a sold nft is not listed anymore
+			for orderHash, listing := range listings[contract] {
+				if listing.nftID == nftID {
+					delete(listings[contract], orderHash)
+				}
+			}
+
+			lastSales[contract] = &HistoricFloor{Contract: wanted[contract], Floor: price.NewPrice(eventPrice), Source: "sale", At: receivedAt}
+		}
+	}
+
+	floors := make(map[common.Address]*HistoricFloor, len(wanted))
+
+	for contract, contractAddress := range wanted {
+		var cheapest *activeListing
+
+		for _, listing := range listings[contract] {
+			if !listing.expiresAt.IsZero() && listing.expiresAt.Before(at) {
+				continue
+			}
+
+			if listing.expiresAt.IsZero() && listing.listedAt.Before(at.Add(-floorListingLookback)) {
+				continue
+			}
+
+			if cheapest == nil || listing.price.Cmp(cheapest.price) < 0 {
+				cheapest = listing
+			}
+		}
+
+		switch {
+		case cheapest != nil:
+			floors[contractAddress] = &HistoricFloor{Contract: contractAddress, Floor: price.NewPrice(cheapest.price), Source: "listing", At: cheapest.listedAt}
+		case lastSales[contract] != nil:
+			floors[contractAddress] = lastSales[contract]
+		}
+	}
+
+	return floors, nil
+}
@@ -0,0 +1,133 @@
+package research
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+// Event is a single market event as persisted to the parquet files.
+type Event struct {
+	// receive time in gloomberg & the time the event was sent/emitted by the source (0 if unknown)
+	ReceivedAtUs int64 `parquet:"received_at_us"`
+	SentAtUs     int64 `parquet:"sent_at_us"`
+
+	// listing, bid, collection_offer, cancel or sale
+	Type   string `parquet:"type,dict"`
+	Source string `parquet:"source,dict"`
+
+	Slug     string `parquet:"slug,dict"`
+	Contract string `parquet:"contract,dict"`
+	TokenID  string `parquet:"token_id"`
+
+	// price per item in wei
+	PriceWei string `parquet:"price_wei"`
+	Quantity int64  `parquet:"quantity"`
+
+	Maker string `parquet:"maker"`
+	Taker string `parquet:"taker"`
+
+	OrderHash   string `parquet:"order_hash"`
+	TxHash      string `parquet:"tx_hash"`
+	ExpiresAtUs int64  `parquet:"expires_at_us"`
+}
+
+var eventsRecordedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_research_events_recorded_total",
+	Help: "The number of market events persisted to the research parquet files.",
+}, []string{"type"})
+
+// recorder writes the events to parquet files in research.directory,
+// a new file is started every research.rotate_interval.
+type recorder struct {
+	events chan *Event
+
+	file   *os.File
+	writer *parquet.GenericWriter[Event]
+}
+
+func newRecorder() *recorder {
+	return &recorder{events: make(chan *Event, 10240)}
+}
+
+func (r *recorder) record(event *Event) {
+	select {
+	case r.events <- event:
+	default:
+		gbl.Log.Warnf("🔬 research queue full, dropping %s event", event.Type)
+	}
+}
+
+func (r *recorder) run() {
+	rotateTicker := time.NewTicker(viper.GetDuration("research.rotate_interval"))
+
+	for {
+		select {
+		case event := <-r.events:
+			if r.writer == nil {
+				if err := r.open(); err != nil {
+					gbl.Log.Errorf("❌ research: creating parquet file failed: %s", err)
+
+					continue
+				}
+			}
+
+			if _, err := r.writer.Write([]Event{*event}); err != nil {
+				gbl.Log.Errorf("❌ research: writing %s event failed: %s", event.Type, err)
+
+				continue
+			}
+
+			eventsRecordedCounter.WithLabelValues(event.Type).Inc()
+
+		case <-rotateTicker.C:
+			r.close()
+		}
+	}
+}
+
+func (r *recorder) open() error {
+	directory := viper.GetString("research.directory")
+
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return err
+	}
+
+	fileName := filepath.Join(directory, fmt.Sprintf("microstructure_%s.parquet", time.Now().UTC().Format("20060102T150405")))
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	r.file = file
+	r.writer = parquet.NewGenericWriter[Event](file, parquet.Compression(&parquet.Zstd))
+
+	gbl.Log.Infof("🔬 research: recording to %s", fileName)
+
+	return nil
+}
+
+// close finishes the current file, a new one is created with the next event.
+func (r *recorder) close() {
+	if r.writer == nil {
+		return
+	}
+
+	if err := r.writer.Close(); err != nil {
+		gbl.Log.Errorf("❌ research: closing parquet writer failed: %s", err)
+	}
+
+	if err := r.file.Close(); err != nil {
+		gbl.Log.Errorf("❌ research: closing parquet file failed: %s", err)
+	}
+
+	r.writer, r.file = nil, nil
+}
@@ -0,0 +1,210 @@
+package research
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/seawa/models"
+	collectionSlugs "github.com/benleb/gloomberg/internal/slugs"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+var (
+	rec   *recorder
+	slugs = mapset.NewSet[string]()
+)
+
+// Start records every listing, bid, collection offer & sale of the collections in
+// research.slugs into parquet files for offline market-microstructure analysis.
+// cancellations are only available if the opensea stream is consumed directly (see RecordStreamEvent).
+func Start(gb *gloomberg.Gloomberg, subscribe func(degendb.SlugSubscriptions) uint64) {
+	for _, slugOrAddress := range viper.GetStringSlice("research.slugs") {
+		slug := slugOrAddress
+
+		// collections can also be configured by address
+		if common.IsHexAddress(slugOrAddress) {
+			var err error
+			if slug, err = collectionSlugs.Slug(context.Background(), common.HexToAddress(slugOrAddress)); err != nil {
+				gbl.Log.Warnf("🔬 research: no slug found for %s: %s", slugOrAddress, err)
+
+				continue
+			}
+		}
+
+		slugs.Add(strings.ToLower(slug))
+	}
+
+	if slugs.Cardinality() == 0 {
+		gbl.Log.Warn("🔬 research mode enabled but no research.slugs configured")
+
+		return
+	}
+
+	rec = newRecorder()
+	go rec.run()
+
+	// make sure we receive all order events for the research collections
+	if subscribe != nil {
+		subscriptions := make(degendb.SlugSubscriptions, 0, slugs.Cardinality())
+		for _, slug := range slugs.ToSlice() {
+			subscriptions = append(subscriptions, degendb.SlugSubscription{
+				Slug:   slug,
+				Events: []degendb.EventType{degendb.Listing, degendb.Bid, degendb.CollectionOffer, degendb.Cancelled},
+			})
+		}
+
+		subscribe(subscriptions)
+	}
+
+	chanItemListed := gb.SubscribeItemListed()
+	chanItemReceivedBid := gb.SubscribeItemReceivedBid()
+	chanCollectionOffer := gb.SubscribeCollectionOffer()
+	chanTokenTransactions := gb.SubscribeTokenTransactions()
+
+	for {
+		select {
+		case event := <-chanItemListed:
+			recordOrder("listing", event.Payload.CollectionSlug.Slug, event.SentAt, &event.Payload.EventPayload, &event.Payload.Item.NftID)
+
+		case event := <-chanItemReceivedBid:
+			recordOrder("bid", event.Payload.EventPayload.CollectionSlug.Slug, event.SentAt, &event.Payload.EventPayload, &event.Payload.Item.NftID)
+
+		case event := <-chanCollectionOffer:
+			recordOrder("collection_offer", event.Payload.Collection.Slug, event.SentAt, &event.Payload.EventPayload, nil)
+
+		case ttx := <-chanTokenTransactions:
+			recordSale(gb, ttx)
+		}
+	}
+}
+
+// Enabled returns true if the events of the collection are recorded.
+func Enabled(slug string) bool {
+	return rec != nil && slugs.Contains(strings.ToLower(slug))
+}
+
+// RecordStreamEvent records events directly from the opensea stream that are not passed through the eventhub (cancellations).
+func RecordStreamEvent(event *models.GeneralEvent) {
+	if degendb.GetEventType(event.EventType) != degendb.Cancelled {
+		return
+	}
+
+	recordOrder("cancel", event.Payload.EventPayload.CollectionSlug.Slug, event.SentAt, &event.Payload.EventPayload, &event.Payload.Item.NftID)
+}
+
+func recordOrder(eventType string, slug string, sentAt time.Time, payload *models.EventPayload, nftID *models.NftID) {
+	if !Enabled(slug) {
+		return
+	}
+
+	event := &Event{
+		ReceivedAtUs: time.Now().UnixMicro(),
+		SentAtUs:     unixMicro(sentAt),
+		Type:         eventType,
+		Source:       "opensea",
+		Slug:         slug,
+		Quantity:     int64(payload.Quantity),
+		Maker:        payload.Maker.Address.Hex(),
+		Taker:        payload.Taker.Address.Hex(),
+		OrderHash:    payload.OrderHash.Hex(),
+		ExpiresAtUs:  unixMicro(payload.ExpirationDate),
+	}
+
+	if payload.BasePrice != nil {
+		pricePerItem := new(big.Int).Set(payload.BasePrice)
+		if payload.Quantity > 1 {
+			pricePerItem.Div(pricePerItem, big.NewInt(int64(payload.Quantity)))
+		}
+
+		event.PriceWei = pricePerItem.String()
+	}
+
+	if nftID != nil && len(*nftID) > 0 {
+		event.Contract = nftID.ContractAddress().Hex()
+		event.TokenID = nftID.TID()
+	}
+
+	rec.record(event)
+}
+
+func recordSale(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
+	if rec == nil || ttx.AmountPaid == nil || ttx.AmountPaid.Sign() == 0 || ttx.TotalTokens == 0 {
+		return
+	}
+
+	if ttx.Action != degendb.Sale && ttx.Action != degendb.Purchase && ttx.Action != degendb.AcceptedOffer && ttx.Action != degendb.AcceptedCollectionOffer {
+		return
+	}
+
+	// bundles of several collections are split by collection
+	numTokensByContract := make(map[common.Address]int64)
+
+	for _, transfer := range ttx.Transfers {
+		if transfer.Standard.IsERC721orERC1155() && transfer.Token != nil && transfer.AmountTokens != nil {
+			numTokensByContract[transfer.Token.Address] += transfer.AmountTokens.Int64()
+		}
+	}
+
+	receivedAt := unixMicro(ttx.ReceivedAt)
+	if receivedAt == 0 {
+		receivedAt = time.Now().UnixMicro()
+	}
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.Token == nil {
+			continue
+		}
+
+		gb.CollectionDB.RWMu.RLock()
+		collection, ok := gb.CollectionDB.Collections[transfer.Token.Address]
+		gb.CollectionDB.RWMu.RUnlock()
+
+		if !ok || collection == nil || !Enabled(collection.OpenseaSlug) {
+			continue
+		}
+
+		pricePerItem := new(big.Int).Div(ttx.AmountPaid, big.NewInt(ttx.TotalTokens))
+		if numTokens := numTokensByContract[transfer.Token.Address]; numTokens > 0 {
+			pricePerItem = new(big.Int).Div(ttx.AmountPaidForCollection(transfer.Token.Address), big.NewInt(numTokens))
+		}
+
+		event := &Event{
+			ReceivedAtUs: receivedAt,
+			Type:         "sale",
+			Source:       "chain",
+			Slug:         collection.OpenseaSlug,
+			Contract:     transfer.Token.Address.Hex(),
+			PriceWei:     pricePerItem.String(),
+			Quantity:     1,
+			Maker:        transfer.From.Hex(),
+			Taker:        transfer.To.Hex(),
+			TxHash:       ttx.TxHash.Hex(),
+		}
+
+		if transfer.Token.ID != nil {
+			event.TokenID = transfer.Token.ID.String()
+		}
+
+		if transfer.AmountTokens != nil {
+			event.Quantity = transfer.AmountTokens.Int64()
+		}
+
+		rec.record(event)
+	}
+}
+
+func unixMicro(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMicro()
+}
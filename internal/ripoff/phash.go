@@ -0,0 +1,114 @@
+package ripoff
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/bits"
+	"sort"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// images are scaled to this size before the dct
+	dctSize = 32
+
+	// the lowest frequencies of the dct are used for the hash
+	hashSize = 8
+)
+
+// dctCosines are the precalculated cosines of the dct.
+var dctCosines = func() [dctSize][dctSize]float64 {
+	var cosines [dctSize][dctSize]float64
+
+	for u := 0; u < dctSize; u++ {
+		for x := 0; x < dctSize; x++ {
+			cosines[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * dctSize))
+		}
+	}
+
+	return cosines
+}()
+
+// PHash returns the 64bit perceptual hash (dct based) of an image.
+// similar images have hashes with a small hamming distance.
+func PHash(img image.Image) uint64 {
+	// transparent parts are shown white on most marketplaces
+	bounds := img.Bounds()
+	flattened := image.NewRGBA(bounds)
+	draw.Draw(flattened, bounds, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flattened, bounds, img, bounds.Min, draw.Over)
+
+	gray := image.NewGray(image.Rect(0, 0, dctSize, dctSize))
+	draw.ApproxBiLinear.Scale(gray, gray.Bounds(), flattened, bounds, draw.Src, nil)
+
+	var pixels [dctSize][dctSize]float64
+
+	for y := 0; y < dctSize; y++ {
+		for x := 0; x < dctSize; x++ {
+			pixels[y][x] = float64(gray.GrayAt(x, y).Y)
+		}
+	}
+
+	coefficients := dct2D(pixels)
+
+	// lowest frequencies without the dc coefficient (average brightness)
+	values := make([]float64, 0, hashSize*hashSize)
+
+	for y := 0; y < hashSize; y++ {
+		for x := 0; x < hashSize; x++ {
+			values = append(values, coefficients[y][x])
+		}
+	}
+
+	sorted := make([]float64, len(values)-1)
+	copy(sorted, values[1:])
+	sort.Float64s(sorted)
+
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+
+	for i, value := range values {
+		if value > median {
+			hash |= 1 << uint(i)
+		}
+	}
+
+	return hash
+}
+
+// Distance returns the hamming distance of two hashes (0 = identical, 64 = inverted).
+func Distance(a uint64, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// dct2D is a (unnormalized) two-dimensional dct-ii, rows first.
+func dct2D(pixels [dctSize][dctSize]float64) [dctSize][dctSize]float64 {
+	var rows, coefficients [dctSize][dctSize]float64
+
+	for y := 0; y < dctSize; y++ {
+		for u := 0; u < dctSize; u++ {
+			var sum float64
+			for x := 0; x < dctSize; x++ {
+				sum += pixels[y][x] * dctCosines[u][x]
+			}
+
+			rows[y][u] = sum
+		}
+	}
+
+	for u := 0; u < dctSize; u++ {
+		for v := 0; v < dctSize; v++ {
+			var sum float64
+			for y := 0; y < dctSize; y++ {
+				sum += rows[y][u] * dctCosines[v][y]
+			}
+
+			coefficients[v][u] = sum
+		}
+	}
+
+	return coefficients
+}
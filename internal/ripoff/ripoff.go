@@ -0,0 +1,301 @@
+package ripoff
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register gif decoder
+	_ "image/jpeg" // register jpeg decoder
+	_ "image/png"  // register png decoder
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+	_ "golang.org/x/image/webp" // register webp decoder
+)
+
+// images larger than this are not hashed.
+const maxImageSize = 16 << 20
+
+var (
+	ErrNoImage       = errors.New("no token image")
+	ErrImageTooLarge = errors.New("token image too large")
+)
+
+// reference is an established collection with the hashes of some of its token images.
+type reference struct {
+	name   string
+	hashes []uint64
+}
+
+var (
+	// established collections to compare new collections with
+	references   = make(map[common.Address]*reference)
+	referencesMu sync.RWMutex
+
+	// collections already checked in this session
+	checked = mapset.NewSet[common.Address]()
+
+	// likely derivatives with the name of the collection they copy
+	derivatives   = make(map[common.Address]string)
+	derivativesMu sync.RWMutex
+
+	// limits the concurrent image downloads
+	fetchSemaphore = make(chan struct{}, 2)
+
+	derivativesCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "gloomberg_ripoff_derivatives_total",
+		Help: "The number of collections detected as likely derivatives of established collections.",
+	})
+)
+
+// Enabled returns true if new collections should be checked for copied art.
+func Enabled() bool {
+	return viper.GetBool("ripoff.enabled")
+}
+
+// LoadReferences hashes the token images of the configured collections & ripoff.references.
+// hashes are cached in redis, so the images are only fetched once.
+func LoadReferences(gb *gloomberg.Gloomberg) {
+	addresses := mapset.NewSet[common.Address]()
+
+	for _, address := range viper.GetStringSlice("ripoff.references") {
+		if common.IsHexAddress(address) {
+			addresses.Add(common.HexToAddress(address))
+		}
+	}
+
+	gb.CollectionDB.RWMu.RLock()
+	for address, collection := range gb.CollectionDB.Collections {
+		if collection.Source == degendb.FromConfiguration {
+			addresses.Add(address)
+		}
+	}
+	gb.CollectionDB.RWMu.RUnlock()
+
+	for _, address := range addresses.ToSlice() {
+		hashes := collectionHashes(gb, address, viper.GetInt("ripoff.samples"))
+		if len(hashes) == 0 {
+			continue
+		}
+
+		name, err := gb.ProviderPool.ERC721CollectionName(context.Background(), address)
+		if err != nil || name == "" {
+			name = style.ShortenAddress(address)
+		}
+
+		referencesMu.Lock()
+		references[address] = &reference{name: name, hashes: hashes}
+		referencesMu.Unlock()
+	}
+
+	referencesMu.RLock()
+	gbl.Log.Infof("🚨 ripoff | %d reference collections loaded", len(references))
+	referencesMu.RUnlock()
+}
+
+// Label returns a label for events of collections detected as likely derivative & starts
+// the check of collections not seen before in the background.
+func Label(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) string {
+	for _, transfer := range ttx.Transfers {
+		if transfer.Token == nil || !transfer.Standard.IsERC721orERC1155() {
+			continue
+		}
+
+		contractAddress := transfer.Token.Address
+
+		derivativesMu.RLock()
+		original, isDerivative := derivatives[contractAddress]
+		derivativesMu.RUnlock()
+
+		if isDerivative {
+			return "⚠️ likely derivative of " + original
+		}
+
+		referencesMu.RLock()
+		_, isReference := references[contractAddress]
+		referencesMu.RUnlock()
+
+		// configured collections are references themselves
+		gb.CollectionDB.RWMu.RLock()
+		collection, isConfigured := gb.CollectionDB.Collections[contractAddress]
+		gb.CollectionDB.RWMu.RUnlock()
+
+		if isConfigured && collection.Source == degendb.FromConfiguration {
+			continue
+		}
+
+		if !isReference && checked.Add(contractAddress) {
+			go check(gb, contractAddress, transfer.Token.ID)
+		}
+	}
+
+	return ""
+}
+
+// check compares the token image of a collection with the reference collections.
+func check(gb *gloomberg.Gloomberg, contractAddress common.Address, tokenID *big.Int) {
+	if tokenID == nil {
+		return
+	}
+
+	imageHash, err := tokenImageHash(gb, contractAddress, tokenID)
+	if err != nil {
+		gbl.Log.Debugf("🚨 ripoff | could not hash the image of %s #%s: %s", contractAddress.Hex(), tokenID.String(), err)
+
+		return
+	}
+
+	maxDistance := viper.GetInt("ripoff.max_distance")
+
+	bestDistance := 65
+	bestMatch := ""
+
+	referencesMu.RLock()
+	for address, ref := range references {
+		if address == contractAddress {
+			continue
+		}
+
+		for _, referenceHash := range ref.hashes {
+			if distance := Distance(imageHash, referenceHash); distance < bestDistance {
+				bestDistance, bestMatch = distance, ref.name
+			}
+		}
+	}
+	referencesMu.RUnlock()
+
+	if bestDistance > maxDistance {
+		return
+	}
+
+	derivativesMu.Lock()
+	derivatives[contractAddress] = bestMatch
+	derivativesMu.Unlock()
+
+	derivativesCounter.Inc()
+
+	name, err := gb.ProviderPool.ERC721CollectionName(context.Background(), contractAddress)
+	if err != nil || name == "" {
+		name = style.ShortenAddress(contractAddress)
+	}
+
+	gloomberg.PrModf("scam", "%s looks like a derivative of %s %s | %s",
+		style.AlmostWhiteStyle.Render(name),
+		style.BoldAlmostWhite(bestMatch),
+		style.GrayStyle.Render(fmt.Sprintf("(image distance %d/64)", bestDistance)),
+		style.TerminalLink(utils.GetEtherscanTokenURLForAddress(contractAddress), style.ShortenAddressStyled(&contractAddress, style.GrayStyle)),
+	)
+}
+
+// collectionHashes returns the image hashes of the first n tokens of a collection.
+func collectionHashes(gb *gloomberg.Gloomberg, contractAddress common.Address, n int) []uint64 {
+	hashes := make([]uint64, 0, n)
+
+	// token ids start at 0 or 1
+	for id := int64(0); id <= int64(n) && len(hashes) < n; id++ {
+		imageHash, err := tokenImageHash(gb, contractAddress, big.NewInt(id))
+		if err != nil {
+			continue
+		}
+
+		hashes = append(hashes, imageHash)
+	}
+
+	return hashes
+}
+
+// tokenImageHash returns the perceptual hash of a token image from redis or fetches & hashes the image.
+func tokenImageHash(gb *gloomberg.Gloomberg, contractAddress common.Address, tokenID *big.Int) (uint64, error) {
+	ctx := context.Background()
+
+	if viper.GetBool("redis.enabled") && gb.Rueidi != nil {
+		if cachedHashes, err := gb.Rueidi.GetImageHashes(ctx, contractAddress); err == nil {
+			if imageHash, ok := cachedHashes[tokenID.String()]; ok {
+				return imageHash, nil
+			}
+		}
+	}
+
+	fetchSemaphore <- struct{}{}
+	defer func() { <-fetchSemaphore }()
+
+	imageURI, err := gb.ProviderPool.GetTokenImageURI(ctx, contractAddress, tokenID)
+	if err != nil {
+		return 0, err
+	}
+
+	img, err := fetchImage(ctx, imageURI)
+	if err != nil {
+		return 0, err
+	}
+
+	imageHash := PHash(img)
+
+	if viper.GetBool("redis.enabled") && gb.Rueidi != nil {
+		if err := gb.Rueidi.StoreImageHash(ctx, contractAddress, tokenID.String(), imageHash); err != nil {
+			gbl.Log.Debugf("🚨 ripoff | could not cache the image hash of %s #%s: %s", contractAddress.Hex(), tokenID.String(), err)
+		}
+	}
+
+	return imageHash, nil
+}
+
+// fetchImage downloads & decodes an image (png, jpeg, gif or webp), data uris are decoded directly.
+func fetchImage(ctx context.Context, imageURI string) (image.Image, error) {
+	if imageURI == "" {
+		return nil, ErrNoImage
+	}
+
+	var reader io.Reader
+
+	if strings.HasPrefix(imageURI, "data:") {
+		_, data, found := strings.Cut(imageURI, ";base64,")
+		if !found {
+			return nil, ErrNoImage
+		}
+
+		reader = base64.NewDecoder(base64.StdEncoding, strings.NewReader(data))
+	} else {
+		response, err := utils.HTTP.GetWithTLS12(ctx, utils.PrepareURL(imageURI))
+		if err != nil {
+			return nil, err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%w: http %d", ErrNoImage, response.StatusCode)
+		}
+
+		reader = response.Body
+	}
+
+	rawImage, err := io.ReadAll(io.LimitReader(reader, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawImage) > maxImageSize {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(rawImage))
+
+	return img, err
+}
@@ -0,0 +1,60 @@
+package royalties
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+)
+
+// Breakdown splits the amount paid of a sale into the proceeds of the seller, the creator royalties & the marketplace
+// fees using the payments of the marketplace orders. nil for other events & sales without order payments (non-seaport).
+func Breakdown(ttx *totra.TokenTransaction) *degendb.FeeBreakdown {
+	if !degendb.SaleTypes.Contains(ttx.Action) || ttx.OrderPayments == nil || ttx.AmountPaid == nil || ttx.AmountPaid.Sign() <= 0 {
+		return nil
+	}
+
+	royalties, fees := big.NewInt(0), big.NewInt(0)
+
+	for recipient, amount := range ttx.OrderPayments {
+		if marketplaceFeeRecipients[recipient] {
+			fees.Add(fees, amount)
+		} else {
+			royalties.Add(royalties, amount)
+		}
+	}
+
+	seller := new(big.Int).Sub(ttx.AmountPaid, royalties)
+	seller.Sub(seller, fees)
+
+	if seller.Sign() < 0 {
+		return nil
+	}
+
+	return &degendb.FeeBreakdown{
+		Seller:          price.NewPrice(seller),
+		Royalties:       price.NewPrice(royalties),
+		MarketplaceFees: price.NewPrice(fees),
+	}
+}
+
+// BreakdownLine formats the breakdown as extra line below the sale.
+func BreakdownLine(breakdown *degendb.FeeBreakdown) string {
+	total := breakdown.Seller.Ether() + breakdown.Royalties.Ether() + breakdown.MarketplaceFees.Ether()
+
+	formatPart := func(name string, part *price.Price) string {
+		share := 0.0
+		if total > 0 {
+			share = part.Ether() / total * 100
+		}
+
+		return style.GrayStyle.Render(name+" ") + style.AlmostWhiteStyle.Render(fmt.Sprintf("%.4fΞ", part.Ether())) + style.DarkGrayStyle.Render(fmt.Sprintf(" (%.1f%%)", share))
+	}
+
+	divider := style.DarkGrayStyle.Render(" · ")
+
+	return "          💸 " + formatPart("seller", breakdown.Seller) + divider + formatPart("royalties", breakdown.Royalties) + divider + formatPart("fees", breakdown.MarketplaceFees)
+}
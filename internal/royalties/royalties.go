@@ -0,0 +1,237 @@
+package royalties
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/provider"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Status is the royalty compliance of a sale.
+type Status string
+
+const (
+	Paid    Status = "paid"
+	Partial Status = "partial"
+	Unpaid  Status = "unpaid"
+)
+
+// royalties paid at least 95% of the expected amount are counted as paid (rounding of the marketplaces).
+const paidTolerancePercent = 95
+
+var basisPoints = big.NewInt(10_000)
+
+// marketplace fee recipients, payments to them are not royalties.
+var marketplaceFeeRecipients = map[common.Address]bool{
+	common.HexToAddress("0x0000a26b00c1F0DF003000390027140000fAa719"): true, // opensea
+	common.HexToAddress("0x8De9C5A032463C561423387a9648c5C7BCC5BC90"): true, // opensea (legacy)
+}
+
+var (
+	// erc2981 royalties by collection, nil if the collection does not support erc2981
+	royaltyInfos   = make(map[common.Address]*provider.Royalty)
+	royaltyInfosMu sync.RWMutex
+)
+
+// Enabled returns true if the royalty compliance of sales should be checked.
+func Enabled() bool {
+	return viper.GetBool("royalties.enabled")
+}
+
+// Check compares the royalties paid by the seaport orders of a sale of a watched collection with the erc2981
+// royalty of the collection, counts the result in the compliance stats & returns a label for the sale.
+// sales of several collections & sales without order payments (non-seaport) are not checked.
+func Check(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) string {
+	if !degendb.SaleTypes.Contains(ttx.Action) || ttx.OrderPayments == nil || ttx.AmountPaid == nil || ttx.AmountPaid.Sign() <= 0 {
+		return ""
+	}
+
+	transfersByContract := ttx.GetTransfersByContract()
+
+	var contractAddress common.Address
+
+	var tokenID *big.Int
+
+	for address, transfers := range transfersByContract {
+		if len(transfers) == 0 || !transfers[0].Standard.IsERC721orERC1155() {
+			continue
+		}
+
+		if tokenID != nil {
+			// several collections
+			return ""
+		}
+
+		contractAddress, tokenID = address, transfers[0].Token.ID
+	}
+
+	if tokenID == nil || !isWatched(gb, contractAddress) {
+		return ""
+	}
+
+	royalty := Info(gb, contractAddress, tokenID)
+	if royalty == nil || royalty.BasisPoints <= 0 || royalty.Receiver == internal.ZeroAddress {
+		return ""
+	}
+
+	expected := new(big.Int).Mul(ttx.AmountPaid, big.NewInt(royalty.BasisPoints))
+	expected.Div(expected, basisPoints)
+
+	paid := paidRoyalty(ttx.OrderPayments, royalty.Receiver)
+	status := statusOf(paid, expected)
+
+	go record(gb, contractAddress, status, expected, paid)
+
+	fmtBps := strings.TrimSuffix(strings.TrimRight(strconv.FormatFloat(float64(royalty.BasisPoints)/100, 'f', 2, 64), "0"), ".")
+
+	switch status {
+	case Paid:
+		return "👑 " + style.TrendLightGreenStyle.Render("royalty paid") + style.DarkGrayStyle.Render(fmt.Sprintf(" (%s%%)", fmtBps))
+	case Partial:
+		return "👑 " + style.BoldAlmostWhite("royalty partially paid") + style.DarkGrayStyle.Render(fmt.Sprintf(" (%d%% of %s%%)", percentOf(paid, expected), fmtBps))
+	default:
+		return "👑 " + style.TrendLightRedStyle.Render("royalty skipped") + style.DarkGrayStyle.Render(fmt.Sprintf(" (%s%%)", fmtBps))
+	}
+}
+
+// Info returns the erc2981 royalty of a collection (queried with the given token) or nil if it is not supported.
+// the royalty is assumed to be the same for all tokens of a collection & cached in memory & redis.
+func Info(gb *gloomberg.Gloomberg, contractAddress common.Address, tokenID *big.Int) *provider.Royalty {
+	royaltyInfosMu.RLock()
+	royalty, ok := royaltyInfos[contractAddress]
+	royaltyInfosMu.RUnlock()
+
+	if ok {
+		return royalty
+	}
+
+	useRedis := viper.GetBool("redis.enabled") && gb.Rueidi != nil
+
+	if useRedis {
+		if cached, err := gb.Rueidi.GetCachedRoyaltyInfo(context.Background(), contractAddress); err == nil {
+			return remember(contractAddress, parseRoyaltyInfo(cached))
+		}
+	}
+
+	royalty, err := gb.ProviderPool.RoyaltyInfo(context.Background(), contractAddress, tokenID)
+	if err != nil {
+		gbl.Log.Debugf("👑 no erc2981 royalty for %s: %s", contractAddress.Hex(), err)
+	}
+
+	if useRedis {
+		if err := gb.Rueidi.StoreRoyaltyInfo(context.Background(), contractAddress, formatRoyaltyInfo(royalty)); err != nil {
+			gbl.Log.Debugf("❌ caching royalty info of %s failed: %s", contractAddress.Hex(), err)
+		}
+	}
+
+	return remember(contractAddress, royalty)
+}
+
+// paidRoyalty returns the amount paid to the royalty receiver. if the receiver got nothing, payments to other
+// recipients than the marketplaces are counted as royalties (creator fees configured with another payout address).
+func paidRoyalty(payments map[common.Address]*big.Int, receiver common.Address) *big.Int {
+	if paid := payments[receiver]; paid != nil && paid.Sign() > 0 {
+		return paid
+	}
+
+	paid := big.NewInt(0)
+
+	for recipient, amount := range payments {
+		if !marketplaceFeeRecipients[recipient] {
+			paid.Add(paid, amount)
+		}
+	}
+
+	return paid
+}
+
+func statusOf(paid *big.Int, expected *big.Int) Status {
+	switch {
+	case paid.Sign() <= 0:
+		return Unpaid
+	case percentOf(paid, expected) >= paidTolerancePercent:
+		return Paid
+	default:
+		return Partial
+	}
+}
+
+func percentOf(amount *big.Int, total *big.Int) int64 {
+	if total.Sign() <= 0 {
+		return 100
+	}
+
+	percent := new(big.Int).Mul(amount, big.NewInt(100))
+
+	return percent.Div(percent, total).Int64()
+}
+
+// isWatched returns true for the collections configured or held by the user (not the ones discovered via the stream).
+func isWatched(gb *gloomberg.Gloomberg, contractAddress common.Address) bool {
+	gb.CollectionDB.RWMu.RLock()
+	collection, ok := gb.CollectionDB.Collections[contractAddress]
+	gb.CollectionDB.RWMu.RUnlock()
+
+	return ok && collection.Source != degendb.FromStream
+}
+
+// record counts the sale in the royalty compliance stats of the collection.
+func record(gb *gloomberg.Gloomberg, contractAddress common.Address, status Status, expected *big.Int, paid *big.Int) {
+	if !viper.GetBool("redis.enabled") || gb.Rueidi == nil {
+		return
+	}
+
+	increments := map[string]int64{
+		"sales":         1,
+		string(status):  1,
+		"expected_gwei": new(big.Int).Div(expected, big.NewInt(1e9)).Int64(),
+		"paid_gwei":     new(big.Int).Div(paid, big.NewInt(1e9)).Int64(),
+	}
+
+	if err := gb.Rueidi.IncrRoyaltyStats(context.Background(), contractAddress, increments); err != nil {
+		gbl.Log.Debugf("❗️ royalties | could not count sale of %s: %s", contractAddress.Hex(), err)
+	}
+}
+
+// remember caches the royalty of a collection in memory.
+func remember(contractAddress common.Address, royalty *provider.Royalty) *provider.Royalty {
+	royaltyInfosMu.Lock()
+	royaltyInfos[contractAddress] = royalty
+	royaltyInfosMu.Unlock()
+
+	return royalty
+}
+
+func formatRoyaltyInfo(royalty *provider.Royalty) string {
+	if royalty == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%s:%d", royalty.Receiver.Hex(), royalty.BasisPoints)
+}
+
+func parseRoyaltyInfo(cached string) *provider.Royalty {
+	receiver, rawBps, found := strings.Cut(cached, ":")
+	if !found {
+		return nil
+	}
+
+	royaltyBps, err := strconv.ParseInt(rawBps, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	return &provider.Royalty{Receiver: common.HexToAddress(receiver), BasisPoints: royaltyBps}
+}
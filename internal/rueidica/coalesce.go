@@ -0,0 +1,120 @@
+package rueidica
+
+import (
+	"context"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/charmbracelet/log"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/rueidis"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/singleflight"
+)
+
+// fetches coalesces concurrent upstream requests for the same key, also without redis.
+var fetches singleflight.Group
+
+// ContractName returns the cached name of a contract or fetches & caches it.
+func (r *Rueidica) ContractName(ctx context.Context, address common.Address, fetch func(context.Context) (string, error)) (string, error) {
+	log.Debugf("rueidica.ContractName | %+v", address)
+
+	return r.getOrFetch(ctx, keyContract(address), viper.GetDuration("cache.names_ttl"), fetch)
+}
+
+// ENSName returns the cached ens name of an address or fetches & caches it.
+func (r *Rueidica) ENSName(ctx context.Context, address common.Address, fetch func(context.Context) (string, error)) (string, error) {
+	log.Debugf("rueidica.ENSName | %+v", address)
+
+	return r.getOrFetch(ctx, keyENS(address), viper.GetDuration("cache.ens_ttl"), fetch)
+}
+
+// OSSlug returns the cached opensea slug of a contract or fetches & caches it.
+func (r *Rueidica) OSSlug(ctx context.Context, address common.Address, fetch func(context.Context) (string, error)) (string, error) {
+	log.Debugf("rueidica.OSSlug | %+v", address)
+
+	return r.getOrFetch(ctx, keyAddresToOSSlug(address), viper.GetDuration("cache.slug_ttl"), fetch)
+}
+
+// OSSlugAddress returns the cached contract address of an opensea slug or fetches & caches it.
+func (r *Rueidica) OSSlugAddress(ctx context.Context, slug string, fetch func(context.Context) (common.Address, error)) (common.Address, error) {
+	log.Debugf("rueidica.OSSlugAddress | %+v", slug)
+
+	rawAddress, err := r.getOrFetch(ctx, keyOSSlugsToAddress(slug), viper.GetDuration("cache.slug_ttl"), func(ctx context.Context) (string, error) {
+		address, err := fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return address.Hex(), nil
+	})
+
+	if err != nil || !common.IsHexAddress(rawAddress) {
+		return common.Address{}, err
+	}
+
+	return common.HexToAddress(rawAddress), nil
+}
+
+// getOrFetch returns the cached value of the key. on a miss, the value is fetched once for all concurrent callers
+// & cached with the ttl. values in the last cache.refresh_ahead share of their ttl are returned & refreshed in the background.
+func (r *Rueidica) getOrFetch(ctx context.Context, key string, ttl time.Duration, fetch func(context.Context) (string, error)) (string, error) {
+	if r != nil {
+		clientCacheTTL := viper.GetDuration("cache.names_client_ttl")
+
+		results := r.DoMultiCache(ctx,
+			rueidis.CT(r.B().Get().Key(key).Cache(), clientCacheTTL),
+			rueidis.CT(r.B().Pttl().Key(key).Cache(), clientCacheTTL),
+		)
+
+		cachedValue, err := results[0].ToString()
+
+		switch {
+		case err == nil && cachedValue != "":
+			if remaining, err := results[1].AsInt64(); err == nil && remaining > 0 && time.Duration(remaining)*time.Millisecond < refreshAhead(ttl) {
+				gbl.Log.Debugf("rueidis | refreshing %s ahead of expiry", key)
+
+				// the result is not awaited, concurrent refreshes are coalesced too
+				fetches.DoChan(key, func() (interface{}, error) {
+					return r.fetchAndStore(context.WithoutCancel(ctx), key, ttl, fetch)
+				})
+			}
+
+			return cachedValue, nil
+
+		case err != nil && !rueidis.IsRedisNil(err):
+			gbl.Log.Errorf("rueidis | error getting %s: %s", key, err)
+		}
+	}
+
+	value, err, shared := fetches.Do(key, func() (interface{}, error) {
+		return r.fetchAndStore(context.WithoutCancel(ctx), key, ttl, fetch)
+	})
+
+	if shared {
+		gbl.Log.Debugf("rueidis | coalesced fetch of %s", key)
+	}
+
+	fetchedValue, _ := value.(string)
+
+	return fetchedValue, err
+}
+
+// fetchAndStore fetches the value & caches it if it is not empty.
+func (r *Rueidica) fetchAndStore(ctx context.Context, key string, ttl time.Duration, fetch func(context.Context) (string, error)) (string, error) {
+	value, err := fetch(ctx)
+	if err != nil || value == "" || r == nil {
+		return value, err
+	}
+
+	if err := r.Do(ctx, r.B().Set().Key(key).Value(value).ExSeconds(int64(ttl.Seconds())).Build()).Error(); err != nil {
+		gbl.Log.Errorf("rueidis | error caching %s: %s", key, err)
+	}
+
+	return value, nil
+}
+
+// refreshAhead returns the remaining ttl below which cached values are refreshed.
+func refreshAhead(ttl time.Duration) time.Duration {
+	return time.Duration(float64(ttl) * viper.GetFloat64("cache.refresh_ahead"))
+}
@@ -0,0 +1,110 @@
+package rueidica
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/charmbracelet/log"
+	"github.com/redis/rueidis"
+	"github.com/spf13/viper"
+)
+
+var ErrInvalidRedisCA = errors.New("no valid certificates found in redis ca file")
+
+// ClientOptionFromConfig builds the redis client options from the config.
+//
+// - single instance: redis.address (or the old redis.host + redis.port)
+// - cluster: multiple seed nodes in redis.addresses, the cluster mode is detected automatically
+// - sentinel: redis.sentinel.master_set with the sentinels in redis.addresses
+// - acl & tls: redis.username/redis.password & redis.tls.*.
+func ClientOptionFromConfig() (rueidis.ClientOption, error) {
+	// use hostname as client name
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Error(fmt.Sprintf("❗️ error getting hostname: %s", err))
+
+		hostname = "unknown"
+	}
+
+	redisClientOptions := rueidis.ClientOption{
+		InitAddress: initAddresses(),
+		ClientName:  hostname + "_gloomberg_v" + internal.GloombergVersion,
+
+		Username: viper.GetString("redis.username"),
+		Password: viper.GetString("redis.password"),
+		SelectDB: viper.GetInt("redis.database"),
+	}
+
+	if viper.GetBool("redis.tls.enabled") {
+		tlsConfig, err := tlsConfigFromConfig()
+		if err != nil {
+			return rueidis.ClientOption{}, err
+		}
+
+		redisClientOptions.TLSConfig = tlsConfig
+	}
+
+	if masterSet := viper.GetString("redis.sentinel.master_set"); masterSet != "" {
+		redisClientOptions.Sentinel = rueidis.SentinelOption{
+			MasterSet:  masterSet,
+			Username:   viper.GetString("redis.sentinel.username"),
+			Password:   viper.GetString("redis.sentinel.password"),
+			ClientName: redisClientOptions.ClientName,
+			TLSConfig:  redisClientOptions.TLSConfig,
+		}
+	}
+
+	return redisClientOptions, nil
+}
+
+func initAddresses() []string {
+	if addresses := viper.GetStringSlice("redis.addresses"); len(addresses) > 0 {
+		return addresses
+	}
+
+	if viper.IsSet("redis.address") {
+		return []string{viper.GetString("redis.address")}
+	}
+
+	// fallback to old config
+	return []string{fmt.Sprintf("%s:%d", viper.GetString("redis.host"), viper.GetInt("redis.port"))}
+}
+
+func tlsConfigFromConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         viper.GetString("redis.tls.server_name"),
+		InsecureSkipVerify: viper.GetBool("redis.tls.insecure_skip_verify"), //nolint:gosec
+	}
+
+	// custom ca, e.g. for self-signed managed instances
+	if caFile := viper.GetString("redis.tls.ca_file"); caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(caCert) {
+			return nil, ErrInvalidRedisCA
+		}
+
+		tlsConfig.RootCAs = certPool
+	}
+
+	// client certificate (mtls)
+	if certFile, keyFile := viper.GetString("redis.tls.cert_file"), viper.GetString("redis.tls.key_file"); certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
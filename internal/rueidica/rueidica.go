@@ -4,11 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"strconv"
 	"time"
 
-	"github.com/benleb/gloomberg/internal"
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/style"
@@ -20,15 +18,30 @@ import (
 )
 
 const (
-	keywordContractName string = "contractName"
-	keywordAccountType  string = "accountType"
-	keywordENS          string = "ensDomain"
-	keywordFloorOS      string = "floorOS"
-	keywordOSSlug       string = "osslug"
-	keywordAddress      string = "address"
-	keywordBlurSlug     string = "blurslug"
-	keywordSalira       string = "salira"
-	keyDelimiter        string = ":"
+	keywordContractName      string = "contractName"
+	keywordAccountType       string = "accountType"
+	keywordENS               string = "ensDomain"
+	keywordFloorOS           string = "floorOS"
+	keywordOSSlug            string = "osslug"
+	keywordAddress           string = "address"
+	keywordBlurSlug          string = "blurslug"
+	keywordSalira            string = "salira"
+	keywordRoyalty           string = "royaltyBps"
+	keywordRoyaltyInfo       string = "erc2981"
+	keywordRoyaltyStats      string = "royaltyStats"
+	keywordWalletAge         string = "walletAge"
+	keywordContractABI       string = "abi"
+	keywordWalletLedger      string = "pnlLedger"
+	keywordSalesHeatmap      string = "salesHeatmap"
+	keywordGasHistory        string = "gasHistory"
+	keywordImageHashes       string = "imageHashes"
+	keywordDiscarded         string = "discarded"
+	keywordPositions         string = "positions"
+	keywordMarketSamples     string = "marketSamples"
+	keywordMarketCollections string = "marketCollections"
+	keywordClaim             string = "claim"
+	keywordTokenboundAccount string = "tokenboundAccount"
+	keyDelimiter             string = ":"
 )
 
 type Rueidica struct {
@@ -107,7 +120,83 @@ func (r *Rueidica) StoreSalira(ctx context.Context, address common.Address, valu
 	return r.cacheName(ctx, address, fmt.Sprint(value), keySalira, viper.GetDuration("cache.salira_ttl"))
 }
 
+// Royalties.
+func (r *Rueidica) GetCachedRoyalty(ctx context.Context, address common.Address) (float64, error) {
+	log.Debugf("rueidica.GetCachedRoyalty | %+v", address)
+
+	return r.getCachedNumber(ctx, address, keyRoyalty)
+}
+
+func (r *Rueidica) StoreRoyalty(ctx context.Context, address common.Address, basisPoints int64) error {
+	log.Debugf("rueidica.StoreRoyalty | %+v -> %+v", address.Hex(), basisPoints)
+
+	return r.cacheName(ctx, address, fmt.Sprint(basisPoints), keyRoyalty, viper.GetDuration("cache.royalty_ttl"))
+}
+
+// ERC-2981 royalty info ("<receiver>:<bps>", "none" if not supported).
+func (r *Rueidica) GetCachedRoyaltyInfo(ctx context.Context, address common.Address) (string, error) {
+	log.Debugf("rueidica.GetCachedRoyaltyInfo | %+v", address)
+
+	return r.getCachedName(ctx, address, keyRoyaltyInfo)
+}
+
+func (r *Rueidica) StoreRoyaltyInfo(ctx context.Context, address common.Address, royaltyInfo string) error {
+	log.Debugf("rueidica.StoreRoyaltyInfo | %+v -> %+v", address.Hex(), royaltyInfo)
+
+	return r.cacheName(ctx, address, royaltyInfo, keyRoyaltyInfo, viper.GetDuration("cache.royalty_info_ttl"))
+}
+
+// Royalty compliance of the sales of a collection (counters & gwei sums by field, no expiry).
+func (r *Rueidica) IncrRoyaltyStats(ctx context.Context, address common.Address, increments map[string]int64) error {
+	cmds := make(rueidis.Commands, 0, len(increments))
+
+	for field, increment := range increments {
+		cmds = append(cmds, r.B().Hincrby().Key(keyRoyaltyStats(address)).Field(field).Increment(increment).Build())
+	}
+
+	for _, resp := range r.DoMulti(ctx, cmds...) {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetRoyaltyStats returns the royalty compliance counters of a collection by field.
+func (r *Rueidica) GetRoyaltyStats(ctx context.Context, address common.Address) (map[string]int64, error) {
+	log.Debugf("rueidica.GetRoyaltyStats | %+v", address.Hex())
+
+	return r.Do(ctx, r.B().Hgetall().Key(keyRoyaltyStats(address)).Build()).AsIntMap()
+}
+
 // Slugs.
+// Wallet age & tx count ("<first tx unix>:<tx count>").
+func (r *Rueidica) GetCachedWalletAge(ctx context.Context, address common.Address) (string, error) {
+	log.Debugf("rueidica.GetCachedWalletAge | %+v", address)
+
+	return r.getCachedName(ctx, address, keyWalletAge)
+}
+
+func (r *Rueidica) StoreWalletAge(ctx context.Context, address common.Address, walletAge string) error {
+	log.Debugf("rueidica.StoreWalletAge | %+v -> %+v", address.Hex(), walletAge)
+
+	return r.cacheName(ctx, address, walletAge, keyWalletAge, viper.GetDuration("cache.wallet_age_ttl"))
+}
+
+// Token bound accounts & their parent nft ("<token contract>/<token id>").
+func (r *Rueidica) GetCachedTokenboundAccount(ctx context.Context, address common.Address) (string, error) {
+	log.Debugf("rueidica.GetCachedTokenboundAccount | %+v", address)
+
+	return r.getCachedName(ctx, address, keyTokenboundAccount)
+}
+
+func (r *Rueidica) StoreTokenboundAccount(ctx context.Context, address common.Address, parent string) error {
+	log.Debugf("rueidica.StoreTokenboundAccount | %+v -> %+v", address.Hex(), parent)
+
+	return r.cacheName(ctx, address, parent, keyTokenboundAccount, viper.GetDuration("cache.tokenbound_ttl"))
+}
+
 func (r *Rueidica) StoreOSSlugForAddress(ctx context.Context, address common.Address, slug string) error {
 	log.Debugf("rueidica.StoreOSSlugForAddress | %+v -> %+v", address.Hex(), slug)
 
@@ -115,9 +204,9 @@ func (r *Rueidica) StoreOSSlugForAddress(ctx context.Context, address common.Add
 }
 
 func (r *Rueidica) GetOSSlugForAddress(ctx context.Context, address common.Address) (string, error) {
-	log.Debugf("rueidica.GetCachedENSName | %+v", address)
+	log.Debugf("rueidica.GetOSSlugForAddress | %+v", address)
 
-	return r.getCachedName(ctx, address, keyENS)
+	return r.getCachedName(ctx, address, keyAddresToOSSlug)
 }
 
 func (r *Rueidica) StoreAddressForOSSlug(ctx context.Context, slug string, address common.Address) error {
@@ -139,6 +228,166 @@ func (r *Rueidica) StoreBlurSlug(ctx context.Context, address common.Address, sl
 	return r.cacheName(ctx, address, slug, keyBlurSlug, viper.GetDuration("cache.slug_ttl"))
 }
 
+// Contract ABIs.
+func (r *Rueidica) GetCachedContractABI(ctx context.Context, address common.Address) (string, error) {
+	log.Debugf("rueidica.GetCachedContractABI | %+v", address)
+
+	return r.getCachedName(ctx, address, keyContractABI)
+}
+
+func (r *Rueidica) StoreContractABI(ctx context.Context, address common.Address, contractABI string) error {
+	log.Debugf("rueidica.StoreContractABI | %+v", address.Hex())
+
+	return r.cacheName(ctx, address, contractABI, keyContractABI, viper.GetDuration("cache.abi_ttl"))
+}
+
+// PnL ledger of a wallet (no expiry).
+func (r *Rueidica) GetWalletLedger(ctx context.Context, address common.Address) (string, error) {
+	log.Debugf("rueidica.GetWalletLedger | %+v", address)
+
+	return r.getCachedName(ctx, address, keyWalletLedger)
+}
+
+func (r *Rueidica) StoreWalletLedger(ctx context.Context, address common.Address, ledger string) error {
+	log.Debugf("rueidica.StoreWalletLedger | %+v", address.Hex())
+
+	err := r.Do(ctx, r.B().Set().Key(keyWalletLedger(address)).Value(ledger).Build()).Error()
+	if err != nil {
+		gbl.Log.Errorf("rueidis | error storing ledger for %s | %s", address.Hex(), err)
+
+		return err
+	}
+
+	return nil
+}
+
+// Sales heatmap of a collection (number of sales per weekday & hour, no expiry).
+func (r *Rueidica) IncrSalesHeatmap(ctx context.Context, address common.Address, salesAt time.Time, numSales int64) error {
+	field := fmt.Sprintf("%d:%d", salesAt.Weekday(), salesAt.Hour())
+
+	return r.Do(ctx, r.B().Hincrby().Key(keySalesHeatmap(address)).Field(field).Increment(numSales).Build()).Error()
+}
+
+// GetSalesHeatmap returns the number of sales by "weekday:hour".
+func (r *Rueidica) GetSalesHeatmap(ctx context.Context, address common.Address) (map[string]int64, error) {
+	log.Debugf("rueidica.GetSalesHeatmap | %+v", address.Hex())
+
+	return r.Do(ctx, r.B().Hgetall().Key(keySalesHeatmap(address)).Build()).AsIntMap()
+}
+
+// Gas history, a ring buffer of "unix:gwei" samples (newest first, no expiry).
+func (r *Rueidica) AddGasSample(ctx context.Context, sampledAt time.Time, gasPriceGwei float64, size int64) error {
+	sample := fmt.Sprintf("%d:%.2f", sampledAt.Unix(), gasPriceGwei)
+
+	if err := r.Do(ctx, r.B().Lpush().Key(keyGasHistory()).Element(sample).Build()).Error(); err != nil {
+		return err
+	}
+
+	return r.Do(ctx, r.B().Ltrim().Key(keyGasHistory()).Start(0).Stop(size-1).Build()).Error()
+}
+
+// GetGasHistory returns the "unix:gwei" samples of the gas history, newest first.
+func (r *Rueidica) GetGasHistory(ctx context.Context) ([]string, error) {
+	log.Debug("rueidica.GetGasHistory")
+
+	return r.Do(ctx, r.B().Lrange().Key(keyGasHistory()).Start(0).Stop(-1).Build()).AsStrSlice()
+}
+
+// Discarded events, a ring buffer of json encoded events (newest first, no expiry).
+func (r *Rueidica) AddDiscardedEvent(ctx context.Context, event string, size int64) error {
+	if err := r.Do(ctx, r.B().Lpush().Key(keyDiscarded()).Element(event).Build()).Error(); err != nil {
+		return err
+	}
+
+	return r.Do(ctx, r.B().Ltrim().Key(keyDiscarded()).Start(0).Stop(size-1).Build()).Error()
+}
+
+// GetDiscardedEvents returns the json encoded discarded events, newest first.
+func (r *Rueidica) GetDiscardedEvents(ctx context.Context) ([]string, error) {
+	log.Debug("rueidica.GetDiscardedEvents")
+
+	return r.Do(ctx, r.B().Lrange().Key(keyDiscarded()).Start(0).Stop(-1).Build()).AsStrSlice()
+}
+
+// Open listings & offers of own wallets, json encoded by position key (no expiry).
+func (r *Rueidica) StorePosition(ctx context.Context, positionKey string, position string) error {
+	return r.Do(ctx, r.B().Hset().Key(keyPositions()).FieldValue().FieldValue(positionKey, position).Build()).Error()
+}
+
+// DeletePosition removes a closed (sold, accepted, cancelled or expired) position.
+func (r *Rueidica) DeletePosition(ctx context.Context, positionKey string) error {
+	return r.Do(ctx, r.B().Hdel().Key(keyPositions()).Field(positionKey).Build()).Error()
+}
+
+// GetPositions returns the json encoded positions by position key.
+func (r *Rueidica) GetPositions(ctx context.Context) (map[string]string, error) {
+	log.Debug("rueidica.GetPositions")
+
+	return r.Do(ctx, r.B().Hgetall().Key(keyPositions()).Build()).AsStrMap()
+}
+
+// Market samples of a collection, a ring buffer of "unix:floor:volume:topoffer" samples (newest first, no expiry).
+func (r *Rueidica) AddMarketSample(ctx context.Context, address common.Address, sample string, size int64) error {
+	if err := r.Do(ctx, r.B().Lpush().Key(keyMarketSamples(address)).Element(sample).Build()).Error(); err != nil {
+		return err
+	}
+
+	if err := r.Do(ctx, r.B().Hset().Key(keyMarketCollections()).FieldValue().FieldValue(address.Hex(), "1").Build()).Error(); err != nil {
+		return err
+	}
+
+	return r.Do(ctx, r.B().Ltrim().Key(keyMarketSamples(address)).Start(0).Stop(size-1).Build()).Error()
+}
+
+// GetMarketSamples returns the "unix:floor:volume:topoffer" samples of a collection, newest first.
+func (r *Rueidica) GetMarketSamples(ctx context.Context, address common.Address) ([]string, error) {
+	log.Debugf("rueidica.GetMarketSamples | %+v", address.Hex())
+
+	return r.Do(ctx, r.B().Lrange().Key(keyMarketSamples(address)).Start(0).Stop(-1).Build()).AsStrSlice()
+}
+
+// GetMarketSampleAddresses returns the addresses of the collections with market samples.
+func (r *Rueidica) GetMarketSampleAddresses(ctx context.Context) ([]common.Address, error) {
+	rawAddresses, err := r.Do(ctx, r.B().Hkeys().Key(keyMarketCollections()).Build()).AsStrSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]common.Address, 0, len(rawAddresses))
+	for _, rawAddress := range rawAddresses {
+		addresses = append(addresses, common.HexToAddress(rawAddress))
+	}
+
+	return addresses, nil
+}
+
+// Perceptual hashes of token images of a collection by token id (no expiry).
+func (r *Rueidica) StoreImageHash(ctx context.Context, address common.Address, tokenID string, imageHash uint64) error {
+	log.Debugf("rueidica.StoreImageHash | %+v #%s", address.Hex(), tokenID)
+
+	return r.Do(ctx, r.B().Hset().Key(keyImageHashes(address)).FieldValue().FieldValue(tokenID, strconv.FormatUint(imageHash, 16)).Build()).Error()
+}
+
+// GetImageHashes returns the perceptual hashes of the token images of a collection by token id.
+func (r *Rueidica) GetImageHashes(ctx context.Context, address common.Address) (map[string]uint64, error) {
+	log.Debugf("rueidica.GetImageHashes | %+v", address.Hex())
+
+	rawHashes, err := r.Do(ctx, r.B().Hgetall().Key(keyImageHashes(address)).Build()).AsStrMap()
+	if err != nil {
+		return nil, err
+	}
+
+	imageHashes := make(map[string]uint64, len(rawHashes))
+
+	for tokenID, rawHash := range rawHashes {
+		if imageHash, err := strconv.ParseUint(rawHash, 16, 64); err == nil {
+			imageHashes[tokenID] = imageHash
+		}
+	}
+
+	return imageHashes, nil
+}
+
 //
 // implementations
 
@@ -251,26 +500,11 @@ func (r *Rueidica) NotificationLock(txHash common.Hash) (context.CancelFunc, err
 
 // NotificationLockWtihDuration implements a lock to prevent sending multiple notifications for the same event.
 func (r *Rueidica) NotificationLockWtihDuration(identifier string, duration time.Duration) (context.CancelFunc, error) {
-	var connectAddr string
-
-	if viper.IsSet("redis.address") {
-		connectAddr = viper.GetString("redis.address")
-	} else {
-		// fallback to old config
-		connectAddr = fmt.Sprintf("%s:%d", viper.GetString("redis.host"), viper.GetInt("redis.port"))
-	}
-
-	// use hostname as client name
-	hostname, err := os.Hostname()
+	redisClientOptions, err := ClientOptionFromConfig()
 	if err != nil {
-		log.Error(fmt.Sprintf("❗️ error getting hostname: %s", err))
-
-		hostname = "unknown"
+		return nil, err
 	}
 
-	clientName := hostname + "_gloomberg_v" + internal.GloombergVersion
-	redisClientOptions := rueidis.ClientOption{InitAddress: []string{connectAddr}, ClientName: clientName}
-
 	locker, err := rueidislock.NewLocker(rueidislock.LockerOption{
 		ClientOption: redisClientOptions,
 		KeyMajority:  2, // please make sure that all your `Locker`s share the same KeyMajority
@@ -298,9 +532,33 @@ func (r *Rueidica) NotificationLockWtihDuration(identifier string, duration time
 	return cancel, nil
 }
 
+// ClaimOnce atomically claims the identifier for the given duration (SET NX).
+// returns false if it was already claimed, e.g. by another gloomberg instance sharing this redis.
+func (r *Rueidica) ClaimOnce(ctx context.Context, identifier string, duration time.Duration) (bool, error) {
+	err := r.Do(ctx, r.B().Set().Key(keyClaim(identifier)).Value(strconv.FormatInt(time.Now().Unix(), 10)).Nx().Ex(duration).Build()).Error()
+
+	switch {
+	case rueidis.IsRedisNil(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseClaim removes a claim, e.g. if the claimed action failed and should be retried.
+func (r *Rueidica) ReleaseClaim(ctx context.Context, identifier string) error {
+	return r.Do(ctx, r.B().Del().Key(keyClaim(identifier)).Build()).Error()
+}
+
 //
 // keys
 
+func keyClaim(identifier string) string {
+	return fmt.Sprint(keywordClaim, keyDelimiter, identifier)
+}
+
 func keyAccountType(address common.Address) string {
 	return fmt.Sprint(address.Hex(), keyDelimiter, keywordAccountType)
 }
@@ -332,3 +590,59 @@ func keyBlurSlug(address common.Address) string {
 func keySalira(address common.Address) string {
 	return fmt.Sprint(address.Hex(), keyDelimiter, keywordSalira)
 }
+
+func keyRoyalty(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordRoyalty)
+}
+
+func keyRoyaltyInfo(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordRoyaltyInfo)
+}
+
+func keyRoyaltyStats(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordRoyaltyStats)
+}
+
+func keyWalletAge(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordWalletAge)
+}
+
+func keyTokenboundAccount(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordTokenboundAccount)
+}
+
+func keyContractABI(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordContractABI)
+}
+
+func keyWalletLedger(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordWalletLedger)
+}
+
+func keyGasHistory() string {
+	return fmt.Sprint("gloomberg", keyDelimiter, keywordGasHistory)
+}
+
+func keyDiscarded() string {
+	return fmt.Sprint("gloomberg", keyDelimiter, keywordDiscarded)
+}
+
+func keyPositions() string {
+	return fmt.Sprint("gloomberg", keyDelimiter, keywordPositions)
+}
+
+func keyMarketSamples(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordMarketSamples)
+}
+
+func keyMarketCollections() string {
+	return fmt.Sprint("gloomberg", keyDelimiter, keywordMarketCollections)
+}
+
+func keyImageHashes(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordImageHashes)
+}
+
+func keySalesHeatmap(address common.Address) string {
+	return fmt.Sprint(address.Hex(), keyDelimiter, keywordSalesHeatmap)
+}
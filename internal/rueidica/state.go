@@ -0,0 +1,159 @@
+package rueidica
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// keywords of the persistent (non-cache) keys included in exports of the gloomberg state.
+// cached names, floors, slugs, ... are refetched & not exported.
+var stateKeywords = []string{
+	keywordWalletLedger,
+	keywordSalesHeatmap,
+	keywordRoyaltyStats,
+	keywordGasHistory,
+	keywordPositions,
+	keywordMarketSamples,
+	keywordMarketCollections,
+	keywordImageHashes,
+}
+
+// StateEntry is a redis key of the gloomberg state with its value in exports.
+type StateEntry struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+
+	String string            `json:"string,omitempty"`
+	Hash   map[string]string `json:"hash,omitempty"`
+	List   []string          `json:"list,omitempty"`
+
+	// remaining time to live, 0 for keys without expiry
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// ExportState returns the persistent keys (ledgers, heatmaps, royalty stats, ...) with their values.
+func (r *Rueidica) ExportState(ctx context.Context) ([]*StateEntry, error) {
+	keys := make(map[string]bool)
+
+	// scan every node of a cluster
+	for _, node := range r.Nodes() {
+		for _, keyword := range stateKeywords {
+			cursor := uint64(0)
+
+			for {
+				entry, err := node.Do(ctx, node.B().Scan().Cursor(cursor).Match("*"+keyDelimiter+keyword).Count(1000).Build()).AsScanEntry()
+				if err != nil {
+					return nil, fmt.Errorf("scanning %s keys failed: %w", keyword, err)
+				}
+
+				for _, key := range entry.Elements {
+					keys[key] = true
+				}
+
+				if cursor = entry.Cursor; cursor == 0 {
+					break
+				}
+			}
+		}
+	}
+
+	entries := make([]*StateEntry, 0, len(keys))
+
+	for key := range keys {
+		entry, err := r.exportKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	return entries, nil
+}
+
+// ImportState writes the exported keys, existing keys are replaced.
+func (r *Rueidica) ImportState(ctx context.Context, entries []*StateEntry) (int, error) {
+	numImported := 0
+
+	for _, entry := range entries {
+		cmds := rueidis.Commands{r.B().Del().Key(entry.Key).Build()}
+
+		switch entry.Type {
+		case "string":
+			cmds = append(cmds, r.B().Set().Key(entry.Key).Value(entry.String).Build())
+		case "hash":
+			if len(entry.Hash) == 0 {
+				continue
+			}
+
+			fieldValues := r.B().Hset().Key(entry.Key).FieldValue()
+			for field, value := range entry.Hash {
+				fieldValues = fieldValues.FieldValue(field, value)
+			}
+
+			cmds = append(cmds, fieldValues.Build())
+		case "list":
+			if len(entry.List) == 0 {
+				continue
+			}
+
+			cmds = append(cmds, r.B().Rpush().Key(entry.Key).Element(entry.List...).Build())
+		default:
+			continue
+		}
+
+		if entry.TTL > 0 {
+			cmds = append(cmds, r.B().Pexpire().Key(entry.Key).Milliseconds(entry.TTL.Milliseconds()).Build())
+		}
+
+		for _, result := range r.DoMulti(ctx, cmds...) {
+			if err := result.Error(); err != nil {
+				return numImported, fmt.Errorf("importing %s failed: %w", entry.Key, err)
+			}
+		}
+
+		numImported++
+	}
+
+	return numImported, nil
+}
+
+// exportKey returns the value of a key depending on its type or nil for unsupported types.
+func (r *Rueidica) exportKey(ctx context.Context, key string) (*StateEntry, error) {
+	keyType, err := r.Do(ctx, r.B().Type().Key(key).Build()).ToString()
+	if err != nil {
+		return nil, fmt.Errorf("getting the type of %s failed: %w", key, err)
+	}
+
+	entry := &StateEntry{Key: key, Type: strings.ToLower(keyType)}
+
+	switch entry.Type {
+	case "string":
+		entry.String, err = r.Do(ctx, r.B().Get().Key(key).Build()).ToString()
+	case "hash":
+		entry.Hash, err = r.Do(ctx, r.B().Hgetall().Key(key).Build()).AsStrMap()
+	case "list":
+		entry.List, err = r.Do(ctx, r.B().Lrange().Key(key).Start(0).Stop(-1).Build()).AsStrSlice()
+	default:
+		return nil, nil //nolint:nilnil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading %s failed: %w", key, err)
+	}
+
+	if ttl, err := r.Do(ctx, r.B().Pttl().Key(key).Build()).AsInt64(); err == nil && ttl > 0 {
+		entry.TTL = time.Duration(ttl) * time.Millisecond
+	}
+
+	return entry, nil
+}
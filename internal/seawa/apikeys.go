@@ -0,0 +1,193 @@
+package seawa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/gorilla/websocket"
+	"github.com/nshafer/phx"
+	"github.com/spf13/viper"
+)
+
+// endpoint used to check if an api key is (still) accepted by OpenSea.
+const apiKeyCheckURL = "https://api.opensea.io/api/v2/collections?limit=1"
+
+// APIKeysFromConfig returns all configured OpenSea api keys without duplicates.
+// api_keys.opensea & seawatcher.api_key are used first, seawatcher.api_keys are
+// additional keys we rotate to if the active one fails.
+func APIKeysFromConfig() []string {
+	apiKeys := make([]string, 0)
+	seen := make(map[string]bool)
+
+	candidates := []string{viper.GetString("api_keys.opensea"), viper.GetString("seawatcher.api_key")}
+	candidates = append(candidates, viper.GetStringSlice("seawatcher.api_keys")...)
+
+	for _, key := range candidates {
+		if key == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		apiKeys = append(apiKeys, key)
+	}
+
+	return apiKeys
+}
+
+// activeAPIKey returns the api key currently used for the stream.
+func (sw *SeaWatcher) activeAPIKey() string {
+	sw.keysMu.Lock()
+	defer sw.keysMu.Unlock()
+
+	if len(sw.apiKeys) == 0 {
+		return ""
+	}
+
+	return sw.apiKeys[sw.activeKey]
+}
+
+// handleSocketError checks the active key if the handshake was rejected, OpenSea responds with 401/403 for invalid keys.
+func (sw *SeaWatcher) handleSocketError(err error) {
+	gbl.Log.Errorf("❌ seawa socket error: %+v", err)
+
+	health.Breaker(health.SourceOpenSea).Failure()
+
+	if !errors.Is(err, websocket.ErrBadHandshake) {
+		return
+	}
+
+	go sw.checkActiveAPIKey()
+}
+
+// handleDisconnect counts the disconnect & rotates the key if it disconnects too often.
+func (sw *SeaWatcher) handleDisconnect() {
+	apiKey := sw.activeAPIKey()
+	health.APIKeyDisconnected(health.SourceOpenSea, apiKey)
+
+	window := viper.GetDuration("seawatcher.key_rotation.window")
+
+	sw.keysMu.Lock()
+
+	recentDisconnects := make([]time.Time, 0, len(sw.recentDisconnects)+1)
+
+	for _, disconnectedAt := range append(sw.recentDisconnects, time.Now()) {
+		if time.Since(disconnectedAt) < window {
+			recentDisconnects = append(recentDisconnects, disconnectedAt)
+		}
+	}
+
+	sw.recentDisconnects = recentDisconnects
+	tooManyDisconnects := len(recentDisconnects) >= viper.GetInt("seawatcher.key_rotation.max_disconnects")
+
+	sw.keysMu.Unlock()
+
+	if tooManyDisconnects && sw.rotateAPIKey() {
+		sw.Prf("🔑 %d disconnects within %s, switched to the next OpenSea api key", len(recentDisconnects), window)
+	}
+}
+
+// checkActiveAPIKey marks the active key as dead & rotates to the next one if it is rejected by OpenSea.
+func (sw *SeaWatcher) checkActiveAPIKey() {
+	apiKey := sw.activeAPIKey()
+	if apiKey == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*7)
+	defer cancel()
+
+	header := http.Header{}
+	header.Set("X-API-KEY", apiKey)
+
+	response, err := utils.HTTP.GetWithHeader(ctx, apiKeyCheckURL, header)
+	if err != nil {
+		gbl.Log.Debugf("🔑 checking OpenSea api key %s failed: %s", health.MaskAPIKey(apiKey), err)
+
+		return
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusUnauthorized && response.StatusCode != http.StatusForbidden {
+		return
+	}
+
+	health.APIKeyAuthFailed(health.SourceOpenSea, apiKey, response.StatusCode)
+	health.SetAPIKeyDead(health.SourceOpenSea, apiKey, true)
+
+	sw.Prf("💀 OpenSea api key %s rejected (%s)", health.MaskAPIKey(apiKey), response.Status)
+
+	if sw.rotateAPIKey() {
+		sw.Prf("🔑 switched to OpenSea api key %s", health.MaskAPIKey(sw.activeAPIKey()))
+	}
+}
+
+// rotateAPIKey reconnects the stream with the next key that is not dead.
+// returns false if there is no other usable key.
+func (sw *SeaWatcher) rotateAPIKey() bool {
+	if sw.phoenixSocket == nil || !sw.rotating.CompareAndSwap(false, true) {
+		return false
+	}
+	defer sw.rotating.Store(false)
+
+	deadKeys := make(map[string]bool)
+	for _, status := range health.APIKeys(health.SourceOpenSea) {
+		deadKeys[status.Key] = status.Dead
+	}
+
+	sw.keysMu.Lock()
+
+	nextKey := -1
+
+	for offset := 1; offset < len(sw.apiKeys); offset++ {
+		idx := (sw.activeKey + offset) % len(sw.apiKeys)
+
+		if !deadKeys[health.MaskAPIKey(sw.apiKeys[idx])] {
+			nextKey = idx
+
+			break
+		}
+	}
+
+	if nextKey < 0 {
+		sw.keysMu.Unlock()
+
+		gbl.Log.Warnf("🔑 no other usable OpenSea api key available")
+
+		return false
+	}
+
+	sw.activeKey = nextKey
+	sw.recentDisconnects = make([]time.Time, 0)
+	apiKey := sw.apiKeys[nextKey]
+
+	sw.keysMu.Unlock()
+
+	health.SetActiveAPIKey(health.SourceOpenSea, apiKey)
+
+	// the transport copies the endpoint on connect, so we have to fully disconnect to use the new key
+	if err := sw.phoenixSocket.Disconnect(); err != nil {
+		gbl.Log.Debugf("🔑 disconnecting from OpenSea stream failed: %s", err)
+	}
+
+	for i := 0; i < 50 && sw.phoenixSocket.ConnectionState() != phx.ConnectionClosed; i++ {
+		time.Sleep(time.Millisecond * 200)
+	}
+
+	query := sw.phoenixSocket.EndPoint.Query()
+	query.Set("token", apiKey)
+	sw.phoenixSocket.EndPoint.RawQuery = query.Encode()
+
+	if err := sw.phoenixSocket.Connect(); err != nil {
+		sw.Prf("❌ connecting to OpenSea stream with api key %s failed: %s", health.MaskAPIKey(apiKey), err)
+
+		return false
+	}
+
+	return true
+}
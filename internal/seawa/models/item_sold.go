@@ -0,0 +1,41 @@
+package models
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type ItemSold struct {
+	EventType string          `json:"event_type" mapstructure:"event_type"`
+	SentAt    time.Time       `json:"sent_at"    mapstructure:"sent_at"`
+	Payload   ItemSoldPayload `json:"payload"    mapstructure:"payload"`
+
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+type ItemSoldPayload struct {
+	EventPayload `mapstructure:",squash"`
+	Item         Item        `json:"item"        mapstructure:"item"`
+	SalePrice    *big.Int    `json:"sale_price"  mapstructure:"sale_price"`
+	Transaction  Transaction `json:"transaction" mapstructure:"transaction"`
+	IsPrivate    bool        `json:"is_private"  mapstructure:"is_private"`
+
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+type Transaction struct {
+	Hash common.Hash `json:"hash" mapstructure:"hash"`
+
+	Other map[string]interface{} `mapstructure:",remain"`
+}
+
+// PerItemPrice returns the sale price divided by the quantity sold.
+func (p *ItemSoldPayload) PerItemPrice() *big.Int {
+	if p.SalePrice == nil {
+		return big.NewInt(0)
+	}
+
+	return new(big.Int).Div(p.SalePrice, big.NewInt(int64(max(p.Quantity, 1))))
+}
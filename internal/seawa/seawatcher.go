@@ -11,15 +11,20 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/crossdedupe"
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/osmodels"
 	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/positions"
 	"github.com/benleb/gloomberg/internal/pusu"
+	"github.com/benleb/gloomberg/internal/research"
 	"github.com/benleb/gloomberg/internal/seawa/models"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/benleb/gloomberg/internal/utils/hooks"
@@ -58,6 +63,17 @@ type SeaWatcher struct {
 
 	gb *gloomberg.Gloomberg
 
+	// opensea api keys & the index of the one currently used
+	apiKeys   []string
+	activeKey int
+
+	// recent disconnects of the active key, used to decide if we should rotate
+	recentDisconnects []time.Time
+	keysMu            *sync.Mutex
+
+	// set while switching to another api key
+	rotating atomic.Bool
+
 	mu *sync.RWMutex
 }
 
@@ -69,16 +85,24 @@ var eventsReceivedCounter = promauto.NewCounter(prometheus.CounterOpts{
 })
 
 // func NewSeaWatcher(apiToken string, rdb rueidis.Client) *SeaWatcher {.
-func NewSeaWatcher(apiToken string, gb *gloomberg.Gloomberg) *SeaWatcher {
+// NewSeaWatcher connects to the stream with the first of the given api keys, the others are used as fallback.
+func NewSeaWatcher(apiTokens []string, gb *gloomberg.Gloomberg) *SeaWatcher {
 	// we might not connect to the stream api locally if we use other ways to get the events
 	runLocalAPIClient := viper.GetBool("seawatcher.local")
 
-	if runLocalAPIClient && apiToken == "" {
+	if runLocalAPIClient && len(apiTokens) == 0 {
 		log.Info("no OpenSea api token provided, skipping OpenSea stream api")
 
 		return nil
 	}
 
+	var apiToken string
+	if len(apiTokens) > 0 {
+		apiToken = apiTokens[0]
+	}
+
+	health.RegisterAPIKeys(health.SourceOpenSea, apiTokens)
+
 	endpointURL := fmt.Sprint(osmodels.StreamAPIEndpoint, "?token=", apiToken)
 
 	endpoint, err := url.Parse(endpointURL)
@@ -100,6 +124,10 @@ func NewSeaWatcher(apiToken string, gb *gloomberg.Gloomberg) *SeaWatcher {
 		gb:  gb,
 		rdb: gb.Rdb,
 
+		apiKeys:           apiTokens,
+		recentDisconnects: make([]time.Time, 0),
+		keysMu:            &sync.Mutex{},
+
 		mu: &sync.RWMutex{},
 	}
 
@@ -119,21 +147,33 @@ func NewSeaWatcher(apiToken string, gb *gloomberg.Gloomberg) *SeaWatcher {
 		}
 
 		// error function
-		sw.phoenixSocket.OnError(func(err error) { gbl.Log.Errorf("❌ seawa socket error: %+v", err) })
+		sw.phoenixSocket.OnError(sw.handleSocketError)
 
 		// called on successful connection to the socket/OpenSea
 		sw.phoenixSocket.OnOpen(func() {
 			sw.Pr("✅ connected to the OpenSea stream")
+
+			health.SetStreamConnected(health.SourceOpenSea, true)
+			health.Breaker(health.SourceOpenSea).Success()
 		})
 
 		// called on disconnect/connection breaks to the socket/OpenSea
 		sw.phoenixSocket.OnClose(func() {
+			health.SetStreamConnected(health.SourceOpenSea, false)
+
+			// we are switching to another api key & reconnect on our own
+			if sw.rotating.Load() {
+				return
+			}
+
 			sw.Pr("❕ connection to OpenSea closed, trying to reconnect...")
 
 			err := sw.phoenixSocket.Reconnect()
 			if err != nil {
 				sw.Prf("❌ reconnecting to OpenSea stream failed: %s", err)
 			}
+
+			go sw.handleDisconnect()
 		})
 
 		// initial connection to the socket/OpenSea
@@ -157,6 +197,17 @@ func NewSeaWatcher(apiToken string, gb *gloomberg.Gloomberg) *SeaWatcher {
 	return sw
 }
 
+// Reconnect re-establishes the connection to the OpenSea stream.
+func (sw *SeaWatcher) Reconnect() {
+	if sw.phoenixSocket == nil {
+		return
+	}
+
+	if err := sw.phoenixSocket.Reconnect(); err != nil {
+		sw.Prf("❌ reconnecting to OpenSea stream failed: %s", err)
+	}
+}
+
 // Pr prints messages from seawatcher to the terminal.
 func (sw *SeaWatcher) Pr(message string) {
 	gloomberg.PrWithKeywordAndIcon("🌊", style.OpenSea.Render("seawa"), message)
@@ -170,6 +221,7 @@ func (sw *SeaWatcher) Prf(format string, a ...interface{}) {
 // eventHandler handles incoming stream api events and forwards them as map.
 func (sw *SeaWatcher) eventHandler(response any) {
 	eventsReceivedCounter.Inc()
+	health.EventReceived(health.SourceOpenSea)
 
 	rawEvent, ok := response.(map[string]interface{})
 	if !ok {
@@ -275,13 +327,54 @@ func (sw *SeaWatcher) eventHandler(response any) {
 
 		// push to eventHub for further processing
 		sw.gb.In.ItemMetadataUpdated <- itemMetadataUpdated
-	}
 
-	if viper.GetBool("pubsub.server.enabled") {
-		publishChannel := internal.PubSubSeaWatcher + "/" + generalEvent.EventType + "/" + contractAddress.Hex()
-		pusu.Publish(sw.gb, publishChannel, rawEvent)
+	// cancellations are only subscribed to for the research mode
+	case degendb.Cancelled:
+		research.RecordStreamEvent(&generalEvent)
+		positions.Cancel(sw.gb, &generalEvent)
+
+		return
+
+	// sales are merged with the same sale from the chain
+	case degendb.Sale:
+		sw.publish(&generalEvent, contractAddress, rawEvent)
+
+		var itemSold *models.ItemSold
+
+		decoderConfig.Result = &itemSold
+		decoder, _ := mapstructure.NewDecoder(&decoderConfig)
+
+		err := decoder.Decode(rawEvent)
+		if err != nil {
+			log.Infof("⚓️❌ decoding incoming %s event failed: %s", style.Bold(itemEventType), err)
+
+			return
+		}
+
+		perItemPrice := price.NewPrice(itemSold.Payload.PerItemPrice())
+		printSale := func() {
+			logEvent(sw, degendb.Sale, contractAddress, perItemPrice, fmtItemName, &itemSold.Payload.Maker.Address)
+		}
+
+		if !crossdedupe.Enabled() {
+			go printSale()
+
+			return
+		}
+
+		crossdedupe.AddStreamSale(&crossdedupe.StreamSale{
+			TxHash:          itemSold.Payload.Transaction.Hash,
+			ContractAddress: *contractAddress,
+			TokenID:         itemSold.Payload.Item.NftID.TokenID(),
+			Price:           perItemPrice,
+			PaymentSymbol:   itemSold.Payload.PaymentToken.Symbol,
+		}, printSale)
+
+		return
 	}
 
+	sw.publish(&generalEvent, contractAddress, rawEvent)
+
 	// 💄 styled log
 	perItemPrice := price.NewPrice(big.NewInt(0).Div(generalEvent.Payload.GetPrice().Wei(), big.NewInt(int64(generalEvent.Payload.Quantity))))
 
@@ -289,6 +382,14 @@ func (sw *SeaWatcher) eventHandler(response any) {
 	go logEvent(sw, degendb.GetEventType(generalEvent.EventType), contractAddress, perItemPrice, fmtItemName, &generalEvent.Payload.Maker.Address)
 }
 
+// publish forwards the raw event to the pubsub clients.
+func (sw *SeaWatcher) publish(generalEvent *models.GeneralEvent, contractAddress *common.Address, rawEvent map[string]interface{}) {
+	if viper.GetBool("pubsub.server.enabled") {
+		publishChannel := internal.PubSubSeaWatcher + "/" + generalEvent.EventType + "/" + contractAddress.Hex()
+		pusu.Publish(sw.gb, publishChannel, rawEvent)
+	}
+}
+
 func (sw *SeaWatcher) Subscribe(subscriptions degendb.SlugSubscriptions) uint64 {
 	if !viper.GetBool("pubsub.server.enabled") && !viper.GetBool("seawatcher.pubsub") && !viper.GetBool("seawatcher.local") {
 		// runs on the pubsub client side
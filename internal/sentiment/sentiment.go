@@ -0,0 +1,164 @@
+package sentiment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/collections"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Mentions are the mention counts of a collection in the current & the previous window.
+type Mentions struct {
+	Current  int
+	Previous int
+
+	// -1 (bearish) to 1 (bullish), only available if the source provides the texts
+	Sentiment    float64
+	HasSentiment bool
+
+	UpdatedAt time.Time
+}
+
+var (
+	mentions   = make(map[common.Address]*Mentions)
+	mentionsMu = &sync.RWMutex{}
+)
+
+var (
+	bullishWords = []string{"bullish", "moon", "pump", "lfg", "wagmi", "sweep", "bought", "aped", "love", "floor up", "undervalued"}
+	bearishWords = []string{"bearish", "rug", "scam", "dump", "ngmi", "dead", "exploit", "hacked", "overvalued", "sold"}
+)
+
+// Get returns the latest mentions of a collection or nil if there are none.
+func Get(contractAddress common.Address) *Mentions {
+	mentionsMu.RLock()
+	defer mentionsMu.RUnlock()
+
+	return mentions[contractAddress]
+}
+
+// Format returns the mention count with the trend to the previous window & the sentiment, e.g. "💬42↑ +0.3".
+func (m *Mentions) Format() string {
+	fmtMentions := style.GrayStyle.Render("💬"+strconv.Itoa(m.Current)) + style.CreateTrendIndicator(float64(m.Previous), float64(m.Current)).String()
+
+	if m.HasSentiment {
+		sentimentStyle := style.DarkGrayStyle
+
+		switch {
+		case m.Sentiment > 0:
+			sentimentStyle = style.TrendLightGreenStyle
+		case m.Sentiment < 0:
+			sentimentStyle = style.TrendLightRedStyle
+		}
+
+		fmtMentions += " " + sentimentStyle.Render(fmt.Sprintf("%+.1f", m.Sentiment))
+	}
+
+	return fmtMentions
+}
+
+// Start periodically counts the mentions of the collections from the config & own wallets.
+func Start(collectionDB *collections.CollectionDB) {
+	source := sourceFromConfig()
+	if source == nil {
+		gbl.Log.Warn("💬 sentiment enabled but no source configured | set api_keys.twitter_bearer or api_keys.neynar")
+
+		return
+	}
+
+	gbl.Log.Infof("💬 counting collection mentions on %s", source.Name())
+
+	ticker := time.NewTicker(viper.GetDuration("sentiment.interval"))
+
+	for ; true; <-ticker.C {
+		for contractAddress, name := range watchedCollectionNames(collectionDB) {
+			if m := countMentions(source, name); m != nil {
+				mentionsMu.Lock()
+				mentions[contractAddress] = m
+				mentionsMu.Unlock()
+			}
+		}
+	}
+}
+
+func countMentions(source Source, query string) *Mentions {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*17)
+	defer cancel()
+
+	window := viper.GetDuration("sentiment.window")
+	now := time.Now()
+
+	current, texts, err := source.CountMentions(ctx, query, now.Add(-window), now)
+	if err != nil {
+		gbl.Log.Debugf("💬 counting mentions of %s on %s failed: %s", query, source.Name(), err)
+
+		return nil
+	}
+
+	previous, _, err := source.CountMentions(ctx, query, now.Add(-2*window), now.Add(-window))
+	if err != nil {
+		gbl.Log.Debugf("💬 counting previous mentions of %s on %s failed: %s", query, source.Name(), err)
+
+		return nil
+	}
+
+	m := &Mentions{Current: current, Previous: previous, UpdatedAt: now}
+	m.Sentiment, m.HasSentiment = estimateSentiment(texts)
+
+	return m
+}
+
+// estimateSentiment is a very naive word-list based sentiment of the given texts.
+func estimateSentiment(texts []string) (float64, bool) {
+	var numBullish, numBearish int
+
+	for _, text := range texts {
+		text = strings.ToLower(text)
+
+		for _, word := range bullishWords {
+			numBullish += strings.Count(text, word)
+		}
+
+		for _, word := range bearishWords {
+			numBearish += strings.Count(text, word)
+		}
+	}
+
+	if numBullish+numBearish == 0 {
+		return 0, false
+	}
+
+	return float64(numBullish-numBearish) / float64(numBullish+numBearish), true
+}
+
+// watchedCollectionNames returns the names of the collections from the config & own wallets.
+func watchedCollectionNames(collectionDB *collections.CollectionDB) map[common.Address]string {
+	names := make(map[common.Address]string)
+
+	collectionDB.RWMu.RLock()
+	defer collectionDB.RWMu.RUnlock()
+
+	for contractAddress, collection := range collectionDB.Collections {
+		if collection.Source != degendb.FromConfiguration && collection.Source != degendb.FromWallet {
+			continue
+		}
+
+		// skip collections without a proper name
+		if collection.Name == "" || strings.HasPrefix(collection.Name, "0x") {
+			continue
+		}
+
+		names[contractAddress] = collection.Name
+	}
+
+	return names
+}
@@ -0,0 +1,140 @@
+package sentiment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/spf13/viper"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected status code")
+
+// Source is a social network we can count the mentions of a collection on.
+type Source interface {
+	Name() string
+
+	// CountMentions returns the number of mentions of the query between from & to and
+	// the texts of the mentions if available (used to estimate the sentiment).
+	CountMentions(ctx context.Context, query string, from time.Time, to time.Time) (int, []string, error)
+}
+
+// sourceFromConfig returns the configured source or the first one with an api key.
+func sourceFromConfig() Source {
+	twitterToken := viper.GetString("api_keys.twitter_bearer")
+	neynarKey := viper.GetString("api_keys.neynar")
+
+	switch source := viper.GetString("sentiment.source"); {
+	case source == "twitter" && twitterToken != "":
+		return &twitter{bearerToken: twitterToken}
+	case source == "farcaster" && neynarKey != "":
+		return &farcaster{apiKey: neynarKey, searchURL: viper.GetString("sentiment.farcaster.search_url")}
+	case source == "" && twitterToken != "":
+		return &twitter{bearerToken: twitterToken}
+	case source == "" && neynarKey != "":
+		return &farcaster{apiKey: neynarKey, searchURL: viper.GetString("sentiment.farcaster.search_url")}
+	}
+
+	return nil
+}
+
+//
+// twitter
+
+type twitter struct {
+	bearerToken string
+}
+
+type twitterCountsResponse struct {
+	Meta struct {
+		TotalTweetCount int `json:"total_tweet_count"`
+	} `json:"meta"`
+}
+
+func (t *twitter) Name() string { return "twitter" }
+
+// CountMentions uses the recent tweet counts endpoint, which provides counts only.
+func (t *twitter) CountMentions(ctx context.Context, query string, from time.Time, to time.Time) (int, []string, error) {
+	params := url.Values{}
+	params.Set("query", fmt.Sprintf("%q -is:retweet", query))
+	params.Set("start_time", from.UTC().Format(time.RFC3339))
+	params.Set("end_time", to.UTC().Add(-10*time.Second).Format(time.RFC3339)) // end_time must be 10s in the past
+	params.Set("granularity", "day")
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+t.bearerToken)
+
+	var counts twitterCountsResponse
+	if err := getJSON(ctx, "https://api.twitter.com/2/tweets/counts/recent?"+params.Encode(), header, &counts); err != nil {
+		return 0, nil, err
+	}
+
+	return counts.Meta.TotalTweetCount, nil, nil
+}
+
+//
+// farcaster
+
+type farcaster struct {
+	apiKey    string
+	searchURL string
+}
+
+type farcasterSearchResponse struct {
+	Result struct {
+		Casts []struct {
+			Text      string    `json:"text"`
+			Timestamp time.Time `json:"timestamp"`
+		} `json:"casts"`
+	} `json:"result"`
+}
+
+func (f *farcaster) Name() string { return "farcaster" }
+
+// CountMentions searches the most recent casts via a neynar compatible search api.
+// as the search can not be limited to a timeframe, at most 100 mentions are counted.
+func (f *farcaster) CountMentions(ctx context.Context, query string, from time.Time, to time.Time) (int, []string, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", "100")
+
+	header := http.Header{}
+	header.Set("api_key", f.apiKey)
+	header.Set("accept", "application/json")
+
+	var search farcasterSearchResponse
+	if err := getJSON(ctx, f.searchURL+"?"+params.Encode(), header, &search); err != nil {
+		return 0, nil, err
+	}
+
+	texts := make([]string, 0)
+
+	for _, cast := range search.Result.Casts {
+		if cast.Timestamp.Before(from) || !cast.Timestamp.Before(to) {
+			continue
+		}
+
+		texts = append(texts, cast.Text)
+	}
+
+	return len(texts), texts, nil
+}
+
+func getJSON(ctx context.Context, requestURL string, header http.Header, target interface{}) error {
+	response, err := utils.HTTP.GetWithTLS12AndHeader(ctx, requestURL, header)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, response.Status)
+	}
+
+	return json.NewDecoder(response.Body).Decode(target)
+}
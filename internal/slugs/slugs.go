@@ -0,0 +1,288 @@
+package slugs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/external"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/rueidica"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// failed lookups are not retried for this duration.
+	failedLookupTTL = time.Minute * 10
+
+	// minimum delay between two requests to the external apis.
+	apiRequestInterval = time.Millisecond * 337
+)
+
+var ErrNotResolved = errors.New("could not resolve slug/address")
+
+var (
+	gb *gloomberg.Gloomberg
+
+	// in-memory cache for both directions
+	addressForSlug = make(map[string]common.Address)
+	slugForAddress = make(map[common.Address]string)
+	failedLookups  = make(map[string]time.Time)
+	cacheMu        sync.RWMutex
+
+	lastAPIRequest time.Time
+	apiMu          sync.Mutex
+)
+
+// Setup sets the gloomberg instance used for the redis cache & the collection db.
+// resolving works without it but only via the external apis.
+func Setup(instance *gloomberg.Gloomberg) {
+	gb = instance
+}
+
+// rueidi returns the redis cache or nil if not available.
+func rueidi() *rueidica.Rueidica {
+	if gb == nil {
+		return nil
+	}
+
+	return gb.Rueidi
+}
+
+// Add adds a known slug/address pair to the cache.
+func Add(slug string, address common.Address) {
+	slug = normalize(slug)
+	if slug == "" || address == (common.Address{}) {
+		return
+	}
+
+	cacheMu.Lock()
+	addressForSlug[slug] = address
+	slugForAddress[address] = slug
+	cacheMu.Unlock()
+
+	if gb != nil && gb.Rueidi != nil {
+		_ = gb.Rueidi.StoreAddressForOSSlug(context.Background(), slug, address)
+		_ = gb.Rueidi.StoreOSSlugForAddress(context.Background(), address, slug)
+	}
+}
+
+// Address returns the canonical contract address for a slug or address.
+// sources: cache → collection db → redis → opensea → reservoir → on-chain names of the known collections.
+func Address(ctx context.Context, slugOrAddress string) (common.Address, error) {
+	if common.IsHexAddress(slugOrAddress) {
+		return common.HexToAddress(slugOrAddress), nil
+	}
+
+	slug := normalize(slugOrAddress)
+	if slug == "" {
+		return common.Address{}, ErrNotResolved
+	}
+
+	cacheMu.RLock()
+	address, ok := addressForSlug[slug]
+	cacheMu.RUnlock()
+
+	if ok {
+		return address, nil
+	}
+
+	if recentlyFailed(slug) {
+		return common.Address{}, ErrNotResolved
+	}
+
+	// collection db
+	if gb != nil && gb.CollectionDB != nil {
+		gb.CollectionDB.RWMu.RLock()
+		collection := gb.CollectionDB.GetCollectionForSlug(slug)
+		gb.CollectionDB.RWMu.RUnlock()
+
+		if collection != nil {
+			Add(slug, collection.ContractAddress)
+
+			return collection.ContractAddress, nil
+		}
+	}
+
+	// redis, concurrent lookups of the same slug request the external apis only once
+	address, err := rueidi().OSSlugAddress(ctx, slug, func(ctx context.Context) (common.Address, error) {
+		// opensea
+		throttle()
+
+		if collection := opensea.GetCollection(slug); collection != nil && len(collection.Collection.PrimaryAssetContracts) > 0 {
+			address := common.HexToAddress(collection.Collection.PrimaryAssetContracts[0].Address)
+
+			gbl.Log.Debugf("🐌 resolved %s → %s via opensea", slug, address.Hex())
+
+			return address, nil
+		}
+
+		// reservoir
+		throttle()
+
+		collection, err := external.GetReservoirCollectionBySlug(ctx, slug)
+		if err == nil {
+			address := collection.ContractAddress()
+
+			gbl.Log.Debugf("🐌 resolved %s → %s via reservoir", slug, address.Hex())
+
+			return address, nil
+		}
+
+		gbl.Log.Debugf("🐌 resolving %s via reservoir failed: %s", slug, err)
+
+		// on-chain names of the known collections
+		if address, ok := matchCollectionName(slug); ok {
+			gbl.Log.Debugf("🐌 resolved %s → %s via collection name", slug, address.Hex())
+
+			return address, nil
+		}
+
+		return common.Address{}, ErrNotResolved
+	})
+
+	if err == nil && address != (common.Address{}) {
+		Add(slug, address)
+
+		return address, nil
+	}
+
+	gbl.Log.Warnf("🐌 could not resolve slug %s", style.AlmostWhiteStyle.Render(slug))
+
+	markFailed(slug)
+
+	return common.Address{}, ErrNotResolved
+}
+
+// Slug returns the opensea slug for a contract address.
+// sources: cache → collection db → redis → opensea → reservoir.
+func Slug(ctx context.Context, address common.Address) (string, error) {
+	cacheMu.RLock()
+	slug, ok := slugForAddress[address]
+	cacheMu.RUnlock()
+
+	if ok {
+		return slug, nil
+	}
+
+	if recentlyFailed(address.Hex()) {
+		return "", ErrNotResolved
+	}
+
+	// collection db
+	if gb != nil && gb.CollectionDB != nil {
+		gb.CollectionDB.RWMu.RLock()
+		collection, ok := gb.CollectionDB.Collections[address]
+		gb.CollectionDB.RWMu.RUnlock()
+
+		if ok && collection != nil && collection.OpenseaSlug != "" {
+			Add(collection.OpenseaSlug, address)
+
+			return normalize(collection.OpenseaSlug), nil
+		}
+	}
+
+	// redis, concurrent lookups of the same address request the external apis only once
+	slug, err := rueidi().OSSlug(ctx, address, func(ctx context.Context) (string, error) {
+		// opensea
+		throttle()
+
+		if slug := opensea.GetCollectionSlug(address); slug != "" {
+			gbl.Log.Debugf("🐌 resolved %s → %s via opensea", address.Hex(), slug)
+
+			return normalize(slug), nil
+		}
+
+		// reservoir
+		throttle()
+
+		if collection, err := external.GetReservoirCollectionByAddress(ctx, address); err == nil && collection.Slug != "" {
+			gbl.Log.Debugf("🐌 resolved %s → %s via reservoir", address.Hex(), collection.Slug)
+
+			return normalize(collection.Slug), nil
+		}
+
+		return "", ErrNotResolved
+	})
+
+	if err == nil && slug != "" {
+		Add(slug, address)
+
+		return normalize(slug), nil
+	}
+
+	gbl.Log.Warnf("🐌 could not resolve the slug for %s", style.AlmostWhiteStyle.Render(address.Hex()))
+
+	markFailed(address.Hex())
+
+	return "", ErrNotResolved
+}
+
+// matchCollectionName returns the address of the known collection whose name matches the slug.
+func matchCollectionName(slug string) (common.Address, bool) {
+	if gb == nil || gb.CollectionDB == nil {
+		return common.Address{}, false
+	}
+
+	wanted := compact(slug)
+
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	for address, collection := range gb.CollectionDB.Collections {
+		if collection.Name != "" && compact(collection.Name) == wanted {
+			return address, true
+		}
+	}
+
+	return common.Address{}, false
+}
+
+// throttle waits until apiRequestInterval passed since the last external request (don't fuck opensea).
+func throttle() {
+	apiMu.Lock()
+	defer apiMu.Unlock()
+
+	if wait := apiRequestInterval - time.Since(lastAPIRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	lastAPIRequest = time.Now()
+}
+
+func recentlyFailed(key string) bool {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
+	failedAt, ok := failedLookups[key]
+
+	return ok && time.Since(failedAt) < failedLookupTTL
+}
+
+func markFailed(key string) {
+	cacheMu.Lock()
+	failedLookups[key] = time.Now()
+	cacheMu.Unlock()
+}
+
+func normalize(slug string) string {
+	return strings.ToLower(strings.TrimSpace(slug))
+}
+
+// compact removes everything except letters & digits to compare slugs with collection names.
+func compact(name string) string {
+	var compacted strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			compacted.WriteRune(r)
+		}
+	}
+
+	return compacted.String()
+}
@@ -0,0 +1,73 @@
+package style
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// HyperlinkMode controls if TerminalLink emits osc 8 hyperlinks.
+type HyperlinkMode string
+
+const (
+	// HyperlinksAuto enables hyperlinks if the terminal is known to support them.
+	HyperlinksAuto   HyperlinkMode = "auto"
+	HyperlinksAlways HyperlinkMode = "always"
+	HyperlinksNever  HyperlinkMode = "never"
+)
+
+// hyperlinks are enabled until configured otherwise
+var hyperlinksDisabled atomic.Bool
+
+// SetHyperlinks sets the hyperlink mode, unknown modes are treated as auto.
+func SetHyperlinks(mode HyperlinkMode) {
+	switch mode {
+	case HyperlinksAlways:
+		hyperlinksDisabled.Store(false)
+	case HyperlinksNever:
+		hyperlinksDisabled.Store(true)
+	default:
+		hyperlinksDisabled.Store(!terminalSupportsHyperlinks())
+	}
+}
+
+// HyperlinksEnabled returns true if TerminalLink emits osc 8 hyperlinks.
+func HyperlinksEnabled() bool {
+	return !hyperlinksDisabled.Load()
+}
+
+// terminalSupportsHyperlinks checks stdout & the environment for a terminal with osc 8 support.
+// terminals ignoring unknown escape sequences would be fine, but some print them as garbage.
+func terminalSupportsHyperlinks() bool {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return false
+	}
+
+	switch os.Getenv("TERM") {
+	case "", "dumb", "linux":
+		return false
+	}
+
+	for _, env := range []string{"WT_SESSION", "KITTY_WINDOW_ID", "WEZTERM_PANE", "KONSOLE_VERSION", "ALACRITTY_WINDOW_ID", "DOMTERM"} {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby", "tmux":
+		return true
+	case "Apple_Terminal":
+		return false
+	}
+
+	// vte based terminals (gnome terminal, tilix, ...) support them since 0.50
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil {
+		return vte >= 5000
+	}
+
+	return strings.Contains(os.Getenv("TERM"), "kitty") || strings.Contains(os.Getenv("TERM"), "foot")
+}
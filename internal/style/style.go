@@ -433,7 +433,7 @@ func toFixed(num float64, precision int) float64 {
 	return float64(round(num*output)) / output
 }
 
-// TerminalLink formats a link for the terminal using ANSI codes.
+// TerminalLink formats a link for the terminal using osc 8 escape codes (plain text if hyperlinks are disabled).
 func TerminalLink(params ...string) string {
 	var text string
 
@@ -445,5 +445,9 @@ func TerminalLink(params ...string) string {
 		text = url
 	}
 
+	if !HyperlinksEnabled() {
+		return text
+	}
+
 	return fmt.Sprintf("\033]8;;%s\033\\%s\033]8;;\033\\", url, text)
 }
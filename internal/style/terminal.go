@@ -0,0 +1,88 @@
+package style
+
+import (
+	"os"
+	"sync/atomic"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// Layout of the terminal output depending on the terminal width.
+type Layout int
+
+const (
+	LayoutNarrow Layout = iota
+	LayoutNormal
+	LayoutWide
+)
+
+const (
+	narrowBelow = 100
+	wideFrom    = 180
+)
+
+// current width of the terminal, 0 if unknown (e.g. output is not a terminal).
+var terminalWidth atomic.Int64
+
+func (l Layout) String() string {
+	switch l {
+	case LayoutNarrow:
+		return "narrow"
+	case LayoutWide:
+		return "wide"
+	default:
+		return "normal"
+	}
+}
+
+// WatchTerminalSize gets the current terminal width & updates it on every resize.
+func WatchTerminalSize() {
+	updateTerminalWidth()
+
+	watchResize(updateTerminalWidth)
+}
+
+// TerminalWidth returns the current width of the terminal or 0 if unknown.
+func TerminalWidth() int {
+	return int(terminalWidth.Load())
+}
+
+// CurrentLayout returns the layout for the current terminal width.
+func CurrentLayout() Layout {
+	width := TerminalWidth()
+
+	switch {
+	case width == 0:
+		return LayoutNormal
+	case width < narrowBelow:
+		return LayoutNarrow
+	case width >= wideFrom:
+		return LayoutWide
+	default:
+		return LayoutNormal
+	}
+}
+
+// FitToTerminal truncates every line of the output to the terminal width instead of letting the terminal wrap it.
+func FitToTerminal(output string) string {
+	width := TerminalWidth()
+	if width == 0 || !viper.GetBool("terminal.truncate") {
+		return output
+	}
+
+	return lipgloss.NewStyle().MaxWidth(width).Render(output)
+}
+
+func updateTerminalWidth() {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return
+	}
+
+	if previousWidth := terminalWidth.Swap(int64(width)); previousWidth != int64(width) {
+		gbl.Log.Debugf("terminal width changed: %d -> %d | layout: %s", previousWidth, width, CurrentLayout())
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package style
+
+import "time"
+
+// watchResize polls the terminal size as there is no resize signal on this platform.
+func watchResize(onResize func()) {
+	go func() {
+		for range time.NewTicker(time.Second * 2).C {
+			onResize()
+		}
+	}()
+}
@@ -0,0 +1,22 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package style
+
+import (
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchResize calls onResize every time the terminal is resized (SIGWINCH).
+func watchResize(onResize func()) {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, unix.SIGWINCH)
+
+	go func() {
+		for range resized {
+			onResize()
+		}
+	}()
+}
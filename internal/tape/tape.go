@@ -0,0 +1,314 @@
+package tape
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/chawago"
+	chawagoModels "github.com/benleb/gloomberg/internal/chawago/models"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/seawa/models"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/term"
+)
+
+// without a sticky header, the header is repeated every n rows.
+const headerEvery = 25
+
+// interval to refresh the floor from the opensea collection stats.
+const floorRefreshInterval = time.Minute
+
+// Tape prints the listings, sales & offers of a single collection in a dense columnar layout
+// below a header with the live floor & top bid.
+type Tape struct {
+	gb *gloomberg.Gloomberg
+
+	slug            string
+	contractAddress common.Address
+	collectionStyle lipgloss.Style
+
+	// lowest known listing & highest collection offer (per item)
+	floor  *big.Int
+	topBid *big.Int
+
+	numSales    uint64
+	numListings uint64
+	numOffers   uint64
+	volume      *big.Int
+
+	// the header is kept in the first line via a terminal scroll region
+	sticky bool
+	rows   int
+
+	mu *sync.Mutex
+}
+
+// New returns a tape for the collection.
+func New(gb *gloomberg.Gloomberg, slug string, contractAddress common.Address) *Tape {
+	collectionStyle, _ := style.GenerateAddressStyles(&contractAddress)
+
+	return &Tape{
+		gb: gb,
+
+		slug:            slug,
+		contractAddress: contractAddress,
+		collectionStyle: collectionStyle.Bold(true),
+
+		floor:  big.NewInt(0),
+		topBid: big.NewInt(0),
+		volume: big.NewInt(0),
+
+		mu: &sync.Mutex{},
+	}
+}
+
+// Start subscribes to the sales of the collection via the provider pool & prints the received events.
+// listings & offers are received from the opensea stream, the caller subscribes the slug.
+func (t *Tape) Start() error {
+	logs := make(chan types.Log, 1024)
+
+	if _, err := t.gb.ProviderPool.SubscribeToAddresses(logs, []common.Address{t.contractAddress}); err != nil {
+		return err
+	}
+
+	chawago.GetTransactionsForLogs(context.Background(), t.gb, logs, nil)
+
+	t.setupTerminal()
+	t.refreshFloor()
+	t.printHeader()
+
+	go t.handleSales(t.gb.SubscribeTxWithLogs())
+	go t.handleOpenSeaEvents(t.gb.SubscribeItemListed(), t.gb.SubscribeItemReceivedBid(), t.gb.SubscribeCollectionOffer())
+
+	go func() {
+		for range time.NewTicker(floorRefreshInterval).C {
+			t.refreshFloor()
+			t.printHeader()
+		}
+	}()
+
+	return nil
+}
+
+// Close resets the scroll region of the terminal.
+func (t *Tape) Close() {
+	if t.sticky {
+		fmt.Print("\x1b[r")
+	}
+}
+
+// setupTerminal reserves the first line for the header if the output is a terminal.
+func (t *Tape) setupTerminal() {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return
+	}
+
+	_, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || height < 5 {
+		return
+	}
+
+	t.sticky = true
+
+	// clear the screen, scroll region from line 2 to the bottom & move the cursor into it
+	fmt.Printf("\x1b[2J\x1b[2;%dr\x1b[%d;1H", height, height)
+}
+
+func (t *Tape) handleSales(txsWithLogs chan *chawagoModels.TxWithLogs) {
+	for txWithLogs := range txsWithLogs {
+		ttx := totra.NewTokenTransaction(txWithLogs.Transaction, txWithLogs.Receipt, t.gb.ProviderPool)
+		if ttx == nil || !ttx.IsMovingNFTs() || ttx.AmountPaid == nil || ttx.AmountPaid.Sign() == 0 {
+			continue
+		}
+
+		tokenIDs := make([]string, 0)
+		numTokens := int64(0)
+
+		var seller, buyer common.Address
+
+		for _, transfer := range ttx.Transfers {
+			if transfer.Token == nil || transfer.Token.Address != t.contractAddress {
+				continue
+			}
+
+			tokenIDs = append(tokenIDs, style.TerminalLink(links.Item(t.contractAddress, transfer.Token.ID), "#"+transfer.Token.ID.String()))
+			if transfer.AmountTokens != nil {
+				numTokens += max(1, transfer.AmountTokens.Int64())
+			} else {
+				numTokens++
+			}
+			seller, buyer = transfer.From, transfer.To
+		}
+
+		if numTokens == 0 {
+			continue
+		}
+
+		perItemPrice := new(big.Int).Div(ttx.AmountPaid, big.NewInt(numTokens))
+
+		t.mu.Lock()
+		t.numSales += uint64(numTokens)
+		t.volume.Add(t.volume, ttx.AmountPaid)
+		t.mu.Unlock()
+
+		marketplace := ""
+		if ttx.Marketplace != nil {
+			marketplace = lipgloss.NewStyle().Foreground(ttx.Marketplace.Color).Render(ttx.Marketplace.Name)
+		}
+
+		t.printRow(degendb.Sale, style.BoldAlmostWhite("sale "), strings.Join(tokenIDs, " "), perItemPrice, fmt.Sprintf("%s → %s", style.FormatAddress(&seller), style.FormatAddress(&buyer)), marketplace)
+	}
+}
+
+func (t *Tape) handleOpenSeaEvents(listings chan *models.ItemListed, bids chan *models.ItemReceivedBid, offers chan *models.CollectionOffer) {
+	for {
+		select {
+		case listing := <-listings:
+			if listing.Payload.Item.ContractAddress() != t.contractAddress {
+				continue
+			}
+
+			perItemPrice := perItem(listing.Payload.EventPayload)
+
+			t.mu.Lock()
+			t.numListings++
+
+			newFloor := t.floor.Sign() == 0 || perItemPrice.Cmp(t.floor) < 0
+			if newFloor {
+				t.floor = perItemPrice
+			}
+			t.mu.Unlock()
+
+			tokenID := listing.Payload.Item.TokenID()
+			fmtToken := style.TerminalLink(links.Item(t.contractAddress, tokenID), "#"+tokenID.String())
+
+			t.printRow(degendb.Listing, style.TrendLightGreenStyle.Render("list "), fmtToken, perItemPrice, style.FormatAddress(&listing.Payload.Maker.Address), "")
+
+			if newFloor {
+				t.printHeader()
+			}
+
+		case bid := <-bids:
+			if bid.Payload.Item.ContractAddress() != t.contractAddress {
+				continue
+			}
+
+			tokenID := bid.Payload.Item.TokenID()
+			fmtToken := style.TerminalLink(links.Item(t.contractAddress, tokenID), "#"+tokenID.String())
+
+			t.printRow(degendb.Bid, style.GrayStyle.Render("bid  "), fmtToken, perItem(bid.Payload.EventPayload), style.FormatAddress(&bid.Payload.Maker.Address), "")
+
+		case offer := <-offers:
+			if offer.Payload.Collection.Slug != t.slug && offer.Payload.ContractCriteria.Address != t.contractAddress {
+				continue
+			}
+
+			perItemPrice := perItem(offer.Payload.EventPayload)
+
+			t.mu.Lock()
+			t.numOffers++
+
+			newTopBid := perItemPrice.Cmp(t.topBid) > 0
+			if newTopBid {
+				t.topBid = perItemPrice
+			}
+			t.mu.Unlock()
+
+			t.printRow(degendb.CollectionOffer, style.GrayStyle.Render("offer"), style.DarkGrayStyle.Render("collection"), perItemPrice, style.FormatAddress(&offer.Payload.Maker.Address), "")
+
+			if newTopBid {
+				t.printHeader()
+			}
+		}
+	}
+}
+
+// refreshFloor sets the floor from the opensea collection stats, listings below it update it in between.
+func (t *Tape) refreshFloor() {
+	stats := opensea.GetCollectionStats(t.slug)
+	if stats == nil || stats.FloorPrice <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	t.floor = utils.EtherToWei(big.NewFloat(stats.FloorPrice))
+	t.mu.Unlock()
+}
+
+func (t *Tape) printHeader() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	divider := style.DarkGrayStyle.Render(" · ")
+
+	header := strings.Join([]string{
+		t.collectionStyle.Render(t.slug),
+		"floor " + formatPrice(t.floor),
+		"top bid " + formatPrice(t.topBid),
+		fmt.Sprintf("%s sales %s vol", style.BoldAlmostWhite(fmt.Sprint(t.numSales)), formatPrice(t.volume)),
+		fmt.Sprintf("%s listings", style.BoldAlmostWhite(fmt.Sprint(t.numListings))),
+		fmt.Sprintf("%s offers", style.BoldAlmostWhite(fmt.Sprint(t.numOffers))),
+	}, divider)
+
+	if t.sticky {
+		// save cursor, draw the first line & restore the cursor
+		fmt.Printf("\x1b7\x1b[1;1H\x1b[2K%s\x1b8", style.FitToTerminal(header))
+
+		return
+	}
+
+	fmt.Println(style.FitToTerminal(header))
+}
+
+func (t *Tape) printRow(eventType degendb.EventType, fmtType string, fmtToken string, perItemPrice *big.Int, fmtParties string, fmtMarketplace string) {
+	row := fmt.Sprintf("%s %s %s %s %s  %s  %s",
+		style.DarkGrayStyle.Render(time.Now().Format("15:04:05")),
+		eventType.Icon(),
+		fmtType,
+		formatPrice(perItemPrice),
+		fmtToken,
+		fmtParties,
+		fmtMarketplace,
+	)
+
+	t.mu.Lock()
+	t.rows++
+	repeatHeader := !t.sticky && t.rows%headerEvery == 0
+	t.mu.Unlock()
+
+	fmt.Println(style.FitToTerminal(row))
+
+	if repeatHeader {
+		t.printHeader()
+	}
+}
+
+func perItem(payload models.EventPayload) *big.Int {
+	if payload.BasePrice == nil {
+		return big.NewInt(0)
+	}
+
+	return new(big.Int).Div(payload.BasePrice, big.NewInt(int64(max(1, payload.Quantity))))
+}
+
+func formatPrice(wei *big.Int) string {
+	if wei == nil || wei.Sign() == 0 {
+		return style.DarkGrayStyle.Render(fmt.Sprintf("%7s", "-"))
+	}
+
+	return style.BoldAlmostWhite(fmt.Sprintf("%7.4f", price.NewPrice(wei).Ether())) + style.GrayStyle.Render("Ξ")
+}
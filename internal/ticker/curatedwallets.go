@@ -13,12 +13,12 @@ import (
 
 	"github.com/benleb/gloomberg/internal/collections"
 	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/links"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/tokencollections"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
 	"github.com/benleb/gloomberg/internal/notify"
 	"github.com/benleb/gloomberg/internal/style"
-	"github.com/benleb/gloomberg/internal/utils"
 	"github.com/ethereum/go-ethereum/common"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/spf13/viper"
@@ -148,16 +148,13 @@ func (s *AlphaScore) AlphaCallerTicker(gb *gloomberg.Gloomberg, alphaCallerTicke
 
 			// send notification via telegram
 			if viper.GetString("notifications.smart_wallets.telegram_chat_id") != "" {
-				etherscanURL, openseaURL, blurURL := utils.GetLinks(txHash, collectionAddress, tokenID.Int64())
-
-				// emoji arrow up
-				replyMarkup := tgbotapi.NewInlineKeyboardMarkup(
-					tgbotapi.NewInlineKeyboardRow(
-						tgbotapi.NewInlineKeyboardButtonURL("ES", etherscanURL),
-						tgbotapi.NewInlineKeyboardButtonURL("🧡Blur", blurURL),
-						tgbotapi.NewInlineKeyboardButtonURL("🔵OS", openseaURL),
-					),
-				)
+				// etherscan & the configured marketplaces, preferred first
+				buttons := []tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardButtonURL("ES", links.Tx(txHash))}
+				for _, link := range links.ItemLinks(collectionAddress, tokenID) {
+					buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonURL(link.Name, link.URL))
+				}
+
+				replyMarkup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(buttons...))
 
 				// try to acquire the lock
 				// if viper.GetBool("redis.enabled") {
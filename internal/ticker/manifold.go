@@ -129,12 +129,7 @@ func (s *ManifoldStats) ManifoldTicker(manifoldTicker *time.Ticker, queueOutput
 			manifoldLine.WriteString(eventTimestamp)
 			manifoldLine.WriteString(" " + event.Action.Icon())
 
-			if event.TotalTokens == 0 {
-				event.TotalTokens = 1
-			}
-
-			pricePerItem := big.NewInt(0).Div(event.AmountPaid, big.NewInt(event.TotalTokens))
-			priceEtherPerItem, _ := utils.WeiToEther(pricePerItem).Float64()
+			priceEtherPerItem := event.PricePerItem().Ether()
 
 			manifoldLine.WriteString(" " + rowStyle.Render(fmt.Sprintf("%6.3f", priceEtherPerItem)))
 			telegramMessage.WriteString(fmt.Sprintf("%6.3f", priceEtherPerItem))
@@ -235,9 +230,7 @@ func (s *ManifoldStats) OneMinuteTicker(manifoldTicker *time.Ticker) {
 			aggregrateEvents[collection.ContractAddress] = true
 
 			if event.TotalTokens > 0 {
-				pricePerItem := big.NewInt(0).Div(event.AmountPaid, big.NewInt(event.TotalTokens))
-				priceEtherPerItem, _ := utils.WeiToEther(pricePerItem).Float64()
-				telegramMessage.WriteString(fmt.Sprintf("%6.3f", priceEtherPerItem))
+				telegramMessage.WriteString(fmt.Sprintf("%6.3f", event.PricePerItem().Ether()))
 				telegramMessage.WriteString("Ξ")
 			}
 
@@ -0,0 +1,80 @@
+package trapri
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/pnl"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// bookOwnTransaction adds the acquisition context (from the pnl ledger) to tokens sold by own wallets
+// and books the tx to the ledgers of the involved own wallets.
+func bookOwnTransaction(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
+	if ttx.TxReceipt == nil || ttx.TxReceipt.BlockNumber == nil {
+		return
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+
+	ownAddresses := mapset.NewSet[common.Address](gb.OwnWallets.Addresses()...)
+	involvedWallets := mapset.NewSet[common.Address]()
+
+	// number of nfts sent per wallet to split the price if the proceeds per item are unknown
+	numSent := make(map[common.Address]int64)
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() {
+			continue
+		}
+
+		if ownAddresses.Contains(transfer.From) {
+			involvedWallets.Add(transfer.From)
+
+			numSent[transfer.From] += transfer.AmountTokens.Int64()
+		}
+
+		if ownAddresses.Contains(transfer.To) {
+			involvedWallets.Add(transfer.To)
+		}
+	}
+
+	for _, walletAddress := range involvedWallets.ToSlice() {
+		ledger := pnl.GetLedger(ctx, gb.Rueidi, walletAddress)
+
+		// acquisition context must be calculated before the sale is booked
+		if degendb.SaleTypes.Contains(ttx.Action) {
+			for _, transfer := range ttx.Transfers {
+				if transfer.From != walletAddress || !transfer.Standard.IsERC721orERC1155() || transfer.AmountTokens.Sign() == 0 {
+					continue
+				}
+
+				var proceedsPerToken *big.Int
+
+				switch {
+				case transfer.AmountEtherReturned != nil && transfer.AmountEtherReturned.Sign() > 0:
+					proceedsPerToken = new(big.Int).Div(transfer.AmountEtherReturned, transfer.AmountTokens)
+				case ttx.AmountPaid != nil && numSent[walletAddress] > 0:
+					proceedsPerToken = new(big.Int).Div(ttx.AmountPaid, big.NewInt(numSent[walletAddress]))
+				}
+
+				if acquisitionContext := ledger.AcquisitionContext(transfer.Token, proceedsPerToken, now); acquisitionContext != nil {
+					transfer.AcquisitionContext = acquisitionContext.String()
+				}
+			}
+		}
+
+		ledger.AddTokenTransaction(ttx, ttx.TxReceipt.BlockNumber.Uint64(), now)
+
+		if err := ledger.Save(ctx, gb.Rueidi); err != nil {
+			gbl.Log.Warnf("❗️ pnl | could not save ledger of %s: %s", walletAddress.Hex(), err)
+		}
+	}
+}
@@ -12,15 +12,62 @@ import (
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/nemo/token"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/proceeds"
 	"github.com/benleb/gloomberg/internal/seawa/models"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
 )
 
 var (
 	collectionOffers      = map[common.Address]*models.CollectionOffer{}
 	collectionOffersMutex = &sync.Mutex{}
+
+	// last shown offer per collection & maker to throttle bots reposting identical offers
+	shownMakerOffers      = map[makerOffer]*shownOffer{}
+	shownMakerOffersMutex = &sync.Mutex{}
 )
 
+type makerOffer struct {
+	contractAddress common.Address
+	maker           common.Address
+}
+
+type shownOffer struct {
+	price   *big.Int
+	shownAt time.Time
+}
+
+// isRepostedOffer returns true if the maker's offer for the collection was already shown within
+// trapri.offer_dedup_window & the price did not improve. otherwise the offer is remembered as shown.
+func isRepostedOffer(contractAddress common.Address, maker common.Address, perItemPrice *big.Int) bool {
+	window := viper.GetDuration("trapri.offer_dedup_window")
+	if window <= 0 {
+		return false
+	}
+
+	key := makerOffer{contractAddress: contractAddress, maker: maker}
+
+	shownMakerOffersMutex.Lock()
+	defer shownMakerOffersMutex.Unlock()
+
+	if shown, ok := shownMakerOffers[key]; ok && time.Since(shown.shownAt) < window && perItemPrice.Cmp(shown.price) <= 0 {
+		return true
+	}
+
+	shownMakerOffers[key] = &shownOffer{price: perItemPrice, shownAt: time.Now()}
+
+	// forget offers outside the window from time to time
+	if len(shownMakerOffers)%256 == 0 {
+		for k, shown := range shownMakerOffers {
+			if time.Since(shown.shownAt) >= window {
+				delete(shownMakerOffers, k)
+			}
+		}
+	}
+
+	return false
+}
+
 func HandleCollectionOffer(gb *gloomberg.Gloomberg, event *models.CollectionOffer) {
 	contractAddress := common.HexToAddress(event.Payload.ContractCriteria.Address.Hex())
 
@@ -42,6 +89,13 @@ func HandleCollectionOffer(gb *gloomberg.Gloomberg, event *models.CollectionOffe
 		gbl.Log.Warnf("🤷‍♀️ error parsing tokenPrice: %+v", event.Payload)
 	}
 
+	// bidding bots repost the same offer every few minutes
+	if isRepostedOffer(contractAddress, sellerAddress, tokenPrice.Wei()) {
+		gbl.Log.Debugf("🍭 %s reposted offer for %s without improving the price", sellerAddress.Hex(), contractAddress.Hex())
+
+		return
+	}
+
 	// if it should be a new top bid, we highlight it when printing
 	// highlight := false
 
@@ -115,6 +169,20 @@ func HandleCollectionOffer(gb *gloomberg.Gloomberg, event *models.CollectionOffe
 		},
 	}
 
+	// net proceeds of accepting the offer for collections we hold
+	if proceeds.Enabled() {
+		if label := proceeds.Label(gb, contractAddress, tokenPrice.Wei()); label != "" {
+			ttxCollectionOffer.Annotations = append(ttxCollectionOffer.Annotations, label)
+		}
+	}
+
+	// seaport order parameters for debugging (verbose mode only)
+	if showOrderData() {
+		if label := seaportOrderLabel(event.Payload.EventPayload); label != "" {
+			ttxCollectionOffer.Annotations = append(ttxCollectionOffer.Annotations, label)
+		}
+	}
+
 	// format and print
 	gb.In.TokenTransactions <- ttxCollectionOffer
 }
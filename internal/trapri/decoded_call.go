@@ -0,0 +1,88 @@
+package trapri
+
+import (
+	"context"
+	"slices"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/abireg"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/marketplace"
+	"github.com/benleb/gloomberg/internal/nemo/topic"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/benleb/gloomberg/internal/utils"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// maxDecodedEvents limits the decoded events printed per tx.
+const maxDecodedEvents = 3
+
+// printDecodedCall decodes the calldata & the events of txs to watched contracts or unrecognized protocols
+// via the abi registry and prints lines like "decoded call: mint(uint256 3)".
+func printDecodedCall(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) {
+	if !abireg.Enabled() || ttx.Tx == nil || ttx.Tx.To() == nil || len(ttx.Tx.Data()) < 4 {
+		return
+	}
+
+	toAddress := *ttx.Tx.To()
+
+	// only for contracts we watch or protocols we don't know (yet)
+	isWatchedContract := slices.Contains(gb.CollectionDB.UserCollectionsAddresses(), toAddress)
+	isUnknownProtocol := ttx.Action == degendb.Unknown || (ttx.Marketplace == &marketplace.Unknown && ttx.Action != degendb.Transfer)
+
+	if !isWatchedContract && !isUnknownProtocol {
+		return
+	}
+
+	etherscanURL := utils.GetEtherscanTxURL(ttx.TxHash.Hex())
+	txLink := style.TerminalLink(etherscanURL, style.ShortenHashStyled(ttx.TxHash))
+
+	decodedCall, err := gb.ABIs.DecodeCall(context.Background(), toAddress, ttx.Tx.Data())
+	if err != nil {
+		gbl.Log.Debugf("📜 abireg | could not decode call to %s: %s", toAddress.Hex(), err)
+
+		return
+	}
+
+	gloomberg.PrModf("abi", "decoded call: %s %s %s", style.AlmostWhiteStyle.Render(decodedCall), style.DarkGrayStyle.Render("|"), txLink)
+
+	if ttx.TxReceipt == nil {
+		return
+	}
+
+	// events emitted by the called contract, except the token transfers & approvals already shown
+	numDecodedEvents := 0
+
+	for _, txLog := range ttx.TxReceipt.Logs {
+		if numDecodedEvents >= maxDecodedEvents {
+			break
+		}
+
+		if txLog.Address != toAddress || len(txLog.Topics) == 0 || isTokenEvent(txLog.Topics[0]) {
+			continue
+		}
+
+		decodedEvent, err := gb.ABIs.DecodeLog(context.Background(), txLog)
+		if err != nil {
+			gbl.Log.Debugf("📜 abireg | could not decode event of %s: %s", toAddress.Hex(), err)
+
+			continue
+		}
+
+		numDecodedEvents++
+
+		gloomberg.PrModf("abi", "decoded event: %s %s %s", style.AlmostWhiteStyle.Render(decodedEvent), style.DarkGrayStyle.Render("|"), txLink)
+	}
+}
+
+// isTokenEvent returns true for the transfer & approval events of tokens.
+func isTokenEvent(eventTopic common.Hash) bool {
+	switch topic.Topic(eventTopic.Hex()) {
+	case topic.Transfer, topic.TransferSingle, topic.ApprovalForAll:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+package trapri
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/nemo/standard"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// listedEdition is an erc1155 listing with more than one token.
+type listedEdition struct {
+	Quantity  int64
+	Remaining int64
+	ExpiresAt time.Time
+}
+
+var (
+	// open erc1155 listings by token & maker to show partial fills
+	listedEditions   = make(map[string]*listedEdition)
+	listedEditionsMu sync.Mutex
+)
+
+func listedEditionKey(contractAddress common.Address, tokenID int64, maker common.Address) string {
+	return fmt.Sprintf("%s:%d:%s", contractAddress.Hex(), tokenID, maker.Hex())
+}
+
+// addListedEdition remembers the quantity of a listing to calculate the remaining quantity on (partial) fills.
+func addListedEdition(contractAddress common.Address, tokenID int64, maker common.Address, quantity int64, expiresAt time.Time) {
+	if quantity <= 1 {
+		return
+	}
+
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(time.Hour * 24 * 7)
+	}
+
+	listedEditionsMu.Lock()
+	defer listedEditionsMu.Unlock()
+
+	// remove expired listings
+	for key, edition := range listedEditions {
+		if time.Now().After(edition.ExpiresAt) {
+			delete(listedEditions, key)
+		}
+	}
+
+	listedEditions[listedEditionKey(contractAddress, tokenID, maker)] = &listedEdition{Quantity: quantity, Remaining: quantity, ExpiresAt: expiresAt}
+}
+
+// fillListedEdition sets the quantity of the listing filled by an erc1155 sale & reduces its remaining quantity.
+func fillListedEdition(transfer *totra.TokenTransfer) {
+	if transfer.Standard != standard.ERC1155 || transfer.Token == nil || transfer.Token.ID == nil {
+		return
+	}
+
+	key := listedEditionKey(transfer.Token.Address, transfer.Token.ID.Int64(), transfer.From)
+
+	listedEditionsMu.Lock()
+	defer listedEditionsMu.Unlock()
+
+	edition, ok := listedEditions[key]
+	if !ok {
+		return
+	}
+
+	transfer.ListedQuantity = edition.Remaining
+
+	edition.Remaining -= transfer.AmountTokens.Int64()
+	if edition.Remaining <= 0 {
+		delete(listedEditions, key)
+	}
+}
@@ -0,0 +1,166 @@
+package trapri
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+var gwei = big.NewFloat(1e9)
+
+// number of blocks to keep the base fee of
+const baseFeeCacheSize = 64
+
+var (
+	// base fees by chain & block number, only fetched if the priority fee can't be derived from the tx
+	baseFees   = make(map[uint64]map[uint64]*big.Int)
+	baseFeesMu sync.Mutex
+)
+
+// showGasUsed returns true if the gas costs of sales & mints should be shown (verbose mode only).
+func showGasUsed(ttx *totra.TokenTransaction) bool {
+	if !viper.GetBool("log.verbose") || !viper.GetBool("show.gas_used") {
+		return false
+	}
+
+	switch ttx.Action {
+	case degendb.Sale, degendb.Purchase, degendb.Mint:
+		return ttx.Tx != nil && ttx.TxReceipt != nil && ttx.TxReceipt.GasUsed > 0
+	default:
+		return false
+	}
+}
+
+// gasCosts returns the gas used, the effective gas price, the priority fee & the total cost of the tx.
+// if the receipt lacks the effective gas price, the max fee is used as upper bound.
+func gasCosts(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) *degendb.GasCosts {
+	if ttx.Tx == nil || ttx.TxReceipt == nil || ttx.TxReceipt.GasUsed == 0 {
+		return nil
+	}
+
+	costs := &degendb.GasCosts{GasUsed: ttx.TxReceipt.GasUsed, EffectiveGasPrice: ttx.TxReceipt.EffectiveGasPrice}
+
+	if costs.EffectiveGasPrice == nil || costs.EffectiveGasPrice.Sign() == 0 {
+		costs.EffectiveGasPrice = ttx.Tx.GasPrice()
+		costs.IsUpperBound = true
+	}
+
+	costs.TxFee = price.NewPrice(new(big.Int).Mul(new(big.Int).SetUint64(costs.GasUsed), costs.EffectiveGasPrice))
+
+	if !costs.IsUpperBound {
+		costs.PriorityFee = priorityFee(gb, ttx, costs.EffectiveGasPrice)
+	}
+
+	return costs
+}
+
+// priorityFee returns the tip per gas paid by the tx. the effective gas price is baseFee + min(tipCap, feeCap - baseFee),
+// so the full tip cap was paid if the effective price is below the fee cap. otherwise the base fee of the block is needed.
+func priorityFee(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, effectiveGasPrice *big.Int) *big.Int {
+	if ttx.Tx.Type() != types.LegacyTxType && ttx.Tx.Type() != types.AccessListTxType && effectiveGasPrice.Cmp(ttx.Tx.GasFeeCap()) < 0 {
+		return ttx.Tx.GasTipCap()
+	}
+
+	baseFee := getBaseFee(gb, ttx)
+	if baseFee == nil {
+		return nil
+	}
+
+	return new(big.Int).Sub(effectiveGasPrice, baseFee)
+}
+
+// getBaseFee returns the (cached) base fee of the block of the tx or nil.
+func getBaseFee(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) *big.Int {
+	if ttx.TxReceipt.BlockNumber == nil {
+		return nil
+	}
+
+	blockNumber := ttx.TxReceipt.BlockNumber.Uint64()
+
+	baseFeesMu.Lock()
+	baseFee := baseFees[ttx.ChainID][blockNumber]
+	baseFeesMu.Unlock()
+
+	if baseFee != nil {
+		return baseFee
+	}
+
+	pool := gb.PoolFor(ttx.ChainID)
+	if pool == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	header, err := pool.HeaderByNumber(ctx, ttx.TxReceipt.BlockNumber)
+	if err != nil || header.BaseFee == nil {
+		gbl.Log.Debugf("⛽️ getting base fee of block %d failed: %v", blockNumber, err)
+
+		return nil
+	}
+
+	baseFeesMu.Lock()
+	defer baseFeesMu.Unlock()
+
+	if baseFees[ttx.ChainID] == nil {
+		baseFees[ttx.ChainID] = make(map[uint64]*big.Int)
+	}
+
+	baseFees[ttx.ChainID][blockNumber] = header.BaseFee
+
+	for cachedBlock := range baseFees[ttx.ChainID] {
+		if cachedBlock+baseFeeCacheSize < blockNumber {
+			delete(baseFees[ttx.ChainID], cachedBlock)
+		}
+	}
+
+	return header.BaseFee
+}
+
+// gasUsedLabel returns the gas used, the effective gas price, the priority fee & the total cost of the tx.
+func gasUsedLabel(costs *degendb.GasCosts) string {
+	prefix := ""
+	if costs.IsUpperBound {
+		prefix = "≤"
+	}
+
+	fmtTip := ""
+	if costs.PriorityFee != nil {
+		fmtTip = fmt.Sprintf(" (%.1f tip)", toGwei(costs.PriorityFee))
+	}
+
+	return fmt.Sprintf("⛽️ %s gas · %s%.1f gwei%s · %s%sΞ",
+		formatGasUnits(costs.GasUsed),
+		prefix,
+		toGwei(costs.EffectiveGasPrice),
+		fmtTip,
+		prefix,
+		style.BoldAlmostWhite(fmt.Sprintf("%.4f", costs.TxFee.Ether())),
+	)
+}
+
+func toGwei(wei *big.Int) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), gwei).Float64()
+
+	return value
+}
+
+func formatGasUnits(gasUsed uint64) string {
+	if gasUsed >= 1_000_000 {
+		return fmt.Sprintf("%.2fm", float64(gasUsed)/1_000_000)
+	}
+
+	return fmt.Sprintf("%dk", gasUsed/1_000)
+}
@@ -1,16 +1,22 @@
 package trapri
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
 
+	"github.com/benleb/gloomberg/internal/collections"
 	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/links"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/marketplace"
 	"github.com/benleb/gloomberg/internal/nemo/token"
 	"github.com/benleb/gloomberg/internal/nemo/tokencollections"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
 	"github.com/benleb/gloomberg/internal/seawa/models"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/spf13/viper"
 )
 
 func HandleItemListed(gb *gloomberg.Gloomberg, event *models.ItemListed) {
@@ -54,8 +60,23 @@ func HandleItemListed(gb *gloomberg.Gloomberg, event *models.ItemListed) {
 		},
 	}
 
-	// format and print in stream
-	gb.In.TokenTransactions <- ttxListing
+	// seaport order parameters for debugging (verbose mode only)
+	if showOrderData() {
+		if label := seaportOrderLabel(event.Payload.EventPayload); label != "" {
+			ttxListing.Annotations = append(ttxListing.Annotations, label)
+		}
+	}
+
+	// remember the quantity of erc1155 listings to show partial fills
+	addListedEdition(contractAddress, nftID.TokenID().Int64(), sellerAddress, int64(event.Payload.Quantity), event.Payload.ExpirationDate)
+
+	// format and print in stream (after the relist settle period if enabled)
+	listedPricePerItem := event.Payload.GetPrice().Ether()
+	if event.Payload.Quantity > 1 {
+		listedPricePerItem /= float64(event.Payload.Quantity)
+	}
+
+	queueListing(gb, ttxListing, contractAddress, nftID.TokenID().Int64(), sellerAddress, listedPricePerItem)
 
 	// // 💄 style
 	// primaryColor, _ := style.GenerateAddressColors(&contractAddress)
@@ -68,6 +89,55 @@ func HandleItemListed(gb *gloomberg.Gloomberg, event *models.ItemListed) {
 		// counting for salira and more...
 		collection.AddListing(uint64(event.Payload.Quantity))
 
+		// price per item
+		listingPrice := event.Payload.GetPrice().Ether()
+		if event.Payload.Quantity > 1 {
+			listingPrice /= float64(event.Payload.Quantity)
+		}
+
+		// check for deals against the floor before this listing is part of it
+		if viper.GetBool("deals.enabled") && collection.IsOwn() {
+			checkFloorDeal(collection, ttxListing.Transfers[0].Token, listingPrice)
+		}
+
+		collection.AddListingPrice(nftID.TokenID(), listingPrice)
+
 		return
 	}
 }
+
+// checkFloorDeal alerts on listings significantly below the (cross-checked) floor of a collection.
+func checkFloorDeal(collection *collections.Collection, listedToken *token.Token, listingPrice float64) {
+	floor := collection.GetFloorEstimate()
+	if floor.Floor <= 0 || listingPrice <= 0 || listingPrice > floor.Floor*viper.GetFloat64("deals.max_floor_ratio") {
+		return
+	}
+
+	// don't act on floors we don't trust at all
+	if floor.Confidence == collections.FloorConfidenceNone || (floor.Confidence == collections.FloorConfidenceLow && !viper.GetBool("deals.low_confidence")) {
+		gbl.Log.Debugf("🏷️ ignoring deal for %s: floor %.3f has low confidence | lowest: %v | sales avg: %.3f", listedToken.ShortID(), floor.Floor, floor.Lowest, floor.SalesAverage)
+
+		return
+	}
+
+	confidenceStyle := style.TrendLightRedStyle
+	switch floor.Confidence {
+	case collections.FloorConfidenceHigh:
+		confidenceStyle = style.TrendGreenStyle
+	case collections.FloorConfidenceMedium:
+		confidenceStyle = style.TrendLightGreenStyle
+	}
+
+	belowFloor := (1 - listingPrice/floor.Floor) * 100
+
+	itemURL := links.Item(listedToken.Address, listedToken.ID)
+
+	gloomberg.PrModf("deal", "%s %s listed at %s · %s below floor %s %s",
+		collection.Render(collection.Name),
+		style.TerminalLink(itemURL, formatTokenID(collection, listedToken.ID)),
+		style.BoldAlmostWhite(fmt.Sprintf("%.3fΞ", listingPrice)),
+		style.BoldAlmostWhite(fmt.Sprintf("%.0f%%", belowFloor)),
+		style.AlmostWhiteStyle.Render(fmt.Sprintf("%.3fΞ", floor.Floor)),
+		style.DarkGrayStyle.Render("(confidence: ")+confidenceStyle.Render(string(floor.Confidence))+style.DarkGrayStyle.Render(")"),
+	)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/benleb/gloomberg/internal/degendb"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/grails"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/marketplace"
 	"github.com/benleb/gloomberg/internal/nemo/token"
@@ -30,9 +31,11 @@ func HandleItemReceivedBid(gb *gloomberg.Gloomberg, event *models.ItemReceivedBi
 	isOwnToken := gb.OwnWallets.ContainsToken(contractAddress, nftID.TokenID().String())
 	// did someone from us make a bid?
 	isWatchUsersWallet := gb.Watcher != nil && gb.Watcher.Contains(event.Payload.Maker.Address)
+	// a bid for a watched grail?
+	isGrail := grails.Enabled() && grails.Contains(contractAddress, nftID.TokenID())
 
 	// check if we hold the token/got a bid
-	if !isOwnToken && !isWatchUsersWallet {
+	if !isOwnToken && !isWatchUsersWallet && !isGrail {
 		gbl.Log.Debugf("🤷‍♀️ %s | bid for token not held by any of our own wallets", nftID.LinkOS())
 
 		return
@@ -108,6 +111,13 @@ func HandleItemReceivedBid(gb *gloomberg.Gloomberg, event *models.ItemReceivedBi
 		},
 	}
 
+	// seaport order parameters for debugging (verbose mode only)
+	if showOrderData() {
+		if label := seaportOrderLabel(event.Payload.EventPayload); label != "" {
+			ttxBid.Annotations = append(ttxBid.Annotations, label)
+		}
+	}
+
 	// format and print
 	gb.In.TokenTransactions <- ttxBid
 
@@ -0,0 +1,45 @@
+package trapri
+
+import (
+	"fmt"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/currency"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+)
+
+// loanLabel returns the protocol & principal of a loan, repayment or liquidation, e.g. "💸 Blend loan · 4.2000Ξ principal".
+func loanLabel(loanEvent *totra.LoanEvent) string {
+	var action string
+
+	switch loanEvent.Action {
+	case degendb.RepayLoan:
+		action = "repayment"
+	case degendb.Liquidation:
+		action = "liquidation"
+	default:
+		action = "loan"
+	}
+
+	label := fmt.Sprintf("%s %s %s", loanEvent.Action.Icon(), loanEvent.Protocol, action)
+
+	if loanEvent.Principal == nil || loanEvent.Principal.Sign() == 0 {
+		return label
+	}
+
+	fmtPrincipal := fmt.Sprintf("%.4fΞ", price.NewPrice(loanEvent.Principal).Ether())
+
+	if loanEvent.Currency != internal.ZeroAddress {
+		loanCurrency := currency.Get(loanEvent.Currency)
+		if loanCurrency == nil {
+			return label
+		}
+
+		fmtPrincipal = loanCurrency.Format(loanEvent.Principal)
+	}
+
+	return label + " · " + style.BoldAlmostWhite(fmtPrincipal) + " principal"
+}
@@ -0,0 +1,32 @@
+package trapri
+
+import (
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/profittaking"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// trackProfitTaking starts watching own/watched sellers for exchange deposits
+// & annotates the event if one of the transactors is already taking profit.
+func trackProfitTaking(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, transactors []common.Address) {
+	if ttx.Action == degendb.Sale {
+		sellers := make([]common.Address, 0)
+
+		for seller := range ttx.GetNFTSenders() {
+			if gb.Watcher.Contains(seller) || gb.OwnWallets.ContainsAddressFromSlice([]common.Address{seller}) != internal.ZeroAddress {
+				sellers = append(sellers, seller)
+			}
+		}
+
+		profittaking.Track(sellers)
+	}
+
+	for _, transactor := range transactors {
+		if deposit := profittaking.Get(transactor); deposit != nil {
+			ttx.Annotations = append(ttx.Annotations, deposit.Format())
+		}
+	}
+}
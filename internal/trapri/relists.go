@@ -0,0 +1,84 @@
+package trapri
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/grails"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// pendingListing is a listing held back until no relist of the same token happened for the settle period.
+type pendingListing struct {
+	ttx    *totra.TokenTransaction
+	prices []float64
+	timer  *time.Timer
+}
+
+var (
+	// listings waiting for the settle period by token & maker
+	pendingListings   = make(map[string]*pendingListing)
+	pendingListingsMu sync.Mutex
+)
+
+// queueListing passes the listing to the formatter after listings.relist_settle without a relist of the token.
+// rapid list/cancel/list cycles are collapsed into a single "relisted 0.9→0.85→0.8Ξ" line.
+func queueListing(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction, contractAddress common.Address, tokenID int64, maker common.Address, pricePerItem float64) {
+	// grails are never delayed
+	settle := viper.GetDuration("listings.relist_settle")
+	if settle <= 0 || (grails.Enabled() && grails.Contains(contractAddress, big.NewInt(tokenID))) {
+		gb.In.TokenTransactions <- ttx
+
+		return
+	}
+
+	key := fmt.Sprintf("%s:%d:%s", contractAddress.Hex(), tokenID, maker.Hex())
+
+	pendingListingsMu.Lock()
+	defer pendingListingsMu.Unlock()
+
+	// relisted within the settle period, replace the listing & start over
+	if pending, ok := pendingListings[key]; ok {
+		pending.ttx = ttx
+		pending.prices = append(pending.prices, pricePerItem)
+		pending.timer.Reset(settle)
+
+		return
+	}
+
+	pendingListings[key] = &pendingListing{
+		ttx:    ttx,
+		prices: []float64{pricePerItem},
+		timer:  time.AfterFunc(settle, func() { releaseListing(gb, key) }),
+	}
+}
+
+func releaseListing(gb *gloomberg.Gloomberg, key string) {
+	pendingListingsMu.Lock()
+	pending, ok := pendingListings[key]
+	delete(pendingListings, key)
+	pendingListingsMu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if len(pending.prices) > 1 {
+		fmtPrices := make([]string, 0, len(pending.prices))
+		for _, price := range pending.prices {
+			fmtPrices = append(fmtPrices, strconv.FormatFloat(math.Round(price*10_000)/10_000, 'f', -1, 64))
+		}
+
+		pending.ttx.Annotations = append(pending.ttx.Annotations, "relisted "+strings.Join(fmtPrices, "→")+"Ξ")
+	}
+
+	gb.In.TokenTransactions <- pending.ttx
+}
@@ -0,0 +1,96 @@
+package trapri
+
+import (
+	"strings"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/standard"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// addresses we already warned about, used to raise only a single warning per scammer.
+var reportedScamAddresses = mapset.NewSet[common.Address]()
+
+// detectScam checks if a tx involving own wallets is a zero-value transfer or
+// an address poisoning attempt (transfers from/to addresses looking like an own wallet).
+// returns the address of the scammer/lookalike and a reason or the zero address if nothing was found.
+func detectScam(gb *gloomberg.Gloomberg, ttx *totra.TokenTransaction) (common.Address, string) {
+	if gb.OwnWallets == nil || len(*gb.OwnWallets) == 0 {
+		return internal.ZeroAddress, ""
+	}
+
+	ownAddresses := mapset.NewSet[common.Address](gb.OwnWallets.Addresses()...)
+
+	// the tx itself has been sent by one of our wallets -> intentional
+	if ownAddresses.Contains(ttx.From) {
+		return internal.ZeroAddress, ""
+	}
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() {
+			continue
+		}
+
+		fromOwn := ownAddresses.Contains(transfer.From)
+		toOwn := ownAddresses.Contains(transfer.To)
+
+		if !fromOwn && !toOwn {
+			continue
+		}
+
+		counterparty := transfer.To
+		if toOwn {
+			counterparty = transfer.From
+		}
+
+		// erc1155 transfers of 0 tokens & erc721 transfers without eth/weth paid (except mints) only pollute the history
+		if transfer.AmountTokens != nil && transfer.AmountTokens.Sign() == 0 {
+			return counterparty, "zero-value transfer"
+		}
+
+		if transfer.Standard == standard.ERC721 && transfer.From != internal.ZeroAddress && (ttx.AmountPaid == nil || ttx.AmountPaid.Sign() == 0) {
+			return counterparty, "zero-value transfer"
+		}
+
+		for _, ownAddress := range ownAddresses.ToSlice() {
+			if isLookalikeAddress(ownAddress, counterparty) {
+				return counterparty, "lookalike of " + style.ShortenAddress(ownAddress)
+			}
+		}
+	}
+
+	return internal.ZeroAddress, ""
+}
+
+// isLookalikeAddress returns true if both addresses differ but share the same leading & trailing hex characters.
+func isLookalikeAddress(a common.Address, b common.Address) bool {
+	if a == b || b == internal.ZeroAddress {
+		return false
+	}
+
+	// at least 1 char & not more than half of the address
+	numChars := min(max(viper.GetInt("scamfilter.lookalike_chars"), 1), 20)
+
+	hexA := strings.ToLower(a.Hex()[2:])
+	hexB := strings.ToLower(b.Hex()[2:])
+
+	return hexA[:numChars] == hexB[:numChars] && hexA[len(hexA)-numChars:] == hexB[len(hexB)-numChars:]
+}
+
+// warnScam prints a single warning per scammer address.
+func warnScam(ttx *totra.TokenTransaction, scammer common.Address, reason string) {
+	if !reportedScamAddresses.Add(scammer) {
+		return
+	}
+
+	gloomberg.PrModf("scam", "suppressed %s involving own wallet | %s | %s",
+		style.BoldAlmostWhite(reason),
+		style.ShortenAddress(scammer),
+		style.TerminalLink(ttx.GetEtherscanTxURL(), style.ShortenHashStyled(ttx.TxHash)),
+	)
+}
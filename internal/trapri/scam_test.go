@@ -0,0 +1,41 @@
+package trapri
+
+import (
+	"testing"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+func Test_isLookalikeAddress(t *testing.T) {
+	own := common.HexToAddress("0x1234aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa5678")
+
+	tests := []struct {
+		name     string
+		numChars interface{}
+		other    common.Address
+		want     bool
+	}{
+		{name: "lookalike", numChars: 4, other: common.HexToAddress("0x1234bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb5678"), want: true},
+		{name: "lookalike mixed case", numChars: 4, other: common.HexToAddress("0x1234BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB5678"), want: true},
+		{name: "different prefix", numChars: 4, other: common.HexToAddress("0x1235bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb5678"), want: false},
+		{name: "different suffix", numChars: 4, other: common.HexToAddress("0x1234bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb5679"), want: false},
+		{name: "more chars required", numChars: 5, other: common.HexToAddress("0x1234bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb5678"), want: false},
+		{name: "same address", numChars: 4, other: own, want: false},
+		{name: "zero address", numChars: 4, other: internal.ZeroAddress, want: false},
+		{name: "clamped to 1 char", numChars: 0, other: common.HexToAddress("0x1bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb8"), want: true},
+		{name: "negative clamped to 1 char", numChars: -3, other: common.HexToAddress("0x2bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb8"), want: false},
+		{name: "clamped to 20 chars", numChars: 100, other: common.HexToAddress("0x1234aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa5679"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("scamfilter.lookalike_chars", tt.numChars)
+			defer viper.Set("scamfilter.lookalike_chars", nil)
+
+			if got := isLookalikeAddress(own, tt.other); got != tt.want {
+				t.Errorf("isLookalikeAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package trapri
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/seawa/models"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// openSeaConduitKey is the conduit key of the opensea conduit (0x1E0049783F008A0085193E00003D00cd54003c71).
+const openSeaConduitKey = "0x0000007b02230091a7ed01230072f7006a004d60a8d4e71d599b8104250f0000"
+
+// openSeaSaltPrefix is the prefix of the salts of orders created via opensea.
+const openSeaSaltPrefix = "360c6ebe"
+
+// seaportVersions are the known seaport deployments.
+var seaportVersions = map[common.Address]string{
+	common.HexToAddress("0x00000000006c3852cbEf3e08E8dF289169EdE581"): "1.1",
+	common.HexToAddress("0x00000000000006c7676171937C444f6BDe3D6282"): "1.2",
+	common.HexToAddress("0x0000000000000aD24e80fd803C6ac37206a45f15"): "1.4",
+	common.HexToAddress("0x00000000000000ADc04C56Bf30aC9d3c0aAF14dC"): "1.5",
+	common.HexToAddress("0x0000000000000068F116a894984e2DB1123eB395"): "1.6",
+}
+
+// seaportZones are the known zones used by opensea.
+var seaportZones = map[common.Address]string{
+	common.HexToAddress("0x004C00500000aD104D7DBd00e3ae0A5C00560C00"): "pausable",
+	common.HexToAddress("0x000000e7Ec00e7B300774b00001314B8610022b8"): "signed",
+	common.HexToAddress("0x000056F7000000EcE9003ca63978907a00FFD100"): "signed v1.1",
+}
+
+// seaportOrderTypes are the names of the seaport order types.
+var seaportOrderTypes = []string{"FULL_OPEN", "PARTIAL_OPEN", "FULL_RESTRICTED", "PARTIAL_RESTRICTED", "CONTRACT"}
+
+// showOrderData returns true if the seaport order parameters of listings & offers should be shown (verbose mode only).
+func showOrderData() bool {
+	return viper.GetBool("log.verbose") && viper.GetBool("show.order_data")
+}
+
+// seaportOrderLabel returns the decoded seaport order parameters (protocol version, order type, zone, conduit, counter & salt) of a stream event.
+func seaportOrderLabel(payload models.EventPayload) string {
+	parameters := payload.ProtocolData.Parameters
+
+	if payload.ProtocolAddress == internal.ZeroAddress && parameters.ConduitKey == "" && parameters.Salt == "" {
+		return ""
+	}
+
+	fields := make([]string, 0)
+
+	// protocol & order type
+	protocol := "seaport"
+	if version, ok := seaportVersions[payload.ProtocolAddress]; ok {
+		protocol += " " + version
+	} else if payload.ProtocolAddress != internal.ZeroAddress {
+		protocol += " " + style.ShortenAddress(payload.ProtocolAddress)
+	}
+
+	orderType := fmt.Sprintf("type %d", parameters.OrderType)
+	if parameters.OrderType >= 0 && parameters.OrderType < len(seaportOrderTypes) {
+		orderType = seaportOrderTypes[parameters.OrderType]
+	}
+
+	fields = append(fields, fmt.Sprintf("⚓️ %s %s", protocol, style.BoldAlmostWhite(orderType)))
+
+	// zone
+	switch name, ok := seaportZones[parameters.Zone]; {
+	case parameters.Zone == internal.ZeroAddress:
+		fields = append(fields, "no zone")
+	case ok:
+		fields = append(fields, "zone "+name)
+	default:
+		fields = append(fields, "zone "+style.ShortenAddress(parameters.Zone))
+	}
+
+	// conduit
+	switch conduitKey := strings.ToLower(parameters.ConduitKey); {
+	case conduitKey == "" || strings.Trim(strings.TrimPrefix(conduitKey, "0x"), "0") == "":
+		fields = append(fields, "no conduit")
+	case conduitKey == openSeaConduitKey:
+		fields = append(fields, "conduit opensea")
+	default:
+		fields = append(fields, "conduit "+shortenHex(conduitKey))
+	}
+
+	// counter (number of times the offerer cancelled all orders)
+	if parameters.Counter != nil {
+		fields = append(fields, fmt.Sprintf("counter %v", parameters.Counter))
+	}
+
+	// salt
+	if salt := saltHex(parameters.Salt); salt != "" {
+		origin := ""
+		if strings.HasPrefix(salt, openSeaSaltPrefix) {
+			origin = " (opensea)"
+		}
+
+		fields = append(fields, "salt "+shortenHex("0x"+salt)+origin)
+	}
+
+	return strings.Join(fields, " · ")
+}
+
+// saltHex returns the salt (hex or decimal string) as zero-padded 32 byte hex string without prefix.
+func saltHex(salt string) string {
+	if salt == "" {
+		return ""
+	}
+
+	base := 10
+	if strings.HasPrefix(salt, "0x") {
+		salt, base = salt[2:], 16
+	}
+
+	saltInt, ok := new(big.Int).SetString(salt, base)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%064x", saltInt)
+}
+
+// shortenHex returns the first & last 4 characters of a (long) hex string.
+func shortenHex(hexString string) string {
+	hexString = strings.TrimPrefix(hexString, "0x")
+
+	if len(hexString) <= 12 {
+		return "0x" + hexString
+	}
+
+	return "0x" + hexString[:4] + "…" + hexString[len(hexString)-4:]
+}
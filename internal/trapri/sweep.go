@@ -0,0 +1,59 @@
+package trapri
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/spf13/viper"
+)
+
+// isSweep returns true for purchases of at least sweeps.min_items items via a marketplace aggregator.
+func isSweep(ttx *totra.TokenTransaction) bool {
+	if !viper.GetBool("sweeps.enabled") || ttx.Aggregator() == "" || !degendb.SaleTypes.Contains(ttx.Action) {
+		return false
+	}
+
+	return numPurchasedItems(ttx) >= viper.GetInt64("sweeps.min_items")
+}
+
+// sweepSummary collapses the lines of the swept collections into a single line with the first
+// collection, the number of further collections & the total/per-item price.
+func sweepSummary(ttx *totra.TokenTransaction, fmtCollections []string) string {
+	numItems := numPurchasedItems(ttx)
+
+	fmtPrice := ""
+	if ttx.AmountPaid != nil && ttx.AmountPaid.Sign() > 0 {
+		perItem := price.NewPrice(new(big.Int).Div(ttx.AmountPaid, big.NewInt(numItems)))
+		fmtPrice = fmt.Sprintf(" · %sΞ total · ø%sΞ", style.BoldAlmostWhite(fmt.Sprintf("%.3f", price.NewPrice(ttx.AmountPaid).Ether())), fmt.Sprintf("%.3f", perItem.Ether()))
+	}
+
+	return fmt.Sprintf("🧹 %s %s via %s · %s +%d collections%s",
+		style.BoldAlmostWhite("sweep"),
+		style.BoldAlmostWhite(fmt.Sprintf("%dx", numItems)),
+		ttx.Aggregator(),
+		fmtCollections[0],
+		len(fmtCollections)-1,
+		fmtPrice,
+	)
+}
+
+// numPurchasedItems counts the nfts moved in the tx, mints excluded.
+func numPurchasedItems(ttx *totra.TokenTransaction) int64 {
+	numItems := int64(0)
+
+	for _, transfers := range ttx.GetNonZeroNFTSenders() {
+		for _, nftTransfer := range transfers {
+			if nftTransfer.AmountTokens != nil {
+				numItems += max(1, nftTransfer.AmountTokens.Int64())
+			} else {
+				numItems++
+			}
+		}
+	}
+
+	return numItems
+}
@@ -10,24 +10,43 @@ import (
 	"time"
 
 	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/anomaly"
+	"github.com/benleb/gloomberg/internal/bookmarks"
+	"github.com/benleb/gloomberg/internal/chains"
 	"github.com/benleb/gloomberg/internal/collections"
+	"github.com/benleb/gloomberg/internal/crossdedupe"
 	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/discarded"
+	"github.com/benleb/gloomberg/internal/editions"
 	"github.com/benleb/gloomberg/internal/external"
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/grails"
 	"github.com/benleb/gloomberg/internal/jobs"
+	"github.com/benleb/gloomberg/internal/links"
+	"github.com/benleb/gloomberg/internal/mev"
+	"github.com/benleb/gloomberg/internal/mintsigs"
+	"github.com/benleb/gloomberg/internal/nemo/currency"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/marketplace"
 	"github.com/benleb/gloomberg/internal/nemo/price"
 	"github.com/benleb/gloomberg/internal/nemo/standard"
 	"github.com/benleb/gloomberg/internal/nemo/tokencollections"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/notes"
 	"github.com/benleb/gloomberg/internal/notify"
-	"github.com/benleb/gloomberg/internal/opensea"
+	"github.com/benleb/gloomberg/internal/plugins"
+	"github.com/benleb/gloomberg/internal/profittaking"
+	"github.com/benleb/gloomberg/internal/ripoff"
+	"github.com/benleb/gloomberg/internal/royalties"
 	seawatcher "github.com/benleb/gloomberg/internal/seawa"
+	"github.com/benleb/gloomberg/internal/sentiment"
+	"github.com/benleb/gloomberg/internal/slugs"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/benleb/gloomberg/internal/ticker"
 	"github.com/benleb/gloomberg/internal/utils"
 	"github.com/benleb/gloomberg/internal/utils/wwatcher"
+	"github.com/benleb/gloomberg/internal/walletage"
+	"github.com/benleb/gloomberg/internal/washtrade"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	mapset "github.com/deckarep/golang-set/v2"
@@ -91,16 +110,158 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 	isOwnWallet := gb.OwnWallets.ContainsAddressFromSlice(nftTransactors.ToSlice()) != internal.ZeroAddress
 	isWatchUsersWallet := gb.Watcher.ContainsAddressFromSlice(nftTransactors.ToSlice()) != internal.ZeroAddress
 
+	// routed via the priority queue, e.g. a watched wallet paying with weth
+	if ttx.Watched && !isOwnWallet {
+		isWatchUsersWallet = true
+	}
+
+	// a watched grail (grails.tokens) is involved - shown & highlighted regardless of the filters
+	grailLabel := ""
+	if grails.Enabled() {
+		grailLabel = grails.Label(ttx)
+	}
+
+	isGrail := grailLabel != ""
+
 	// is this an intentional purchase or a dump into bids?
 	// isBidDump := false
 
-	// telegram notification
-	if viper.GetBool("notifications.telegram.enabled") && (isOwnWallet || isWatchUsersWallet) { //  && ttx.Action != degendb.Transfer {
-		gbl.Log.Infof("🧱 sending telegram notification | isOwnWallet: %+v | isWatchUsersWallet: %+v", isOwnWallet, isWatchUsersWallet)
+	// suppress zero-value transfers & address poisoning attempts targeting own wallets
+	if isOwnWallet && viper.GetBool("scamfilter.enabled") {
+		if scammer, reason := detectScam(gb, ttx); scammer != internal.ZeroAddress {
+			warnScam(ttx, scammer, reason)
+			discarded.Add(gb, ttx, discarded.ReasonScam, reason)
+
+			return
+		}
+	}
+
+	// let user plugins annotate or veto the event
+	if plugins.Enabled() {
+		result := plugins.Run(gb, ttx, isOwnWallet, isWatchUsersWallet)
+		if result.Veto {
+			discarded.Add(gb, ttx, discarded.ReasonPluginVeto, "")
+
+			return
+		}
+
+		ttx.Annotations = append(ttx.Annotations, result.Annotations...)
+	}
+
+	if isGrail {
+		ttx.Highlight = true
+		ttx.Annotations = append(ttx.Annotations, grailLabel)
+
+		if viper.GetBool("grails.notify") {
+			go notify.SendGrailAlert(ttx, grailLabel)
+		}
+	}
+
+	// label known mev bots & same-block flips
+	if mev.Enabled() {
+		if label := mev.Label(ttx); label != "" {
+			ttx.IsMEV = true
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// flag sales between linked wallets, they are dimmed & not counted in the volume stats
+	if washtrade.Enabled() {
+		if label := washtrade.Label(ttx); label != "" {
+			ttx.IsWash = true
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// link sales of the same token within a few blocks at very different prices (decode errors or wash trades)
+	if anomaly.Enabled() {
+		if label := anomaly.Label(ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// merge the same sale received from the opensea stream into this event
+	if crossdedupe.Enabled() {
+		if label := crossdedupe.Label(ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// mark collections whose art is near-identical to established collections
+	if ripoff.Enabled() {
+		if label := ripoff.Label(gb, ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// notes attached to the involved wallets & collections
+	if notes.Enabled() && notes.ShowInTerminal() {
+		if label := notes.Label(ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// collect the mint function signatures & label mints with the used function
+	if mintsigs.Enabled() && ttx.Action == degendb.Mint {
+		if label := mintsigs.Track(gb, ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+
+			if hint := mintsigs.CopyHint(gb, *ttx.Tx.To()); hint != "" {
+				ttx.Annotations = append(ttx.Annotations, hint)
+			}
+		}
+	}
+
+	// mint, burn & redeem counts & time remaining of the manifold/zora editions of own collections
+	if editions.Enabled() {
+		if label := editions.Track(gb, ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// protocol & principal of loans, repayments & liquidations
+	if ttx.LoanEvent != nil {
+		ttx.Annotations = append(ttx.Annotations, loanLabel(ttx.LoanEvent))
+	}
+
+	// erc2981 royalty paid or skipped by sales of watched collections
+	if royalties.Enabled() {
+		if label := royalties.Check(gb, ttx); label != "" {
+			ttx.Annotations = append(ttx.Annotations, label)
+		}
+	}
+
+	// proceeds of the seller, royalties & marketplace fees of sales via seaport
+	parsedEvent.Fees = royalties.Breakdown(ttx)
+
+	// gas used, effective gas price, priority fee & total cost of the tx, shown for sales & mints
+	parsedEvent.Gas = gasCosts(gb, ttx)
+	if parsedEvent.Gas != nil && showGasUsed(ttx) {
+		ttx.Annotations = append(ttx.Annotations, gasUsedLabel(parsedEvent.Gas))
+	}
+
+	// watch sellers for exchange deposits & tag wallets taking profit
+	if profittaking.Enabled() {
+		trackProfitTaking(gb, ttx, nftTransactors.ToSlice())
+	}
+
+	// keep the pnl ledgers of own wallets up to date & add the acquisition context to own sales
+	if isOwnWallet && viper.GetBool("pnl.live.enabled") && viper.GetBool("redis.enabled") {
+		bookOwnTransaction(gb, ttx)
+	}
+
+	// telegram, discord & webhook notifications
+	if (isOwnWallet || isWatchUsersWallet) && notify.Enabled() { //  && ttx.Action != degendb.Transfer {
+		gbl.Log.Infof("🧱 sending notification | isOwnWallet: %+v | isWatchUsersWallet: %+v", isOwnWallet, isWatchUsersWallet)
 
 		go notify.SendNotification(gb, ttx)
 	}
 
+	// latest events of the tape collections in one continuously edited telegram message
+	if notify.TapeEnabled() {
+		notify.AddToTape(gb, ttx)
+	}
+
 	// if it's a single-collection transaction we set the collection as the currentCollection
 	// from here on already, otherwise we set it to nil and fill it later in the loop
 	// over the collections/transfers
@@ -144,11 +305,23 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		parsedEvent.Colors.PriceCurrency = style.DarkGray
 	}
 
-	formattedCurrencySymbol := priceCurrencyStyle.Render("Ξ")
-	formattedFaintCurrencySymbol := priceCurrencyStyle.Copy().Faint(true).Render("Ξ")
+	// events from other chains (l2s) are tagged with the chain & priced in its native currency
+	chain := chains.ByID(ttx.ChainID)
+	if chain == nil {
+		chain = chains.Ethereum
+	}
+
+	parsedEvent.ChainID = chain.ID
+
+	formattedCurrencySymbol := priceCurrencyStyle.Render(chain.Symbol)
+	formattedFaintCurrencySymbol := priceCurrencyStyle.Copy().Faint(true).Render(chain.Symbol)
 
 	out.WriteString(ttx.Marketplace.RenderFaintTag())
 
+	if !chains.IsMainnet(chain.ID) {
+		out.WriteString(chain.Tag() + " ")
+	}
+
 	// timestamp styling
 	// WEN...??
 	now := time.Now()
@@ -160,9 +333,16 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 	// prepare links
 	etherscanURL, openSeaURL, blurURL := utils.GetLinks(txHash, ttx.Transfers[0].Token.Address, ttx.Transfers[0].Token.ID.Int64())
 
+	if !chains.IsMainnet(chain.ID) {
+		etherscanURL = links.ChainTx(chain.ID, txHash)
+		openSeaURL = links.ChainItem(chain.ID, ttx.Transfers[0].Token.Address, ttx.Transfers[0].Token.ID)
+		blurURL = ""
+	}
+
 	parsedEvent.BlurURL = blurURL
 	parsedEvent.EtherscanURL = etherscanURL
 	parsedEvent.OpenSeaURL = openSeaURL
+	parsedEvent.MarketplaceLinks = links.ChainItemLinks(chain.ID, ttx.Transfers[0].Token.Address, ttx.Transfers[0].Token.ID)
 
 	// print collection name and token id
 	fmtTokensTransferred := make([]string, 0)
@@ -285,9 +465,13 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 				}
 			}
 
-			// add number of tokens transferred
-			if transfer.AmountTokens.Cmp(big.NewInt(1)) > 0 {
-				fmtTokenID.WriteString(style.DarkGrayStyle.Render(transfer.AmountTokens.String() + "x"))
+			// add number of tokens transferred & the remaining quantity of partially filled erc1155 listings
+			if ttx.Action == degendb.Sale {
+				fillListedEdition(transfer)
+			}
+
+			if quantity := transfer.FormatQuantity(); quantity != "" {
+				fmtTokenID.WriteString(style.DarkGrayStyle.Render(quantity + "x"))
 
 				transferredToken.Amount = transfer.AmountTokens.Int64()
 			}
@@ -297,7 +481,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 			fmtTokenID.WriteString(formatTokenID(collection, transfer.Token.ID))
 
 			// add a marker for burned tokens
-			if transfer.To == internal.ZeroAddress {
+			if internal.IsBurnAddress(transfer.To) {
 				fmtTokenID.WriteString("🔥")
 
 				if ttx.Action == degendb.BurnRedeem {
@@ -310,8 +494,18 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 				// set custom collection name
 				collection.Name = "ENS"
 
-				// get ens token metadata
+				// get ens token metadata, resolved on-chain if the metadata service is unavailable
 				metadata, err := external.GetENSMetadataForTokenID(transfer.Token.ID)
+				if err != nil || metadata == nil {
+					ensCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+
+					if name, chainErr := gb.ProviderPool.ENSNameByTokenID(ensCtx, transfer.Token.Address, transfer.Token.ID); chainErr == nil {
+						metadata, err = external.NewENSMetadata(name), nil
+					}
+
+					cancel()
+				}
+
 				if err == nil && metadata != nil {
 					ensMetadata = metadata
 
@@ -324,8 +518,8 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 
 			isOwnCollection = collection.Source == degendb.FromWallet || collection.Source == degendb.FromConfiguration
 
-			// link each token id to opensea
-			_, openseaURL, _ := utils.GetLinks(txHash, transfer.Token.Address, transfer.Token.ID.Int64())
+			// link each token id to the preferred marketplace
+			itemURL := links.ChainItem(chain.ID, transfer.Token.Address, transfer.Token.ID)
 
 			if collection == nil && transfer.Standard == standard.ERC1155 {
 				collection = tokencollections.GetCollection(gb, contractAddress, transfer.Token.ID.Int64())
@@ -341,7 +535,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 				}
 			}
 
-			fmtTokenIds[transfer.Token.Address] = append(fmtTokenIds[transfer.Token.Address], style.TerminalLink(openseaURL, fmtTokenID.String())+fmtTotalSupply)
+			fmtTokenIds[transfer.Token.Address] = append(fmtTokenIds[transfer.Token.Address], style.TerminalLink(itemURL, fmtTokenID.String())+fmtTotalSupply)
 			fmtHistoryTokenIds[transfer.Token.Address] = append(fmtHistoryTokenIds[transfer.Token.Address], fmtRank+formatTokenID(collection, transfer.Token.ID)+fmtTotalSupply)
 
 			if isOwnCollection {
@@ -402,6 +596,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 
 		transferredCollection := degendb.TransferredCollection{
 			CollectionName: collection.Name,
+			Note:           notes.Get(contractAddress),
 			From:           ttx.From.Hex(),
 
 			TransferredTokens: transferredTokens,
@@ -418,7 +613,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		// use a variant without a link for the history
 		// needed due to a bug causing unnecessary line breaks
 
-		fmtEvent.WriteString(name)
+		fmtEvent.WriteString(style.TerminalLink(links.ChainCollection(chain.ID, contractAddress), name))
 		if !ttx.IsCollectionOffer() {
 			fmtEvent.WriteString(" " + strings.Join(fmtTokenIds[contractAddress][:idsShown], collection.StyleSecondary().Copy().Faint(true).Render(", ")))
 			fmtHistoryEvent.WriteString(name + " " + strings.Join(fmtHistoryTokenIds[contractAddress][:idsShown], collection.StyleSecondary().Copy().Faint(true).Render(", ")))
@@ -451,12 +646,30 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		if degendb.SaleTypes.Contains(ttx.Action) {
 			ttx.TotalTokens += numCollectionTokens
 
-			collection.AddSales(ttx.AmountPaid, uint64(numCollectionTokens))
+			if !ttx.IsWash {
+				collection.AddSales(ttx.AmountPaidForCollection(contractAddress), uint64(numCollectionTokens))
+			}
+
+			// sales per weekday/hour of watched collections
+			if viper.GetBool("heatmap.enabled") && collection.Source != degendb.FromStream && !ttx.IsWash {
+				go func(contractAddress common.Address, numSales int64) {
+					if err := gb.Rueidi.IncrSalesHeatmap(context.Background(), contractAddress, time.Now(), numSales); err != nil {
+						gbl.Log.Debugf("❗️ heatmap | could not count sales for %s: %s", contractAddress.Hex(), err)
+					}
+				}(contractAddress, numCollectionTokens)
+			}
+
+			// sold tokens are no longer listed
+			for _, transfer := range transfers {
+				collection.RemoveListing(transfer.Token.ID)
+			}
 		}
 
-		// count mints
+		// count mints, the tx value is split across all minted tokens
 		if ttx.Action == degendb.Mint {
-			collection.AddMintVolume(ttx.AmountPaid, uint64(numCollectionTokens))
+			ttx.TotalTokens += numCollectionTokens
+
+			collection.AddMintVolume(ttx.AmountPaidForCollection(contractAddress), uint64(numCollectionTokens))
 		}
 
 		transferredCollections = append(transferredCollections, transferredCollection)
@@ -474,7 +687,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 	parsedEvent.TransferredCollections = transferredCollections
 
 	// total counting
-	if gb.Stats != nil {
+	if gb.Stats != nil && !ttx.IsWash {
 		var eventType degendb.EventType
 		switch ttx.Action {
 		case degendb.Sale, degendb.Purchase:
@@ -485,12 +698,15 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 			eventType = degendb.Listing
 		}
 
-		gb.Stats.AddEvent(eventType, ttx.TotalTokens, ttx.AmountPaid)
+		gb.Stats.AddEvent(eventType, ttx.TotalTokens, ttx.AmountPaid, ttx.IsMEV)
 	}
 
 	parsedEvent.Colors.Time = style.DarkGray
 
 	switch {
+	case ttx.IsWash && !isOwnWallet:
+		timeNow = style.DarkGrayStyle.Copy().Faint(true).Render(currentTime)
+
 	case ttx.IsListing():
 		timeNow = style.Gray7Style.Render(currentTime)
 		parsedEvent.Colors.Time = style.Gray7
@@ -503,6 +719,10 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		timeNow = lipgloss.NewStyle().Foreground(style.Pink).Bold(true).Render(currentTime)
 		parsedEvent.Colors.Time = lipgloss.Color(style.Pink.Dark)
 
+	case isWatchUsersWallet:
+		timeNow = style.PurplePower.Copy().Bold(true).Render(currentTime)
+		parsedEvent.Colors.Time, _ = style.PurplePower.GetForeground().(lipgloss.Color)
+
 	case isOwnCollection:
 		timeNow = currentCollection.Style().Copy().Bold(true).Render(currentTime)
 		parsedEvent.Colors.Time = currentCollection.Colors.Primary
@@ -576,6 +796,8 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 			)
 		}
 
+		discarded.Add(gb, ttx, discarded.ReasonNoTokens, "")
+
 		return
 	}
 
@@ -596,13 +818,16 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		ttx.DoNotPrint = true
 	}
 
+	// why the tx is not printed (if so)
+	doNotPrintReason, doNotPrintDetail := discarded.ReasonIgnored, "ignore_printing"
+
 	// average price (makes no sense for multi-collections tx)
 	averagePrice := ttx.GetPrice()
 	if ttx.TotalTokens > 1 {
 		if ttx.IsCollectionOffer() {
 			averagePrice = price.NewPrice(big.NewInt(0).Mul(ttx.AmountPaid, big.NewInt(ttx.TotalTokens)))
 		} else {
-			averagePrice = price.NewPrice(big.NewInt(0).Div(ttx.AmountPaid, big.NewInt(ttx.TotalTokens)))
+			averagePrice = ttx.PricePerItem()
 		}
 	}
 
@@ -630,6 +855,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 				gbl.Log.Debugf("price is below min_value, not showing")
 
 				ttx.DoNotPrint = true
+				doNotPrintReason, doNotPrintDetail = discarded.ReasonMinValue, fmt.Sprintf("avg %.3fΞ < %.3fΞ", averagePrice.Ether(), minValue)
 			}
 		}
 	}
@@ -688,11 +914,18 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		)}
 	}
 
+	// aggregator sweeps across collections in a single summary line
+	if len(fmtTokensTransferred) > 1 && isSweep(ttx) {
+		fmtTokensTransferred = []string{sweepSummary(ttx, fmtTokensTransferred)}
+	}
+
 	if len(fmtTokensTransferred) == 0 {
 		gloomberg.PrWarn(fmt.Sprintf("no tokens transferred in tx %s", style.TerminalLink(etherscanURL)))
 
 		gbl.Log.Warnf("no tokens transferred: %+v", fmt.Sprintf("%+v", pretty.Formatter(ttx)))
 
+		discarded.Add(gb, ttx, discarded.ReasonNoTokens, "")
+
 		return
 	}
 
@@ -706,10 +939,15 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 	// and further collections/tokens on the next lines
 	out.WriteString("  " + fmtTokensTransferred[0] + " ")
 
-	// links blur
+	// link to the next configured marketplace, the token ids already link to the preferred one
 	if ttx.TotalTokens == 1 {
-		if ttx.Transfers[0].Standard == standard.ERC721 {
-			out.WriteString(" | " + style.GrayBoldStyle.Copy().Foreground(style.BlurOrange).Faint(true).Render(style.TerminalLink(blurURL, "BL")))
+		if marketplaceLinks := parsedEvent.MarketplaceLinks; ttx.Transfers[0].Standard == standard.ERC721 && len(marketplaceLinks) > 1 {
+			linkStyle := style.GrayBoldStyle.Copy().Faint(true)
+			if marketplaceLinks[1].Marketplace == links.Blur {
+				linkStyle = linkStyle.Foreground(style.BlurOrange)
+			}
+
+			out.WriteString(" | " + linkStyle.Render(style.TerminalLink(marketplaceLinks[1].URL, marketplaceLinks[1].Short)))
 		}
 	}
 
@@ -718,6 +956,20 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		out.WriteString(" | " + style.GrayStyle.Render(style.TerminalLink(etherscanURL, "ES")))
 	}
 
+	// payments in other currencies in their original denomination
+	if viper.GetBool("currencies.show_original") {
+		for currencyAddress, amount := range ttx.PaymentsERC20 {
+			paymentCurrency := currency.Get(currencyAddress)
+
+			fmtPayment := paymentCurrency.Format(amount)
+			if amountWei, err := paymentCurrency.ToWei(ctx, gb.ProviderPool, amount); err == nil {
+				fmtPayment += style.DarkGrayStyle.Render(" ≈ ") + fmt.Sprintf("%.2f", price.NewPrice(amountWei).Ether()) + formattedFaintCurrencySymbol
+			}
+
+			out.WriteString(" | " + priceStyle.Render(fmtPayment))
+		}
+	}
+
 	// // for burns the line ends after the etherscan link, and we do not need a trailing pipe
 	// if !ttx.IsBurn() {
 	// 	out.WriteString(" | ")
@@ -753,15 +1005,28 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 			parsedEvent.From = gb.DegenDB.NewDegen(fromENS, []common.Address{transferFrom}, "", "", 0, []degendb.Tag{})
 
 			parsedEvent.FromAddress = transferFrom
+			parsedEvent.FromNote = notes.Get(transferFrom)
 		} else {
 			gbl.Log.Debugf("🤷‍♀️ from address %s has NO ENS", transferFrom.Hex())
 			fmtFrom = style.ShortenAddressStyled(&transferFrom, fromStyle)
 			// shortName := style.ShortenAddress(&transferFrom)
 			parsedEvent.From = gb.DegenDB.NewDegen(fromENS, []common.Address{transferFrom}, "", "", 0, []degendb.Tag{})
 			parsedEvent.FromAddress = transferFrom
+			parsedEvent.FromNote = notes.Get(transferFrom)
+		}
+
+		// attribute token bound account activity to the parent nft
+		if fmtTBA, ok := gb.FormatTokenboundAccount(transferFrom); ok {
+			fmtFrom = fmtTBA
+		} else {
+			fmtFrom = style.TerminalLink(links.Profile(transferFrom), fmtFrom)
 		}
 
 		out.WriteString(fmtFrom)
+
+		if fmtAge := walletage.Render(gb, transferFrom); fmtAge != "" {
+			out.WriteString(" " + fmtAge)
+		}
 	}
 
 	// buyer
@@ -795,6 +1060,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 
 		parsedEvent.To = gb.DegenDB.NewDegen(buyerENS, []common.Address{buyer}, "", "", 0, []degendb.Tag{})
 		parsedEvent.ToAddress = buyer
+		parsedEvent.ToNote = notes.Get(buyer)
 	} else {
 		gbl.Log.Debugf("❌ failed to resolve ENS name for %s: %s", buyer.Hex(), err)
 
@@ -802,6 +1068,14 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 
 		parsedEvent.To = gb.DegenDB.NewDegen(buyerENS, []common.Address{buyer}, "", "", 0, []degendb.Tag{})
 		parsedEvent.ToAddress = buyer
+		parsedEvent.ToNote = notes.Get(buyer)
+	}
+
+	// attribute token bound account activity to the parent nft
+	if fmtTBA, ok := gb.FormatTokenboundAccount(buyer); ok {
+		fmtBuyer = fmtTBA
+	} else {
+		fmtBuyer = style.TerminalLink(links.Profile(buyer), fmtBuyer)
 	}
 
 	arrow := style.DividerArrowRight
@@ -811,6 +1085,11 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 
 	out.WriteString(arrow.String() + fmtBuyer)
 
+	// fresh wallets minting/buying are a common bot/insider signal
+	if fmtAge := walletage.Render(gb, buyer); fmtAge != "" {
+		out.WriteString(" " + fmtAge)
+	}
+
 	// 'maybe important wallet' indicator
 	if wwatcher.MIWC.MIWs.Contains(buyer) {
 		level := strings.Repeat(" 👀", int(math.Min(3.0, float64(wwatcher.MIWC.WeightedMIWs[buyer]))))
@@ -819,23 +1098,38 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		// out.WriteString("   " + style.PinkBoldStyle.Render(level))
 	}
 
-	// don't apply excludes to "own" events
-	if !(isOwnWallet || isWatchUsersWallet) {
+	// acquisition context of tokens sold by own wallets
+	for _, transfer := range ttx.Transfers {
+		if transfer.AcquisitionContext != "" {
+			out.WriteString(style.DarkGrayStyle.Render(" | ") + style.GrayStyle.Render(transfer.AcquisitionContext))
+
+			break
+		}
+	}
+
+	// don't apply excludes to "own" events & grails
+	if !(isOwnWallet || isWatchUsersWallet || isGrail) {
 		// DoNotPrint can be set by the "pipeline" the tx is going through (e.g. when a collection has the IgnorePrinting flag set)
 		if ttx.DoNotPrint {
 			log.Debugf("skipping tx %s | doNotPrint flaf: %v | %+v", style.Bold(txHash.String()), ttx.DoNotPrint, ttx)
 
+			discarded.Add(gb, ttx, doNotPrintReason, doNotPrintDetail)
+
 			return
 		}
 
 		if !isOwnCollection && !currentCollection.Show.Mints && (ttx.Action == degendb.Mint || ttx.Action == degendb.Airdrop) && !viper.GetBool("show.mints") {
 			log.Debugf("skipping mint %s | viper.GetBool(show.mints): %v | %+v", style.Bold(txHash.String()), viper.GetBool("show.mints"), ttx)
 
+			discarded.Add(gb, ttx, discarded.ReasonHiddenMint, "show.mints")
+
 			return
 		}
 
-		if (ttx.Action == degendb.Burn) && !viper.GetBool("show.burns") {
-			log.Debugf("skipping burn/airdrop %s | viper.GetBool(show.burns): %v | %+v", style.Bold(txHash.String()), viper.GetBool("show.burns"), ttx)
+		if !isOwnCollection && !currentCollection.Show.Burns && (ttx.Action == degendb.Burn) && !viper.GetBool("show.burns") {
+			log.Debugf("skipping burn %s | viper.GetBool(show.burns): %v | %+v", style.Bold(txHash.String()), viper.GetBool("show.burns"), ttx)
+
+			discarded.Add(gb, ttx, discarded.ReasonHiddenBurn, "show.burns")
 
 			return
 		}
@@ -843,18 +1137,24 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		if (ttx.Action == degendb.BurnRedeem) && !viper.GetBool("show.reburns") {
 			log.Debugf("skipping re-burn %s | viper.GetBool(show.burns): %v | %+v", style.Bold(txHash.String()), viper.GetBool("show.reburns"), ttx)
 
+			discarded.Add(gb, ttx, discarded.ReasonHiddenReburn, "show.reburns")
+
 			return
 		}
 
 		if (ttx.Action == degendb.Transfer) && !viper.GetBool("show.transfers") {
 			log.Debugf("skipping transfer %s | viper.GetBool(show.transfers): %v | %+v", style.Bold(txHash.String()), viper.GetBool("show.transfers"), ttx)
 
+			discarded.Add(gb, ttx, discarded.ReasonHiddenTransfer, "show.transfers")
+
 			return
 		}
 
 		if (ttx.Action == degendb.Unknown) && !viper.GetBool("show.unknown") {
 			log.Debugf("skipping unknown %s | viper.GetBool(show.unknown): %v | %+v", style.TerminalLink(txHash.String(), style.ShortenHashStyled(txHash)), viper.GetBool("show.unknown"), ttx)
 
+			discarded.Add(gb, ttx, discarded.ReasonHiddenUnknown, "show.unknown")
+
 			return
 		}
 	}
@@ -878,7 +1178,7 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 			// auto-subscribe to opensea events after X sales (to calculate the salira of the collection)
 			if autoSubscribeAfterSales := viper.GetUint64("seawatcher.auto_subscribe_after_sales"); uint64(numLastSales) >= autoSubscribeAfterSales {
 				if currentCollection.OpenseaSlug == "" {
-					currentCollection.OpenseaSlug = opensea.GetCollectionSlug(currentCollection.ContractAddress)
+					currentCollection.OpenseaSlug, _ = slugs.Slug(context.Background(), currentCollection.ContractAddress)
 				}
 
 				// if !alreadySubscribed.Contains(currentCollection.OpenseaSlug) && !seawa.IsSubscribedToAllEvents(currentCollection.OpenseaSlug) {
@@ -913,6 +1213,16 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 		}
 	}
 
+	// social mentions & sentiment
+	if mentions := sentiment.Get(currentCollection.ContractAddress); mentions != nil {
+		out.WriteString(style.DarkGrayStyle.Render(" | ") + mentions.Format())
+	}
+
+	// annotations from user plugins
+	if len(ttx.Annotations) > 0 {
+		out.WriteString(style.DarkGrayStyle.Render(" | ") + style.GrayStyle.Render(strings.Join(ttx.Annotations, " · ")))
+	}
+
 	// multi-line output for multi-collection events
 	if len(fmtTokensTransferred) > 1 {
 		for _, fmtTokenCollection := range fmtTokensTransferred[1:] {
@@ -996,23 +1306,47 @@ func formatTokenTransaction(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatche
 	// 	}
 	// }
 
+	// own & watched events restore the full cadence of the tickers, also in headless mode
+	if isOwnWallet || isOwnCollection || isWatchUsersWallet || isGrail || ttx.Highlight {
+		gloomberg.MarkActivity()
+	}
+
 	//
 	// 🌈 finally print the sale/listing/whatever 🌈
 	if !viper.GetBool("ui.headless") {
-		if ttx.IsListing() && !isOwnCollection {
+		if ttx.IsListing() && !isOwnCollection && !isGrail {
 			return
 		}
 
-		// highlight special events with newlines above and below
 		printLine := out.String()
+
+		// fee breakdown as extra line (verbose mode only)
+		if parsedEvent.Fees != nil && viper.GetBool("log.verbose") && viper.GetBool("show.fee_breakdown") {
+			printLine += "\n" + royalties.BreakdownLine(parsedEvent.Fees)
+		}
+
+		// highlight special events with newlines above and below
 		if ttx.Highlight {
 			printLine = "\n" + printLine + "\n"
 		}
 
-		// print to terminal
-		gloomberg.TerminalPrinterQueue <- printLine
+		// print to terminal, own events first & the general tape is thinned out under heavy volume
+		switch {
+		case isOwnWallet:
+			gloomberg.PrintWithPriority(printLine, gloomberg.PriorityOwn)
+		case isOwnCollection || isWatchUsersWallet || isGrail || ttx.Highlight:
+			gloomberg.PrintWithPriority(printLine, gloomberg.PriorityAlert)
+		default:
+			gloomberg.PrintWithPriority(printLine, gloomberg.PriorityTape)
+		}
 
 		gb.In.ParsedEvents <- &parsedEvent
+
+		// keep the printed event to bookmark it via keybinding or web ui
+		bookmarks.Remember(&parsedEvent)
+
+		// decode calls to watched/unknown contracts via their verified abi
+		go printDecodedCall(gb, ttx)
 	}
 
 	// add to history
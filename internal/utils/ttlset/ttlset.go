@@ -0,0 +1,83 @@
+package ttlset
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Set is a bounded set remembering keys for a ttl since they were last seen. if the set is full, the least
+// recently seen key is evicted.
+type Set[K comparable] struct {
+	entries map[K]*list.Element
+	// most recently seen keys first
+	order *list.List
+
+	size int
+	ttl  time.Duration
+
+	// current time, replaced in tests
+	now func() time.Time
+
+	mu sync.Mutex
+}
+
+type entry[K comparable] struct {
+	key    K
+	seenAt time.Time
+}
+
+// New returns a set holding at most size keys, each for ttl after it was last seen (0 = until evicted).
+func New[K comparable](size int, ttl time.Duration) *Set[K] {
+	return &Set[K]{
+		entries: make(map[K]*list.Element, size),
+		order:   list.New(),
+		size:    max(size, 1),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// Add adds the key & returns true if it was not in the set (or expired).
+func (s *Set[K]) Add(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		if e := element.Value.(*entry[K]); !s.isExpired(e) {
+			// keeps the keys ordered by their last sighting, so the expired ones are always at the back
+			e.seenAt = s.now()
+			s.order.MoveToFront(element)
+
+			return false
+		}
+
+		s.remove(element)
+	}
+
+	// evict expired & least recently seen keys
+	for oldest := s.order.Back(); oldest != nil && (s.order.Len() >= s.size || s.isExpired(oldest.Value.(*entry[K]))); oldest = s.order.Back() {
+		s.remove(oldest)
+	}
+
+	s.entries[key] = s.order.PushFront(&entry[K]{key: key, seenAt: s.now()})
+
+	return true
+}
+
+// Len returns the number of keys in the set, including expired ones not evicted yet.
+func (s *Set[K]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *Set[K]) isExpired(e *entry[K]) bool {
+	return s.ttl > 0 && s.now().Sub(e.seenAt) > s.ttl
+}
+
+func (s *Set[K]) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*entry[K]).key)
+}
@@ -0,0 +1,68 @@
+package ttlset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSet_Add(t *testing.T) {
+	s := New[string](3, 0)
+
+	if !s.Add("a") || !s.Add("b") || !s.Add("c") {
+		t.Fatal("Add() of new keys = false, want true")
+	}
+
+	if s.Add("a") {
+		t.Error("Add() of a known key = true, want false")
+	}
+
+	// "b" is the least recently seen key now
+	if !s.Add("d") {
+		t.Error("Add() of a new key = false, want true")
+	}
+
+	if s.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", s.Len())
+	}
+
+	if !s.Add("b") {
+		t.Error("Add() of the evicted key = false, want true")
+	}
+
+	if s.Add("a") {
+		t.Error("Add() of a recently seen key = true, want false")
+	}
+}
+
+func TestSet_AddTTL(t *testing.T) {
+	ttl := time.Minute
+
+	now := time.Now()
+
+	s := New[string](10, ttl)
+	s.now = func() time.Time { return now }
+
+	s.Add("seen")
+	s.Add("once")
+
+	// seeing a key refreshes its ttl
+	now = now.Add(ttl * 6 / 10)
+	s.Add("seen")
+	now = now.Add(ttl * 6 / 10)
+
+	if s.Add("seen") {
+		t.Error("Add() of a key seen within the ttl = true, want false")
+	}
+
+	if !s.Add("once") {
+		t.Error("Add() of an expired key = false, want true")
+	}
+
+	// all expired keys are evicted with the next add
+	now = now.Add(ttl * 12 / 10)
+	s.Add("new")
+
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", s.Len())
+	}
+}
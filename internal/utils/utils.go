@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/benleb/gloomberg/internal/links"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/spf13/viper"
@@ -39,12 +40,12 @@ func GetEtherscanTokenURLForAddress(address common.Address) string {
 
 // blur.io.
 func getBlurLink(contractAddress string, tokenID int64) string {
-	return fmt.Sprintf("https://blur.io/asset/%s/%d", strings.ToLower(contractAddress), tokenID)
+	return links.ItemOn(links.Blur, common.HexToAddress(contractAddress), big.NewInt(tokenID))
 }
 
 // opensea.io.
 func GetOpenseaItemLink(contractAddress string, tokenID int64) string {
-	return fmt.Sprintf("https://opensea.io/assets/ethereum/%s/%d", contractAddress, tokenID)
+	return links.ItemOn(links.OpenSea, common.HexToAddress(contractAddress), big.NewInt(tokenID))
 }
 
 func GetOpenseaCollectionLink(slug string) string {
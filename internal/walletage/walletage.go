@@ -0,0 +1,188 @@
+package walletage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/external"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// Info is the age & activity of a wallet.
+type Info struct {
+	// zero if unknown (no etherscan api key or no tx at all)
+	FirstTx time.Time
+	TxCount uint64
+
+	fetchedAt time.Time
+}
+
+var (
+	infos   = make(map[common.Address]*Info)
+	infosMu sync.RWMutex
+)
+
+// Age returns the time since the first tx of the wallet.
+func (i *Info) Age() time.Duration {
+	if i.FirstTx.IsZero() {
+		return 0
+	}
+
+	return time.Since(i.FirstTx)
+}
+
+// IsFresh returns true if the wallet is younger than walletage.fresh.
+func (i *Info) IsFresh() bool {
+	return !i.FirstTx.IsZero() && i.Age() < viper.GetDuration("walletage.fresh")
+}
+
+// Get returns the age & tx count of the wallet. results are cached in memory & redis for cache.wallet_age_ttl.
+func Get(gb *gloomberg.Gloomberg, address common.Address) (*Info, error) {
+	if address == internal.ZeroAddress {
+		return nil, errors.New("zero address")
+	}
+
+	ttl := viper.GetDuration("cache.wallet_age_ttl")
+
+	infosMu.RLock()
+	info, ok := infos[address]
+	infosMu.RUnlock()
+
+	if ok && time.Since(info.fetchedAt) < ttl {
+		return info, nil
+	}
+
+	useRedis := viper.GetBool("redis.enabled") && gb.Rueidi != nil
+
+	if useRedis {
+		if cached, err := gb.Rueidi.GetCachedWalletAge(context.Background(), address); err == nil {
+			if info, err := parse(cached); err == nil {
+				return remember(address, info), nil
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("walletage.timeout"))
+	defer cancel()
+
+	// the nonce is the number of txs sent by the wallet
+	txCount, err := gb.ProviderPool.GetNonceAt(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	info = &Info{TxCount: txCount}
+
+	if firstTx, err := external.GetFirstTransaction(address); err == nil {
+		if timestamp, err := strconv.ParseInt(firstTx.TimeStamp, 10, 64); err == nil {
+			info.FirstTx = time.Unix(timestamp, 0)
+		}
+	} else {
+		gbl.Log.Debugf("👶 no first tx for %s: %s", address.Hex(), err)
+	}
+
+	if useRedis {
+		if err := gb.Rueidi.StoreWalletAge(context.Background(), address, format(info)); err != nil {
+			gbl.Log.Debugf("❌ caching wallet age of %s failed: %s", address.Hex(), err)
+		}
+	}
+
+	return remember(address, info), nil
+}
+
+// Render returns a short age & tx count label like "🐣 2d · 3tx" for fresh wallets or "3y · 1204tx".
+func Render(gb *gloomberg.Gloomberg, address common.Address) string {
+	if !viper.GetBool("walletage.enabled") {
+		return ""
+	}
+
+	info, err := Get(gb, address)
+	if err != nil {
+		gbl.Log.Debugf("👶 wallet age of %s unknown: %s", address.Hex(), err)
+
+		return ""
+	}
+
+	fmtAge := "?"
+	if !info.FirstTx.IsZero() {
+		fmtAge = formatAge(info.Age())
+	}
+
+	label := fmt.Sprintf("%s · %dtx", fmtAge, info.TxCount)
+
+	if info.IsFresh() {
+		return "🐣 " + style.PinkBoldStyle.Render(label)
+	}
+
+	return style.DarkGrayStyle.Render(label)
+}
+
+// formatAge returns the age in the largest fitting unit, e.g. 5h, 3d, 7m (months) or 2y.
+func formatAge(age time.Duration) string {
+	day := 24 * time.Hour
+
+	switch {
+	case age < day:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	case age < 30*day:
+		return fmt.Sprintf("%dd", int(age/day))
+	case age < 365*day:
+		return fmt.Sprintf("%dmo", int(age/(30*day)))
+	default:
+		return fmt.Sprintf("%.1fy", age.Hours()/24/365)
+	}
+}
+
+func remember(address common.Address, info *Info) *Info {
+	info.fetchedAt = time.Now()
+
+	infosMu.Lock()
+	infos[address] = info
+	infosMu.Unlock()
+
+	return info
+}
+
+// format & parse convert the info to/from the cached "<first tx unix>:<tx count>" representation.
+func format(info *Info) string {
+	var firstTx int64
+	if !info.FirstTx.IsZero() {
+		firstTx = info.FirstTx.Unix()
+	}
+
+	return fmt.Sprintf("%d:%d", firstTx, info.TxCount)
+}
+
+func parse(cached string) (*Info, error) {
+	firstTxString, txCountString, ok := strings.Cut(cached, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid wallet age: %s", cached)
+	}
+
+	firstTx, err := strconv.ParseInt(firstTxString, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	txCount, err := strconv.ParseUint(txCountString, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{TxCount: txCount}
+	if firstTx > 0 {
+		info.FirstTx = time.Unix(firstTx, 0)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,153 @@
+package washtrade
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal"
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// tokenSale is a sale of a token seen in the stream.
+type tokenSale struct {
+	seller common.Address
+	buyer  common.Address
+	soldAt time.Time
+}
+
+var (
+	// funding source by wallet from the degendb address labels
+	fundedBy   = make(map[common.Address]common.Address)
+	exchanges  = make(map[common.Address]bool)
+	fundedByMu sync.RWMutex
+
+	// recent sales per nft id to detect tokens going back & forth
+	recentSales   = make(map[string][]tokenSale)
+	recentSalesMu sync.Mutex
+)
+
+// Enabled returns true if sales between linked wallets should be flagged.
+func Enabled() bool {
+	return viper.GetBool("washtrading.enabled")
+}
+
+// LoadFundings remembers the funding sources (funded_by) of the labeled addresses.
+// exchanges fund everyone, so a shared exchange funding source does not link wallets.
+func LoadFundings(labels map[common.Address]*degendb.Address) {
+	fundedByMu.Lock()
+	defer fundedByMu.Unlock()
+
+	for address, label := range labels {
+		if label.HasTag(degendb.TagExchange, degendb.TagExchangeDeposit) {
+			exchanges[address] = true
+		}
+
+		if label.FundedBy != nil && *label.FundedBy != internal.ZeroAddress {
+			fundedBy[address] = *label.FundedBy
+		}
+	}
+
+	gbl.Log.Infof("🧼 %d wallet funding sources loaded", len(fundedBy))
+}
+
+// Label returns a label with the reason if buyer & seller of a sale are linked: the same address, one funded
+// the other or both were funded by the same (non-exchange) wallet, or the token was sold back to its previous seller.
+func Label(ttx *totra.TokenTransaction) string {
+	if !degendb.SaleTypes.Contains(ttx.Action) {
+		return ""
+	}
+
+	var reason string
+
+	for _, transfer := range ttx.Transfers {
+		if !transfer.Standard.IsERC721orERC1155() || transfer.Token == nil || transfer.From == internal.ZeroAddress {
+			continue
+		}
+
+		seller, buyer := transfer.From, transfer.To
+
+		// the sale is remembered even if already flagged to catch the way back
+		backAndForth := isBackAndForth(transfer.Token.NftID(), seller, buyer)
+
+		if reason != "" {
+			continue
+		}
+
+		switch {
+		case seller == buyer:
+			reason = "self-trade " + style.ShortenAdressPTR(&seller)
+		case linkedByFunding(seller, buyer) != "":
+			reason = linkedByFunding(seller, buyer)
+		case backAndForth:
+			reason = "sold back to " + style.ShortenAdressPTR(&buyer)
+		}
+	}
+
+	if reason == "" {
+		return ""
+	}
+
+	return "🧼 " + style.BoldAlmostWhite("wash trade?") + " " + reason
+}
+
+// linkedByFunding returns the funding relationship between both wallets or an empty string.
+func linkedByFunding(seller common.Address, buyer common.Address) string {
+	fundedByMu.RLock()
+	defer fundedByMu.RUnlock()
+
+	sellerFunder, sellerFunded := fundedBy[seller]
+	buyerFunder, buyerFunded := fundedBy[buyer]
+
+	switch {
+	case buyerFunded && buyerFunder == seller:
+		return "buyer funded by seller"
+	case sellerFunded && sellerFunder == buyer:
+		return "seller funded by buyer"
+	case sellerFunded && buyerFunded && sellerFunder == buyerFunder && !exchanges[sellerFunder]:
+		return "both funded by " + style.ShortenAdressPTR(&sellerFunder)
+	}
+
+	return ""
+}
+
+// isBackAndForth remembers the sale & returns true if the buyer sold the token to the seller within the window.
+func isBackAndForth(nftID string, seller common.Address, buyer common.Address) bool {
+	window := viper.GetDuration("washtrading.window")
+	now := time.Now()
+
+	recentSalesMu.Lock()
+	defer recentSalesMu.Unlock()
+
+	sales := make([]tokenSale, 0, len(recentSales[nftID])+1)
+	backAndForth := false
+
+	for _, sale := range recentSales[nftID] {
+		if now.Sub(sale.soldAt) > window {
+			continue
+		}
+
+		if sale.seller == buyer && sale.buyer == seller {
+			backAndForth = true
+		}
+
+		sales = append(sales, sale)
+	}
+
+	recentSales[nftID] = append(sales, tokenSale{seller: seller, buyer: buyer, soldAt: now})
+
+	// forget old sales from time to time
+	if len(recentSales)%1024 == 0 {
+		for id, tokenSales := range recentSales {
+			if now.Sub(tokenSales[len(tokenSales)-1].soldAt) > window {
+				delete(recentSales, id)
+			}
+		}
+	}
+
+	return backAndForth
+}
@@ -0,0 +1,84 @@
+package watchdog
+
+import (
+	"context"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/health"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	seawatcher "github.com/benleb/gloomberg/internal/seawa"
+	"github.com/spf13/viper"
+)
+
+// Start watches the chain subscription, the OpenSea stream & the stats ticker and restarts them if they are stalled.
+func Start(gb *gloomberg.Gloomberg, seawa *seawatcher.SeaWatcher, queueOutput chan string) {
+	wd := New()
+
+	// no logs from the chain despite healthy nodes
+	if gb.ProviderPool != nil {
+		wd.Watch(&Subsystem{
+			Name: "chain",
+			Stalled: func() string {
+				lastLog := health.LastEventAt(health.SourceChain)
+				if lastLog.IsZero() || time.Since(lastLog) < viper.GetDuration("watchdog.chain_timeout") {
+					return ""
+				}
+
+				// if the nodes themselves are down, a restart won't help
+				ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("metrics.health_timeout"))
+				defer cancel()
+
+				if _, err := gb.ProviderPool.BlockNumber(ctx); err != nil {
+					return ""
+				}
+
+				return "no logs received since " + Since(lastLog) + " despite healthy nodes"
+			},
+			Restart: gb.ProviderPool.ReconnectProviders,
+		})
+	}
+
+	// stream connected but no events
+	if seawa != nil {
+		wd.Watch(&Subsystem{
+			Name: "opensea",
+			Stalled: func() string {
+				connectedSince := health.StreamConnectedSince(health.SourceOpenSea)
+				if connectedSince.IsZero() {
+					return ""
+				}
+
+				lastEvent := health.LastEventAt(health.SourceOpenSea)
+				if lastEvent.Before(connectedSince) {
+					lastEvent = connectedSince
+				}
+
+				if time.Since(lastEvent) < viper.GetDuration("watchdog.stream_timeout") {
+					return ""
+				}
+
+				return "connected but no events since " + Since(lastEvent)
+			},
+			Restart: seawa.Reconnect,
+		})
+	}
+
+	// stats box ticker stopped
+	if viper.GetBool("stats.enabled") && gb.Stats != nil {
+		wd.Watch(&Subsystem{
+			Name: "statsbox",
+			Stalled: func() string {
+				lastTick := health.LastEventAt(health.SourceStatsTicker)
+				// the ticker is slowed down while idle
+				if lastTick.IsZero() || time.Since(lastTick) < gloomberg.IdleInterval(viper.GetDuration("ticker.statsbox"))*3 {
+					return ""
+				}
+
+				return "ticker stopped " + Since(lastTick)
+			},
+			Restart: func() { gb.Stats.StartTicker(viper.GetDuration("ticker.statsbox"), queueOutput) },
+		})
+	}
+
+	wd.Run()
+}
@@ -0,0 +1,100 @@
+package watchdog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/spf13/viper"
+)
+
+var restartsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gloomberg_watchdog_restarts_total",
+	Help: "The number of restarts of stalled subsystems by the watchdog.",
+}, []string{"subsystem"})
+
+// Subsystem is a component watched by the watchdog.
+type Subsystem struct {
+	Name string
+
+	// Stalled returns a reason if the subsystem is stalled or an empty string if it is fine
+	Stalled func() string
+
+	// Restart restarts the subsystem
+	Restart func()
+
+	lastRestart time.Time
+}
+
+// Watchdog periodically checks the registered subsystems and restarts them if they are stalled.
+type Watchdog struct {
+	subsystems []*Subsystem
+
+	mu *sync.Mutex
+}
+
+func New() *Watchdog {
+	return &Watchdog{
+		subsystems: make([]*Subsystem, 0),
+		mu:         &sync.Mutex{},
+	}
+}
+
+// Watch adds a subsystem to the watchdog.
+func (wd *Watchdog) Watch(subsystem *Subsystem) {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	wd.subsystems = append(wd.subsystems, subsystem)
+}
+
+// Run starts the periodic checks.
+func (wd *Watchdog) Run() {
+	ticker := time.NewTicker(viper.GetDuration("watchdog.interval"))
+
+	gbl.Log.Infof("🐕 watchdog started | watching %d subsystems", len(wd.subsystems))
+
+	for range ticker.C {
+		wd.check()
+	}
+}
+
+func (wd *Watchdog) check() {
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	for _, subsystem := range wd.subsystems {
+		reason := subsystem.Stalled()
+		if reason == "" {
+			continue
+		}
+
+		// give the subsystem some time to recover after a restart
+		if time.Since(subsystem.lastRestart) < viper.GetDuration("watchdog.cooldown") {
+			gbl.Log.Debugf("🐕 %s still stalled (%s), waiting for cooldown", subsystem.Name, reason)
+
+			continue
+		}
+
+		gloomberg.PrModf("watchdog", "%s stalled: %s %s restarting...", style.BoldAlmostWhite(subsystem.Name), reason, style.DarkGrayStyle.Render("|"))
+
+		subsystem.lastRestart = time.Now()
+		restartsCounter.WithLabelValues(subsystem.Name).Inc()
+
+		go subsystem.Restart()
+	}
+}
+
+// Since formats the time since t for incident lines.
+func Since(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+
+	return fmt.Sprintf("%.0fmin ago", time.Since(t).Minutes())
+}
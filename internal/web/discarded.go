@@ -0,0 +1,24 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/benleb/gloomberg/internal/discarded"
+	"github.com/benleb/gloomberg/internal/gbl"
+)
+
+// serveDiscarded returns the recently discarded events with their reason as json (?n=100&reason=min-value).
+func serveDiscarded(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	if err != nil || n <= 0 {
+		n = 100
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(discarded.Recent(n, discarded.Reason(r.URL.Query().Get("reason")))); err != nil {
+		gbl.Log.Debugf("❗️ encoding discarded events failed: %s", err)
+	}
+}
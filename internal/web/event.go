@@ -59,6 +59,13 @@ type EventPayload struct {
 	Message string `json:"message"`
 }
 
+// CommandPayload is sent by the web ui to trigger an action.
+type CommandPayload struct {
+	Command string `json:"command"`
+	TxHash  string `json:"tx_hash,omitempty"`
+	Note    string `json:"note,omitempty"`
+}
+
 // 	// action performed by the tx
 // 	Action totra.TxType `json:"action"`
 
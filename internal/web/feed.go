@@ -0,0 +1,114 @@
+package web
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/style"
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/spf13/viper"
+)
+
+// feed streams the raw token transactions as json to gloomberg instances running in client mode.
+// the clients format & filter the events themselves.
+type feed struct {
+	gb *gloomberg.Gloomberg
+
+	clients   map[net.Conn]chan []byte
+	clientsMu sync.RWMutex
+
+	startOnce sync.Once
+}
+
+func newFeed(gb *gloomberg.Gloomberg) *feed {
+	return &feed{
+		gb:      gb,
+		clients: make(map[net.Conn]chan []byte),
+	}
+}
+
+// serveFeed upgrades the connection & sends all token transactions to the client until it disconnects.
+func (f *feed) serveFeed(w http.ResponseWriter, r *http.Request) {
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		gbl.Log.Error(err)
+
+		return
+	}
+
+	// subscribe to the token transactions on the first client
+	f.startOnce.Do(func() { go f.broadcaster() })
+
+	egress := make(chan []byte, viper.GetInt("gloomberg.eventhub.outQueuesSize"))
+
+	f.clientsMu.Lock()
+	f.clients[conn] = egress
+	f.clientsMu.Unlock()
+
+	gloomberg.PrModf("web", "feed client connected: %s", style.AlmostWhiteStyle.Render(conn.RemoteAddr().String()))
+
+	// writer
+	go func() {
+		for msg := range egress {
+			if err := wsutil.WriteServerText(conn, msg); err != nil {
+				gbl.Log.Debugf("sending to feed client %s failed: %s", conn.RemoteAddr(), err)
+
+				break
+			}
+		}
+
+		conn.Close()
+	}()
+
+	// reader, only used to handle control frames & to notice disconnects
+	go func() {
+		for {
+			if _, _, err := wsutil.ReadClientData(conn); err != nil {
+				break
+			}
+		}
+
+		f.removeClient(conn)
+
+		gloomberg.PrModf("web", "feed client disconnected: %s", style.AlmostWhiteStyle.Render(conn.RemoteAddr().String()))
+	}()
+}
+
+func (f *feed) removeClient(conn net.Conn) {
+	f.clientsMu.Lock()
+	defer f.clientsMu.Unlock()
+
+	if egress, ok := f.clients[conn]; ok {
+		close(egress)
+		delete(f.clients, conn)
+	}
+}
+
+func (f *feed) broadcaster() {
+	for ttx := range f.gb.SubscribeTokenTransactions() {
+		marshalledTTX, err := json.Marshal(ttx)
+		if err != nil {
+			gbl.Log.Errorf("error marshalling ttx: %s", err.Error())
+
+			continue
+		}
+
+		f.clientsMu.RLock()
+
+		for conn, egress := range f.clients {
+			// drop events for slow clients instead of blocking the others
+			select {
+			case egress <- marshalledTTX:
+			default:
+				gbl.Log.Debugf("feed client %s is too slow, dropping event", conn.RemoteAddr())
+			}
+		}
+
+		f.clientsMu.RUnlock()
+	}
+}
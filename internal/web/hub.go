@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/benleb/gloomberg/internal/bookmarks"
 	"github.com/benleb/gloomberg/internal/external"
 	"github.com/benleb/gloomberg/internal/gbl"
 	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
@@ -42,6 +43,7 @@ var (
 	// }.
 
 	ErrEventNotSupported = errors.New("this event type is not supported")
+	ErrInvalidCommand    = errors.New("invalid command")
 )
 
 // // checkOrigin will check origin and return true if its allowed
@@ -241,6 +243,32 @@ func (wh *WsHub) setupEventHandlers() {
 	wh.handlers[MsgCommand] = func(msg Message, _ *WsClient) error {
 		gbl.Log.Info("received message: ", msg)
 
+		if msg.Payload == nil {
+			return ErrInvalidCommand
+		}
+
+		var command CommandPayload
+		if err := json.Unmarshal(*msg.Payload, &command); err != nil {
+			return err
+		}
+
+		switch command.Command {
+		case "bookmark":
+			if len(common.FromHex(command.TxHash)) != common.HashLength {
+				return ErrInvalidCommand
+			}
+
+			bookmark, err := bookmarks.Add(common.HexToHash(command.TxHash), command.Note)
+			if err != nil {
+				return err
+			}
+
+			gloomberg.PrModf("web", "🔖 bookmarked %s %s | %s", bookmark.Action, style.BoldAlmostWhite(strings.Join(bookmark.Collections, ", ")), style.ShortenHashStyled(common.HexToHash(bookmark.TxHash)))
+
+		default:
+			return ErrInvalidCommand
+		}
+
 		return nil
 	}
 }
@@ -0,0 +1,313 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benleb/gloomberg/internal/degendb"
+	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
+	"github.com/benleb/gloomberg/internal/nemo/pnl"
+	"github.com/benleb/gloomberg/internal/nemo/price"
+	"github.com/benleb/gloomberg/internal/nemo/wallet"
+	"github.com/spf13/viper"
+)
+
+var ErrNoPortfolioSecret = errors.New("web.portfolio.secret is not set")
+
+// max number of recent events shown on the portfolio page.
+const maxPortfolioEvents = 25
+
+// portfolio is the read-only view of a wallet group.
+type portfolio struct {
+	Group     string
+	UpdatedAt string
+
+	Balance   string
+	CostBasis string
+	Realized  string
+
+	Wallets  []portfolioWallet
+	Holdings []portfolioHolding
+	Events   []portfolioEvent
+}
+
+type portfolioWallet struct {
+	Name    string
+	Address string
+	Balance string
+}
+
+type portfolioHolding struct {
+	Collection string
+	TokenID    string
+	Amount     int64
+	AvgCost    string
+	HeldFor    string
+}
+
+type portfolioEvent struct {
+	Time        string
+	Action      string
+	Collections string
+	Price       string
+}
+
+var portfolioTemplate = template.Must(template.New("portfolio").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Group}} | gloomberg</title>
+<style>
+body { background: #1a1b26; color: #c0caf5; font-family: monospace; margin: 2em; }
+h1, h2 { color: #bb9af7; font-weight: normal; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+td, th { padding: 0.2em 1em 0.2em 0; text-align: left; }
+th { color: #565f89; font-weight: normal; }
+.num { text-align: right; }
+.gray { color: #565f89; }
+</style>
+</head>
+<body>
+<h1>{{.Group}}</h1>
+<p>balance <b>{{.Balance}}Ξ</b> · cost basis <b>{{.CostBasis}}Ξ</b> · realized pnl <b>{{.Realized}}Ξ</b> <span class="gray">· {{.UpdatedAt}}</span></p>
+
+<h2>wallets</h2>
+<table>
+{{range .Wallets}}<tr><td>{{.Name}}</td><td class="gray">{{.Address}}</td><td class="num">{{.Balance}}Ξ</td></tr>
+{{end}}</table>
+
+<h2>holdings</h2>
+<table>
+<tr><th>collection</th><th>token</th><th class="num">amount</th><th class="num">avg cost</th><th>held</th></tr>
+{{range .Holdings}}<tr><td>{{.Collection}}</td><td>#{{.TokenID}}</td><td class="num">{{.Amount}}</td><td class="num">{{.AvgCost}}Ξ</td><td class="gray">{{.HeldFor}}</td></tr>
+{{else}}<tr><td class="gray">no holdings in the pnl ledgers (import the wallets with "gloomberg import-wallet")</td></tr>
+{{end}}</table>
+
+<h2>recent activity</h2>
+<table>
+{{range .Events}}<tr><td class="gray">{{.Time}}</td><td>{{.Action}}</td><td>{{.Collections}}</td><td class="num">{{.Price}}Ξ</td></tr>
+{{else}}<tr><td class="gray">no recent activity</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// PortfolioToken returns the access token of the portfolio link of a wallet group.
+// it is derived from web.portfolio.secret, so each link only grants access to its group.
+func PortfolioToken(group string) (string, error) {
+	secret := viper.GetString("web.portfolio.secret")
+	if secret == "" {
+		return "", ErrNoPortfolioSecret
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(group))
+
+	return hex.EncodeToString(mac.Sum(nil))[:32], nil
+}
+
+// PortfolioLink returns the shareable read-only link to the portfolio of a wallet group.
+func PortfolioLink(group string) (string, error) {
+	token, err := PortfolioToken(group)
+	if err != nil {
+		return "", err
+	}
+
+	baseURL := viper.GetString("web.portfolio.url")
+	if baseURL == "" {
+		scheme := "http"
+		if viper.GetString("tls.certificate") != "" {
+			scheme = "https"
+		}
+
+		baseURL = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(viper.GetString("web.host"), fmt.Sprint(viper.GetInt("web.portfolio.port"))))
+	}
+
+	return fmt.Sprintf("%s/portfolio/%s?token=%s", strings.TrimRight(baseURL, "/"), group, token), nil
+}
+
+// StartPortfolioServer serves the read-only portfolio pages of the wallet groups on a separate port
+// without the rest of the web ui.
+func StartPortfolioServer(gb *gloomberg.Gloomberg) error {
+	if viper.GetString("web.portfolio.secret") == "" {
+		return ErrNoPortfolioSecret
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/portfolio/", func(w http.ResponseWriter, r *http.Request) {
+		servePortfolio(gb, w, r)
+	})
+
+	listenOn := &net.TCPAddr{IP: net.ParseIP(viper.GetString("web.host")), Port: viper.GetInt("web.portfolio.port")}
+
+	server := &http.Server{
+		Addr:              listenOn.AddrPort().String(),
+		ReadHeaderTimeout: 2 * time.Second,
+		Handler:           mux,
+	}
+
+	// use tls like the web ui if a certificate is configured
+	if viper.GetString("tls.certificate") != "" {
+		tlsConfig, err := gloomberg.GetServerTLSConfig()
+		if err != nil {
+			return err
+		}
+
+		server.TLSConfig = tlsConfig
+	}
+
+	go func() {
+		var err error
+
+		if server.TLSConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+
+		if err != nil {
+			gbl.Log.Errorf("❌ portfolio server stopped: %s", err)
+		}
+	}()
+
+	_, groupNames := gb.OwnWallets.Groups()
+	for _, group := range groupNames {
+		if link, err := PortfolioLink(group); err == nil {
+			gloomberg.PrModf("web", "portfolio of %s: %s", group, link)
+		}
+	}
+
+	return nil
+}
+
+func servePortfolio(gb *gloomberg.Gloomberg, w http.ResponseWriter, r *http.Request) {
+	group := strings.Trim(strings.TrimPrefix(r.URL.Path, "/portfolio/"), "/")
+
+	expectedToken, err := PortfolioToken(group)
+	if err != nil || !hmac.Equal([]byte(expectedToken), []byte(r.URL.Query().Get("token"))) {
+		http.Error(w, "not found", http.StatusNotFound)
+
+		return
+	}
+
+	groups, _ := gb.OwnWallets.Groups()
+
+	groupWallets, ok := groups[group]
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := portfolioTemplate.Execute(w, newPortfolio(r.Context(), gb, group, groupWallets)); err != nil {
+		gbl.Log.Debugf("❗️ rendering portfolio of %s failed: %s", group, err)
+	}
+}
+
+// newPortfolio collects the balances, the holdings & pnl from the ledgers & the recent events of the wallets.
+func newPortfolio(ctx context.Context, gb *gloomberg.Gloomberg, group string, groupWallets []*wallet.Wallet) *portfolio {
+	view := &portfolio{Group: group, UpdatedAt: time.Now().Format("2006-01-02 15:04")}
+
+	balance, costBasis, realized := big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	addresses := make(map[string]bool, len(groupWallets))
+
+	useLedgers := viper.GetBool("redis.enabled") && gb.Rueidi != nil
+
+	for _, w := range groupWallets {
+		addresses[w.Address.Hex()] = true
+
+		if w.Balance != nil {
+			balance.Add(balance, w.Balance)
+		}
+
+		view.Wallets = append(view.Wallets, portfolioWallet{Name: w.Name, Address: w.Address.Hex(), Balance: formatEther(w.Balance)})
+
+		if !useLedgers {
+			continue
+		}
+
+		ledger := pnl.GetLedger(ctx, gb.Rueidi, w.Address)
+
+		costBasis.Add(costBasis, ledger.CostBasis().Wei())
+		realized.Add(realized, ledger.RealizedPnL().Wei())
+
+		for _, holding := range pnl.SortedHoldings(ledger.Holdings) {
+			view.Holdings = append(view.Holdings, portfolioHolding{
+				Collection: collectionName(gb, holding),
+				TokenID:    holding.Token.ID.String(),
+				Amount:     holding.Amount,
+				AvgCost:    fmt.Sprintf("%.3f", holding.AvgCost().Ether()),
+				HeldFor:    pnl.FormatHeldFor(time.Since(holding.AcquiredAt)),
+			})
+		}
+	}
+
+	view.Balance, view.CostBasis, view.Realized = formatEther(balance), formatEther(costBasis), formatEther(realized)
+
+	// recent events of the group wallets
+	events := make([]*degendb.PreformattedEvent, 0)
+
+	for _, event := range gb.RecentOwnEvents.ToSlice() {
+		if addresses[event.FromAddress.Hex()] || addresses[event.ToAddress.Hex()] {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].ReceivedAt.After(events[j].ReceivedAt) })
+
+	for _, event := range events[:min(len(events), maxPortfolioEvents)] {
+		collectionNames := make([]string, 0, len(event.TransferredCollections))
+		for _, collection := range event.TransferredCollections {
+			collectionNames = append(collectionNames, fmt.Sprintf("%dx %s", len(collection.TransferredTokens), collection.CollectionName))
+		}
+
+		eventPrice := "0.000"
+		if event.Price != nil {
+			eventPrice = fmt.Sprintf("%.3f", event.Price.Ether())
+		}
+
+		view.Events = append(view.Events, portfolioEvent{
+			Time:        event.ReceivedAt.Format("01-02 15:04"),
+			Action:      event.Action,
+			Collections: strings.Join(collectionNames, ", "),
+			Price:       eventPrice,
+		})
+	}
+
+	return view
+}
+
+func collectionName(gb *gloomberg.Gloomberg, holding *pnl.Holding) string {
+	gb.CollectionDB.RWMu.RLock()
+	defer gb.CollectionDB.RWMu.RUnlock()
+
+	if collection, ok := gb.CollectionDB.Collections[holding.Token.Address]; ok && collection.Name != "" {
+		return collection.Name
+	}
+
+	return holding.Token.Address.Hex()
+}
+
+func formatEther(wei *big.Int) string {
+	if wei == nil {
+		return "0.000"
+	}
+
+	return fmt.Sprintf("%.3f", price.NewPrice(wei).Ether())
+}
@@ -52,6 +52,12 @@ func StartWebUI(gb *gloomberg.Gloomberg) (*WsHub, error) {
 	// websocket endpoint
 	http.HandleFunc("/ws", hub.serveWS)
 
+	// raw token transactions for gloomberg instances in client mode
+	http.HandleFunc("/feed", newFeed(gb).serveFeed)
+
+	// recently discarded events with their reason to tune the filters
+	http.HandleFunc("/discarded", serveDiscarded)
+
 	// prometheus metrics
 	http.Handle("/metrics", promhttp.Handler())
 
@@ -1,58 +1,87 @@
-//nolint:all
 package ws
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
-	"log"
 	"time"
 
 	"github.com/benleb/gloomberg/internal/gbl"
+	"github.com/benleb/gloomberg/internal/health"
+	"github.com/benleb/gloomberg/internal/nemo/gloomberg"
 	"github.com/benleb/gloomberg/internal/nemo/totra"
+	"github.com/benleb/gloomberg/internal/style"
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
+	"github.com/spf13/viper"
 )
 
-// StartWsClient connects to a btv server to receive events from.
-func StartWsClient(url string, queueEvents *chan *totra.TokenTransaction) {
-	ctx := context.Background()
+// StartClient connects to the feed of a remote gloomberg server (e.g. wss://home-server:42069/feed)
+// & queues the received token transactions to be formatted & filtered locally.
+// the connection is re-established with an increasing backoff if it gets lost.
+func StartClient(gb *gloomberg.Gloomberg, url string) {
+	dialer := ws.Dialer{
+		Timeout: time.Second * 10,
+		//nolint:gosec
+		TLSConfig: &tls.Config{InsecureSkipVerify: viper.GetBool("websockets.client.insecure")},
+	}
+
+	backoff := time.Second
+
+	for {
+		gloomberg.PrModf("ws", "connecting to gloomberg server %s", style.AlmostWhiteStyle.Render(url))
+
+		connected, err := receive(gb, dialer, url)
+		if err != nil {
+			gbl.Log.Warnf("❗️ connection to gloomberg server %s lost: %s", url, err)
+		}
+
+		health.SetStreamConnected(health.SourceRemote, false)
+
+		// start over with a short backoff if we were connected
+		if connected {
+			backoff = time.Second
+		}
 
-	gbl.Log.Infof("starting websocket client | url: %s", url)
+		time.Sleep(backoff)
 
-	// TODO remove sleep and use a proper wait
-	time.Sleep(1 * time.Second)
+		backoff = min(backoff*2, viper.GetDuration("websockets.client.max_backoff"))
+	}
+}
 
-	conn, _, _, err := ws.Dial(ctx, url)
+// receive reads token transactions from the server until the connection is closed.
+// returns true if the connection was established.
+func receive(gb *gloomberg.Gloomberg, dialer ws.Dialer, url string) (bool, error) {
+	conn, _, _, err := dialer.Dial(context.Background(), url)
 	if err != nil {
-		// handle error
-		gbl.Log.Errorf("error connecting to server: %s", err)
-		return
+		return false, err
 	}
 
-	gbl.Log.Infof("client conn: %+v | err: %s\n", conn, err)
+	defer conn.Close()
 
-	go func() {
-		defer conn.Close()
+	health.SetStreamConnected(health.SourceRemote, true)
 
-		for {
-			// msg, op, err := wsutil.ReadServerData(conn)
+	gloomberg.PrModf("ws", "connected to gloomberg server %s", style.AlmostWhiteStyle.Render(url))
 
-			// msg, err := wsutil.ReadServerText(conn)
-			// m := []wsutil.Message{}
-			msg, err := wsutil.ReadServerText(conn)
-			if err != nil {
-				// handle error
-				gbl.Log.Errorf("client msg2: %v | err: %s\n", msg, err)
-			}
+	for {
+		msg, err := wsutil.ReadServerText(conn)
+		if err != nil {
+			return true, err
+		}
 
-			var ttx *totra.TokenTransaction
-			if err := json.Unmarshal(msg, &ttx); err != nil {
-				log.Fatal(err)
-			}
+		health.EventReceived(health.SourceRemote)
 
-			gbl.Log.Infof("client event: %v\n", ttx)
+		var ttx *totra.TokenTransaction
+		if err := json.Unmarshal(msg, &ttx); err != nil || ttx == nil {
+			gbl.Log.Warnf("❗️ error unmarshalling ttx from gloomberg server: %s", err)
 
-			*queueEvents <- ttx
+			continue
 		}
-	}()
+
+		// annotations & labels are added locally
+		ttx.Annotations = nil
+		ttx.IsMEV = false
+
+		gb.In.TokenTransactions <- ttx
+	}
 }
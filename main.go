@@ -2,15 +2,10 @@
 package main
 
 import (
-	"fmt"
-	"os"
-	"os/signal"
-
 	"github.com/benleb/gloomberg/cmd"
 	"github.com/benleb/gloomberg/internal"
 	"github.com/benleb/gloomberg/internal/style"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/ethereum/go-ethereum/log"
 	"github.com/muesli/termenv"
 )
 
@@ -27,27 +22,10 @@ func main() {
 	lipgloss.SetColorProfile(termenv.TrueColor)
 	termenv.DefaultOutput().SetForegroundColor(termenv.RGBColor(style.LightGrayForeground))
 
-	// signal handler channel
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-
-	go func() {
-		sig := <-c
-
-		// ctrl+c handler
-		log.Debug(fmt.Sprintf("Got %s signal. Aborting...\n", sig))
-
-		// if err := client.Disconnect(context.TODO()); err != nil {
-		// 	panic(err)
-		// }
-		cmd.GracefulShutdown()
-
-		// reset/restore default foreground color
-		termenv.DefaultOutput().SetForegroundColor(defaultForeground)
-		termenv.DefaultOutput().Reset()
-
-		os.Exit(0)
-	}()
-
+	// ctrl+c/sigterm are handled by the commands, e.g. live drains the queued events before shutting down
 	cmd.Execute()
+
+	// reset/restore default foreground color
+	termenv.DefaultOutput().SetForegroundColor(defaultForeground)
+	termenv.DefaultOutput().Reset()
 }